@@ -0,0 +1,144 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta2
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AutomatedRuleSpec defines the desired state of AutomatedRule.
+type AutomatedRuleSpec struct {
+	// Name of the Cryostat instance in this namespace that the rule belongs to.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=1
+	CryostatName string `json:"cryostatName"`
+	// Name of the rule within Cryostat. Defaults to the name of this AutomatedRule,
+	// with any characters other than letters, digits and underscores replaced by underscores.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_]+$`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Name string `json:"name,omitempty"`
+	// A description of the rule.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Description string `json:"description,omitempty"`
+	// Expression used to select the targets that the rule applies to, such as
+	// "target.annotations.cryostat['NAMESPACE'] == 'my-app-namespace'".
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=2
+	MatchExpression string `json:"matchExpression"`
+	// Event specifier for the recordings started by this rule, such as
+	// "template=Continuous,type=TARGET".
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=3
+	EventSpecifier string `json:"eventSpecifier"`
+	// How often to copy the rule's recordings into Cryostat's archives.
+	// If unset, recordings are not archived periodically.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	ArchivalPeriod *metav1.Duration `json:"archivalPeriod,omitempty"`
+	// How long to wait after a target is discovered before starting the recording.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	InitialDelay *metav1.Duration `json:"initialDelay,omitempty"`
+	// Number of archived copies to keep for each target. Older copies are deleted.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	PreservedArchives int32 `json:"preservedArchives,omitempty"`
+	// Maximum age of the data retained by each recording. If unset, the target's default is used.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Maximum Age"
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+	// Maximum size of the data retained by each recording. If unset, the target's default is used.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Maximum Size"
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+	// Whether the rule should start recordings in matching targets. Defaults to true.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// AutomatedRuleStatus defines the observed state of AutomatedRule.
+type AutomatedRuleStatus struct {
+	// Conditions of the AutomatedRule.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Name of the rule within Cryostat.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	RuleName string `json:"ruleName,omitempty"`
+	// ID of the rule within Cryostat.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	RuleID *int64 `json:"ruleId,omitempty"`
+	// The most recent drift between this specification and the rule in Cryostat,
+	// which the operator corrected.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	LastDrift *AutomatedRuleDrift `json:"lastDrift,omitempty"`
+}
+
+// AutomatedRuleDrift describes changes made to a rule within Cryostat,
+// rather than through its AutomatedRule.
+type AutomatedRuleDrift struct {
+	// Time when the drift was detected.
+	DetectedTime metav1.Time `json:"detectedTime"`
+	// Fields of the rule in Cryostat that differed from the specification.
+	// Empty if the rule was missing from Cryostat.
+	// +optional
+	Fields []string `json:"fields,omitempty"`
+}
+
+// AutomatedRuleConditionType refers to a Condition type that may be used in an AutomatedRule's status.conditions
+type AutomatedRuleConditionType string
+
+const (
+	// Whether the rule in Cryostat matches the AutomatedRule's specification.
+	ConditionTypeAutomatedRuleSynchronized AutomatedRuleConditionType = "Synchronized"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=automatedrules,scope=Namespaced
+
+// AutomatedRule manages an automated rule within Cryostat, which starts recordings in
+// all targets matching an expression. An AutomatedRule must be created in the same
+// namespace as its Cryostat instance.
+// +operator-sdk:csv:customresourcedefinitions:resources={}
+// +kubebuilder:printcolumn:name="Cryostat",type=string,JSONPath=`.spec.cryostatName`
+// +kubebuilder:printcolumn:name="Rule",type=string,JSONPath=`.status.ruleName`
+// +kubebuilder:printcolumn:name="Synchronized",type=string,JSONPath=`.status.conditions[?(@.type=="Synchronized")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type AutomatedRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AutomatedRuleSpec   `json:"spec,omitempty"`
+	Status AutomatedRuleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AutomatedRuleList contains a list of AutomatedRule
+type AutomatedRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AutomatedRule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AutomatedRule{}, &AutomatedRuleList{})
+}
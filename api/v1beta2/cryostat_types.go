@@ -18,8 +18,10 @@ import (
 	authzv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 // CryostatSpec defines the desired state of Cryostat.
@@ -40,11 +42,23 @@ type CryostatSpec struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Event Templates"
 	EventTemplates []TemplateConfigMap `json:"eventTemplates,omitempty"`
+	// Selects ConfigMaps containing Flight Recorder Event Templates to upload to Cryostat.
+	// ConfigMaps are discovered in the installation namespace and in each target namespace,
+	// and every key ending in ".jfc" is uploaded as a template. Unlike eventTemplates,
+	// templates are uploaded through the Cryostat API without restarting Cryostat.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Event Template Selector"
+	EventTemplateSelector *metav1.LabelSelector `json:"eventTemplateSelector,omitempty"`
 	// Use cert-manager to secure in-cluster communication between Cryostat components.
 	// Requires cert-manager to be installed.
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=3,displayName="Enable cert-manager Integration",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	EnableCertManager *bool `json:"enableCertManager"`
+	// Options to rotate the certificate authority used to secure communication between Cryostat
+	// components and agents. Requires cert-manager integration to be enabled.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="CA Rotation Options"
+	CARotation *CARotationOptions `json:"caRotation,omitempty"`
 	// Options to customize the storage provisioned for the database and object storage.
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
@@ -82,7 +96,8 @@ type CryostatSpec struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
 	SecurityOptions *SecurityOptions `json:"securityOptions,omitempty"`
-	// Options to configure scheduling for the Cryostat deployment
+	// Options to configure scheduling for the Cryostat deployment. These also apply to the database
+	// and object storage deployments, unless scheduling options are configured for those components.
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	SchedulingOptions *SchedulingConfiguration `json:"schedulingOptions,omitempty"`
@@ -90,10 +105,22 @@ type CryostatSpec struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	TargetDiscoveryOptions *TargetDiscoveryOptions `json:"targetDiscoveryOptions,omitempty"`
+	// Additional configuration for the Cryostat application container, such as environment variables and JVM options.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Core Options"
+	CoreOptions *CoreOptions `json:"coreOptions,omitempty"`
+	// Options to configure the Grafana dashboard and JFR datasource, such as additional dashboards and datasources.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Grafana Options"
+	GrafanaOptions *GrafanaOptions `json:"grafanaOptions,omitempty"`
 	// Options to configure the Cryostat application's database.
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Database Options"
 	DatabaseOptions *DatabaseOptions `json:"databaseOptions,omitempty"`
+	// Options to configure the Cryostat application's object storage.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Object Storage Options"
+	ObjectStorageOptions *ObjectStorageOptions `json:"objectStorageOptions,omitempty"`
 	// Options to configure the Cryostat deployments and pods metadata
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Operand metadata"
@@ -103,6 +130,179 @@ type CryostatSpec struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Agent Options"
 	AgentOptions *AgentOptions `json:"agentOptions,omitempty"`
+	// Scale down all Cryostat components, while keeping their Persistent Volume Claims, Secrets and RBAC.
+	// The Cryostat Agent webhook does not inject agents into pods for this Cryostat while it is paused.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Paused",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Paused bool `json:"paused,omitempty"`
+	// Stop the operator from creating, updating or deleting any resources for this Cryostat,
+	// so that they can be modified manually. Deleting the Cryostat is still handled by the operator.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Reconcile Paused",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ReconcilePaused bool `json:"reconcilePaused,omitempty"`
+	// Override the images of Cryostat components for this Cryostat, in place of the images
+	// provided by the operator. Overridden images are not updated when the operator is upgraded.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Image Overrides",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Images *ImageOverrides `json:"images,omitempty"`
+	// Secrets containing credentials to pull the images of Cryostat components, such as from a private mirror.
+	// These are added to all pods created for this Cryostat, and to its Service Account.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Image Pull Secrets",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// Partial pod templates to strategically merge over the pod templates generated for each component.
+	// These may be used to configure properties not otherwise exposed, such as additional containers,
+	// volumes or environment variables. Overrides that conflict with properties set by the operator
+	// are reported by the OverridesApplied condition.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Pod Template Overrides",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Overrides *PodTemplateOverrides `json:"overrides,omitempty"`
+	// Options to tune the health probes and graceful shutdown of each Cryostat component.
+	// When unset, probe timeouts and startup probe thresholds are derived from the CPU
+	// requested for each container, allowing more time to containers with smaller CPU requests.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Probe Options",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	ProbeOptions *ProbeOptions `json:"probeOptions,omitempty"`
+}
+
+// ProbeOptions contains probe and graceful shutdown settings for Cryostat components.
+type ProbeOptions struct {
+	// Probe settings for the Cryostat application. The termination grace period applies to the Cryostat pod.
+	// +optional
+	Core *PodProbeConfiguration `json:"core,omitempty"`
+	// Probe settings for the report generator.
+	// +optional
+	Reports *PodProbeConfiguration `json:"reports,omitempty"`
+	// Probe settings for the database. The liveness probe settings are applied to the database's readiness probe.
+	// +optional
+	Database *PodProbeConfiguration `json:"database,omitempty"`
+	// Probe settings for the object storage.
+	// +optional
+	Storage *PodProbeConfiguration `json:"storage,omitempty"`
+	// Probe settings for Grafana. The termination grace period only applies when Grafana
+	// runs in a separate deployment.
+	// +optional
+	Grafana *PodProbeConfiguration `json:"grafana,omitempty"`
+	// Probe settings for the JFR datasource.
+	// +optional
+	Datasource *ContainerProbeConfiguration `json:"datasource,omitempty"`
+	// Probe settings for the authorization proxy.
+	// +optional
+	AuthProxy *ContainerProbeConfiguration `json:"authProxy,omitempty"`
+}
+
+// ContainerProbeConfiguration contains probe settings for a container.
+type ContainerProbeConfiguration struct {
+	// Settings for the liveness probe of the container.
+	// +optional
+	LivenessProbe *ProbeSettings `json:"livenessProbe,omitempty"`
+	// Settings for the startup probe of the container. The startup probe uses the same check as
+	// the liveness probe, and is added to containers that do not have one by default.
+	// +optional
+	StartupProbe *StartupProbeSettings `json:"startupProbe,omitempty"`
+}
+
+// PodProbeConfiguration contains probe settings for a container, along with settings
+// for the pod it runs in.
+type PodProbeConfiguration struct {
+	ContainerProbeConfiguration `json:",inline"`
+	// Duration in seconds that the pod is given to shut down gracefully before it is forcibly stopped.
+	// Defaults to 30 seconds.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+}
+
+// ProbeSettings contains tunable properties of a probe. Unset properties keep their defaults.
+type ProbeSettings struct {
+	// Number of seconds after the container has started before the probe is initiated.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+	// How often in seconds to perform the probe.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+	// Number of seconds after which the probe times out.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+	// Number of consecutive failures before the probe is considered failed.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+}
+
+// StartupProbeSettings contains tunable properties of a startup probe.
+type StartupProbeSettings struct {
+	ProbeSettings `json:",inline"`
+	// Remove the startup probe from the container.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// PodTemplateOverrides contains partial pod templates for Cryostat components.
+type PodTemplateOverrides struct {
+	// Partial pod template for the Cryostat application.
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Core *corev1.PodTemplateSpec `json:"core,omitempty"`
+	// Partial pod template for the report generator.
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Reports *corev1.PodTemplateSpec `json:"reports,omitempty"`
+	// Partial pod template for the database.
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Database *corev1.PodTemplateSpec `json:"database,omitempty"`
+	// Partial pod template for the object storage.
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Storage *corev1.PodTemplateSpec `json:"storage,omitempty"`
+	// Partial pod template for the Grafana dashboard and JFR datasource. Only applies when
+	// spec.grafanaOptions.separateDeployment is true; otherwise, these run in the Cryostat application's pod.
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Grafana *corev1.PodTemplateSpec `json:"grafana,omitempty"`
+}
+
+// ImageOverrides contains images to use for Cryostat components in place of those provided by the operator.
+type ImageOverrides struct {
+	// Image for the Cryostat application.
+	// +optional
+	Core string `json:"core,omitempty"`
+	// Image for the JFR datasource.
+	// +optional
+	Datasource string `json:"datasource,omitempty"`
+	// Image for the Grafana dashboard.
+	// +optional
+	Grafana string `json:"grafana,omitempty"`
+	// Image for the reports generator.
+	// +optional
+	Reports string `json:"reports,omitempty"`
+	// Image for the object storage.
+	// +optional
+	Storage string `json:"storage,omitempty"`
+	// Image for the database.
+	// +optional
+	Database string `json:"database,omitempty"`
+	// Image for the authorization proxy. This replaces the OpenShift OAuth proxy on OpenShift,
+	// and the OAuth2 proxy elsewhere.
+	// +optional
+	AuthProxy string `json:"authProxy,omitempty"`
+	// Image for the agent proxy.
+	// +optional
+	AgentProxy string `json:"agentProxy,omitempty"`
 }
 
 type OperandMetadata struct {
@@ -114,6 +314,43 @@ type OperandMetadata struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Pods metadata"
 	PodMetadata *ResourceMetadata `json:"podMetadata,omitempty"`
+	// Options to configure the metadata of the Persistent Volume Claims, Secrets and Config Maps
+	// created for Cryostat.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Resources metadata"
+	ResourceMetadata *ResourceMetadata `json:"resourceMetadata,omitempty"`
+	// Options to configure the metadata of the Cryostat application's deployment and pods. These take
+	// precedence over the deployments and pods metadata above.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Core metadata"
+	Core *ComponentMetadata `json:"core,omitempty"`
+	// Options to configure the metadata of the report generator's deployment and pods. These take
+	// precedence over the deployments and pods metadata above.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Reports metadata"
+	Reports *ComponentMetadata `json:"reports,omitempty"`
+	// Options to configure the metadata of the database's deployment and pods. These take
+	// precedence over the deployments and pods metadata above.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Database metadata"
+	Database *ComponentMetadata `json:"database,omitempty"`
+	// Options to configure the metadata of the object storage's deployment and pods. These take
+	// precedence over the deployments and pods metadata above.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Storage metadata"
+	Storage *ComponentMetadata `json:"storage,omitempty"`
+}
+
+// ComponentMetadata contains metadata options for the deployment and pods of a single Cryostat component.
+type ComponentMetadata struct {
+	// Options to configure the component's deployment metadata
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	DeploymentMetadata *ResourceMetadata `json:"deploymentMetadata,omitempty"`
+	// Options to configure the component's pods metadata
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	PodMetadata *ResourceMetadata `json:"podMetadata,omitempty"`
 }
 
 // ResourceMetadata contains common metadata options used in several properties.
@@ -184,6 +421,238 @@ type CryostatStatus struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=status,order=2,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret"}
 	DatabaseSecret string `json:"databaseSecret,omitempty"`
+	// Expiry of the TLS certificates used by Cryostat components, and where their
+	// Secrets are located. Only present if cert-manager integration is enabled.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Certificates []CertificateStatus `json:"certificates,omitempty"`
+	// Progress of any rotation of the Cryostat certificate authority.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="CA Rotation"
+	CARotation *CARotationStatus `json:"caRotation,omitempty"`
+	// Event templates discovered using the event template selector, and the result of
+	// uploading each of them to Cryostat.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	EventTemplates []EventTemplateStatus `json:"eventTemplates,omitempty"`
+	// Usage of the object storage by archived recordings.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	StorageUsage *StorageUsageStatus `json:"storageUsage,omitempty"`
+	// Persistent Volume Claims mounted by the database and object storage, and the progress
+	// of any migration of their data to a new Persistent Volume Claim.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Persistent Volume Claims"
+	PersistentVolumeClaims []PersistentVolumeClaimStatus `json:"persistentVolumeClaims,omitempty"`
+	// Database image known to be compatible with the database's data, and the progress
+	// of any upgrade of the data to a new PostgreSQL major version.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Database *DatabaseStatus `json:"database,omitempty"`
+	// The generation of the Cryostat spec that was most recently reconciled.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Observed Generation"
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Version of the Cryostat application, from the tag of its image.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Version string `json:"version,omitempty"`
+	// Summary of the Deployment of each Cryostat component.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Components []ComponentStatus `json:"components,omitempty"`
+}
+
+// ComponentStatus summarizes the Deployment of a Cryostat component.
+type ComponentStatus struct {
+	// Name of the component: core, reports, database or storage.
+	Name string `json:"name"`
+	// Name of the component's Deployment.
+	DeploymentName string `json:"deploymentName"`
+	// Image of the component's primary container that the operator is deploying.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// Digest of the image that the operator is deploying. Either taken from the image
+	// reference, or resolved from the component's pods once they run the image.
+	// +optional
+	ImageDigest string `json:"imageDigest,omitempty"`
+	// Image of the primary container in the component's ready pods. This differs from
+	// the image being deployed while a new version of the component is rolling out.
+	// +optional
+	CurrentImage string `json:"currentImage,omitempty"`
+	// Digest of the image run by the component's ready pods.
+	// +optional
+	CurrentImageDigest string `json:"currentImageDigest,omitempty"`
+	// Number of pods desired for the component.
+	Replicas int32 `json:"replicas"`
+	// Number of the component's pods that are ready.
+	ReadyReplicas int32 `json:"readyReplicas"`
+	// Address used to connect to the component. For the core component, this is
+	// the address of the Cryostat web application outside of the cluster.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// DatabaseStatus describes the PostgreSQL data used by the database.
+type DatabaseStatus struct {
+	// Most recent database image that successfully started with the database's data.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// PostgreSQL major version of the database's data.
+	// +optional
+	DataVersion string `json:"dataVersion,omitempty"`
+	// Progress of an upgrade of the database's data to the PostgreSQL major version of a new database image.
+	// +optional
+	Upgrade *DatabaseUpgradeStatus `json:"upgrade,omitempty"`
+}
+
+// DatabaseUpgradeStatus describes the progress of dumping the database's data using the
+// previous database image, and restoring it using the new database image.
+type DatabaseUpgradeStatus struct {
+	// Database image the data is being upgraded for.
+	TargetImage string `json:"targetImage"`
+	// PostgreSQL major version of the data before the upgrade.
+	FromVersion string `json:"fromVersion"`
+	// PostgreSQL major version of the target image.
+	ToVersion string `json:"toVersion"`
+	// Current phase of the upgrade.
+	Phase DatabaseUpgradePhase `json:"phase"`
+	// Name of the Job upgrading the data.
+	// +optional
+	JobName string `json:"jobName,omitempty"`
+	// Why the upgrade failed, if it did.
+	// +optional
+	FailureMessage string `json:"failureMessage,omitempty"`
+	// Time at which the upgrade started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+}
+
+// DatabaseUpgradePhase is a stage of a database upgrade.
+// +kubebuilder:validation:Enum=ScalingDown;Upgrading;Failed
+type DatabaseUpgradePhase string
+
+const (
+	// The database is being scaled down, so that its data is no longer in use.
+	DatabaseUpgradePhaseScalingDown DatabaseUpgradePhase = "ScalingDown"
+	// A Job is dumping the data using the previous image and restoring it using the target image.
+	DatabaseUpgradePhaseUpgrading DatabaseUpgradePhase = "Upgrading"
+	// The data could not be upgraded. The database is rolled back to the previous image, if known.
+	DatabaseUpgradePhaseFailed DatabaseUpgradePhase = "Failed"
+)
+
+// PersistentVolumeClaimStatus describes the Persistent Volume Claim used by a Cryostat component.
+type PersistentVolumeClaimStatus struct {
+	// Component mounting the Persistent Volume Claim, either "database" or "storage".
+	Component string `json:"component"`
+	// Name of the Persistent Volume Claim mounted by the component.
+	ClaimName string `json:"claimName"`
+	// Progress of a migration of the component's data to a new Persistent Volume Claim.
+	// +optional
+	Migration *PVCMigrationStatus `json:"migration,omitempty"`
+}
+
+// PVCMigrationStatus describes the progress of copying a component's data to a new
+// Persistent Volume Claim, created with fields that cannot be changed on the existing one.
+type PVCMigrationStatus struct {
+	// Name of the Persistent Volume Claim the data is copied to.
+	TargetClaimName string `json:"targetClaimName"`
+	// Current phase of the migration.
+	Phase PVCMigrationPhase `json:"phase"`
+	// Name of the Job copying the data.
+	// +optional
+	JobName string `json:"jobName,omitempty"`
+	// Why the migration was started.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Time at which the migration started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+}
+
+// PVCMigrationPhase is a stage of a Persistent Volume Claim migration.
+// +kubebuilder:validation:Enum=ScalingDown;Copying;Failed
+type PVCMigrationPhase string
+
+const (
+	// The component is being scaled down, so that its Persistent Volume Claim is no longer in use.
+	PVCMigrationPhaseScalingDown PVCMigrationPhase = "ScalingDown"
+	// A Job is copying the data to the new Persistent Volume Claim.
+	PVCMigrationPhaseCopying PVCMigrationPhase = "Copying"
+	// The data could not be copied. The component continues to use its existing Persistent Volume Claim.
+	PVCMigrationPhaseFailed PVCMigrationPhase = "Failed"
+)
+
+// StorageUsageStatus describes the archived recordings held in the object storage.
+type StorageUsageStatus struct {
+	// Number of archived recordings.
+	ArchivedRecordings int32 `json:"archivedRecordings"`
+	// Total size of all archived recordings.
+	ArchivedRecordingsSize resource.Quantity `json:"archivedRecordingsSize"`
+	// Capacity of the object storage Persistent Volume Claim, if one is used.
+	// +optional
+	Capacity *resource.Quantity `json:"capacity,omitempty"`
+	// Number of archived recordings deleted by the retention policy when the usage was last updated.
+	// +optional
+	PrunedRecordings int32 `json:"prunedRecordings,omitempty"`
+	// Time at which the usage was last updated.
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// EventTemplateStatus describes an event template discovered in a ConfigMap.
+type EventTemplateStatus struct {
+	// Namespace of the ConfigMap containing the template.
+	Namespace string `json:"namespace"`
+	// Name of the ConfigMap containing the template.
+	ConfigMapName string `json:"configMapName"`
+	// Key within the ConfigMap containing the template file.
+	Filename string `json:"filename"`
+	// Name of the template within Cryostat, from the label of its configuration element.
+	// +optional
+	TemplateName string `json:"templateName,omitempty"`
+	// SHA-256 digest of the template file that was uploaded to Cryostat.
+	// +optional
+	Digest string `json:"digest,omitempty"`
+	// Reason the template could not be uploaded to Cryostat. Empty if the template was uploaded.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// CARotationStatus describes the progress of a staged rotation of the Cryostat certificate authority.
+type CARotationStatus struct {
+	// Revision of the certificate authority currently issuing certificates.
+	// An empty revision refers to the original certificate authority.
+	// +optional
+	ActiveRevision string `json:"activeRevision,omitempty"`
+	// Revision of the certificate authority being rotated to. It is trusted, but not yet used to issue certificates.
+	// +optional
+	PendingRevision *string `json:"pendingRevision,omitempty"`
+	// Revision of the certificate authority that previously issued certificates. It remains trusted until removed.
+	// +optional
+	PreviousRevision *string `json:"previousRevision,omitempty"`
+	// Time at which the current stage of the rotation began.
+	// +optional
+	StageStartTime *metav1.Time `json:"stageStartTime,omitempty"`
+}
+
+// CertificateStatus describes a TLS certificate issued for Cryostat.
+type CertificateStatus struct {
+	// Name of the cert-manager Certificate.
+	Name string `json:"name"`
+	// Namespace of the cert-manager Certificate.
+	Namespace string `json:"namespace"`
+	// Name of the Secret containing the certificate.
+	SecretName string `json:"secretName"`
+	// Namespaces, other than that of the Certificate, containing a copy of the certificate in a Secret of the same name.
+	// +optional
+	CopyNamespaces []string `json:"copyNamespaces,omitempty"`
+	// Time at which the certificate expires.
+	// +optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+	// Time at which cert-manager will attempt to renew the certificate.
+	// +optional
+	RenewalTime *metav1.Time `json:"renewalTime,omitempty"`
 }
 
 // CryostatConditionType refers to a Condition type that may be used in status.conditions
@@ -214,10 +683,62 @@ const (
 	ConditionTypeReportsDeploymentProgressing CryostatConditionType = "ReportsDeploymentProgressing"
 	// If enabled, whether pods in the reports deployment failed to be created or destroyed.
 	ConditionTypeReportsDeploymentReplicaFailure CryostatConditionType = "ReportsDeploymentReplicaFailure"
+	// If Grafana runs in a separate deployment, whether that deployment is available.
+	ConditionTypeGrafanaDeploymentAvailable CryostatConditionType = "GrafanaDeploymentAvailable"
+	// If Grafana runs in a separate deployment, whether that deployment is progressing.
+	ConditionTypeGrafanaDeploymentProgressing CryostatConditionType = "GrafanaDeploymentProgressing"
+	// If Grafana runs in a separate deployment, whether pods in that deployment failed to be created or destroyed.
+	ConditionTypeGrafanaDeploymentReplicaFailure CryostatConditionType = "GrafanaDeploymentReplicaFailure"
 	// If enabled, whether TLS setup is complete for the Cryostat components.
 	ConditionTypeTLSSetupComplete CryostatConditionType = "TLSSetupComplete"
+	// If TLS is enabled, whether any of the certificates for the Cryostat components are close to expiring.
+	ConditionTypeCertificatesExpiringSoon CryostatConditionType = "CertificatesExpiringSoon"
+	// If CA rotation is configured, whether a rotation of the Cryostat certificate authority is in progress.
+	ConditionTypeCARotationProgressing CryostatConditionType = "CARotationProgressing"
+	// If an event template selector is specified, whether all selected event templates were uploaded to Cryostat.
+	ConditionTypeEventTemplatesSynchronized CryostatConditionType = "EventTemplatesSynchronized"
+	// If a storage retention policy is specified, whether it was applied to the archived recordings.
+	ConditionTypeStorageRetentionApplied CryostatConditionType = "StorageRetentionApplied"
+	// If the object storage uses a Persistent Volume Claim, whether archived recordings are close to filling it.
+	ConditionTypeStorageNearCapacity CryostatConditionType = "StorageNearCapacity"
+	// If true, data is being copied to a new Persistent Volume Claim for the database or object storage.
+	ConditionTypePVCMigrationProgressing CryostatConditionType = "PVCMigrationProgressing"
+	// Whether all Cryostat components are scaled down by the paused property.
+	ConditionTypePaused CryostatConditionType = "Paused"
+	// If true, the operator is not modifying any resources for this Cryostat.
+	ConditionTypeReconcilePaused CryostatConditionType = "ReconcilePaused"
+	// Whether all Cryostat components are available.
+	ConditionTypeReady CryostatConditionType = "Ready"
+	// If true, new images for Cryostat components are being rolled out.
+	ConditionTypeUpgrading CryostatConditionType = "Upgrading"
+	// If true, new images for Cryostat components failed to roll out.
+	ConditionTypeUpgradeFailed CryostatConditionType = "UpgradeFailed"
+	// Whether the pod template overrides were applied, and if they conflict with properties set by the operator.
+	ConditionTypeOverridesApplied CryostatConditionType = "OverridesApplied"
+	// If core options specify environment variables, whether they were all added to the Cryostat application container.
+	ConditionTypeCoreEnvApplied CryostatConditionType = "CoreEnvApplied"
 )
 
+// CARotationOptions controls the staged rotation of the Cryostat certificate authority.
+// A rotation first distributes a trust bundle containing both the current and new CAs,
+// then switches to issuing certificates from the new CA, and finally stops trusting the
+// previous CA. Each stage lasts for the overlap period.
+type CARotationOptions struct {
+	// Revision of the certificate authority to use. Changing this value begins a rotation
+	// to a newly generated certificate authority.
+	// +kubebuilder:validation:Pattern=^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
+	// +kubebuilder:validation:MaxLength=20
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Revision string `json:"revision"`
+	// Time that each stage of a rotation lasts. Agents must be restarted within this period
+	// after the new CA has been distributed in order to trust it, and within this period after
+	// the new CA begins issuing certificates in order to obtain a new certificate.
+	// Defaults to 24 hours.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	OverlapPeriod *metav1.Duration `json:"overlapPeriod,omitempty"`
+}
+
 // StorageConfigurations provides customization to the storage provisioned for
 // the database and the object storage.
 type StorageConfigurations struct {
@@ -228,10 +749,36 @@ type StorageConfigurations struct {
 	// Configuration for the Persistent Volume Claim to be created by the operator for the object storage.
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
-	ObjectStorage              *StorageConfiguration `json:"objectStorage,omitempty"`
+	ObjectStorage *StorageConfiguration `json:"objectStorage,omitempty"`
+	// Retention policy for archived recordings in the object storage. If unset,
+	// archived recordings are kept until deleted by users.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Retention                  *StorageRetention `json:"retention,omitempty"`
 	LegacyStorageConfiguration `json:",inline"`
 }
 
+// StorageRetention limits the archived recordings kept in the object storage.
+// The operator periodically deletes archived recordings exceeding any of the limits,
+// starting with the oldest.
+type StorageRetention struct {
+	// Maximum age of archived recordings, such as "720h". Older archived recordings are deleted.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+	// Maximum total size of all archived recordings, such as "5Gi". The oldest archived
+	// recordings are deleted until their total size is within this limit.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	MaxTotalSize *resource.Quantity `json:"maxTotalSize,omitempty"`
+	// Maximum number of archived recordings kept for each target. The oldest archived
+	// recordings of a target are deleted when it has more than this number.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	MaxArchivesPerTarget *int32 `json:"maxArchivesPerTarget,omitempty"`
+}
+
 // StorageConfiguration provides customization to the storage created by the
 // operator to contain persisted data. If no configurations are specified, a
 // PVC will be created by default.
@@ -246,8 +793,35 @@ type StorageConfiguration struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	EmptyDir *EmptyDirConfig `json:"emptyDir,omitempty"`
+	// What to do with the Persistent Volume Claim when the Cryostat is deleted.
+	// "Delete" deletes the Persistent Volume Claim along with the Cryostat.
+	// "Retain" keeps the Persistent Volume Claim, so that a Cryostat later created with the same name uses it again.
+	// "Snapshot" takes a VolumeSnapshot of the Persistent Volume Claim before it is deleted.
+	// Defaults to "Delete".
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	DeletionPolicy PVCDeletionPolicy `json:"deletionPolicy,omitempty"`
+	// Name of the VolumeSnapshotClass used when the deletion policy is "Snapshot".
+	// Defaults to the cluster's default VolumeSnapshotClass.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	VolumeSnapshotClassName *string `json:"volumeSnapshotClassName,omitempty"`
 }
 
+// PVCDeletionPolicy is what the operator does with a Persistent Volume Claim
+// when the Cryostat that owns it is deleted.
+// +kubebuilder:validation:Enum=Delete;Retain;Snapshot
+type PVCDeletionPolicy string
+
+const (
+	// The Persistent Volume Claim is deleted with the Cryostat.
+	PVCDeletionPolicyDelete PVCDeletionPolicy = "Delete"
+	// The Persistent Volume Claim is kept after the Cryostat is deleted.
+	PVCDeletionPolicyRetain PVCDeletionPolicy = "Retain"
+	// A VolumeSnapshot of the Persistent Volume Claim is taken before it is deleted.
+	PVCDeletionPolicySnapshot PVCDeletionPolicy = "Snapshot"
+)
+
 // LegacyStorageConfiguration provides customization to the storage created by the
 // operator to contain persisted data. If no configurations are specified, a
 // PVC will be created by default.
@@ -314,6 +888,19 @@ type SchedulingConfiguration struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Constraints on how Cryostat pods are spread across topology domains, such as zones or nodes.
+	// See: https://kubernetes.io/docs/concepts/scheduling-eviction/topology-spread-constraints/
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// Name of the PriorityClass for Cryostat pods. See: https://kubernetes.io/docs/concepts/scheduling-eviction/pod-priority-preemption/
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// Name of the RuntimeClass used to run Cryostat pods. See: https://kubernetes.io/docs/concepts/containers/runtime-class/
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	RuntimeClassName *string `json:"runtimeClassName,omitempty"`
 }
 
 // Affinity groups different kinds of affinity configurations for Cryostat pods
@@ -361,6 +948,20 @@ type ReportsServiceConfig struct {
 	ServiceConfig `json:",inline"`
 }
 
+// GrafanaServiceConfig provides customization for the service handling
+// traffic for Grafana and the JFR datasource.
+type GrafanaServiceConfig struct {
+	// HTTP port number for the Grafana dashboard.
+	// Defaults to 3000.
+	// +optional
+	HTTPPort *int32 `json:"httpPort,omitempty"`
+	// HTTP port number for the JFR datasource.
+	// Defaults to 8989.
+	// +optional
+	DatasourcePort *int32 `json:"datasourcePort,omitempty"`
+	ServiceConfig  `json:",inline"`
+}
+
 // DatabaseServiceConfig provides customization for the service handling
 // traffic for the cryostat application's database.
 type DatabaseServiceConfig struct {
@@ -413,6 +1014,10 @@ type ServiceConfigList struct {
 	// Specification for the service responsible for the storage to be created by the operator.
 	// +optional
 	StorageConfig *StorageServiceConfig `json:"storageConfig,omitempty"`
+	// Specification for the service responsible for Grafana and the JFR datasource,
+	// when these run in a separate deployment.
+	// +optional
+	GrafanaConfig *GrafanaServiceConfig `json:"grafanaConfig,omitempty"`
 	// Specification for the service responsible for agents to communicate with Cryostat.
 	// +optional
 	AgentGatewayConfig *AgentGatewayServiceConfig `json:"agentGatewayConfig,omitempty"`
@@ -437,6 +1042,9 @@ type NetworkPoliciesList struct {
 	// NetworkPolicy configuration for the storage service.
 	// +optional
 	StorageConfig *NetworkPolicyConfig `json:"storageConfig,omitempty"`
+	// NetworkPolicy configuration for the Grafana service, when Grafana runs in a separate deployment.
+	// +optional
+	GrafanaConfig *NetworkPolicyConfig `json:"grafanaConfig,omitempty"`
 }
 
 type NetworkPolicyConfig struct {
@@ -450,6 +1058,8 @@ type NetworkPolicyConfig struct {
 // service, so that it can be reached from outside the cluster.
 // On OpenShift, a Route is created by default. On Kubernetes, an Ingress will
 // be created if the IngressSpec is defined within this NetworkConfiguration.
+// If the Gateway API is installed and HTTPRoute is defined, an HTTPRoute
+// is created instead of a Route or Ingress.
 type NetworkConfiguration struct {
 	// Externally routable host to be used to reach this
 	// Cryostat service. Used to define a Route's host on
@@ -463,7 +1073,46 @@ type NetworkConfiguration struct {
 	// (if a single external IP is being used) to differentiate between ingresses/services.
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
-	IngressSpec      *netv1.IngressSpec `json:"ingressSpec,omitempty"`
+	IngressSpec *netv1.IngressSpec `json:"ingressSpec,omitempty"`
+	// Configuration for a Gateway API HTTPRoute object.
+	// Requires the Gateway API CRDs to be installed in the cluster.
+	// When specified, this takes precedence over the Route and Ingress.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="HTTPRoute"
+	HTTPRoute        *HTTPRouteConfiguration `json:"httpRoute,omitempty"`
+	ResourceMetadata `json:",inline"`
+}
+
+// HTTPRouteConfiguration provides customization for exposing a Cryostat service
+// using a Gateway API HTTPRoute attached to an existing Gateway.
+type HTTPRouteConfiguration struct {
+	// Reference to an existing Gateway that the HTTPRoute will attach to.
+	// If the namespace is omitted, the Gateway is expected to be in the
+	// same namespace as Cryostat.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	ParentRef gatewayv1.ParentReference `json:"parentRef"`
+	// Hostnames that should match against the HTTP Host header to select
+	// the HTTPRoute. The first hostname is used to compute the application URL.
+	// If omitted, the hostname of the Gateway listener is used.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Hostnames []gatewayv1.Hostname `json:"hostnames,omitempty"`
+	// Configuration for a BackendTLSPolicy, which instructs the Gateway
+	// to re-encrypt traffic to Cryostat using the Cryostat CA.
+	// Unless disabled, the BackendTLSPolicy is created when TLS is enabled using
+	// cert-manager and the BackendTLSPolicy CRD is installed in the cluster.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="BackendTLSPolicy"
+	BackendTLSPolicy *BackendTLSPolicyConfiguration `json:"backendTLSPolicy,omitempty"`
+}
+
+// BackendTLSPolicyConfiguration provides customization for the BackendTLSPolicy
+// created for an HTTPRoute.
+type BackendTLSPolicyConfiguration struct {
+	// Disable the creation of a BackendTLSPolicy.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Disable BackendTLSPolicy",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Disabled         *bool `json:"disabled,omitempty"`
 	ResourceMetadata `json:",inline"`
 }
 
@@ -476,6 +1125,67 @@ type NetworkConfigurationList struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	CoreConfig *NetworkConfiguration `json:"coreConfig,omitempty"`
+	// Specifications for how to expose the Cryostat agent gateway service,
+	// which allows Cryostat agents running outside of the cluster
+	// to communicate with Cryostat.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	AgentGatewayConfig *AgentGatewayNetworkConfiguration `json:"agentGatewayConfig,omitempty"`
+}
+
+// AgentGatewayNetworkConfiguration provides customization for how to expose the
+// Cryostat agent gateway outside of the cluster. Agents authenticate to the agent
+// gateway using mutual TLS, so TLS connections must be passed through to the gateway.
+// On OpenShift, a Route with passthrough termination is created. On Kubernetes, an Ingress
+// will be created if the IngressSpec is defined, and a Service of type LoadBalancer will
+// be created if the LoadBalancerConfig is defined.
+type AgentGatewayNetworkConfiguration struct {
+	// Externally routable host to be used to reach the agent gateway.
+	// Used to define a Route's host on OpenShift when it is first created.
+	// This host is added to the agent gateway's TLS certificate.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	ExternalHost *string `json:"externalHost,omitempty"`
+	// Configuration for an Ingress object. The Ingress controller must be
+	// configured to pass TLS connections through to the agent gateway.
+	// Hosts in the Ingress rules are added to the agent gateway's TLS certificate.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	IngressSpec *netv1.IngressSpec `json:"ingressSpec,omitempty"`
+	// Configuration for a Service of type LoadBalancer exposing the agent gateway.
+	// Addresses assigned to the load balancer are added to the agent gateway's TLS certificate.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	LoadBalancerConfig *AgentGatewayLoadBalancerConfig `json:"loadBalancerConfig,omitempty"`
+	// Client certificates to issue for Cryostat agents running outside of the cluster.
+	// Each certificate is signed by the Cryostat CA and stored in a Secret named
+	// "<cryostat-name>-agent-client-<client-name>", along with the Cryostat CA certificate.
+	// Requires TLS to be enabled using cert-manager.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	ClientCertificates []AgentClientCertificate `json:"clientCertificates,omitempty"`
+	ResourceMetadata   `json:",inline"`
+}
+
+// AgentGatewayLoadBalancerConfig provides customization for the Service of type
+// LoadBalancer exposing the agent gateway.
+type AgentGatewayLoadBalancerConfig struct {
+	// Port number for the load balancer.
+	// Defaults to 8282.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	Port             *int32 `json:"port,omitempty"`
+	ResourceMetadata `json:",inline"`
+}
+
+// AgentClientCertificate specifies a client certificate to issue for a
+// Cryostat agent running outside of the cluster.
+type AgentClientCertificate struct {
+	// Name of the agent client. Used as the common name of the client certificate.
+	// +kubebuilder:validation:Pattern=^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
+	// +kubebuilder:validation:MaxLength=63
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Name string `json:"name"`
 }
 
 // PersistentVolumeClaimConfig holds all customization options to
@@ -538,7 +1248,9 @@ type TargetConnectionCacheOptions struct {
 // application and its related components.
 // A Cryostat instance must be created to instruct the operator
 // to deploy the Cryostat application.
-// +operator-sdk:csv:customresourcedefinitions:resources={{Deployment,v1},{Ingress,v1},{PersistentVolumeClaim,v1},{Secret,v1},{Service,v1},{Route,v1},{ConsoleLink,v1}}
+// +operator-sdk:csv:customresourcedefinitions:resources={{Deployment,v1},{Ingress,v1},{PersistentVolumeClaim,v1},{Secret,v1},{Service,v1},{Route,v1},{ConsoleLink,v1},{HTTPRoute,v1},{BackendTLSPolicy,v1alpha3}}
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.status.version`
 // +kubebuilder:printcolumn:name="Application URL",type=string,JSONPath=`.status.applicationUrl`
 // +kubebuilder:printcolumn:name="Target Namespaces",type=string,JSONPath=`.status.targetNamespaces`
 // +kubebuilder:printcolumn:name="Storage Secret",type=string,JSONPath=`.status.storageSecret`
@@ -686,6 +1398,78 @@ type SecurityOptions struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	AgentProxySecurityContext *corev1.SecurityContext `json:"agentProxySecurityContext,omitempty"`
+	// TLS protocol versions and cipher suites to use for all TLS endpoints served by Cryostat
+	// components, as well as their connections to one another. Requires TLS to be enabled
+	// using cert-manager. Defaults to the Intermediate profile.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	TLSProfile *TLSProfile `json:"tlsProfile,omitempty"`
+}
+
+// TLSProfileType is the name of a predefined TLS profile, or Custom.
+// +kubebuilder:validation:Enum=Old;Intermediate;Modern;Custom
+type TLSProfileType string
+
+const (
+	// TLS profile based on Mozilla's "Old" recommended configuration,
+	// compatible with legacy clients.
+	TLSProfileOld TLSProfileType = "Old"
+	// TLS profile based on Mozilla's "Intermediate" recommended configuration.
+	TLSProfileIntermediate TLSProfileType = "Intermediate"
+	// TLS profile based on Mozilla's "Modern" recommended configuration,
+	// which only permits TLS 1.3.
+	TLSProfileModern TLSProfileType = "Modern"
+	// TLS profile using the ciphers and minimum TLS version specified by the user.
+	TLSProfileCustom TLSProfileType = "Custom"
+)
+
+// TLSProtocolVersion is a version of the TLS protocol.
+// +kubebuilder:validation:Enum=VersionTLS10;VersionTLS11;VersionTLS12;VersionTLS13
+type TLSProtocolVersion string
+
+const (
+	// Version 1.0 of the TLS protocol.
+	VersionTLS10 TLSProtocolVersion = "VersionTLS10"
+	// Version 1.1 of the TLS protocol.
+	VersionTLS11 TLSProtocolVersion = "VersionTLS11"
+	// Version 1.2 of the TLS protocol.
+	VersionTLS12 TLSProtocolVersion = "VersionTLS12"
+	// Version 1.3 of the TLS protocol.
+	VersionTLS13 TLSProtocolVersion = "VersionTLS13"
+)
+
+// TLSProfile selects the TLS protocol versions and cipher suites used by Cryostat components.
+// The predefined profiles match those of OpenShift's TLSSecurityProfile.
+type TLSProfile struct {
+	// Type of TLS profile to use. Old, Intermediate and Modern are based on Mozilla's
+	// recommended server configurations. Custom uses the ciphers and minimum TLS version
+	// specified in the custom property. If not specified, defaults to Intermediate,
+	// unless inheriting the profile from the cluster's API server.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Type *TLSProfileType `json:"type,omitempty"`
+	// Ciphers and minimum TLS version to use with the Custom profile type.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Custom *CustomTLSProfile `json:"custom,omitempty"`
+	// Use the TLS security profile configured for the cluster's API server,
+	// when no profile type is specified. Only supported on OpenShift.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	InheritFromAPIServer *bool `json:"inheritFromAPIServer,omitempty"`
+}
+
+// CustomTLSProfile specifies the individual parameters of a custom TLS profile.
+type CustomTLSProfile struct {
+	// Cipher suites to negotiate during the TLS handshake, using their OpenSSL names
+	// (e.g. ECDHE-RSA-AES128-GCM-SHA256). TLS 1.3 cipher suites use their IANA names
+	// (e.g. TLS_AES_128_GCM_SHA256). Components may ignore cipher suites they do not support.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Ciphers []string `json:"ciphers,omitempty"`
+	// Minimum version of the TLS protocol to accept.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	MinTLSVersion TLSProtocolVersion `json:"minTLSVersion"`
 }
 
 // ReportsSecurityOptions contains Security Context customizations for the
@@ -701,6 +1485,105 @@ type ReportsSecurityOptions struct {
 	ReportsSecurityContext *corev1.SecurityContext `json:"reportsSecurityContext,omitempty"`
 }
 
+// CoreOptions provides additional configuration for the Cryostat application container.
+type CoreOptions struct {
+	// Additional environment variables for the Cryostat application container.
+	// Variables that are already set by the operator cannot be overridden, and are ignored.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Env []corev1.EnvVar `json:"env,omitempty"`
+	// Secrets and Config Maps in the installation namespace whose entries are added to the Cryostat application
+	// container as environment variables. Variables set by the operator take precedence over these entries.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+	// Additional options for the Cryostat application's JVM, such as "-Xmx1g".
+	// These are appended to the JVM options chosen by the container image.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	JavaOptions string `json:"javaOptions,omitempty"`
+}
+
+// GrafanaOptions provides configuration for the Grafana dashboard and JFR datasource.
+type GrafanaOptions struct {
+	// Remove the Grafana dashboard and JFR datasource containers. Cryostat's dashboard
+	// features are unavailable while these are disabled.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Disabled bool `json:"disabled,omitempty"`
+	// Names of Config Maps in the installation namespace containing Grafana dashboards.
+	// Each entry in a Config Map should be a dashboard in Grafana's JSON model.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:ConfigMap"}
+	DashboardConfigMaps []string `json:"dashboardConfigMaps,omitempty"`
+	// Selects additional Config Maps in the installation namespace containing Grafana dashboards.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	DashboardSelector *metav1.LabelSelector `json:"dashboardSelector,omitempty"`
+	// Additional datasources to provision in Grafana, alongside the JFR datasource.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Datasources []GrafanaDatasource `json:"datasources,omitempty"`
+	// Grafana plugins to install when Grafana starts, such as "grafana-clock-panel" or "grafana-clock-panel 1.0.1".
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Plugins []string `json:"plugins,omitempty"`
+	// Grafana configuration settings, as environment variables beginning with "GF_".
+	// Variables without this prefix, or already set by the operator, are ignored.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Env []corev1.EnvVar `json:"env,omitempty"`
+	// Run Grafana and the JFR datasource in their own deployment, instead of alongside Cryostat in its pod.
+	// Their resource requirements are still configured in spec.resources.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	SeparateDeployment bool `json:"separateDeployment,omitempty"`
+	// Options to configure scheduling for the Grafana deployment, when Grafana runs in a separate deployment.
+	// If unset, the scheduling options for the Cryostat deployment are used.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	SchedulingOptions *SchedulingConfiguration `json:"schedulingOptions,omitempty"`
+}
+
+// GrafanaDatasource is a datasource to provision in Grafana.
+type GrafanaDatasource struct {
+	// Name of the datasource, as displayed in Grafana.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Name string `json:"name"`
+	// Type of the datasource, such as "prometheus".
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Type string `json:"type"`
+	// URL of the datasource.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	URL string `json:"url,omitempty"`
+	// Whether Grafana's server connects to the datasource ("proxy"), or the browser does ("direct").
+	// Defaults to "proxy".
+	// +optional
+	// +kubebuilder:validation:Enum=proxy;direct
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Access string `json:"access,omitempty"`
+	// Make this the default datasource in Grafana.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	IsDefault bool `json:"isDefault,omitempty"`
+	// Datasource-specific settings. Values may refer to environment variables set in
+	// the Grafana options using Grafana's $VARIABLE syntax.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	JSONData map[string]string `json:"jsonData,omitempty"`
+	// Datasource-specific settings that Grafana stores encrypted, such as passwords or tokens.
+	// To avoid storing credentials in the Cryostat CR, use secureJsonDataFrom instead.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	SecureJSONData map[string]string `json:"secureJsonData,omitempty"`
+	// Datasource-specific settings that Grafana stores encrypted, whose values are read from keys of
+	// Secrets in the installation namespace. These take precedence over the same settings in secureJsonData.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	SecureJSONDataFrom map[string]corev1.SecretKeySelector `json:"secureJsonDataFrom,omitempty"`
+}
+
 // TargetDiscoveryOptions provides configuration options to the Cryostat application's target discovery mechanisms.
 type TargetDiscoveryOptions struct {
 	// When true, the Cryostat application will disable the built-in discovery mechanisms. Defaults to false
@@ -735,6 +1618,20 @@ type DatabaseOptions struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret"}
 	SecretName *string `json:"secretName,omitempty"`
+	// Options to configure scheduling for the database deployment.
+	// If unset, the scheduling options for the Cryostat deployment are used.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	SchedulingOptions *SchedulingConfiguration `json:"schedulingOptions,omitempty"`
+}
+
+// ObjectStorageOptions provides configuration options to the Cryostat application's object storage.
+type ObjectStorageOptions struct {
+	// Options to configure scheduling for the object storage deployment.
+	// If unset, the scheduling options for the Cryostat deployment are used.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	SchedulingOptions *SchedulingConfiguration `json:"schedulingOptions,omitempty"`
 }
 
 // AgentOptions provides customization for how the operator configures Cryostat Agents.
@@ -0,0 +1,151 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta2
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CryostatBackupSpec defines the desired state of CryostatBackup.
+type CryostatBackupSpec struct {
+	// Name of the Cryostat instance in this namespace to back up.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=1
+	CryostatName string `json:"cryostatName"`
+	// Where to store the backup archive. Exactly one of pvc or s3 must be specified.
+	// The backup is taken once, so changes to this field after the backup has started have no effect.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=2
+	Destination BackupLocation `json:"destination"`
+}
+
+// BackupLocation is where backup archives are stored.
+type BackupLocation struct {
+	// Store backup archives in an existing Persistent Volume Claim.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	PVC *BackupPVCLocation `json:"pvc,omitempty"`
+	// Store backup archives in a bucket of an S3-compatible object storage service.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	S3 *BackupS3Location `json:"s3,omitempty"`
+}
+
+// BackupPVCLocation is a directory within a Persistent Volume Claim containing backup archives.
+type BackupPVCLocation struct {
+	// Name of a Persistent Volume Claim in this namespace.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:PersistentVolumeClaim"}
+	ClaimName string `json:"claimName"`
+	// Directory within the volume containing backup archives. Defaults to the root of the volume.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Path string `json:"path,omitempty"`
+}
+
+// BackupS3Location is a bucket of an S3-compatible object storage service containing backup archives.
+type BackupS3Location struct {
+	// URL of the object storage service, such as "https://s3.us-east-1.amazonaws.com".
+	// Buckets are addressed using path-style URLs.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Endpoint string `json:"endpoint"`
+	// Name of the bucket.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Bucket string `json:"bucket"`
+	// Prefix of the keys of backup archives within the bucket, such as "cryostat/".
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Prefix string `json:"prefix,omitempty"`
+	// Region of the bucket. Defaults to "us-east-1".
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Region string `json:"region,omitempty"`
+	// Name of a Secret in this namespace containing the credentials used to access the bucket,
+	// using the "AWS_ACCESS_KEY_ID" and "AWS_SECRET_ACCESS_KEY" keys.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret"}
+	SecretName string `json:"secretName"`
+}
+
+// CryostatBackupStatus defines the observed state of CryostatBackup.
+type CryostatBackupStatus struct {
+	// Conditions of the CryostatBackup.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Name of the Job taking the backup.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes:Job"}
+	JobName string `json:"jobName,omitempty"`
+	// Time at which the backup started.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// Time at which the backup completed.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Name of the backup archive within the destination.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ArchiveName string `json:"archiveName,omitempty"`
+	// Size of the backup archive.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Size *resource.Quantity `json:"size,omitempty"`
+	// Checksum of the backup archive, such as "sha256:<hex digest>".
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// CryostatBackupConditionType refers to a Condition type that may be used in a CryostatBackup's
+// status.conditions
+type CryostatBackupConditionType string
+
+const (
+	// Whether the backup has finished successfully.
+	ConditionTypeBackupComplete CryostatBackupConditionType = "Complete"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=cryostatbackups,scope=Namespaced
+
+// CryostatBackup takes a backup of a Cryostat instance's database and object storage,
+// and stores it as a single archive in a Persistent Volume Claim or an S3-compatible bucket.
+// The backup can be restored using a CryostatRestore, including to a Cryostat instance in another cluster.
+// +operator-sdk:csv:customresourcedefinitions:resources={{Job,v1}}
+// +kubebuilder:printcolumn:name="Cryostat",type=string,JSONPath=`.spec.cryostatName`
+// +kubebuilder:printcolumn:name="Complete",type=string,JSONPath=`.status.conditions[?(@.type=="Complete")].status`
+// +kubebuilder:printcolumn:name="Size",type=string,JSONPath=`.status.size`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type CryostatBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CryostatBackupSpec   `json:"spec,omitempty"`
+	Status CryostatBackupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CryostatBackupList contains a list of CryostatBackup
+type CryostatBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CryostatBackup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CryostatBackup{}, &CryostatBackupList{})
+}
@@ -0,0 +1,111 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CryostatRestoreSpec defines the desired state of CryostatRestore.
+type CryostatRestoreSpec struct {
+	// Name of the Cryostat instance in this namespace to restore the backup into.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=1
+	CryostatName string `json:"cryostatName"`
+	// Name of a completed CryostatBackup in this namespace to restore.
+	// Either backupName or source must be specified.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=2
+	BackupName string `json:"backupName,omitempty"`
+	// Location of a backup archive to restore, such as one taken by a Cryostat
+	// instance in another cluster. Ignored if backupName is specified.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Source *BackupSource `json:"source,omitempty"`
+}
+
+// BackupSource is the location of an existing backup archive.
+type BackupSource struct {
+	BackupLocation `json:",inline"`
+	// Name of the backup archive within the location, as reported by the
+	// status.archiveName property of the CryostatBackup.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	ArchiveName string `json:"archiveName"`
+	// Expected checksum of the backup archive, as reported by the status.checksum
+	// property of the CryostatBackup. If specified, the archive is verified before it is restored.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// CryostatRestoreStatus defines the observed state of CryostatRestore.
+type CryostatRestoreStatus struct {
+	// Conditions of the CryostatRestore.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Name of the Job restoring the backup.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes:Job"}
+	JobName string `json:"jobName,omitempty"`
+	// Time at which the restore started.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// Time at which the restore completed.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// CryostatRestoreConditionType refers to a Condition type that may be used in a CryostatRestore's
+// status.conditions
+type CryostatRestoreConditionType string
+
+const (
+	// Whether the restore has finished successfully.
+	ConditionTypeRestoreComplete CryostatRestoreConditionType = "RestoreComplete"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=cryostatrestores,scope=Namespaced
+
+// CryostatRestore restores a backup taken by a CryostatBackup into a Cryostat instance,
+// replacing the contents of its database and adding the backed up files to its object storage.
+// +operator-sdk:csv:customresourcedefinitions:resources={{Job,v1}}
+// +kubebuilder:printcolumn:name="Cryostat",type=string,JSONPath=`.spec.cryostatName`
+// +kubebuilder:printcolumn:name="Backup",type=string,JSONPath=`.spec.backupName`
+// +kubebuilder:printcolumn:name="Complete",type=string,JSONPath=`.status.conditions[?(@.type=="RestoreComplete")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type CryostatRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CryostatRestoreSpec   `json:"spec,omitempty"`
+	Status CryostatRestoreStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CryostatRestoreList contains a list of CryostatRestore
+type CryostatRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CryostatRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CryostatRestore{}, &CryostatRestoreList{})
+}
@@ -0,0 +1,157 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta2
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RecordingSpec defines the desired state of Recording.
+type RecordingSpec struct {
+	// Reference to the Cryostat instance that should create this recording. This namespace
+	// must be one of that Cryostat instance's target namespaces.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=1
+	Cryostat CryostatReference `json:"cryostat"`
+	// Selects the targets within this namespace to record.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=2
+	Target RecordingTarget `json:"target"`
+	// Event template used to configure the recording. Defaults to the target's "Continuous" template.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	EventTemplate *EventTemplateReference `json:"eventTemplate,omitempty"`
+	// How long the recording should run before stopping. If unset, the recording runs continuously.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// Maximum age of the data retained by the recording. If unset, the target's default is used.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Maximum Age"
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+	// Maximum size of the data retained by the recording. If unset, the target's default is used.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Maximum Size"
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+	// Copy the recording to Cryostat's archives once it stops, including when this Recording is deleted.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	ArchiveOnStop bool `json:"archiveOnStop,omitempty"`
+}
+
+// CryostatReference refers to a Cryostat instance.
+type CryostatReference struct {
+	// Name of the Cryostat instance.
+	Name string `json:"name"`
+	// Namespace of the Cryostat instance. Defaults to the namespace of the referring resource.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// RecordingTarget selects the targets that a recording applies to.
+// Exactly one of selector and connectUrl should be specified.
+type RecordingTarget struct {
+	// Selects the pods to record by their labels. All targets discovered by Cryostat
+	// within these pods are recorded, once per JVM.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Connection URL of the single target to record, as shown by Cryostat.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Connection URL"
+	ConnectURL string `json:"connectUrl,omitempty"`
+}
+
+// EventTemplateReference refers to a Flight Recorder event template known to Cryostat.
+type EventTemplateReference struct {
+	// Name of the event template.
+	Name string `json:"name"`
+	// Type of the event template. "TARGET" templates are provided by the target JVM,
+	// while "CUSTOM" templates have been uploaded to Cryostat. Defaults to "TARGET".
+	// +optional
+	// +kubebuilder:validation:Enum=TARGET;CUSTOM
+	Type string `json:"type,omitempty"`
+}
+
+// RecordingStatus defines the observed state of Recording.
+type RecordingStatus struct {
+	// Conditions of the Recording.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// The recording within each selected target.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Targets []RecordingTargetStatus `json:"targets,omitempty"`
+}
+
+// RecordingTargetStatus describes a recording within a single target.
+type RecordingTargetStatus struct {
+	// Connection URL of the target.
+	ConnectURL string `json:"connectUrl"`
+	// ID of the target in Cryostat.
+	TargetID int64 `json:"targetId"`
+	// Name of the pod containing the target, if known.
+	// +optional
+	PodName string `json:"podName,omitempty"`
+	// ID of the recording in Cryostat.
+	// +optional
+	RecordingID *int64 `json:"recordingId,omitempty"`
+	// State of the recording, such as RUNNING or STOPPED.
+	// +optional
+	State string `json:"state,omitempty"`
+	// Names of archived copies of the recording in Cryostat.
+	// +optional
+	Archives []string `json:"archives,omitempty"`
+}
+
+// RecordingConditionType refers to a Condition type that may be used in a Recording's status.conditions
+type RecordingConditionType string
+
+const (
+	// Whether the recordings in Cryostat match the Recording's specification.
+	ConditionTypeRecordingSynchronized RecordingConditionType = "Synchronized"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=recordings,scope=Namespaced
+
+// Recording manages a JDK Flight Recording within one or more targets using Cryostat.
+// The recording is started when this object is created, and removed from its targets
+// when this object is deleted.
+// +operator-sdk:csv:customresourcedefinitions:resources={}
+// +kubebuilder:printcolumn:name="Cryostat",type=string,JSONPath=`.spec.cryostat.name`
+// +kubebuilder:printcolumn:name="Synchronized",type=string,JSONPath=`.status.conditions[?(@.type=="Synchronized")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type Recording struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RecordingSpec   `json:"spec,omitempty"`
+	Status RecordingStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RecordingList contains a list of Recording
+type RecordingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Recording `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Recording{}, &RecordingList{})
+}
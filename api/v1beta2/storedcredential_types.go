@@ -0,0 +1,99 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StoredCredentialSpec defines the desired state of StoredCredential.
+type StoredCredentialSpec struct {
+	// Reference to the Cryostat instance that should store this credential. This namespace
+	// must be one of that Cryostat instance's target namespaces.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=1
+	Cryostat CryostatReference `json:"cryostat"`
+	// Name of a Secret in this namespace containing the credential, using the "username"
+	// and "password" keys. Secrets of type "kubernetes.io/basic-auth" use these keys.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=2,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret"}
+	SecretName string `json:"secretName"`
+	// Expression used to select the targets that the credential applies to, such as
+	// "target.labels.app == 'my-app'". The credential only ever applies to targets within
+	// this namespace. If unset, the credential applies to all targets within this namespace.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	MatchExpression string `json:"matchExpression,omitempty"`
+}
+
+// StoredCredentialStatus defines the observed state of StoredCredential.
+type StoredCredentialStatus struct {
+	// Conditions of the StoredCredential.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ID of the stored credential within Cryostat.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	CredentialID *int64 `json:"credentialId,omitempty"`
+	// Match expression of the stored credential within Cryostat, which is limited
+	// to targets within this namespace.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	MatchExpression string `json:"matchExpression,omitempty"`
+	// Resource version of the Secret when the credential was stored. The credential
+	// is replaced when the Secret changes.
+	// +optional
+	SecretResourceVersion string `json:"secretResourceVersion,omitempty"`
+}
+
+// StoredCredentialConditionType refers to a Condition type that may be used in a StoredCredential's status.conditions
+type StoredCredentialConditionType string
+
+const (
+	// Whether the stored credential in Cryostat matches the StoredCredential's specification and Secret.
+	ConditionTypeStoredCredentialSynchronized StoredCredentialConditionType = "Synchronized"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=storedcredentials,scope=Namespaced
+
+// StoredCredential stores a JMX credential from a Secret in Cryostat, which Cryostat
+// uses to connect to matching targets. The credential is replaced when the Secret changes,
+// and removed from Cryostat when either this object or the Secret is deleted.
+// +operator-sdk:csv:customresourcedefinitions:resources={}
+// +kubebuilder:printcolumn:name="Cryostat",type=string,JSONPath=`.spec.cryostat.name`
+// +kubebuilder:printcolumn:name="Secret",type=string,JSONPath=`.spec.secretName`
+// +kubebuilder:printcolumn:name="Synchronized",type=string,JSONPath=`.status.conditions[?(@.type=="Synchronized")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type StoredCredential struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   StoredCredentialSpec   `json:"spec,omitempty"`
+	Status StoredCredentialStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// StoredCredentialList contains a list of StoredCredential
+type StoredCredentialList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StoredCredential `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&StoredCredential{}, &StoredCredentialList{})
+}
@@ -24,6 +24,7 @@ import (
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	apisv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -72,6 +73,78 @@ func (in *AgentCallbackServiceConfig) DeepCopy() *AgentCallbackServiceConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentClientCertificate) DeepCopyInto(out *AgentClientCertificate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentClientCertificate.
+func (in *AgentClientCertificate) DeepCopy() *AgentClientCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentClientCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentGatewayLoadBalancerConfig) DeepCopyInto(out *AgentGatewayLoadBalancerConfig) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	in.ResourceMetadata.DeepCopyInto(&out.ResourceMetadata)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentGatewayLoadBalancerConfig.
+func (in *AgentGatewayLoadBalancerConfig) DeepCopy() *AgentGatewayLoadBalancerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentGatewayLoadBalancerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentGatewayNetworkConfiguration) DeepCopyInto(out *AgentGatewayNetworkConfiguration) {
+	*out = *in
+	if in.ExternalHost != nil {
+		in, out := &in.ExternalHost, &out.ExternalHost
+		*out = new(string)
+		**out = **in
+	}
+	if in.IngressSpec != nil {
+		in, out := &in.IngressSpec, &out.IngressSpec
+		*out = new(networkingv1.IngressSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LoadBalancerConfig != nil {
+		in, out := &in.LoadBalancerConfig, &out.LoadBalancerConfig
+		*out = new(AgentGatewayLoadBalancerConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientCertificates != nil {
+		in, out := &in.ClientCertificates, &out.ClientCertificates
+		*out = make([]AgentClientCertificate, len(*in))
+		copy(*out, *in)
+	}
+	in.ResourceMetadata.DeepCopyInto(&out.ResourceMetadata)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentGatewayNetworkConfiguration.
+func (in *AgentGatewayNetworkConfiguration) DeepCopy() *AgentGatewayNetworkConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentGatewayNetworkConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AgentGatewayServiceConfig) DeepCopyInto(out *AgentGatewayServiceConfig) {
 	*out = *in
@@ -150,99 +223,79 @@ func (in *AuthorizationProperties) DeepCopy() *AuthorizationProperties {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CertificateSecret) DeepCopyInto(out *CertificateSecret) {
+func (in *AutomatedRule) DeepCopyInto(out *AutomatedRule) {
 	*out = *in
-	if in.CertificateKey != nil {
-		in, out := &in.CertificateKey, &out.CertificateKey
-		*out = new(string)
-		**out = **in
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateSecret.
-func (in *CertificateSecret) DeepCopy() *CertificateSecret {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutomatedRule.
+func (in *AutomatedRule) DeepCopy() *AutomatedRule {
 	if in == nil {
 		return nil
 	}
-	out := new(CertificateSecret)
+	out := new(AutomatedRule)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CoreServiceConfig) DeepCopyInto(out *CoreServiceConfig) {
-	*out = *in
-	if in.HTTPPort != nil {
-		in, out := &in.HTTPPort, &out.HTTPPort
-		*out = new(int32)
-		**out = **in
-	}
-	in.ServiceConfig.DeepCopyInto(&out.ServiceConfig)
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoreServiceConfig.
-func (in *CoreServiceConfig) DeepCopy() *CoreServiceConfig {
-	if in == nil {
-		return nil
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AutomatedRule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	out := new(CoreServiceConfig)
-	in.DeepCopyInto(out)
-	return out
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Cryostat) DeepCopyInto(out *Cryostat) {
+func (in *AutomatedRuleDrift) DeepCopyInto(out *AutomatedRuleDrift) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	in.DetectedTime.DeepCopyInto(&out.DetectedTime)
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cryostat.
-func (in *Cryostat) DeepCopy() *Cryostat {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutomatedRuleDrift.
+func (in *AutomatedRuleDrift) DeepCopy() *AutomatedRuleDrift {
 	if in == nil {
 		return nil
 	}
-	out := new(Cryostat)
+	out := new(AutomatedRuleDrift)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Cryostat) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CryostatList) DeepCopyInto(out *CryostatList) {
+func (in *AutomatedRuleList) DeepCopyInto(out *AutomatedRuleList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Cryostat, len(*in))
+		*out = make([]AutomatedRule, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatList.
-func (in *CryostatList) DeepCopy() *CryostatList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutomatedRuleList.
+func (in *AutomatedRuleList) DeepCopy() *AutomatedRuleList {
 	if in == nil {
 		return nil
 	}
-	out := new(CryostatList)
+	out := new(AutomatedRuleList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CryostatList) DeepCopyObject() runtime.Object {
+func (in *AutomatedRuleList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -250,120 +303,48 @@ func (in *CryostatList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CryostatSpec) DeepCopyInto(out *CryostatSpec) {
+func (in *AutomatedRuleSpec) DeepCopyInto(out *AutomatedRuleSpec) {
 	*out = *in
-	if in.TargetNamespaces != nil {
-		in, out := &in.TargetNamespaces, &out.TargetNamespaces
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.TrustedCertSecrets != nil {
-		in, out := &in.TrustedCertSecrets, &out.TrustedCertSecrets
-		*out = make([]CertificateSecret, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.EventTemplates != nil {
-		in, out := &in.EventTemplates, &out.EventTemplates
-		*out = make([]TemplateConfigMap, len(*in))
-		copy(*out, *in)
-	}
-	if in.EnableCertManager != nil {
-		in, out := &in.EnableCertManager, &out.EnableCertManager
-		*out = new(bool)
+	if in.ArchivalPeriod != nil {
+		in, out := &in.ArchivalPeriod, &out.ArchivalPeriod
+		*out = new(v1.Duration)
 		**out = **in
 	}
-	if in.StorageOptions != nil {
-		in, out := &in.StorageOptions, &out.StorageOptions
-		*out = new(StorageConfigurations)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ServiceOptions != nil {
-		in, out := &in.ServiceOptions, &out.ServiceOptions
-		*out = new(ServiceConfigList)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.NetworkPolicies != nil {
-		in, out := &in.NetworkPolicies, &out.NetworkPolicies
-		*out = new(NetworkPoliciesList)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.NetworkOptions != nil {
-		in, out := &in.NetworkOptions, &out.NetworkOptions
-		*out = new(NetworkConfigurationList)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ReportOptions != nil {
-		in, out := &in.ReportOptions, &out.ReportOptions
-		*out = new(ReportConfiguration)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.TargetConnectionCacheOptions != nil {
-		in, out := &in.TargetConnectionCacheOptions, &out.TargetConnectionCacheOptions
-		*out = new(TargetConnectionCacheOptions)
+	if in.InitialDelay != nil {
+		in, out := &in.InitialDelay, &out.InitialDelay
+		*out = new(v1.Duration)
 		**out = **in
 	}
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(ResourceConfigList)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.AuthorizationOptions != nil {
-		in, out := &in.AuthorizationOptions, &out.AuthorizationOptions
-		*out = new(AuthorizationOptions)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.SecurityOptions != nil {
-		in, out := &in.SecurityOptions, &out.SecurityOptions
-		*out = new(SecurityOptions)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.SchedulingOptions != nil {
-		in, out := &in.SchedulingOptions, &out.SchedulingOptions
-		*out = new(SchedulingConfiguration)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.TargetDiscoveryOptions != nil {
-		in, out := &in.TargetDiscoveryOptions, &out.TargetDiscoveryOptions
-		*out = new(TargetDiscoveryOptions)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.DatabaseOptions != nil {
-		in, out := &in.DatabaseOptions, &out.DatabaseOptions
-		*out = new(DatabaseOptions)
-		(*in).DeepCopyInto(*out)
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(v1.Duration)
+		**out = **in
 	}
-	if in.OperandMetadata != nil {
-		in, out := &in.OperandMetadata, &out.OperandMetadata
-		*out = new(OperandMetadata)
-		(*in).DeepCopyInto(*out)
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
 	}
-	if in.AgentOptions != nil {
-		in, out := &in.AgentOptions, &out.AgentOptions
-		*out = new(AgentOptions)
-		(*in).DeepCopyInto(*out)
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatSpec.
-func (in *CryostatSpec) DeepCopy() *CryostatSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutomatedRuleSpec.
+func (in *AutomatedRuleSpec) DeepCopy() *AutomatedRuleSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(CryostatSpec)
+	out := new(AutomatedRuleSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CryostatStatus) DeepCopyInto(out *CryostatStatus) {
+func (in *AutomatedRuleStatus) DeepCopyInto(out *AutomatedRuleStatus) {
 	*out = *in
-	if in.TargetNamespaces != nil {
-		in, out := &in.TargetNamespaces, &out.TargetNamespaces
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -371,267 +352,1753 @@ func (in *CryostatStatus) DeepCopyInto(out *CryostatStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RuleID != nil {
+		in, out := &in.RuleID, &out.RuleID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LastDrift != nil {
+		in, out := &in.LastDrift, &out.LastDrift
+		*out = new(AutomatedRuleDrift)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatStatus.
-func (in *CryostatStatus) DeepCopy() *CryostatStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutomatedRuleStatus.
+func (in *AutomatedRuleStatus) DeepCopy() *AutomatedRuleStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(CryostatStatus)
+	out := new(AutomatedRuleStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DatabaseOptions) DeepCopyInto(out *DatabaseOptions) {
+func (in *BackendTLSPolicyConfiguration) DeepCopyInto(out *BackendTLSPolicyConfiguration) {
 	*out = *in
-	if in.SecretName != nil {
-		in, out := &in.SecretName, &out.SecretName
-		*out = new(string)
+	if in.Disabled != nil {
+		in, out := &in.Disabled, &out.Disabled
+		*out = new(bool)
 		**out = **in
 	}
+	in.ResourceMetadata.DeepCopyInto(&out.ResourceMetadata)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseOptions.
-func (in *DatabaseOptions) DeepCopy() *DatabaseOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendTLSPolicyConfiguration.
+func (in *BackendTLSPolicyConfiguration) DeepCopy() *BackendTLSPolicyConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(DatabaseOptions)
+	out := new(BackendTLSPolicyConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DatabaseServiceConfig) DeepCopyInto(out *DatabaseServiceConfig) {
+func (in *BackupLocation) DeepCopyInto(out *BackupLocation) {
 	*out = *in
-	if in.DatabasePort != nil {
-		in, out := &in.DatabasePort, &out.DatabasePort
-		*out = new(int32)
+	if in.PVC != nil {
+		in, out := &in.PVC, &out.PVC
+		*out = new(BackupPVCLocation)
+		**out = **in
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(BackupS3Location)
 		**out = **in
 	}
-	in.ServiceConfig.DeepCopyInto(&out.ServiceConfig)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseServiceConfig.
-func (in *DatabaseServiceConfig) DeepCopy() *DatabaseServiceConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupLocation.
+func (in *BackupLocation) DeepCopy() *BackupLocation {
 	if in == nil {
 		return nil
 	}
-	out := new(DatabaseServiceConfig)
+	out := new(BackupLocation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EmptyDirConfig) DeepCopyInto(out *EmptyDirConfig) {
+func (in *BackupPVCLocation) DeepCopyInto(out *BackupPVCLocation) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmptyDirConfig.
-func (in *EmptyDirConfig) DeepCopy() *EmptyDirConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupPVCLocation.
+func (in *BackupPVCLocation) DeepCopy() *BackupPVCLocation {
 	if in == nil {
 		return nil
 	}
-	out := new(EmptyDirConfig)
+	out := new(BackupPVCLocation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LegacyStorageConfiguration) DeepCopyInto(out *LegacyStorageConfiguration) {
+func (in *BackupS3Location) DeepCopyInto(out *BackupS3Location) {
 	*out = *in
-	if in.PVC != nil {
-		in, out := &in.PVC, &out.PVC
-		*out = new(PersistentVolumeClaimConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.EmptyDir != nil {
-		in, out := &in.EmptyDir, &out.EmptyDir
-		*out = new(EmptyDirConfig)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LegacyStorageConfiguration.
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupS3Location.
+func (in *BackupS3Location) DeepCopy() *BackupS3Location {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupS3Location)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupSource) DeepCopyInto(out *BackupSource) {
+	*out = *in
+	in.BackupLocation.DeepCopyInto(&out.BackupLocation)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupSource.
+func (in *BackupSource) DeepCopy() *BackupSource {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CARotationOptions) DeepCopyInto(out *CARotationOptions) {
+	*out = *in
+	if in.OverlapPeriod != nil {
+		in, out := &in.OverlapPeriod, &out.OverlapPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CARotationOptions.
+func (in *CARotationOptions) DeepCopy() *CARotationOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(CARotationOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CARotationStatus) DeepCopyInto(out *CARotationStatus) {
+	*out = *in
+	if in.PendingRevision != nil {
+		in, out := &in.PendingRevision, &out.PendingRevision
+		*out = new(string)
+		**out = **in
+	}
+	if in.PreviousRevision != nil {
+		in, out := &in.PreviousRevision, &out.PreviousRevision
+		*out = new(string)
+		**out = **in
+	}
+	if in.StageStartTime != nil {
+		in, out := &in.StageStartTime, &out.StageStartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CARotationStatus.
+func (in *CARotationStatus) DeepCopy() *CARotationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CARotationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateSecret) DeepCopyInto(out *CertificateSecret) {
+	*out = *in
+	if in.CertificateKey != nil {
+		in, out := &in.CertificateKey, &out.CertificateKey
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateSecret.
+func (in *CertificateSecret) DeepCopy() *CertificateSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
+	*out = *in
+	if in.CopyNamespaces != nil {
+		in, out := &in.CopyNamespaces, &out.CopyNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+	if in.RenewalTime != nil {
+		in, out := &in.RenewalTime, &out.RenewalTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateStatus.
+func (in *CertificateStatus) DeepCopy() *CertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentMetadata) DeepCopyInto(out *ComponentMetadata) {
+	*out = *in
+	if in.DeploymentMetadata != nil {
+		in, out := &in.DeploymentMetadata, &out.DeploymentMetadata
+		*out = new(ResourceMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodMetadata != nil {
+		in, out := &in.PodMetadata, &out.PodMetadata
+		*out = new(ResourceMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentMetadata.
+func (in *ComponentMetadata) DeepCopy() *ComponentMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentStatus) DeepCopyInto(out *ComponentStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentStatus.
+func (in *ComponentStatus) DeepCopy() *ComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerProbeConfiguration) DeepCopyInto(out *ContainerProbeConfiguration) {
+	*out = *in
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(ProbeSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartupProbe != nil {
+		in, out := &in.StartupProbe, &out.StartupProbe
+		*out = new(StartupProbeSettings)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerProbeConfiguration.
+func (in *ContainerProbeConfiguration) DeepCopy() *ContainerProbeConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerProbeConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CoreOptions) DeepCopyInto(out *CoreOptions) {
+	*out = *in
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]corev1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoreOptions.
+func (in *CoreOptions) DeepCopy() *CoreOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(CoreOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CoreServiceConfig) DeepCopyInto(out *CoreServiceConfig) {
+	*out = *in
+	if in.HTTPPort != nil {
+		in, out := &in.HTTPPort, &out.HTTPPort
+		*out = new(int32)
+		**out = **in
+	}
+	in.ServiceConfig.DeepCopyInto(&out.ServiceConfig)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoreServiceConfig.
+func (in *CoreServiceConfig) DeepCopy() *CoreServiceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CoreServiceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Cryostat) DeepCopyInto(out *Cryostat) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cryostat.
+func (in *Cryostat) DeepCopy() *Cryostat {
+	if in == nil {
+		return nil
+	}
+	out := new(Cryostat)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Cryostat) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatBackup) DeepCopyInto(out *CryostatBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatBackup.
+func (in *CryostatBackup) DeepCopy() *CryostatBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CryostatBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatBackupList) DeepCopyInto(out *CryostatBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CryostatBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatBackupList.
+func (in *CryostatBackupList) DeepCopy() *CryostatBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CryostatBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatBackupSpec) DeepCopyInto(out *CryostatBackupSpec) {
+	*out = *in
+	in.Destination.DeepCopyInto(&out.Destination)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatBackupSpec.
+func (in *CryostatBackupSpec) DeepCopy() *CryostatBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatBackupStatus) DeepCopyInto(out *CryostatBackupStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatBackupStatus.
+func (in *CryostatBackupStatus) DeepCopy() *CryostatBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatList) DeepCopyInto(out *CryostatList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Cryostat, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatList.
+func (in *CryostatList) DeepCopy() *CryostatList {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CryostatList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatReference) DeepCopyInto(out *CryostatReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatReference.
+func (in *CryostatReference) DeepCopy() *CryostatReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatRestore) DeepCopyInto(out *CryostatRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatRestore.
+func (in *CryostatRestore) DeepCopy() *CryostatRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CryostatRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatRestoreList) DeepCopyInto(out *CryostatRestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CryostatRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatRestoreList.
+func (in *CryostatRestoreList) DeepCopy() *CryostatRestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CryostatRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatRestoreSpec) DeepCopyInto(out *CryostatRestoreSpec) {
+	*out = *in
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(BackupSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatRestoreSpec.
+func (in *CryostatRestoreSpec) DeepCopy() *CryostatRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatRestoreStatus) DeepCopyInto(out *CryostatRestoreStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatRestoreStatus.
+func (in *CryostatRestoreStatus) DeepCopy() *CryostatRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatSpec) DeepCopyInto(out *CryostatSpec) {
+	*out = *in
+	if in.TargetNamespaces != nil {
+		in, out := &in.TargetNamespaces, &out.TargetNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TrustedCertSecrets != nil {
+		in, out := &in.TrustedCertSecrets, &out.TrustedCertSecrets
+		*out = make([]CertificateSecret, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EventTemplates != nil {
+		in, out := &in.EventTemplates, &out.EventTemplates
+		*out = make([]TemplateConfigMap, len(*in))
+		copy(*out, *in)
+	}
+	if in.EventTemplateSelector != nil {
+		in, out := &in.EventTemplateSelector, &out.EventTemplateSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EnableCertManager != nil {
+		in, out := &in.EnableCertManager, &out.EnableCertManager
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CARotation != nil {
+		in, out := &in.CARotation, &out.CARotation
+		*out = new(CARotationOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StorageOptions != nil {
+		in, out := &in.StorageOptions, &out.StorageOptions
+		*out = new(StorageConfigurations)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceOptions != nil {
+		in, out := &in.ServiceOptions, &out.ServiceOptions
+		*out = new(ServiceConfigList)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicies != nil {
+		in, out := &in.NetworkPolicies, &out.NetworkPolicies
+		*out = new(NetworkPoliciesList)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkOptions != nil {
+		in, out := &in.NetworkOptions, &out.NetworkOptions
+		*out = new(NetworkConfigurationList)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReportOptions != nil {
+		in, out := &in.ReportOptions, &out.ReportOptions
+		*out = new(ReportConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TargetConnectionCacheOptions != nil {
+		in, out := &in.TargetConnectionCacheOptions, &out.TargetConnectionCacheOptions
+		*out = new(TargetConnectionCacheOptions)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(ResourceConfigList)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuthorizationOptions != nil {
+		in, out := &in.AuthorizationOptions, &out.AuthorizationOptions
+		*out = new(AuthorizationOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityOptions != nil {
+		in, out := &in.SecurityOptions, &out.SecurityOptions
+		*out = new(SecurityOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SchedulingOptions != nil {
+		in, out := &in.SchedulingOptions, &out.SchedulingOptions
+		*out = new(SchedulingConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TargetDiscoveryOptions != nil {
+		in, out := &in.TargetDiscoveryOptions, &out.TargetDiscoveryOptions
+		*out = new(TargetDiscoveryOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CoreOptions != nil {
+		in, out := &in.CoreOptions, &out.CoreOptions
+		*out = new(CoreOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GrafanaOptions != nil {
+		in, out := &in.GrafanaOptions, &out.GrafanaOptions
+		*out = new(GrafanaOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatabaseOptions != nil {
+		in, out := &in.DatabaseOptions, &out.DatabaseOptions
+		*out = new(DatabaseOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ObjectStorageOptions != nil {
+		in, out := &in.ObjectStorageOptions, &out.ObjectStorageOptions
+		*out = new(ObjectStorageOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OperandMetadata != nil {
+		in, out := &in.OperandMetadata, &out.OperandMetadata
+		*out = new(OperandMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AgentOptions != nil {
+		in, out := &in.AgentOptions, &out.AgentOptions
+		*out = new(AgentOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = new(ImageOverrides)
+		**out = **in
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = new(PodTemplateOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProbeOptions != nil {
+		in, out := &in.ProbeOptions, &out.ProbeOptions
+		*out = new(ProbeOptions)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatSpec.
+func (in *CryostatSpec) DeepCopy() *CryostatSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryostatStatus) DeepCopyInto(out *CryostatStatus) {
+	*out = *in
+	if in.TargetNamespaces != nil {
+		in, out := &in.TargetNamespaces, &out.TargetNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = make([]CertificateStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CARotation != nil {
+		in, out := &in.CARotation, &out.CARotation
+		*out = new(CARotationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EventTemplates != nil {
+		in, out := &in.EventTemplates, &out.EventTemplates
+		*out = make([]EventTemplateStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.StorageUsage != nil {
+		in, out := &in.StorageUsage, &out.StorageUsage
+		*out = new(StorageUsageStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PersistentVolumeClaims != nil {
+		in, out := &in.PersistentVolumeClaims, &out.PersistentVolumeClaims
+		*out = make([]PersistentVolumeClaimStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Database != nil {
+		in, out := &in.Database, &out.Database
+		*out = new(DatabaseStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryostatStatus.
+func (in *CryostatStatus) DeepCopy() *CryostatStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CryostatStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomTLSProfile) DeepCopyInto(out *CustomTLSProfile) {
+	*out = *in
+	if in.Ciphers != nil {
+		in, out := &in.Ciphers, &out.Ciphers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomTLSProfile.
+func (in *CustomTLSProfile) DeepCopy() *CustomTLSProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomTLSProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseOptions) DeepCopyInto(out *DatabaseOptions) {
+	*out = *in
+	if in.SecretName != nil {
+		in, out := &in.SecretName, &out.SecretName
+		*out = new(string)
+		**out = **in
+	}
+	if in.SchedulingOptions != nil {
+		in, out := &in.SchedulingOptions, &out.SchedulingOptions
+		*out = new(SchedulingConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseOptions.
+func (in *DatabaseOptions) DeepCopy() *DatabaseOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseServiceConfig) DeepCopyInto(out *DatabaseServiceConfig) {
+	*out = *in
+	if in.DatabasePort != nil {
+		in, out := &in.DatabasePort, &out.DatabasePort
+		*out = new(int32)
+		**out = **in
+	}
+	in.ServiceConfig.DeepCopyInto(&out.ServiceConfig)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseServiceConfig.
+func (in *DatabaseServiceConfig) DeepCopy() *DatabaseServiceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseServiceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseStatus) DeepCopyInto(out *DatabaseStatus) {
+	*out = *in
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(DatabaseUpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseStatus.
+func (in *DatabaseStatus) DeepCopy() *DatabaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseUpgradeStatus) DeepCopyInto(out *DatabaseUpgradeStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUpgradeStatus.
+func (in *DatabaseUpgradeStatus) DeepCopy() *DatabaseUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmptyDirConfig) DeepCopyInto(out *EmptyDirConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmptyDirConfig.
+func (in *EmptyDirConfig) DeepCopy() *EmptyDirConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EmptyDirConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventTemplateReference) DeepCopyInto(out *EventTemplateReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventTemplateReference.
+func (in *EventTemplateReference) DeepCopy() *EventTemplateReference {
+	if in == nil {
+		return nil
+	}
+	out := new(EventTemplateReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventTemplateStatus) DeepCopyInto(out *EventTemplateStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventTemplateStatus.
+func (in *EventTemplateStatus) DeepCopy() *EventTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EventTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDatasource) DeepCopyInto(out *GrafanaDatasource) {
+	*out = *in
+	if in.JSONData != nil {
+		in, out := &in.JSONData, &out.JSONData
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SecureJSONData != nil {
+		in, out := &in.SecureJSONData, &out.SecureJSONData
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SecureJSONDataFrom != nil {
+		in, out := &in.SecureJSONDataFrom, &out.SecureJSONDataFrom
+		*out = make(map[string]corev1.SecretKeySelector, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDatasource.
+func (in *GrafanaDatasource) DeepCopy() *GrafanaDatasource {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDatasource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaOptions) DeepCopyInto(out *GrafanaOptions) {
+	*out = *in
+	if in.DashboardConfigMaps != nil {
+		in, out := &in.DashboardConfigMaps, &out.DashboardConfigMaps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DashboardSelector != nil {
+		in, out := &in.DashboardSelector, &out.DashboardSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Datasources != nil {
+		in, out := &in.Datasources, &out.Datasources
+		*out = make([]GrafanaDatasource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SchedulingOptions != nil {
+		in, out := &in.SchedulingOptions, &out.SchedulingOptions
+		*out = new(SchedulingConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaOptions.
+func (in *GrafanaOptions) DeepCopy() *GrafanaOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaServiceConfig) DeepCopyInto(out *GrafanaServiceConfig) {
+	*out = *in
+	if in.HTTPPort != nil {
+		in, out := &in.HTTPPort, &out.HTTPPort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DatasourcePort != nil {
+		in, out := &in.DatasourcePort, &out.DatasourcePort
+		*out = new(int32)
+		**out = **in
+	}
+	in.ServiceConfig.DeepCopyInto(&out.ServiceConfig)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaServiceConfig.
+func (in *GrafanaServiceConfig) DeepCopy() *GrafanaServiceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaServiceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRouteConfiguration) DeepCopyInto(out *HTTPRouteConfiguration) {
+	*out = *in
+	in.ParentRef.DeepCopyInto(&out.ParentRef)
+	if in.Hostnames != nil {
+		in, out := &in.Hostnames, &out.Hostnames
+		*out = make([]apisv1.Hostname, len(*in))
+		copy(*out, *in)
+	}
+	if in.BackendTLSPolicy != nil {
+		in, out := &in.BackendTLSPolicy, &out.BackendTLSPolicy
+		*out = new(BackendTLSPolicyConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRouteConfiguration.
+func (in *HTTPRouteConfiguration) DeepCopy() *HTTPRouteConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRouteConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageOverrides) DeepCopyInto(out *ImageOverrides) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageOverrides.
+func (in *ImageOverrides) DeepCopy() *ImageOverrides {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageOverrides)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LegacyStorageConfiguration) DeepCopyInto(out *LegacyStorageConfiguration) {
+	*out = *in
+	if in.PVC != nil {
+		in, out := &in.PVC, &out.PVC
+		*out = new(PersistentVolumeClaimConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EmptyDir != nil {
+		in, out := &in.EmptyDir, &out.EmptyDir
+		*out = new(EmptyDirConfig)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LegacyStorageConfiguration.
 func (in *LegacyStorageConfiguration) DeepCopy() *LegacyStorageConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(LegacyStorageConfiguration)
+	out := new(LegacyStorageConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkConfiguration) DeepCopyInto(out *NetworkConfiguration) {
+	*out = *in
+	if in.ExternalHost != nil {
+		in, out := &in.ExternalHost, &out.ExternalHost
+		*out = new(string)
+		**out = **in
+	}
+	if in.IngressSpec != nil {
+		in, out := &in.IngressSpec, &out.IngressSpec
+		*out = new(networkingv1.IngressSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTPRoute != nil {
+		in, out := &in.HTTPRoute, &out.HTTPRoute
+		*out = new(HTTPRouteConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ResourceMetadata.DeepCopyInto(&out.ResourceMetadata)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfiguration.
+func (in *NetworkConfiguration) DeepCopy() *NetworkConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkConfigurationList) DeepCopyInto(out *NetworkConfigurationList) {
+	*out = *in
+	if in.CoreConfig != nil {
+		in, out := &in.CoreConfig, &out.CoreConfig
+		*out = new(NetworkConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AgentGatewayConfig != nil {
+		in, out := &in.AgentGatewayConfig, &out.AgentGatewayConfig
+		*out = new(AgentGatewayNetworkConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfigurationList.
+func (in *NetworkConfigurationList) DeepCopy() *NetworkConfigurationList {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkConfigurationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPoliciesList) DeepCopyInto(out *NetworkPoliciesList) {
+	*out = *in
+	if in.CoreConfig != nil {
+		in, out := &in.CoreConfig, &out.CoreConfig
+		*out = new(NetworkPolicyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReportsConfig != nil {
+		in, out := &in.ReportsConfig, &out.ReportsConfig
+		*out = new(NetworkPolicyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatabaseConfig != nil {
+		in, out := &in.DatabaseConfig, &out.DatabaseConfig
+		*out = new(NetworkPolicyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StorageConfig != nil {
+		in, out := &in.StorageConfig, &out.StorageConfig
+		*out = new(NetworkPolicyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GrafanaConfig != nil {
+		in, out := &in.GrafanaConfig, &out.GrafanaConfig
+		*out = new(NetworkPolicyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPoliciesList.
+func (in *NetworkPoliciesList) DeepCopy() *NetworkPoliciesList {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPoliciesList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkConfiguration) DeepCopyInto(out *NetworkConfiguration) {
+func (in *NetworkPolicyConfig) DeepCopyInto(out *NetworkPolicyConfig) {
 	*out = *in
-	if in.ExternalHost != nil {
-		in, out := &in.ExternalHost, &out.ExternalHost
-		*out = new(string)
+	if in.Disabled != nil {
+		in, out := &in.Disabled, &out.Disabled
+		*out = new(bool)
 		**out = **in
 	}
-	if in.IngressSpec != nil {
-		in, out := &in.IngressSpec, &out.IngressSpec
-		*out = new(networkingv1.IngressSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyConfig.
+func (in *NetworkPolicyConfig) DeepCopy() *NetworkPolicyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectStorageOptions) DeepCopyInto(out *ObjectStorageOptions) {
+	*out = *in
+	if in.SchedulingOptions != nil {
+		in, out := &in.SchedulingOptions, &out.SchedulingOptions
+		*out = new(SchedulingConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStorageOptions.
+func (in *ObjectStorageOptions) DeepCopy() *ObjectStorageOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectStorageOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenShiftSSOConfig) DeepCopyInto(out *OpenShiftSSOConfig) {
+	*out = *in
+	if in.Disable != nil {
+		in, out := &in.Disable, &out.Disable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AccessReview != nil {
+		in, out := &in.AccessReview, &out.AccessReview
+		*out = new(authorizationv1.ResourceAttributes)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenShiftSSOConfig.
+func (in *OpenShiftSSOConfig) DeepCopy() *OpenShiftSSOConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenShiftSSOConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandMetadata) DeepCopyInto(out *OperandMetadata) {
+	*out = *in
+	if in.DeploymentMetadata != nil {
+		in, out := &in.DeploymentMetadata, &out.DeploymentMetadata
+		*out = new(ResourceMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodMetadata != nil {
+		in, out := &in.PodMetadata, &out.PodMetadata
+		*out = new(ResourceMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceMetadata != nil {
+		in, out := &in.ResourceMetadata, &out.ResourceMetadata
+		*out = new(ResourceMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Core != nil {
+		in, out := &in.Core, &out.Core
+		*out = new(ComponentMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Reports != nil {
+		in, out := &in.Reports, &out.Reports
+		*out = new(ComponentMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Database != nil {
+		in, out := &in.Database, &out.Database
+		*out = new(ComponentMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(ComponentMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandMetadata.
+func (in *OperandMetadata) DeepCopy() *OperandMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCMigrationStatus) DeepCopyInto(out *PVCMigrationStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCMigrationStatus.
+func (in *PVCMigrationStatus) DeepCopy() *PVCMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistentVolumeClaimConfig) DeepCopyInto(out *PersistentVolumeClaimConfig) {
+	*out = *in
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(corev1.PersistentVolumeClaimSpec)
 		(*in).DeepCopyInto(*out)
 	}
 	in.ResourceMetadata.DeepCopyInto(&out.ResourceMetadata)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfiguration.
-func (in *NetworkConfiguration) DeepCopy() *NetworkConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentVolumeClaimConfig.
+func (in *PersistentVolumeClaimConfig) DeepCopy() *PersistentVolumeClaimConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkConfiguration)
+	out := new(PersistentVolumeClaimConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkConfigurationList) DeepCopyInto(out *NetworkConfigurationList) {
+func (in *PersistentVolumeClaimStatus) DeepCopyInto(out *PersistentVolumeClaimStatus) {
 	*out = *in
-	if in.CoreConfig != nil {
-		in, out := &in.CoreConfig, &out.CoreConfig
-		*out = new(NetworkConfiguration)
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(PVCMigrationStatus)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfigurationList.
-func (in *NetworkConfigurationList) DeepCopy() *NetworkConfigurationList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentVolumeClaimStatus.
+func (in *PersistentVolumeClaimStatus) DeepCopy() *PersistentVolumeClaimStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistentVolumeClaimStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodProbeConfiguration) DeepCopyInto(out *PodProbeConfiguration) {
+	*out = *in
+	in.ContainerProbeConfiguration.DeepCopyInto(&out.ContainerProbeConfiguration)
+	if in.TerminationGracePeriodSeconds != nil {
+		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodProbeConfiguration.
+func (in *PodProbeConfiguration) DeepCopy() *PodProbeConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PodProbeConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodTemplateOverrides) DeepCopyInto(out *PodTemplateOverrides) {
+	*out = *in
+	if in.Core != nil {
+		in, out := &in.Core, &out.Core
+		*out = new(corev1.PodTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Reports != nil {
+		in, out := &in.Reports, &out.Reports
+		*out = new(corev1.PodTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Database != nil {
+		in, out := &in.Database, &out.Database
+		*out = new(corev1.PodTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(corev1.PodTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Grafana != nil {
+		in, out := &in.Grafana, &out.Grafana
+		*out = new(corev1.PodTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodTemplateOverrides.
+func (in *PodTemplateOverrides) DeepCopy() *PodTemplateOverrides {
+	if in == nil {
+		return nil
+	}
+	out := new(PodTemplateOverrides)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeOptions) DeepCopyInto(out *ProbeOptions) {
+	*out = *in
+	if in.Core != nil {
+		in, out := &in.Core, &out.Core
+		*out = new(PodProbeConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Reports != nil {
+		in, out := &in.Reports, &out.Reports
+		*out = new(PodProbeConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Database != nil {
+		in, out := &in.Database, &out.Database
+		*out = new(PodProbeConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(PodProbeConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Grafana != nil {
+		in, out := &in.Grafana, &out.Grafana
+		*out = new(PodProbeConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Datasource != nil {
+		in, out := &in.Datasource, &out.Datasource
+		*out = new(ContainerProbeConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuthProxy != nil {
+		in, out := &in.AuthProxy, &out.AuthProxy
+		*out = new(ContainerProbeConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeOptions.
+func (in *ProbeOptions) DeepCopy() *ProbeOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeSettings) DeepCopyInto(out *ProbeSettings) {
+	*out = *in
+	if in.InitialDelaySeconds != nil {
+		in, out := &in.InitialDelaySeconds, &out.InitialDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PeriodSeconds != nil {
+		in, out := &in.PeriodSeconds, &out.PeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeSettings.
+func (in *ProbeSettings) DeepCopy() *ProbeSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Recording) DeepCopyInto(out *Recording) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Recording.
+func (in *Recording) DeepCopy() *Recording {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkConfigurationList)
+	out := new(Recording)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Recording) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkPoliciesList) DeepCopyInto(out *NetworkPoliciesList) {
+func (in *RecordingList) DeepCopyInto(out *RecordingList) {
 	*out = *in
-	if in.CoreConfig != nil {
-		in, out := &in.CoreConfig, &out.CoreConfig
-		*out = new(NetworkPolicyConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ReportsConfig != nil {
-		in, out := &in.ReportsConfig, &out.ReportsConfig
-		*out = new(NetworkPolicyConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.DatabaseConfig != nil {
-		in, out := &in.DatabaseConfig, &out.DatabaseConfig
-		*out = new(NetworkPolicyConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.StorageConfig != nil {
-		in, out := &in.StorageConfig, &out.StorageConfig
-		*out = new(NetworkPolicyConfig)
-		(*in).DeepCopyInto(*out)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Recording, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPoliciesList.
-func (in *NetworkPoliciesList) DeepCopy() *NetworkPoliciesList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecordingList.
+func (in *RecordingList) DeepCopy() *RecordingList {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkPoliciesList)
+	out := new(RecordingList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RecordingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkPolicyConfig) DeepCopyInto(out *NetworkPolicyConfig) {
+func (in *RecordingSpec) DeepCopyInto(out *RecordingSpec) {
 	*out = *in
-	if in.Disabled != nil {
-		in, out := &in.Disabled, &out.Disabled
-		*out = new(bool)
+	out.Cryostat = in.Cryostat
+	in.Target.DeepCopyInto(&out.Target)
+	if in.EventTemplate != nil {
+		in, out := &in.EventTemplate, &out.EventTemplate
+		*out = new(EventTemplateReference)
+		**out = **in
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyConfig.
-func (in *NetworkPolicyConfig) DeepCopy() *NetworkPolicyConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecordingSpec.
+func (in *RecordingSpec) DeepCopy() *RecordingSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkPolicyConfig)
+	out := new(RecordingSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenShiftSSOConfig) DeepCopyInto(out *OpenShiftSSOConfig) {
+func (in *RecordingStatus) DeepCopyInto(out *RecordingStatus) {
 	*out = *in
-	if in.Disable != nil {
-		in, out := &in.Disable, &out.Disable
-		*out = new(bool)
-		**out = **in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.AccessReview != nil {
-		in, out := &in.AccessReview, &out.AccessReview
-		*out = new(authorizationv1.ResourceAttributes)
-		**out = **in
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]RecordingTargetStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenShiftSSOConfig.
-func (in *OpenShiftSSOConfig) DeepCopy() *OpenShiftSSOConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecordingStatus.
+func (in *RecordingStatus) DeepCopy() *RecordingStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenShiftSSOConfig)
+	out := new(RecordingStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperandMetadata) DeepCopyInto(out *OperandMetadata) {
+func (in *RecordingTarget) DeepCopyInto(out *RecordingTarget) {
 	*out = *in
-	if in.DeploymentMetadata != nil {
-		in, out := &in.DeploymentMetadata, &out.DeploymentMetadata
-		*out = new(ResourceMetadata)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.PodMetadata != nil {
-		in, out := &in.PodMetadata, &out.PodMetadata
-		*out = new(ResourceMetadata)
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandMetadata.
-func (in *OperandMetadata) DeepCopy() *OperandMetadata {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecordingTarget.
+func (in *RecordingTarget) DeepCopy() *RecordingTarget {
 	if in == nil {
 		return nil
 	}
-	out := new(OperandMetadata)
+	out := new(RecordingTarget)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PersistentVolumeClaimConfig) DeepCopyInto(out *PersistentVolumeClaimConfig) {
+func (in *RecordingTargetStatus) DeepCopyInto(out *RecordingTargetStatus) {
 	*out = *in
-	if in.Spec != nil {
-		in, out := &in.Spec, &out.Spec
-		*out = new(corev1.PersistentVolumeClaimSpec)
-		(*in).DeepCopyInto(*out)
+	if in.RecordingID != nil {
+		in, out := &in.RecordingID, &out.RecordingID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Archives != nil {
+		in, out := &in.Archives, &out.Archives
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	in.ResourceMetadata.DeepCopyInto(&out.ResourceMetadata)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentVolumeClaimConfig.
-func (in *PersistentVolumeClaimConfig) DeepCopy() *PersistentVolumeClaimConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecordingTargetStatus.
+func (in *RecordingTargetStatus) DeepCopy() *RecordingTargetStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PersistentVolumeClaimConfig)
+	out := new(RecordingTargetStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -781,6 +2248,18 @@ func (in *SchedulingConfiguration) DeepCopyInto(out *SchedulingConfiguration) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RuntimeClassName != nil {
+		in, out := &in.RuntimeClassName, &out.RuntimeClassName
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingConfiguration.
@@ -861,6 +2340,11 @@ func (in *SecurityOptions) DeepCopyInto(out *SecurityOptions) {
 		*out = new(corev1.SecurityContext)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TLSProfile != nil {
+		in, out := &in.TLSProfile, &out.TLSProfile
+		*out = new(TLSProfile)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityOptions.
@@ -917,6 +2401,11 @@ func (in *ServiceConfigList) DeepCopyInto(out *ServiceConfigList) {
 		*out = new(StorageServiceConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GrafanaConfig != nil {
+		in, out := &in.GrafanaConfig, &out.GrafanaConfig
+		*out = new(GrafanaServiceConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AgentGatewayConfig != nil {
 		in, out := &in.AgentGatewayConfig, &out.AgentGatewayConfig
 		*out = new(AgentGatewayServiceConfig)
@@ -939,6 +2428,22 @@ func (in *ServiceConfigList) DeepCopy() *ServiceConfigList {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StartupProbeSettings) DeepCopyInto(out *StartupProbeSettings) {
+	*out = *in
+	in.ProbeSettings.DeepCopyInto(&out.ProbeSettings)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StartupProbeSettings.
+func (in *StartupProbeSettings) DeepCopy() *StartupProbeSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(StartupProbeSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageConfiguration) DeepCopyInto(out *StorageConfiguration) {
 	*out = *in
@@ -952,6 +2457,11 @@ func (in *StorageConfiguration) DeepCopyInto(out *StorageConfiguration) {
 		*out = new(EmptyDirConfig)
 		**out = **in
 	}
+	if in.VolumeSnapshotClassName != nil {
+		in, out := &in.VolumeSnapshotClassName, &out.VolumeSnapshotClassName
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageConfiguration.
@@ -977,6 +2487,11 @@ func (in *StorageConfigurations) DeepCopyInto(out *StorageConfigurations) {
 		*out = new(StorageConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(StorageRetention)
+		(*in).DeepCopyInto(*out)
+	}
 	in.LegacyStorageConfiguration.DeepCopyInto(&out.LegacyStorageConfiguration)
 }
 
@@ -990,6 +2505,36 @@ func (in *StorageConfigurations) DeepCopy() *StorageConfigurations {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageRetention) DeepCopyInto(out *StorageRetention) {
+	*out = *in
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxTotalSize != nil {
+		in, out := &in.MaxTotalSize, &out.MaxTotalSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxArchivesPerTarget != nil {
+		in, out := &in.MaxArchivesPerTarget, &out.MaxArchivesPerTarget
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageRetention.
+func (in *StorageRetention) DeepCopy() *StorageRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageServiceConfig) DeepCopyInto(out *StorageServiceConfig) {
 	*out = *in
@@ -1011,6 +2556,163 @@ func (in *StorageServiceConfig) DeepCopy() *StorageServiceConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageUsageStatus) DeepCopyInto(out *StorageUsageStatus) {
+	*out = *in
+	out.ArchivedRecordingsSize = in.ArchivedRecordingsSize.DeepCopy()
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageUsageStatus.
+func (in *StorageUsageStatus) DeepCopy() *StorageUsageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageUsageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StoredCredential) DeepCopyInto(out *StoredCredential) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StoredCredential.
+func (in *StoredCredential) DeepCopy() *StoredCredential {
+	if in == nil {
+		return nil
+	}
+	out := new(StoredCredential)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StoredCredential) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StoredCredentialList) DeepCopyInto(out *StoredCredentialList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]StoredCredential, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StoredCredentialList.
+func (in *StoredCredentialList) DeepCopy() *StoredCredentialList {
+	if in == nil {
+		return nil
+	}
+	out := new(StoredCredentialList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StoredCredentialList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StoredCredentialSpec) DeepCopyInto(out *StoredCredentialSpec) {
+	*out = *in
+	out.Cryostat = in.Cryostat
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StoredCredentialSpec.
+func (in *StoredCredentialSpec) DeepCopy() *StoredCredentialSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StoredCredentialSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StoredCredentialStatus) DeepCopyInto(out *StoredCredentialStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CredentialID != nil {
+		in, out := &in.CredentialID, &out.CredentialID
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StoredCredentialStatus.
+func (in *StoredCredentialStatus) DeepCopy() *StoredCredentialStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StoredCredentialStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSProfile) DeepCopyInto(out *TLSProfile) {
+	*out = *in
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(TLSProfileType)
+		**out = **in
+	}
+	if in.Custom != nil {
+		in, out := &in.Custom, &out.Custom
+		*out = new(CustomTLSProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InheritFromAPIServer != nil {
+		in, out := &in.InheritFromAPIServer, &out.InheritFromAPIServer
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSProfile.
+func (in *TLSProfile) DeepCopy() *TLSProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TargetConnectionCacheOptions) DeepCopyInto(out *TargetConnectionCacheOptions) {
 	*out = *in
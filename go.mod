@@ -15,6 +15,7 @@ require (
 	k8s.io/apimachinery v0.30.12
 	k8s.io/client-go v0.30.12
 	sigs.k8s.io/controller-runtime v0.18.7
+	sigs.k8s.io/gateway-api v1.1.0
 )
 
 require (
@@ -70,7 +71,6 @@ require (
 	k8s.io/klog/v2 v2.120.1 // indirect
 	k8s.io/kube-openapi v0.0.0-20240430033511-f0e62f92d13f // indirect
 	k8s.io/utils v0.0.0-20240502163921-fe8a2dddb1d0 // indirect
-	sigs.k8s.io/gateway-api v1.1.0 // indirect
 	sigs.k8s.io/json v0.0.0-20221116044647-bc3834ca7abd // indirect
 	sigs.k8s.io/structured-merge-diff/v4 v4.4.1 // indirect
 	sigs.k8s.io/yaml v1.4.0 // indirect
@@ -0,0 +1,294 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"slices"
+
+	certv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/constants"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const agentGatewayComponent = "cryostat-agent-gateway"
+
+func newAgentGatewayRoute(cr *model.CryostatInstance) *routev1.Route {
+	return &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name + "-agent",
+			Namespace: cr.InstallNamespace,
+		},
+	}
+}
+
+func newAgentGatewayIngress(cr *model.CryostatInstance) *netv1.Ingress {
+	return &netv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name + "-agent",
+			Namespace: cr.InstallNamespace,
+		},
+	}
+}
+
+func newAgentGatewayLoadBalancerService(cr *model.CryostatInstance) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name + "-agent-external",
+			Namespace: cr.InstallNamespace,
+		},
+	}
+}
+
+func isAgentGatewayExposed(cr *model.CryostatInstance) bool {
+	return cr.Spec.NetworkOptions != nil && cr.Spec.NetworkOptions.AgentGatewayConfig != nil
+}
+
+func isAgentGatewayIngressRequested(cr *model.CryostatInstance) bool {
+	return isAgentGatewayExposed(cr) && cr.Spec.NetworkOptions.AgentGatewayConfig.IngressSpec != nil
+}
+
+func isAgentGatewayLoadBalancerRequested(cr *model.CryostatInstance) bool {
+	return isAgentGatewayExposed(cr) && cr.Spec.NetworkOptions.AgentGatewayConfig.LoadBalancerConfig != nil
+}
+
+// reconcileAgentGatewayNetwork exposes the agent gateway outside of the cluster, if requested.
+// On OpenShift, this is done using a Route. On Kubernetes, an Ingress is used. On either platform,
+// a Service of type LoadBalancer may also be requested.
+func (r *Reconciler) reconcileAgentGatewayNetwork(ctx context.Context, cr *model.CryostatInstance, svc *corev1.Service,
+	tls *resource_definitions.TLSConfig) error {
+	if r.IsOpenShift {
+		err := r.reconcileAgentGatewayRoute(ctx, cr, svc, tls)
+		if err != nil {
+			return err
+		}
+	} else {
+		err := r.reconcileAgentGatewayIngress(ctx, cr)
+		if err != nil {
+			return err
+		}
+	}
+	return r.reconcileAgentGatewayLoadBalancer(ctx, cr)
+}
+
+func (r *Reconciler) reconcileAgentGatewayRoute(ctx context.Context, cr *model.CryostatInstance, svc *corev1.Service,
+	tls *resource_definitions.TLSConfig) error {
+	route := newAgentGatewayRoute(cr)
+	if !isAgentGatewayExposed(cr) {
+		// User has not requested the agent gateway be exposed, delete the Route if it exists
+		return r.deleteRoute(ctx, route)
+	}
+
+	port, err := GetHTTPPort(svc)
+	if err != nil {
+		return err
+	}
+	config := configureAgentGatewayRoute(cr)
+	_, err = r.createOrUpdateRoute(ctx, route, cr.Object, svc, port, newAgentGatewayRouteTLSConfig(tls), config)
+	return err
+}
+
+func newAgentGatewayRouteTLSConfig(tlsConfig *resource_definitions.TLSConfig) *routev1.TLSConfig {
+	if tlsConfig == nil {
+		return newRouteTLSConfig(tlsConfig)
+	}
+	// Agents authenticate to the gateway using client certificates,
+	// so TLS must be terminated by the gateway itself
+	return &routev1.TLSConfig{
+		Termination:                   routev1.TLSTerminationPassthrough,
+		InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyNone,
+	}
+}
+
+func (r *Reconciler) reconcileAgentGatewayIngress(ctx context.Context, cr *model.CryostatInstance) error {
+	ingress := newAgentGatewayIngress(cr)
+	if !isAgentGatewayIngressRequested(cr) {
+		// User has not requested an Ingress, delete if it exists
+		return r.deleteIngress(ctx, ingress)
+	}
+
+	config := configureAgentGatewayIngress(cr)
+	_, err := r.createOrUpdateIngress(ctx, ingress, cr.Object, config)
+	return err
+}
+
+func (r *Reconciler) reconcileAgentGatewayLoadBalancer(ctx context.Context, cr *model.CryostatInstance) error {
+	svc := newAgentGatewayLoadBalancerService(cr)
+	if !isAgentGatewayLoadBalancerRequested(cr) {
+		// User has not requested a load balancer, delete if it exists
+		return r.deleteService(ctx, svc)
+	}
+
+	config := configureAgentGatewayLoadBalancer(cr)
+	svcType := corev1.ServiceTypeLoadBalancer
+	svcConfig := &operatorv1beta2.ServiceConfig{
+		ServiceType:      &svcType,
+		ResourceMetadata: config.ResourceMetadata,
+	}
+	return r.createOrUpdateService(ctx, svc, cr.Object, svcConfig, func() error {
+		svc.Spec.Selector = map[string]string{
+			"app":       cr.Name,
+			"component": "cryostat",
+		}
+		svc.Spec.Ports = []corev1.ServicePort{
+			{
+				Name:       constants.HttpPortName,
+				Port:       *config.Port,
+				TargetPort: intstr.IntOrString{IntVal: constants.AgentProxyContainerPort},
+			},
+		}
+		return nil
+	})
+}
+
+// getAgentGatewayExternalHosts returns the hosts and IP addresses that agents outside
+// of the cluster may use to reach the agent gateway. These must be included in the
+// agent gateway's TLS certificate.
+func (r *Reconciler) getAgentGatewayExternalHosts(ctx context.Context, cr *model.CryostatInstance) ([]string, error) {
+	if !isAgentGatewayExposed(cr) {
+		return nil, nil
+	}
+	config := cr.Spec.NetworkOptions.AgentGatewayConfig
+
+	hosts := []string{}
+	if config.ExternalHost != nil && len(*config.ExternalHost) > 0 {
+		hosts = append(hosts, *config.ExternalHost)
+	}
+
+	if r.IsOpenShift {
+		// Use the host assigned to the Route, if it exists yet
+		route := newAgentGatewayRoute(cr)
+		err := r.Client.Get(ctx, types.NamespacedName{Name: route.Name, Namespace: route.Namespace}, route)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+		if len(route.Spec.Host) > 0 {
+			hosts = append(hosts, route.Spec.Host)
+		}
+	} else if config.IngressSpec != nil {
+		for _, rule := range config.IngressSpec.Rules {
+			if len(rule.Host) > 0 {
+				hosts = append(hosts, rule.Host)
+			}
+		}
+	}
+
+	if config.LoadBalancerConfig != nil {
+		// Use the addresses assigned to the load balancer, if any
+		svc := newAgentGatewayLoadBalancerService(cr)
+		err := r.Client.Get(ctx, types.NamespacedName{Name: svc.Name, Namespace: svc.Namespace}, svc)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			if len(ingress.Hostname) > 0 {
+				hosts = append(hosts, ingress.Hostname)
+			}
+			if len(ingress.IP) > 0 {
+				hosts = append(hosts, ingress.IP)
+			}
+		}
+	}
+
+	// Remove any duplicates, while preserving order
+	result := []string{}
+	for _, host := range hosts {
+		if !slices.Contains(result, host) {
+			result = append(result, host)
+		}
+	}
+	return result, nil
+}
+
+// reconcileAgentClientCertificates issues a client certificate, signed by the Cryostat CA,
+// for each agent running outside of the cluster. Certificates no longer requested are deleted.
+func (r *Reconciler) reconcileAgentClientCertificates(ctx context.Context, cr *model.CryostatInstance) ([]*certv1.Certificate, error) {
+	certs := []*certv1.Certificate{}
+	if isAgentGatewayExposed(cr) {
+		for _, agentClient := range cr.Spec.NetworkOptions.AgentGatewayConfig.ClientCertificates {
+			cert := resource_definitions.NewAgentClientCert(cr, agentClient.Name)
+			err := r.createOrUpdateCertificate(ctx, cert, cr.Object)
+			if err != nil {
+				return nil, err
+			}
+			certs = append(certs, cert)
+		}
+	}
+
+	// Delete any client certificates that were removed from the spec
+	existing := &certv1.CertificateList{}
+	err := r.Client.List(ctx, existing, client.InNamespace(cr.InstallNamespace), client.MatchingLabels{
+		"app":       cr.Name,
+		"component": "cryostat-agent-client",
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, cert := range existing.Items {
+		requested := slices.ContainsFunc(certs, func(c *certv1.Certificate) bool {
+			return c.Name == cert.Name
+		})
+		if !requested && metav1.IsControlledBy(&existing.Items[i], cr.Object) {
+			err := r.deleteCertWithSecret(ctx, &existing.Items[i])
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return certs, nil
+}
+
+func newAgentGatewayNetworkConfig(cr *model.CryostatInstance) *operatorv1beta2.NetworkConfiguration {
+	agentConfig := cr.Spec.NetworkOptions.AgentGatewayConfig
+	return &operatorv1beta2.NetworkConfiguration{
+		ExternalHost:     agentConfig.ExternalHost,
+		IngressSpec:      agentConfig.IngressSpec,
+		ResourceMetadata: *agentConfig.ResourceMetadata.DeepCopy(),
+	}
+}
+
+func configureAgentGatewayRoute(cr *model.CryostatInstance) *operatorv1beta2.NetworkConfiguration {
+	config := newAgentGatewayNetworkConfig(cr)
+	configureRoute(config, cr.Name, agentGatewayComponent)
+	return config
+}
+
+func configureAgentGatewayIngress(cr *model.CryostatInstance) *operatorv1beta2.NetworkConfiguration {
+	config := newAgentGatewayNetworkConfig(cr)
+	configureIngress(config, cr.Name, agentGatewayComponent)
+	return config
+}
+
+func configureAgentGatewayLoadBalancer(cr *model.CryostatInstance) *operatorv1beta2.AgentGatewayLoadBalancerConfig {
+	config := cr.Spec.NetworkOptions.AgentGatewayConfig.LoadBalancerConfig.DeepCopy()
+	configureMetadata(&config.ResourceMetadata, cr.Name, agentGatewayComponent)
+
+	// Apply default port if not provided
+	if config.Port == nil {
+		port := constants.AgentProxyContainerPort
+		config.Port = &port
+	}
+	return config
+}
@@ -0,0 +1,385 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/common"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
+	"github.com/cryostatio/cryostat-operator/internal/cryostatclient"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Verify that *AutomatedRuleReconciler implements CommonReconciler.
+var _ CommonReconciler = (*AutomatedRuleReconciler)(nil)
+
+// AutomatedRuleReconciler reconciles an AutomatedRule object
+type AutomatedRuleReconciler struct {
+	*ReconcilerConfig
+}
+
+// Name used for Finalizer that handles AutomatedRule deletion
+const automatedRuleFinalizer = "operator.cryostat.io/automatedrule.finalizer"
+
+// How often rules are compared against Cryostat to detect drift
+const automatedRuleSyncPeriod = time.Minute
+
+const eventRuleDriftType = "RuleDrift"
+
+// Reasons for AutomatedRule Conditions
+const reasonRuleSynchronized = "RuleSynchronized"
+
+// Characters that may not be used in a rule name
+var invalidRuleNameRegexp = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+func NewAutomatedRuleReconciler(config *ReconcilerConfig) (*AutomatedRuleReconciler, error) {
+	return &AutomatedRuleReconciler{
+		ReconcilerConfig: config,
+	}, nil
+}
+
+// +kubebuilder:rbac:groups=operator.cryostat.io,resources=automatedrules,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=operator.cryostat.io,resources=automatedrules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=operator.cryostat.io,resources=automatedrules/finalizers,verbs=update
+
+// Reconcile processes an AutomatedRule CR and manages the corresponding rule in Cryostat
+func (r *AutomatedRuleReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+
+	reqLogger.Info("Reconciling AutomatedRule")
+
+	// Fetch the AutomatedRule instance
+	rule := &operatorv1beta2.AutomatedRule{}
+	err := r.Client.Get(ctx, request.NamespacedName, rule)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			reqLogger.Info("AutomatedRule instance not found")
+			return reconcile.Result{}, nil
+		}
+		reqLogger.Error(err, "Error reading AutomatedRule instance")
+		return reconcile.Result{}, err
+	}
+
+	// Check if this AutomatedRule is being deleted
+	if rule.GetDeletionTimestamp() != nil {
+		if controllerutil.ContainsFinalizer(rule, automatedRuleFinalizer) {
+			err := r.finalizeAutomatedRule(ctx, rule)
+			if isCryostatPaused(err) {
+				// Reconciled again once the Cryostat is resumed
+				reqLogger.Info("Cryostat is paused, waiting to delete rule")
+				return reconcile.Result{}, nil
+			}
+			if err != nil {
+				reqLogger.Error(err, "Failed to delete rule from Cryostat")
+				return reconcile.Result{}, err
+			}
+
+			err = common.RemoveFinalizer(ctx, r.Client, rule, automatedRuleFinalizer)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		return reconcile.Result{}, nil
+	}
+
+	// Add our finalizer, so we can delete the rule in Cryostat upon deletion
+	if !controllerutil.ContainsFinalizer(rule, automatedRuleFinalizer) {
+		err := common.AddFinalizer(ctx, r.Client, rule, automatedRuleFinalizer)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	cr, err := r.getRuleCryostat(ctx, rule)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// Reconciled again once the Cryostat is created
+			return reconcile.Result{}, r.updateAutomatedRuleCondition(ctx, rule, metav1.ConditionFalse,
+				reasonCryostatNotFound, fmt.Sprintf("Cryostat %s/%s does not exist", rule.Namespace, rule.Spec.CryostatName))
+		} else if isCryostatPaused(err) {
+			// Reconciled again once the Cryostat is resumed
+			reason, message := getCryostatPausedCondition(err, rule.Namespace, rule.Spec.CryostatName)
+			return reconcile.Result{}, r.updateAutomatedRuleCondition(ctx, rule, metav1.ConditionFalse, reason, message)
+		}
+		return reconcile.Result{}, err
+	}
+
+	apiClient, err := r.newCryostatAPIClient(ctx, cr)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err = r.synchronizeRule(ctx, apiClient, rule)
+	if err != nil {
+		reqLogger.Error(err, "Failed to synchronize rule with Cryostat")
+		updateErr := r.updateAutomatedRuleCondition(ctx, rule, metav1.ConditionFalse, reasonCryostatAPIError, err.Error())
+		if updateErr != nil {
+			return reconcile.Result{}, updateErr
+		}
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{RequeueAfter: automatedRuleSyncPeriod}, r.updateAutomatedRuleCondition(ctx, rule,
+		metav1.ConditionTrue, reasonRuleSynchronized, fmt.Sprintf("Rule %q is synchronized with Cryostat", rule.Status.RuleName))
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AutomatedRuleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c := r.NewControllerBuilder(mgr)
+	c = c.For(&operatorv1beta2.AutomatedRule{})
+	// Reconcile AutomatedRules whenever their Cryostat changes, such as when it is created
+	c = c.Watches(&operatorv1beta2.Cryostat{}, c.EnqueueRequestsFromMapFunc(r.rulesForCryostat))
+	return c.Complete(r)
+}
+
+func (r *AutomatedRuleReconciler) GetConfig() *ReconcilerConfig {
+	return r.ReconcilerConfig
+}
+
+func (r *AutomatedRuleReconciler) rulesForCryostat(ctx context.Context, obj client.Object) []reconcile.Request {
+	rules := &operatorv1beta2.AutomatedRuleList{}
+	err := r.Client.List(ctx, rules, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		r.Log.Error(err, "failed to list AutomatedRules", "Cryostat", obj.GetName())
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, rule := range rules.Items {
+		if rule.Spec.CryostatName == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Name:      rule.Name,
+				Namespace: rule.Namespace,
+			}})
+		}
+	}
+	return requests
+}
+
+func (r *AutomatedRuleReconciler) getRuleCryostat(ctx context.Context, rule *operatorv1beta2.AutomatedRule) (*model.CryostatInstance, error) {
+	cr := &operatorv1beta2.Cryostat{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: rule.Spec.CryostatName, Namespace: rule.Namespace}, cr)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCryostatPaused(cr); err != nil {
+		return nil, err
+	}
+	return model.FromCryostat(cr), nil
+}
+
+// synchronizeRule creates the rule in Cryostat, or recreates it if it differs from the specification
+func (r *AutomatedRuleReconciler) synchronizeRule(ctx context.Context, apiClient cryostatclient.Client,
+	rule *operatorv1beta2.AutomatedRule) error {
+	desired := newCryostatRule(rule)
+
+	// Remove the previous rule if the rule was renamed
+	if len(rule.Status.RuleName) > 0 && rule.Status.RuleName != desired.Name {
+		err := apiClient.DeleteRule(ctx, rule.Status.RuleName, true)
+		if err != nil && !cryostatclient.IsNotFound(err) {
+			return err
+		}
+		rule.Status.RuleID = nil
+	}
+	rule.Status.RuleName = desired.Name
+
+	actual, err := apiClient.GetRule(ctx, desired.Name)
+	if err != nil {
+		if !cryostatclient.IsNotFound(err) {
+			return err
+		}
+		actual = nil
+	}
+
+	// Changes made within Cryostat since the last synchronization are considered drift, while
+	// differences due to an updated specification or an adopted rule are expected
+	synchronized := meta.FindStatusCondition(rule.Status.Conditions, string(operatorv1beta2.ConditionTypeAutomatedRuleSynchronized))
+	specUnchanged := synchronized != nil && synchronized.Status == metav1.ConditionTrue &&
+		synchronized.ObservedGeneration == rule.Generation
+	if actual == nil {
+		if rule.Status.RuleID != nil && specUnchanged {
+			r.recordDrift(rule, nil)
+		}
+		return r.createRule(ctx, apiClient, rule, desired)
+	}
+
+	fields := diffRules(desired, actual)
+	if len(fields) > 0 && rule.Status.RuleID != nil && *rule.Status.RuleID == actual.ID && specUnchanged {
+		r.recordDrift(rule, fields)
+	}
+	if len(fields) == 1 && fields[0] == "enabled" {
+		// The enabled state is the only field that can be updated in place
+		err := apiClient.SetRuleEnabled(ctx, desired.Name, desired.Enabled)
+		if err != nil {
+			return err
+		}
+	} else if len(fields) > 0 {
+		err := apiClient.DeleteRule(ctx, desired.Name, true)
+		if err != nil && !cryostatclient.IsNotFound(err) {
+			return err
+		}
+		return r.createRule(ctx, apiClient, rule, desired)
+	}
+	rule.Status.RuleID = &actual.ID
+	return nil
+}
+
+func (r *AutomatedRuleReconciler) createRule(ctx context.Context, apiClient cryostatclient.Client,
+	rule *operatorv1beta2.AutomatedRule, desired *cryostatclient.Rule) error {
+	created, err := apiClient.CreateRule(ctx, desired)
+	if err != nil {
+		return err
+	}
+	r.Log.Info("Created rule", "AutomatedRule.Namespace", rule.Namespace, "AutomatedRule.Name", rule.Name,
+		"Rule", created.Name)
+	rule.Status.RuleID = &created.ID
+	return nil
+}
+
+func (r *AutomatedRuleReconciler) recordDrift(rule *operatorv1beta2.AutomatedRule, fields []string) {
+	rule.Status.LastDrift = &operatorv1beta2.AutomatedRuleDrift{
+		DetectedTime: metav1.Now(),
+		Fields:       fields,
+	}
+	if len(fields) == 0 {
+		r.EventRecorder.Eventf(rule, corev1.EventTypeWarning, eventRuleDriftType,
+			"Rule %q was removed from Cryostat, recreating it", rule.Status.RuleName)
+	} else {
+		r.EventRecorder.Eventf(rule, corev1.EventTypeWarning, eventRuleDriftType,
+			"Rule %q was modified in Cryostat (%s), restoring it", rule.Status.RuleName, strings.Join(fields, ", "))
+	}
+}
+
+// finalizeAutomatedRule deletes the rule from Cryostat, along with any recordings it started
+func (r *AutomatedRuleReconciler) finalizeAutomatedRule(ctx context.Context, rule *operatorv1beta2.AutomatedRule) error {
+	cr, err := r.getRuleCryostat(ctx, rule)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// Cryostat has been deleted, so there is nothing to clean up
+			return nil
+		}
+		return err
+	}
+
+	apiClient, err := r.newCryostatAPIClient(ctx, cr)
+	if err != nil {
+		return err
+	}
+
+	name := rule.Status.RuleName
+	if len(name) == 0 {
+		name = getCryostatRuleName(rule)
+	}
+	err = apiClient.DeleteRule(ctx, name, true)
+	if err != nil && !cryostatclient.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func getCryostatRuleName(rule *operatorv1beta2.AutomatedRule) string {
+	if len(rule.Spec.Name) > 0 {
+		return rule.Spec.Name
+	}
+	return invalidRuleNameRegexp.ReplaceAllString(rule.Name, "_")
+}
+
+func newCryostatRule(rule *operatorv1beta2.AutomatedRule) *cryostatclient.Rule {
+	result := &cryostatclient.Rule{
+		Name:              getCryostatRuleName(rule),
+		Description:       rule.Spec.Description,
+		MatchExpression:   rule.Spec.MatchExpression,
+		EventSpecifier:    rule.Spec.EventSpecifier,
+		PreservedArchives: rule.Spec.PreservedArchives,
+		Enabled:           rule.Spec.Enabled == nil || *rule.Spec.Enabled,
+	}
+	if rule.Spec.ArchivalPeriod != nil {
+		result.ArchivalPeriodSeconds = int64(rule.Spec.ArchivalPeriod.Seconds())
+	}
+	if rule.Spec.InitialDelay != nil {
+		result.InitialDelaySeconds = int64(rule.Spec.InitialDelay.Seconds())
+	}
+	if rule.Spec.MaxAge != nil {
+		result.MaxAgeSeconds = int64(rule.Spec.MaxAge.Seconds())
+	}
+	if rule.Spec.MaxSize != nil {
+		result.MaxSizeBytes = rule.Spec.MaxSize.Value()
+	}
+	return result
+}
+
+// diffRules returns the names of the fields that differ between the two rules
+func diffRules(desired *cryostatclient.Rule, actual *cryostatclient.Rule) []string {
+	fields := []string{}
+	if desired.Description != actual.Description {
+		fields = append(fields, "description")
+	}
+	if desired.MatchExpression != actual.MatchExpression {
+		fields = append(fields, "matchExpression")
+	}
+	if desired.EventSpecifier != actual.EventSpecifier {
+		fields = append(fields, "eventSpecifier")
+	}
+	if desired.ArchivalPeriodSeconds != actual.ArchivalPeriodSeconds {
+		fields = append(fields, "archivalPeriod")
+	}
+	if desired.InitialDelaySeconds != actual.InitialDelaySeconds {
+		fields = append(fields, "initialDelay")
+	}
+	if desired.PreservedArchives != actual.PreservedArchives {
+		fields = append(fields, "preservedArchives")
+	}
+	if desired.MaxAgeSeconds != actual.MaxAgeSeconds {
+		fields = append(fields, "maxAge")
+	}
+	if desired.MaxSizeBytes != actual.MaxSizeBytes {
+		fields = append(fields, "maxSize")
+	}
+	if desired.Enabled != actual.Enabled {
+		fields = append(fields, "enabled")
+	}
+	return fields
+}
+
+func (r *AutomatedRuleReconciler) updateAutomatedRuleCondition(ctx context.Context, rule *operatorv1beta2.AutomatedRule,
+	status metav1.ConditionStatus, reason string, message string) error {
+	meta.SetStatusCondition(&rule.Status.Conditions, metav1.Condition{
+		Type:               string(operatorv1beta2.ConditionTypeAutomatedRuleSynchronized),
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: rule.Generation,
+	})
+	err := r.Client.Status().Update(ctx, rule)
+	if err != nil {
+		r.Log.Error(err, "failed to update condition", "AutomatedRule.Namespace", rule.Namespace,
+			"AutomatedRule.Name", rule.Name)
+	}
+	return err
+}
@@ -0,0 +1,412 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controllers"
+	"github.com/cryostatio/cryostat-operator/internal/cryostatclient"
+	"github.com/cryostatio/cryostat-operator/internal/test"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type automatedRuleTestInput struct {
+	controller *controllers.AutomatedRuleReconciler
+	rule       *operatorv1beta2.AutomatedRule
+	*test.ControllerTestInput
+}
+
+var _ = Describe("AutomatedRuleController", func() {
+	var t *automatedRuleTestInput
+
+	BeforeEach(func() {
+		t = &automatedRuleTestInput{
+			ControllerTestInput: test.NewControllerTestInput(),
+		}
+		t.rule = t.NewAutomatedRule()
+		t.Objs = []ctrlclient.Object{
+			t.NewNamespace(),
+			t.NewCryostat().Object,
+			t.NewCABundleSecret(),
+		}
+	})
+
+	JustBeforeEach(func() {
+		t.Objs = append(t.Objs, t.rule)
+		var err error
+		t.controller, err = controllers.NewAutomatedRuleReconciler(t.NewReconcilerConfig(&operatorv1beta2.AutomatedRule{}))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Describe("reconciling a request", func() {
+		Context("with default options", func() {
+			JustBeforeEach(func() {
+				t.reconcileAutomatedRule()
+			})
+
+			It("should add a finalizer", func() {
+				t.ExpectFinalizer(t.rule, "operator.cryostat.io/automatedrule.finalizer")
+			})
+
+			It("should connect to Cryostat using its service and CA bundle", func() {
+				Expect(t.CryostatClient.Configs).ToNot(BeEmpty())
+				config := t.CryostatClient.Configs[0]
+				Expect(config.BaseURL.String()).To(Equal("https://cryostat.test.svc:4180"))
+				Expect(config.CACert).To(Equal(t.NewCABundleSecret().Data["ca.crt"]))
+			})
+
+			It("should create an enabled rule named after the AutomatedRule", func() {
+				Expect(t.CryostatClient.Rules).To(HaveKey("my_rule"))
+				Expect(t.CryostatClient.Rules["my_rule"]).To(Equal(cryostatclient.Rule{
+					ID:              1,
+					Name:            "my_rule",
+					MatchExpression: "target.labels.app == 'my-app'",
+					EventSpecifier:  "template=Continuous,type=TARGET",
+					Enabled:         true,
+				}))
+			})
+
+			It("should report the rule in the status", func() {
+				rule := t.getAutomatedRule()
+				Expect(rule.Status.RuleName).To(Equal("my_rule"))
+				Expect(rule.Status.RuleID).To(Equal(&[]int64{1}[0]))
+				Expect(rule.Status.LastDrift).To(BeNil())
+				t.expectCondition(rule, metav1.ConditionTrue, "RuleSynchronized")
+			})
+
+			It("should requeue to detect drift", func() {
+				result, err := t.reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: time.Minute}))
+			})
+
+			It("should not recreate an unchanged rule", func() {
+				t.reconcileAutomatedRule()
+				Expect(t.CryostatClient.Rules["my_rule"].ID).To(Equal(int64(1)))
+				Expect(t.getAutomatedRule().Status.LastDrift).To(BeNil())
+			})
+
+			Context("when the rule is deleted from Cryostat", func() {
+				JustBeforeEach(func() {
+					delete(t.CryostatClient.Rules, "my_rule")
+					t.reconcileAutomatedRule()
+				})
+
+				It("should recreate the rule", func() {
+					Expect(t.CryostatClient.Rules).To(HaveKey("my_rule"))
+					Expect(*t.getAutomatedRule().Status.RuleID).To(Equal(int64(2)))
+				})
+
+				It("should report the drift", func() {
+					drift := t.getAutomatedRule().Status.LastDrift
+					Expect(drift).ToNot(BeNil())
+					Expect(drift.Fields).To(BeEmpty())
+					Expect(t.Recorder.Events).To(Receive(ContainSubstring("RuleDrift")))
+				})
+			})
+
+			Context("when the rule is disabled in Cryostat", func() {
+				JustBeforeEach(func() {
+					rule := t.CryostatClient.Rules["my_rule"]
+					rule.Enabled = false
+					t.CryostatClient.Rules["my_rule"] = rule
+					t.reconcileAutomatedRule()
+				})
+
+				It("should enable the rule in place", func() {
+					Expect(t.CryostatClient.Rules["my_rule"].Enabled).To(BeTrue())
+					Expect(t.CryostatClient.Rules["my_rule"].ID).To(Equal(int64(1)))
+				})
+
+				It("should report the drift", func() {
+					drift := t.getAutomatedRule().Status.LastDrift
+					Expect(drift).ToNot(BeNil())
+					Expect(drift.Fields).To(ConsistOf("enabled"))
+				})
+			})
+
+			Context("when the rule is modified in Cryostat", func() {
+				JustBeforeEach(func() {
+					rule := t.CryostatClient.Rules["my_rule"]
+					rule.MatchExpression = "true"
+					rule.MaxAgeSeconds = 60
+					t.CryostatClient.Rules["my_rule"] = rule
+					t.reconcileAutomatedRule()
+				})
+
+				It("should recreate the rule", func() {
+					Expect(t.CryostatClient.Rules["my_rule"].MatchExpression).To(Equal("target.labels.app == 'my-app'"))
+					Expect(t.CryostatClient.Rules["my_rule"].MaxAgeSeconds).To(BeZero())
+					Expect(t.CryostatClient.CleanedRules).To(ConsistOf("my_rule"))
+				})
+
+				It("should report the drift", func() {
+					drift := t.getAutomatedRule().Status.LastDrift
+					Expect(drift).ToNot(BeNil())
+					Expect(drift.Fields).To(ConsistOf("matchExpression", "maxAge"))
+				})
+			})
+
+			Context("when the specification changes", func() {
+				JustBeforeEach(func() {
+					rule := t.getAutomatedRule()
+					rule.Spec.MatchExpression = "true"
+					rule.Generation++
+					Expect(t.Client.Update(context.Background(), rule)).To(Succeed())
+					t.reconcileAutomatedRule()
+				})
+
+				It("should recreate the rule", func() {
+					Expect(t.CryostatClient.Rules["my_rule"].MatchExpression).To(Equal("true"))
+				})
+
+				It("should not report drift", func() {
+					Expect(t.getAutomatedRule().Status.LastDrift).To(BeNil())
+				})
+			})
+
+			Context("when the rule is renamed", func() {
+				JustBeforeEach(func() {
+					rule := t.getAutomatedRule()
+					rule.Spec.Name = "renamed"
+					rule.Generation++
+					Expect(t.Client.Update(context.Background(), rule)).To(Succeed())
+					t.reconcileAutomatedRule()
+				})
+
+				It("should replace the old rule", func() {
+					Expect(t.CryostatClient.Rules).To(HaveLen(1))
+					Expect(t.CryostatClient.Rules).To(HaveKey("renamed"))
+					Expect(t.CryostatClient.CleanedRules).To(ConsistOf("my_rule"))
+					Expect(t.getAutomatedRule().Status.RuleName).To(Equal("renamed"))
+				})
+			})
+
+			Context("when deleted", func() {
+				JustBeforeEach(func() {
+					t.deleteAutomatedRule()
+				})
+
+				It("should delete the rule and its recordings", func() {
+					Expect(t.CryostatClient.Rules).To(BeEmpty())
+					Expect(t.CryostatClient.CleanedRules).To(ConsistOf("my_rule"))
+				})
+
+				It("should remove the finalizer", func() {
+					t.expectNoAutomatedRule()
+				})
+			})
+		})
+
+		Context("with options", func() {
+			BeforeEach(func() {
+				t.rule = t.NewAutomatedRuleWithOptions()
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileAutomatedRule()
+			})
+
+			It("should create the rule with the options", func() {
+				Expect(t.CryostatClient.Rules["continuous_my_app"]).To(Equal(cryostatclient.Rule{
+					ID:                    1,
+					Name:                  "continuous_my_app",
+					Description:           "Continuous recording of my-app",
+					MatchExpression:       "target.labels.app == 'my-app'",
+					EventSpecifier:        "template=Continuous,type=TARGET",
+					ArchivalPeriodSeconds: 1800,
+					InitialDelaySeconds:   10,
+					PreservedArchives:     3,
+					MaxAgeSeconds:         3600,
+					MaxSizeBytes:          10 * 1024 * 1024,
+					Enabled:               false,
+				}))
+			})
+		})
+
+		Context("with an existing rule in Cryostat", func() {
+			BeforeEach(func() {
+				t.CryostatClient.Rules["my_rule"] = cryostatclient.Rule{
+					ID:              42,
+					Name:            "my_rule",
+					MatchExpression: "true",
+					EventSpecifier:  "template=Profiling,type=TARGET",
+				}
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileAutomatedRule()
+			})
+
+			It("should replace the rule without reporting drift", func() {
+				Expect(t.CryostatClient.Rules["my_rule"].EventSpecifier).To(Equal("template=Continuous,type=TARGET"))
+				rule := t.getAutomatedRule()
+				Expect(rule.Status.LastDrift).To(BeNil())
+				t.expectCondition(rule, metav1.ConditionTrue, "RuleSynchronized")
+			})
+		})
+
+		Context("with a missing Cryostat", func() {
+			BeforeEach(func() {
+				t.rule.Spec.CryostatName = "missing"
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileAutomatedRule()
+			})
+
+			It("should report that Cryostat was not found", func() {
+				Expect(t.CryostatClient.Configs).To(BeEmpty())
+				t.expectCondition(t.getAutomatedRule(), metav1.ConditionFalse, "CryostatNotFound")
+			})
+
+			It("should remove the finalizer when deleted", func() {
+				t.deleteAutomatedRule()
+				t.expectNoAutomatedRule()
+			})
+		})
+
+		Context("with a paused Cryostat", func() {
+			BeforeEach(func() {
+				cr := t.NewCryostat()
+				cr.Spec.Paused = true
+				t.Objs[1] = cr.Object
+			})
+
+			It("should report that Cryostat is paused without requeueing", func() {
+				result, err := t.reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{}))
+				Expect(t.CryostatClient.Configs).To(BeEmpty())
+				t.expectCondition(t.getAutomatedRule(), metav1.ConditionFalse, "CryostatPaused")
+			})
+		})
+
+		Context("with reconciliation of Cryostat paused", func() {
+			BeforeEach(func() {
+				cr := t.NewCryostat()
+				cr.Spec.ReconcilePaused = true
+				t.Objs[1] = cr.Object
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileAutomatedRule()
+			})
+
+			It("should report that reconciliation is paused", func() {
+				Expect(t.CryostatClient.Configs).To(BeEmpty())
+				t.expectCondition(t.getAutomatedRule(), metav1.ConditionFalse, "CryostatReconcilePaused")
+			})
+		})
+
+		Context("when deleted while Cryostat is paused", func() {
+			JustBeforeEach(func() {
+				t.reconcileAutomatedRule()
+				t.UpdateCryostat(func(cr *operatorv1beta2.Cryostat) {
+					cr.Spec.Paused = true
+				})
+				t.deleteAutomatedRule()
+			})
+
+			It("should keep the rule and finalizer", func() {
+				Expect(t.CryostatClient.Rules).To(HaveLen(1))
+				t.ExpectFinalizer(t.getAutomatedRule(), "operator.cryostat.io/automatedrule.finalizer")
+			})
+
+			Context("then resumed", func() {
+				JustBeforeEach(func() {
+					t.UpdateCryostat(func(cr *operatorv1beta2.Cryostat) {
+						cr.Spec.Paused = false
+					})
+					t.reconcileAutomatedRule()
+				})
+
+				It("should delete the rule", func() {
+					Expect(t.CryostatClient.Rules).To(BeEmpty())
+					t.expectNoAutomatedRule()
+				})
+			})
+		})
+
+		Context("when the Cryostat API fails", func() {
+			BeforeEach(func() {
+				t.CryostatClient.Err = errors.New("connection refused")
+			})
+
+			It("should return an error and report it", func() {
+				_, err := t.reconcile()
+				Expect(err).To(HaveOccurred())
+				t.expectCondition(t.getAutomatedRule(), metav1.ConditionFalse, "CryostatAPIError")
+			})
+		})
+	})
+
+	Describe("setting up the controller", func() {
+		JustBeforeEach(func() {
+			err := t.controller.SetupWithManager(nil)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should reconcile AutomatedRules when their Cryostat changes", func() {
+			builder := t.ControllerBuilder
+			Expect(builder.MapFuncs).To(HaveLen(1))
+			other := t.NewAutomatedRule()
+			other.Name = "other-rule"
+			other.Spec.CryostatName = "other"
+			Expect(t.Client.Create(context.Background(), other)).To(Succeed())
+
+			requests := builder.MapFuncs[0](context.Background(), t.NewCryostat().Object)
+			Expect(requests).To(ConsistOf(reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: "my-rule", Namespace: t.Namespace},
+			}))
+		})
+	})
+})
+
+func (t *automatedRuleTestInput) reconcile() (reconcile.Result, error) {
+	return t.Reconcile(t.controller, t.rule)
+}
+
+func (t *automatedRuleTestInput) reconcileAutomatedRule() {
+	t.ExpectReconcile(t.controller, t.rule)
+}
+
+func (t *automatedRuleTestInput) deleteAutomatedRule() {
+	t.DeleteAndReconcile(t.controller, t.getAutomatedRule())
+}
+
+func (t *automatedRuleTestInput) getAutomatedRule() *operatorv1beta2.AutomatedRule {
+	rule := t.rule.DeepCopy()
+	t.Get(rule)
+	return rule
+}
+
+func (t *automatedRuleTestInput) expectNoAutomatedRule() {
+	t.ExpectNotFound(t.rule.DeepCopy())
+}
+
+func (t *automatedRuleTestInput) expectCondition(rule *operatorv1beta2.AutomatedRule, status metav1.ConditionStatus, reason string) {
+	test.ExpectCondition(rule.Status.Conditions, string(operatorv1beta2.ConditionTypeAutomatedRuleSynchronized),
+		status, reason, rule.Generation)
+}
@@ -0,0 +1,215 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/constants"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Default time that each stage of a CA rotation lasts
+const defaultCARotationOverlapPeriod = 24 * time.Hour
+
+const eventCARotationType = "CARotation"
+
+// caRotationState describes which revisions of the Cryostat CA should be used
+type caRotationState struct {
+	// Revision of the CA issuing certificates
+	issuing string
+	// Revision of a CA that is trusted, but not issuing certificates
+	trusted *string
+}
+
+func newCABundleSecret(cr *model.CryostatInstance) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name + "-ca-bundle",
+			Namespace: cr.InstallNamespace,
+		},
+	}
+}
+
+func getCARotationOverlapPeriod(cr *model.CryostatInstance) time.Duration {
+	if cr.Spec.CARotation != nil && cr.Spec.CARotation.OverlapPeriod != nil {
+		return cr.Spec.CARotation.OverlapPeriod.Duration
+	}
+	return defaultCARotationOverlapPeriod
+}
+
+// reconcileCARotation advances any rotation of the Cryostat CA that is in progress. A rotation
+// consists of three stages:
+//  1. The new CA is created and distributed alongside the current CA in a trust bundle.
+//  2. After the overlap period, the new CA begins issuing certificates. The previous CA remains trusted.
+//  3. After another overlap period, the previous CA is deleted and no longer trusted.
+func (r *Reconciler) reconcileCARotation(ctx context.Context, cr *model.CryostatInstance) (*caRotationState, error) {
+	status := cr.Status.CARotation
+	if status == nil {
+		if cr.Spec.CARotation == nil {
+			// Rotation has never been requested, use the original CA
+			return &caRotationState{}, nil
+		}
+		// Determine the revision of any existing CA. If none exists yet,
+		// there is nothing to rotate from.
+		status = &operatorv1beta2.CARotationStatus{}
+		existing := resource_definitions.NewCryostatCACert(r.gvk, cr)
+		err := r.Client.Get(ctx, types.NamespacedName{Name: existing.Name, Namespace: existing.Namespace}, existing)
+		if err != nil {
+			if !kerrors.IsNotFound(err) {
+				return nil, err
+			}
+			status.ActiveRevision = cr.Spec.CARotation.Revision
+		}
+		cr.Status.CARotation = status
+	}
+
+	desired := ""
+	if cr.Spec.CARotation != nil {
+		desired = cr.Spec.CARotation.Revision
+	}
+	overlap := getCARotationOverlapPeriod(cr)
+	now := metav1.Now()
+	stageEnded := status.StageStartTime != nil && !now.Time.Before(status.StageStartTime.Add(overlap))
+
+	if status.PreviousRevision != nil {
+		// Stage 3: Stop trusting the previous CA once the overlap period has passed
+		if !stageEnded {
+			return r.retirePreviousCA(ctx, cr, overlap)
+		}
+		previous := *status.PreviousRevision
+		err := r.deleteCertWithSecret(ctx, resource_definitions.NewCryostatCACertForRevision(r.gvk, cr, previous))
+		if err != nil {
+			return nil, err
+		}
+		r.EventRecorder.Eventf(cr.Object, corev1.EventTypeNormal, eventCARotationType,
+			"Removed CA revision %q from the trust bundle", previous)
+		status.PreviousRevision = nil
+		status.StageStartTime = nil
+	}
+
+	if status.PendingRevision != nil && *status.PendingRevision != desired {
+		// The requested revision changed before the pending CA began issuing certificates, so discard it
+		pending := *status.PendingRevision
+		if pending != status.ActiveRevision {
+			err := r.deleteCertWithSecret(ctx, resource_definitions.NewCryostatCACertForRevision(r.gvk, cr, pending))
+			if err != nil {
+				return nil, err
+			}
+		}
+		status.PendingRevision = nil
+		status.StageStartTime = nil
+	}
+
+	if desired != status.ActiveRevision {
+		// Stage 1: Distribute the new CA alongside the current CA
+		if status.PendingRevision == nil {
+			status.PendingRevision = &desired
+			status.StageStartTime = &now
+			stageEnded = overlap <= 0
+			r.EventRecorder.Eventf(cr.Object, corev1.EventTypeNormal, eventCARotationType,
+				"Distributing trust bundle containing CA revisions %q and %q", status.ActiveRevision, desired)
+		}
+		if !stageEnded {
+			return r.updateCARotationCondition(ctx, cr, &caRotationState{issuing: status.ActiveRevision, trusted: status.PendingRevision},
+				reasonCADistributingTrustBundle, fmt.Sprintf("Distributing a trust bundle containing CA revisions %q and %q. "+
+					"Restart agents before %s to trust the new CA, which will then begin issuing certificates.",
+					status.ActiveRevision, desired, status.StageStartTime.Add(overlap).UTC().Format(time.RFC3339)))
+		}
+
+		// Stage 2: Switch to issuing certificates from the new CA
+		r.EventRecorder.Eventf(cr.Object, corev1.EventTypeNormal, eventCARotationType,
+			"Switching certificate issuance from CA revision %q to %q", status.ActiveRevision, desired)
+		previous := status.ActiveRevision
+		status.PreviousRevision = &previous
+		status.ActiveRevision = desired
+		status.PendingRevision = nil
+		status.StageStartTime = &now
+		// The previous CA is removed on a later reconcile, once certificates have been reissued
+		return r.retirePreviousCA(ctx, cr, overlap)
+	}
+
+	return r.updateCARotationCondition(ctx, cr, &caRotationState{issuing: status.ActiveRevision},
+		reasonCARotationComplete, fmt.Sprintf("Certificates are issued by CA revision %q.", status.ActiveRevision))
+}
+
+func (r *Reconciler) retirePreviousCA(ctx context.Context, cr *model.CryostatInstance, overlap time.Duration) (*caRotationState, error) {
+	status := cr.Status.CARotation
+	return r.updateCARotationCondition(ctx, cr, &caRotationState{issuing: status.ActiveRevision, trusted: status.PreviousRevision},
+		reasonCAIssuingCertificates, fmt.Sprintf("Certificates are now issued by CA revision %q. "+
+			"Restart agents before %s to obtain new certificates, after which CA revision %q will no longer be trusted.",
+			status.ActiveRevision, status.StageStartTime.Add(overlap).UTC().Format(time.RFC3339), *status.PreviousRevision))
+}
+
+func (r *Reconciler) updateCARotationCondition(ctx context.Context, cr *model.CryostatInstance, state *caRotationState,
+	reason string, message string) (*caRotationState, error) {
+	status := metav1.ConditionFalse
+	if state.trusted != nil {
+		status = metav1.ConditionTrue
+	}
+	err := r.updateCondition(ctx, cr, operatorv1beta2.ConditionTypeCARotationProgressing, status, reason, message)
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// getCARotationRequeueAfter returns the time remaining in the current stage of a CA rotation,
+// or zero if no rotation is in progress
+func getCARotationRequeueAfter(cr *model.CryostatInstance) time.Duration {
+	status := cr.Status.CARotation
+	if status == nil || status.StageStartTime == nil ||
+		(status.PendingRevision == nil && status.PreviousRevision == nil) {
+		return 0
+	}
+	remaining := time.Until(status.StageStartTime.Add(getCARotationOverlapPeriod(cr)))
+	if remaining < time.Second {
+		return time.Second
+	}
+	return remaining
+}
+
+func (r *Reconciler) createOrUpdateCABundleSecret(ctx context.Context, secret *corev1.Secret, cr *model.CryostatInstance,
+	bundle []byte) error {
+	return r.createOrUpdateSecret(ctx, secret, cr.Object, func() error {
+		mergeResourceMetadata(cr, &secret.ObjectMeta)
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[constants.CAKey] = bundle
+		return nil
+	})
+}
+
+// newCABundle concatenates the PEM-encoded CA certificates into a single bundle
+func newCABundle(caCerts ...[]byte) []byte {
+	bundle := []byte{}
+	for _, caCert := range caCerts {
+		if len(bundle) > 0 && !bytes.HasSuffix(bundle, []byte("\n")) {
+			bundle = append(bundle, '\n')
+		}
+		bundle = append(bundle, caCert...)
+	}
+	return bundle
+}
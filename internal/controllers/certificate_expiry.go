@@ -0,0 +1,103 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	certv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Certificates expiring within this period are reported by the CertificatesExpiringSoon condition.
+// cert-manager renews certificates well before this point, so a certificate reaching it
+// indicates that renewal is failing. The Cryostat CR is requeued for when the next certificate
+// enters this period, in addition to being checked on each reconcile.
+const certExpiryWarningPeriod = 7 * 24 * time.Hour
+
+const eventCertExpiringSoonType = reasonCertsExpiringSoon
+
+func newCertificateStatus(cert *certv1.Certificate, copyNamespaces ...string) operatorv1beta2.CertificateStatus {
+	status := operatorv1beta2.CertificateStatus{
+		Name:        cert.Name,
+		Namespace:   cert.Namespace,
+		SecretName:  cert.Spec.SecretName,
+		NotAfter:    cert.Status.NotAfter.DeepCopy(),
+		RenewalTime: cert.Status.RenewalTime.DeepCopy(),
+	}
+	if len(copyNamespaces) > 0 {
+		status.CopyNamespaces = copyNamespaces
+	}
+	return status
+}
+
+// reconcileCertificateExpiry records the expiry of each certificate in the Cryostat CR's status,
+// and warns the user of any certificates that are close to expiring.
+func (r *Reconciler) reconcileCertificateExpiry(ctx context.Context, cr *model.CryostatInstance,
+	certs []operatorv1beta2.CertificateStatus) error {
+	cr.Status.Certificates = certs
+
+	deadline := time.Now().Add(certExpiryWarningPeriod)
+	expiring := []operatorv1beta2.CertificateStatus{}
+	names := []string{}
+	for _, cert := range certs {
+		if cert.NotAfter != nil && cert.NotAfter.Time.Before(deadline) {
+			expiring = append(expiring, cert)
+			names = append(names, cert.Name)
+		}
+	}
+
+	if len(expiring) > 0 {
+		message := fmt.Sprintf("Certificates expired or expiring within %d days: %s.",
+			int(certExpiryWarningPeriod.Hours()/24), strings.Join(names, ", "))
+		// Only emit events when the expiring certificates change, rather than on every reconcile
+		previous := meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeCertificatesExpiringSoon))
+		if previous == nil || previous.Status != metav1.ConditionTrue || previous.Message != message {
+			r.Log.Info("Certificates are close to expiring", "certificates", strings.Join(names, ", "))
+			for _, cert := range expiring {
+				r.EventRecorder.Eventf(cr.Object, corev1.EventTypeWarning, eventCertExpiringSoonType,
+					"Certificate %s in namespace %s expires at %s", cert.Name, cert.Namespace,
+					cert.NotAfter.UTC().Format(time.RFC3339))
+			}
+		}
+		return r.updateCondition(ctx, cr, operatorv1beta2.ConditionTypeCertificatesExpiringSoon, metav1.ConditionTrue,
+			reasonCertsExpiringSoon, message)
+	}
+	return r.updateCondition(ctx, cr, operatorv1beta2.ConditionTypeCertificatesExpiringSoon, metav1.ConditionFalse,
+		reasonNoCertsExpiringSoon, "No certificates for Cryostat components are close to expiring.")
+}
+
+// getCertificateExpiryRequeueAfter returns the time until the next certificate enters the
+// expiry warning period, or zero if no certificates are yet to enter it
+func getCertificateExpiryRequeueAfter(cr *model.CryostatInstance) time.Duration {
+	var next time.Duration
+	for _, cert := range cr.Status.Certificates {
+		if cert.NotAfter == nil {
+			continue
+		}
+		remaining := time.Until(cert.NotAfter.Add(-certExpiryWarningPeriod))
+		if remaining > 0 && (next == 0 || remaining < next) {
+			next = remaining
+		}
+	}
+	return next
+}
@@ -21,8 +21,10 @@ import (
 	"strings"
 
 	certv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
 	"github.com/cryostatio/cryostat-operator/internal/controllers/common"
 	resources "github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/constants"
 	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
@@ -58,22 +60,34 @@ func (r *Reconciler) setupTLS(ctx context.Context, cr *model.CryostatInstance) (
 		return nil, err
 	}
 
-	// Create CA certificate for Cryostat using the self-signed issuer
-	caCert := resources.NewCryostatCACert(r.gvk, cr)
-	err = r.createOrUpdateCertificate(ctx, caCert, cr.Object)
+	// Determine which CAs should be issuing and trusted, advancing any CA rotation
+	caState, err := r.reconcileCARotation(ctx, cr)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create CA issuer using the CA cert just created
-	err = r.createOrUpdateIssuer(ctx, resources.NewCryostatCAIssuer(r.gvk, cr), cr.Object)
+	// Create CA certificates for Cryostat using the self-signed issuer
+	caCert := resources.NewCryostatCACertForRevision(r.gvk, cr, caState.issuing)
+	caCerts := []*certv1.Certificate{caCert}
+	if caState.trusted != nil {
+		caCerts = append(caCerts, resources.NewCryostatCACertForRevision(r.gvk, cr, *caState.trusted))
+	}
+	for _, cert := range caCerts {
+		err = r.createOrUpdateCertificate(ctx, cert, cr.Object)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Create CA issuer using the issuing CA cert just created
+	err = r.createOrUpdateIssuer(ctx, resources.NewCryostatCAIssuer(r.gvk, cr, caState.issuing), cr.Object)
 	if err != nil {
 		return nil, err
 	}
 
 	// Create secret to hold keystore password
 	keystoreSecret := newKeystoreSecret(cr)
-	err = r.createOrUpdateKeystoreSecret(ctx, keystoreSecret, cr.Object)
+	err = r.createOrUpdateKeystoreSecret(ctx, keystoreSecret, cr)
 	if err != nil {
 		return nil, err
 	}
@@ -92,6 +106,17 @@ func (r *Reconciler) setupTLS(ctx context.Context, cr *model.CryostatInstance) (
 		return nil, err
 	}
 
+	// Create a certificate for Grafana signed by the Cryostat CA, if it runs in its own deployment
+	grafanaCert := resources.NewGrafanaCert(cr)
+	if resources.IsGrafanaSeparate(cr) {
+		err = r.createOrUpdateCertificate(ctx, grafanaCert, cr.Object)
+	} else {
+		err = r.deleteCertWithSecret(ctx, grafanaCert)
+	}
+	if err != nil {
+		return nil, err
+	}
+
 	// Create a certificate for the Cryostat database signed by the Cryostat CA
 	databaseCert := resources.NewDatabaseCert(cr)
 	err = r.createOrUpdateCertificate(ctx, databaseCert, cr.Object)
@@ -106,18 +131,70 @@ func (r *Reconciler) setupTLS(ctx context.Context, cr *model.CryostatInstance) (
 		return nil, err
 	}
 
-	// Create a certificate for the agent proxy signed by the Cryostat CA
-	agentProxyCert := resources.NewAgentProxyCert(cr)
+	// Create a certificate for the agent proxy signed by the Cryostat CA,
+	// including any hosts used to reach it from outside the cluster
+	externalHosts, err := r.getAgentGatewayExternalHosts(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	agentProxyCert := resources.NewAgentProxyCert(cr, externalHosts)
 	err = r.createOrUpdateCertificate(ctx, agentProxyCert, cr.Object)
 	if err != nil {
 		return nil, err
 	}
 
+	// Create client certificates for agents running outside of the cluster
+	agentClientCerts, err := r.reconcileAgentClientCertificates(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+
 	// List of certificates whose secrets should be owned by this CR
-	certificates := []*certv1.Certificate{caCert, cryostatCert, reportsCert, agentProxyCert}
+	certificates := append([]*certv1.Certificate{}, caCerts...)
+	certificates = append(certificates, cryostatCert, reportsCert, agentProxyCert)
+	if resources.IsGrafanaSeparate(cr) {
+		certificates = append(certificates, grafanaCert)
+	}
+	certificates = append(certificates, agentClientCerts...)
+
+	// Status of all certificates, including where copies of their secrets are located
+	certStatuses := []operatorv1beta2.CertificateStatus{}
+	for _, cert := range caCerts {
+		certStatuses = append(certStatuses, newCertificateStatus(cert, caCopyNamespaces(cr)...))
+	}
+	certStatuses = append(certStatuses,
+		newCertificateStatus(cryostatCert),
+		newCertificateStatus(reportsCert),
+		newCertificateStatus(databaseCert),
+		newCertificateStatus(storageCert),
+		newCertificateStatus(agentProxyCert),
+	)
+	if resources.IsGrafanaSeparate(cr) {
+		certStatuses = append(certStatuses, newCertificateStatus(grafanaCert))
+	}
+	for _, agentClientCert := range agentClientCerts {
+		certStatuses = append(certStatuses, newCertificateStatus(agentClientCert))
+	}
+
+	// Get the Cryostat CA certificate bytes from each certificate secret,
+	// and combine them into a bundle of all trusted CAs
+	trustedCABytes := [][]byte{}
+	for _, cert := range caCerts {
+		caBytes, err := r.getCertficateBytes(ctx, cert)
+		if err != nil {
+			return nil, err
+		}
+		trustedCABytes = append(trustedCABytes, caBytes)
+	}
+	caBundle := newCABundle(trustedCABytes...)
+	caBundleSecret := newCABundleSecret(cr)
+	err = r.createOrUpdateCABundleSecret(ctx, caBundleSecret, cr, caBundle)
+	if err != nil {
+		return nil, err
+	}
 
-	// Get the Cryostat CA certificate bytes from certificate secret
-	caBytes, err := r.getCertficateBytes(ctx, caCert)
+	// Determine the TLS protocol versions and cipher suites for all components
+	tlsProfile, err := r.getTLSProfile(ctx, cr)
 	if err != nil {
 		return nil, err
 	}
@@ -127,23 +204,28 @@ func (r *Reconciler) setupTLS(ctx context.Context, cr *model.CryostatInstance) (
 		DatabaseSecret:     databaseCert.Spec.SecretName,
 		StorageSecret:      storageCert.Spec.SecretName,
 		ReportsSecret:      reportsCert.Spec.SecretName,
+		GrafanaSecret:      grafanaCert.Spec.SecretName,
 		AgentProxySecret:   agentProxyCert.Spec.SecretName,
 		KeystorePassSecret: cryostatCert.Spec.Keystores.PKCS12.PasswordSecretRef.Name,
-		CACert:             caBytes,
+		CACert:             caBundle,
+		CABundleSecret:     caBundleSecret.Name,
+		Profile:            tlsProfile,
 	}
 
+	// Copies of the CA in target namespaces keep the original name across rotations
+	caCopyName := resources.NewCryostatCACert(r.gvk, cr).Spec.SecretName
 	agentCertsNotReady := []string{}
 	for _, ns := range cr.TargetNamespaces {
-		// Copy Cryostat CA secret in each target namespace
+		// Copy Cryostat CA bundle in each target namespace
 		if ns != cr.InstallNamespace {
 			namespaceSecret := &corev1.Secret{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      caCert.Spec.SecretName,
+					Name:      caCopyName,
 					Namespace: ns,
 				},
 				Type: corev1.SecretTypeOpaque,
 			}
-			err = r.createOrUpdateCertSecret(ctx, namespaceSecret, caBytes,
+			err = r.createOrUpdateCertSecret(ctx, namespaceSecret, cr, caBundle,
 				common.LabelsForTargetNamespaceObject(cr))
 			if err != nil {
 				return nil, err
@@ -152,7 +234,7 @@ func (r *Reconciler) setupTLS(ctx context.Context, cr *model.CryostatInstance) (
 
 		// Create a certificate for Cryostat agents in each target namespace
 		agentCert := resources.NewAgentCert(cr, ns, r.gvk)
-		err := r.reconcileAgentCertificate(ctx, agentCert, cr, ns)
+		err := r.reconcileAgentCertificate(ctx, agentCert, cr, ns, caBundle)
 		if err != nil {
 			if err == common.ErrCertNotReady {
 				// Continue with other namespaces if the cert isn't ready
@@ -162,6 +244,11 @@ func (r *Reconciler) setupTLS(ctx context.Context, cr *model.CryostatInstance) (
 			}
 		}
 		certificates = append(certificates, agentCert)
+		if ns != cr.InstallNamespace {
+			certStatuses = append(certStatuses, newCertificateStatus(agentCert, ns))
+		} else {
+			certStatuses = append(certStatuses, newCertificateStatus(agentCert))
+		}
 	}
 
 	if len(agentCertsNotReady) > 0 {
@@ -182,7 +269,7 @@ func (r *Reconciler) setupTLS(ctx context.Context, cr *model.CryostatInstance) (
 		if ns != cr.InstallNamespace {
 			namespaceSecret := &corev1.Secret{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      caCert.Spec.SecretName,
+					Name:      caCopyName,
 					Namespace: ns,
 				},
 			}
@@ -216,9 +303,26 @@ func (r *Reconciler) setupTLS(ctx context.Context, cr *model.CryostatInstance) (
 		}
 	}
 
+	// Report the expiry of each certificate, and warn about any close to expiring
+	err = r.reconcileCertificateExpiry(ctx, cr, certStatuses)
+	if err != nil {
+		return nil, err
+	}
+
 	return tlsConfig, nil
 }
 
+// caCopyNamespaces returns the namespaces where a copy of the Cryostat CA certificate is stored
+func caCopyNamespaces(cr *model.CryostatInstance) []string {
+	namespaces := []string{}
+	for _, ns := range cr.TargetNamespaces {
+		if ns != cr.InstallNamespace {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
 func (r *Reconciler) finalizeTLS(ctx context.Context, cr *model.CryostatInstance) error {
 	caCert := resources.NewCryostatCACert(r.gvk, cr)
 	for _, ns := range cr.TargetNamespaces {
@@ -349,8 +453,9 @@ func (r *Reconciler) deleteCertChain(ctx context.Context, namespace string, caSe
 	}
 
 	for i, cert := range certs.Items {
-		// Is the certificate owned by this CR, and not the CA itself?
-		if metav1.IsControlledBy(&certs.Items[i], owner) && cert.Spec.SecretName != caSecretName {
+		// Is the certificate owned by this CR, and not the CA itself? Other CAs
+		// are retained while a CA rotation is in progress.
+		if metav1.IsControlledBy(&certs.Items[i], owner) && cert.Spec.SecretName != caSecretName && !cert.Spec.IsCA {
 			err := r.deleteCertWithSecret(ctx, &certs.Items[i])
 			if err != nil {
 				return err
@@ -382,7 +487,8 @@ func (r *Reconciler) deleteCertWithSecret(ctx context.Context, cert *certv1.Cert
 	return nil
 }
 
-func (r *Reconciler) reconcileAgentCertificate(ctx context.Context, cert *certv1.Certificate, cr *model.CryostatInstance, namespace string) error {
+func (r *Reconciler) reconcileAgentCertificate(ctx context.Context, cert *certv1.Certificate, cr *model.CryostatInstance, namespace string,
+	caBundle []byte) error {
 	// Create the Agent certificate in the install namespace
 	err := r.createOrUpdateCertificate(ctx, cert, cr.Object)
 	if err != nil {
@@ -403,9 +509,15 @@ func (r *Reconciler) reconcileAgentCertificate(ctx context.Context, cert *certv1
 			},
 		}
 		err = r.createOrUpdateSecret(ctx, targetSecret, nil, func() error {
+			mergeResourceMetadata(cr, &targetSecret.ObjectMeta)
 			common.MergeLabelsAndAnnotations(&targetSecret.ObjectMeta,
 				common.LabelsForTargetNamespaceObject(cr), map[string]string{})
-			targetSecret.Data = secret.Data
+			// Include all trusted CAs, so agents continue to trust Cryostat during a CA rotation
+			targetSecret.Data = make(map[string][]byte, len(secret.Data)+1)
+			for key, value := range secret.Data {
+				targetSecret.Data[key] = value
+			}
+			targetSecret.Data[constants.CAKey] = caBundle
 			return nil
 		})
 		if err != nil {
@@ -436,7 +548,13 @@ func (r *Reconciler) createOrUpdateCertificate(ctx context.Context, cert *certv1
 	})
 	if err != nil {
 		if err == errCertificateModified {
-			return r.recreateCertificate(ctx, certCopy, owner)
+			err = r.recreateCertificate(ctx, certCopy, owner)
+			if err != nil {
+				return err
+			}
+			// Reflect the recreated certificate, which is not yet ready, to the caller
+			certCopy.DeepCopyInto(cert)
+			return nil
 		}
 		return err
 	}
@@ -461,9 +579,10 @@ func newKeystoreSecret(cr *model.CryostatInstance) *corev1.Secret {
 	}
 }
 
-func (r *Reconciler) createOrUpdateKeystoreSecret(ctx context.Context, secret *corev1.Secret, owner metav1.Object) error {
+func (r *Reconciler) createOrUpdateKeystoreSecret(ctx context.Context, secret *corev1.Secret, cr *model.CryostatInstance) error {
 	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
-		if err := controllerutil.SetControllerReference(owner, secret, r.Scheme); err != nil {
+		mergeResourceMetadata(cr, &secret.ObjectMeta)
+		if err := controllerutil.SetControllerReference(cr.Object, secret, r.Scheme); err != nil {
 			return err
 		}
 
@@ -482,13 +601,15 @@ func (r *Reconciler) createOrUpdateKeystoreSecret(ctx context.Context, secret *c
 	return nil
 }
 
-func (r *Reconciler) createOrUpdateCertSecret(ctx context.Context, secret *corev1.Secret, cert []byte,
-	labels map[string]string) error {
+func (r *Reconciler) createOrUpdateCertSecret(ctx context.Context, secret *corev1.Secret, cr *model.CryostatInstance,
+	cert []byte, labels map[string]string) error {
 	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		mergeResourceMetadata(cr, &secret.ObjectMeta)
 		common.MergeLabelsAndAnnotations(&secret.ObjectMeta, labels, map[string]string{})
 		if secret.Data == nil {
 			secret.Data = map[string][]byte{}
 		}
+		// Always copy the current certificate, such as after the CA has been renewed
 		secret.Data[corev1.TLSCertKey] = cert
 		return nil
 	})
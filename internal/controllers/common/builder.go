@@ -26,6 +26,7 @@ import (
 // ControllerBuilder wraps controller-runtime's builder.Builder
 // as an interface to aid testing.
 type ControllerBuilder interface {
+	Named(name string) ControllerBuilder
 	For(object client.Object, opts ...builder.ForOption) ControllerBuilder
 	Owns(object client.Object, opts ...builder.OwnsOption) ControllerBuilder
 	Watches(object client.Object, eventHandler handler.EventHandler, opts ...builder.WatchesOption) ControllerBuilder
@@ -47,6 +48,12 @@ func NewControllerBuilder(mgr ctrl.Manager) ControllerBuilder {
 	}
 }
 
+// Named wraps the [builder.Builder.Named] method
+func (b *ctrlBuilder) Named(name string) ControllerBuilder {
+	b.impl = b.impl.Named(name)
+	return b
+}
+
 // For wraps the [builder.Builder.For] method
 func (b *ctrlBuilder) For(object client.Object, opts ...builder.ForOption) ControllerBuilder {
 	b.impl = b.impl.For(object, opts...)
@@ -32,6 +32,7 @@ import (
 	"github.com/cryostatio/cryostat-operator/internal/controllers/constants"
 	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -222,6 +223,9 @@ func AnnotateWithObjRefHashes(ctx context.Context, client client.Client, namespa
 	// Collect names of secrets and config maps used by this pod template
 	secrets := newObjectSet[string]()
 	configMaps := newObjectSet[string]()
+	// Optional references may point to objects that do not exist yet
+	optionalSecrets := newObjectSet[string]()
+	optionalConfigMaps := newObjectSet[string]()
 
 	// Look for secrets and config maps references in environment variables
 	for _, container := range template.Spec.Containers {
@@ -229,18 +233,19 @@ func AnnotateWithObjRefHashes(ctx context.Context, client client.Client, namespa
 		for _, env := range container.Env {
 			if env.ValueFrom != nil {
 				if env.ValueFrom.SecretKeyRef != nil {
-					secrets.add(env.ValueFrom.SecretKeyRef.Name)
+					addObjRef(secrets, optionalSecrets, env.ValueFrom.SecretKeyRef.Name, env.ValueFrom.SecretKeyRef.Optional)
 				} else if env.ValueFrom.ConfigMapKeyRef != nil {
-					configMaps.add(env.ValueFrom.ConfigMapKeyRef.Name)
+					addObjRef(configMaps, optionalConfigMaps, env.ValueFrom.ConfigMapKeyRef.Name,
+						env.ValueFrom.ConfigMapKeyRef.Optional)
 				}
 			}
 		}
 		// Look through EnvFrom for secret/config map refs
 		for _, envFrom := range container.EnvFrom {
 			if envFrom.SecretRef != nil {
-				secrets.add(envFrom.SecretRef.Name)
+				addObjRef(secrets, optionalSecrets, envFrom.SecretRef.Name, envFrom.SecretRef.Optional)
 			} else if envFrom.ConfigMapRef != nil {
-				configMaps.add(envFrom.ConfigMapRef.Name)
+				addObjRef(configMaps, optionalConfigMaps, envFrom.ConfigMapRef.Name, envFrom.ConfigMapRef.Optional)
 			}
 		}
 	}
@@ -249,28 +254,28 @@ func AnnotateWithObjRefHashes(ctx context.Context, client client.Client, namespa
 	for _, vol := range template.Spec.Volumes {
 		if vol.Secret != nil {
 			// Look for secret volumes
-			secrets.add(vol.Secret.SecretName)
+			addObjRef(secrets, optionalSecrets, vol.Secret.SecretName, vol.Secret.Optional)
 		} else if vol.ConfigMap != nil {
 			// Look for config map volumes
-			configMaps.add(vol.ConfigMap.Name)
+			addObjRef(configMaps, optionalConfigMaps, vol.ConfigMap.Name, vol.ConfigMap.Optional)
 		} else if vol.Projected != nil {
 			// Also look for secret/config map sources in projected volumes
 			for _, source := range vol.Projected.Sources {
 				if source.Secret != nil {
-					secrets.add(source.Secret.Name)
+					addObjRef(secrets, optionalSecrets, source.Secret.Name, source.Secret.Optional)
 				} else if source.ConfigMap != nil {
-					configMaps.add(source.ConfigMap.Name)
+					addObjRef(configMaps, optionalConfigMaps, source.ConfigMap.Name, source.ConfigMap.Optional)
 				}
 			}
 		}
 	}
 
 	// Hash the discovered secrets and config maps
-	secretHash, err := hashSecrets(ctx, client, namespace, secrets)
+	secretHash, err := hashSecrets(ctx, client, namespace, secrets, optionalSecrets)
 	if err != nil {
 		return err
 	}
-	configMapHash, err := hashConfigMaps(ctx, client, namespace, configMaps)
+	configMapHash, err := hashConfigMaps(ctx, client, namespace, configMaps, optionalConfigMaps)
 	if err != nil {
 		return err
 	}
@@ -281,13 +286,29 @@ func AnnotateWithObjRefHashes(ctx context.Context, client client.Client, namespa
 	return nil
 }
 
-func hashSecrets(ctx context.Context, client client.Client, namespace string, secrets *objectSet[string]) (*string, error) {
+// addObjRef adds the named object to the set of references. If every reference to the
+// object is optional, it is also added to the set of optional references.
+func addObjRef(refs *objectSet[string], optionalRefs *objectSet[string], name string, optional *bool) {
+	isOptional := optional != nil && *optional
+	if isOptional && !refs.contains(name) {
+		optionalRefs.add(name)
+	} else if !isOptional {
+		optionalRefs.remove(name)
+	}
+	refs.add(name)
+}
+
+func hashSecrets(ctx context.Context, client client.Client, namespace string, secrets *objectSet[string],
+	optionalSecrets *objectSet[string]) (*string, error) {
 	// Collect the JSON of all secret data, sorted by object name
 	combinedJSON := []byte{}
 	for _, name := range secrets.toSortedSlice() {
 		secret := &corev1.Secret{}
 		err := client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, secret)
 		if err != nil {
+			if kerrors.IsNotFound(err) && optionalSecrets.contains(name) {
+				continue
+			}
 			return nil, err
 		}
 		// Marshal secret data as JSON. Keys are sorted, see: [json.Marshal]
@@ -302,13 +323,17 @@ func hashSecrets(ctx context.Context, client client.Client, namespace string, se
 	return &hashed, nil
 }
 
-func hashConfigMaps(ctx context.Context, client client.Client, namespace string, configMaps *objectSet[string]) (*string, error) {
+func hashConfigMaps(ctx context.Context, client client.Client, namespace string, configMaps *objectSet[string],
+	optionalConfigMaps *objectSet[string]) (*string, error) {
 	// Collect the JSON of all config map data, sorted by object name
 	combinedJSON := []byte{}
 	for _, name := range configMaps.toSortedSlice() {
 		cm := &corev1.ConfigMap{}
 		err := client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, cm)
 		if err != nil {
+			if kerrors.IsNotFound(err) && optionalConfigMaps.contains(name) {
+				continue
+			}
 			return nil, err
 		}
 		// Marshal config map data as JSON. Keys are sorted, see: [json.Marshal]
@@ -356,6 +381,15 @@ func (s *objectSet[T]) add(obj T) {
 	s.impl[obj] = struct{}{}
 }
 
+func (s *objectSet[T]) remove(obj T) {
+	delete(s.impl, obj)
+}
+
+func (s *objectSet[T]) contains(obj T) bool {
+	_, ok := s.impl[obj]
+	return ok
+}
+
 func (s *objectSet[T]) toSortedSlice() []T {
 	// Convert set to a sorted slice
 	slice := make([]T, 0, len(s.impl))
@@ -30,3 +30,7 @@ func AgentGatewayServiceName(cr *model.CryostatInstance) string {
 func AgentCertificateName(gvk *schema.GroupVersionKind, cr *model.CryostatInstance, targetNamespace string) string {
 	return ClusterUniqueNameWithPrefixTargetNS(gvk, "agent", cr.Name, cr.InstallNamespace, targetNamespace)
 }
+
+func AgentClientCertificateName(cr *model.CryostatInstance, clientName string) string {
+	return cr.Name + "-agent-client-" + clientName
+}
@@ -0,0 +1,494 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource_definitions
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/common"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/constants"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StorageBuckets are the buckets created in Cryostat's object storage
+const StorageBuckets = "archivedrecordings,archivedreports,eventtemplates,probes"
+
+const (
+	backupWorkDir       = "/tmp/cryostat-backup"
+	backupLocationMount = "/backup"
+	backupBackoffLimit  = int32(2)
+)
+
+// BackupJobConfig contains the parameters used to connect a backup or restore Job
+// to the database and object storage of a Cryostat instance
+type BackupJobConfig struct {
+	// Image containing pg_dump, pg_restore and curl, normally the database image
+	ImageTag string
+	// Port of the database Service
+	DatabasePort int32
+	// URL of the object storage Service
+	StorageURL *url.URL
+	// Secret containing the CA certificates used to verify the database and object storage,
+	// or empty if TLS is disabled
+	CASecretName string
+	// fsGroup used when the Cryostat CR does not specify a pod security context
+	FSGroup int64
+	// Whether the operator is running on OpenShift
+	OpenShift bool
+}
+
+// BackupPodLabels are the labels of pods running backup and restore Jobs,
+// which are permitted to connect to the database and object storage
+func BackupPodLabels(cr *model.CryostatInstance) map[string]string {
+	return map[string]string{
+		"app":       cr.Name,
+		"kind":      "cryostat",
+		"component": "backup",
+	}
+}
+
+// NewBackupJob returns a Job that stores a backup archive of the Cryostat instance's
+// database and object storage in the destination of the CryostatBackup
+func NewBackupJob(cr *model.CryostatInstance, backup *operatorv1beta2.CryostatBackup, config *BackupJobConfig) *batchv1.Job {
+	envs := []corev1.EnvVar{
+		{
+			Name:  "ARCHIVE_NAME",
+			Value: backup.Status.ArchiveName,
+		},
+	}
+	envs = append(envs, newBackupLocationEnvs(&backup.Spec.Destination)...)
+	return newBackupJob(cr, backup.Name+"-backup", backup.Namespace, backupScript, envs,
+		&backup.Spec.Destination, config)
+}
+
+// NewRestoreJob returns a Job that restores the backup archive found in the source
+// into the Cryostat instance's database and object storage
+func NewRestoreJob(cr *model.CryostatInstance, restore *operatorv1beta2.CryostatRestore,
+	source *operatorv1beta2.BackupSource, config *BackupJobConfig) *batchv1.Job {
+	envs := []corev1.EnvVar{
+		{
+			Name:  "ARCHIVE_NAME",
+			Value: source.ArchiveName,
+		},
+		{
+			Name:  "EXPECTED_CHECKSUM",
+			Value: source.Checksum,
+		},
+	}
+	envs = append(envs, newBackupLocationEnvs(&source.BackupLocation)...)
+	return newBackupJob(cr, restore.Name+"-restore", restore.Namespace, restoreScript, envs,
+		&source.BackupLocation, config)
+}
+
+func newBackupJob(cr *model.CryostatInstance, name string, namespace string, script string,
+	locationEnvs []corev1.EnvVar, location *operatorv1beta2.BackupLocation, config *BackupJobConfig) *batchv1.Job {
+	optional := false
+	dbSecret := getDatabaseSecret(cr)
+	envs := []corev1.EnvVar{
+		{
+			Name:  "WORK_DIR",
+			Value: backupWorkDir,
+		},
+		{
+			Name:  "PGHOST",
+			Value: fmt.Sprintf("%s-database.%s.svc.cluster.local", cr.Name, cr.InstallNamespace),
+		},
+		{
+			Name:  "PGPORT",
+			Value: strconv.Itoa(int(config.DatabasePort)),
+		},
+		{
+			Name:  "PGUSER",
+			Value: "cryostat",
+		},
+		{
+			Name:  "PGDATABASE",
+			Value: DatabaseName,
+		},
+		{
+			Name: "PGPASSWORD",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: dbSecret,
+					},
+					Key:      constants.DatabaseSecretConnectionKey,
+					Optional: &optional,
+				},
+			},
+		},
+		{
+			Name:  "STORAGE_URL",
+			Value: config.StorageURL.String(),
+		},
+		{
+			Name:  "STORAGE_BUCKETS",
+			Value: StorageBuckets,
+		},
+		{
+			Name:  "STORAGE_ACCESS_KEY",
+			Value: "cryostat",
+		},
+		{
+			Name: "STORAGE_SECRET_KEY",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: cr.Name + "-storage",
+					},
+					Key:      "SECRET_KEY",
+					Optional: &optional,
+				},
+			},
+		},
+	}
+	envs = append(envs, locationEnvs...)
+
+	mounts := []corev1.VolumeMount{
+		{
+			Name:      "work",
+			MountPath: backupWorkDir,
+		},
+	}
+	volumes := []corev1.Volume{
+		{
+			Name: "work",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	}
+
+	if len(config.CASecretName) > 0 {
+		caPath := path.Join(SecretMountPrefix, config.CASecretName)
+		caFile := path.Join(caPath, constants.CAKey)
+		envs = append(envs,
+			corev1.EnvVar{
+				Name:  "PGSSLMODE",
+				Value: "verify-full",
+			},
+			corev1.EnvVar{
+				Name:  "PGSSLROOTCERT",
+				Value: caFile,
+			},
+			corev1.EnvVar{
+				Name:  "STORAGE_CA_FILE",
+				Value: caFile,
+			},
+		)
+		readOnlyMode := int32(0440)
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      "ca-bundle",
+			MountPath: caPath,
+			ReadOnly:  true,
+		})
+		volumes = append(volumes, corev1.Volume{
+			Name: "ca-bundle",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName:  config.CASecretName,
+					DefaultMode: &readOnlyMode,
+				},
+			},
+		})
+	}
+
+	if location.PVC != nil {
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      "backup-location",
+			MountPath: backupLocationMount,
+		})
+		volumes = append(volumes, corev1.Volume{
+			Name: "backup-location",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: location.PVC.ClaimName,
+				},
+			},
+		})
+	}
+
+	var dbSc *corev1.SecurityContext
+	if cr.Spec.SecurityOptions != nil {
+		dbSc = cr.Spec.SecurityOptions.DatabaseSecurityContext
+	}
+	containerSc, podSc := newJobSecurityContexts(cr, dbSc, config.FSGroup, config.OpenShift)
+
+	backoffLimit := backupBackoffLimit
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    BackupPodLabels(cr),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: BackupPodLabels(cr),
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:            "backup",
+							Image:           config.ImageTag,
+							ImagePullPolicy: common.GetPullPolicy(config.ImageTag),
+							Command:         []string{"/bin/bash", "-c", script},
+							Env:             envs,
+							VolumeMounts:    mounts,
+							SecurityContext: containerSc,
+						},
+					},
+					SecurityContext:  podSc,
+					Volumes:          volumes,
+					ImagePullSecrets: cr.Spec.ImagePullSecrets,
+				},
+			},
+		},
+	}
+}
+
+// newJobSecurityContexts returns the security contexts for a Job's container and pod,
+// preferring containerSc and any pod security context from the Cryostat CR
+func newJobSecurityContexts(cr *model.CryostatInstance, containerSc *corev1.SecurityContext,
+	fsGroup int64, openshift bool) (*corev1.SecurityContext, *corev1.PodSecurityContext) {
+	if containerSc == nil {
+		privEscalation := false
+		containerSc = &corev1.SecurityContext{
+			AllowPrivilegeEscalation: &privEscalation,
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{constants.CapabilityAll},
+			},
+		}
+	}
+
+	var podSc *corev1.PodSecurityContext
+	if cr.Spec.SecurityOptions != nil && cr.Spec.SecurityOptions.PodSecurityContext != nil {
+		podSc = cr.Spec.SecurityOptions.PodSecurityContext
+	} else {
+		nonRoot := true
+		podSc = &corev1.PodSecurityContext{
+			// Ensure PV mounts are writable
+			FSGroup:        &fsGroup,
+			RunAsNonRoot:   &nonRoot,
+			SeccompProfile: common.SeccompProfile(openshift),
+		}
+	}
+	return containerSc, podSc
+}
+
+func newBackupLocationEnvs(location *operatorv1beta2.BackupLocation) []corev1.EnvVar {
+	if location.PVC != nil {
+		return []corev1.EnvVar{
+			{
+				Name:  "LOCATION_DIR",
+				Value: path.Join(backupLocationMount, location.PVC.Path),
+			},
+		}
+	}
+	if location.S3 == nil {
+		return nil
+	}
+	region := location.S3.Region
+	if len(region) == 0 {
+		region = "us-east-1"
+	}
+	optional := false
+	return []corev1.EnvVar{
+		{
+			Name:  "LOCATION_S3_URL",
+			Value: location.S3.Endpoint + "/" + url.PathEscape(location.S3.Bucket),
+		},
+		{
+			Name:  "LOCATION_S3_PREFIX",
+			Value: location.S3.Prefix,
+		},
+		{
+			Name:  "LOCATION_S3_REGION",
+			Value: region,
+		},
+		{
+			Name: "AWS_ACCESS_KEY_ID",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: location.S3.SecretName,
+					},
+					Key:      "AWS_ACCESS_KEY_ID",
+					Optional: &optional,
+				},
+			},
+		},
+		{
+			Name: "AWS_SECRET_ACCESS_KEY",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: location.S3.SecretName,
+					},
+					Key:      "AWS_SECRET_ACCESS_KEY",
+					Optional: &optional,
+				},
+			},
+		},
+	}
+}
+
+// Functions shared by the backup and restore scripts. Object storage requests are
+// signed by curl, so no S3 client is needed in the image.
+const backupScriptCommon = `set -euo pipefail
+
+storage_curl() {
+	curl --silent --show-error --fail ${STORAGE_CA_FILE:+--cacert "${STORAGE_CA_FILE}"} \
+		--aws-sigv4 "aws:amz:us-east-1:s3" --user "${STORAGE_ACCESS_KEY}:${STORAGE_SECRET_KEY}" "$@"
+}
+
+location_curl() {
+	curl --silent --show-error --fail \
+		--aws-sigv4 "aws:amz:${LOCATION_S3_REGION}:s3" --user "${AWS_ACCESS_KEY_ID}:${AWS_SECRET_ACCESS_KEY}" "$@"
+}
+
+# Percent-encodes a string for use in a URL, leaving any characters in $2 unencoded
+urlencode() {
+	local LC_ALL=C s="$1" keep="${2:-}" out="" c i
+	for ((i = 0; i < ${#s}; i++)); do
+		c="${s:i:1}"
+		case "${c}" in
+			[a-zA-Z0-9.~_-]) out+="${c}" ;;
+			*)
+				if [[ "${keep}" == *"${c}"* ]]; then
+					out+="${c}"
+				else
+					out+=$(printf '%%%02X' "'${c}")
+				fi
+				;;
+		esac
+	done
+	printf '%s' "${out}"
+}
+
+xml_values() {
+	{ grep -o "<$1>[^<]*</$1>" || true; } | sed -e "s/^<$1>//" -e "s/<\/$1>$//" \
+		-e 's/&lt;/</g' -e 's/&gt;/>/g' -e 's/&quot;/"/g' -e "s/&apos;/'/g" -e 's/&amp;/\&/g'
+}
+
+# Lists the keys of all objects in a bucket of Cryostat's object storage
+list_keys() {
+	local bucket="$1" query listing token=""
+	while true; do
+		query="list-type=2"
+		if [ -n "${token}" ]; then
+			query="${query}&continuation-token=$(urlencode "${token}")"
+		fi
+		listing=$(storage_curl "${STORAGE_URL}/${bucket}?${query}")
+		printf '%s' "${listing}" | xml_values Key
+		token=$(printf '%s' "${listing}" | xml_values NextContinuationToken)
+		if [ -z "${token}" ]; then
+			break
+		fi
+	done
+}
+
+fail() {
+	echo "$1" | tee /dev/termination-log >&2
+	exit 1
+}
+`
+
+const backupScript = backupScriptCommon + `
+contents="${WORK_DIR}/contents"
+mkdir -p "${contents}/buckets"
+
+echo "Dumping database ${PGDATABASE}"
+pg_dump --format=custom --file="${contents}/database.dump" || fail "Failed to dump database"
+
+for bucket in ${STORAGE_BUCKETS//,/ }; do
+	echo "Copying bucket ${bucket}"
+	mkdir -p "${contents}/buckets/${bucket}"
+	list_keys "${bucket}" | while IFS= read -r key; do
+		file="${contents}/buckets/${bucket}/${key}"
+		mkdir -p "$(dirname "${file}")"
+		storage_curl --output "${file}" "${STORAGE_URL}/${bucket}/$(urlencode "${key}" /)"
+	done || fail "Failed to copy bucket ${bucket}"
+done
+
+if [ -n "${LOCATION_DIR:-}" ]; then
+	mkdir -p "${LOCATION_DIR}"
+	archive="${LOCATION_DIR}/${ARCHIVE_NAME}"
+else
+	archive="${WORK_DIR}/${ARCHIVE_NAME}"
+fi
+echo "Creating archive ${ARCHIVE_NAME}"
+tar -czf "${archive}" -C "${contents}" . || fail "Failed to create archive"
+checksum=$(sha256sum "${archive}" | cut -d ' ' -f 1)
+size=$(stat -c %s "${archive}")
+
+if [ -n "${LOCATION_S3_URL:-}" ]; then
+	echo "Uploading archive to ${LOCATION_S3_URL}"
+	location_curl --upload-file "${archive}" \
+		"${LOCATION_S3_URL}/$(urlencode "${LOCATION_S3_PREFIX}${ARCHIVE_NAME}" /)" || fail "Failed to upload archive"
+fi
+
+printf '{"size":%s,"checksum":"sha256:%s"}' "${size}" "${checksum}" > /dev/termination-log
+echo "Backup complete"
+`
+
+const restoreScript = backupScriptCommon + `
+contents="${WORK_DIR}/contents"
+mkdir -p "${contents}"
+
+if [ -n "${LOCATION_DIR:-}" ]; then
+	archive="${LOCATION_DIR}/${ARCHIVE_NAME}"
+	if [ ! -f "${archive}" ]; then
+		fail "Backup archive ${ARCHIVE_NAME} not found"
+	fi
+else
+	archive="${WORK_DIR}/${ARCHIVE_NAME}"
+	echo "Downloading archive from ${LOCATION_S3_URL}"
+	location_curl --output "${archive}" \
+		"${LOCATION_S3_URL}/$(urlencode "${LOCATION_S3_PREFIX}${ARCHIVE_NAME}" /)" || fail "Failed to download backup archive"
+fi
+
+if [ -n "${EXPECTED_CHECKSUM:-}" ]; then
+	checksum="sha256:$(sha256sum "${archive}" | cut -d ' ' -f 1)"
+	if [ "${checksum}" != "${EXPECTED_CHECKSUM}" ]; then
+		fail "Checksum of backup archive is ${checksum}, expected ${EXPECTED_CHECKSUM}"
+	fi
+fi
+tar -xzf "${archive}" -C "${contents}" || fail "Failed to extract backup archive"
+
+echo "Restoring database ${PGDATABASE}"
+pg_restore --clean --if-exists --no-owner --single-transaction --dbname="${PGDATABASE}" \
+	"${contents}/database.dump" || fail "Failed to restore database"
+
+for dir in "${contents}"/buckets/*/; do
+	bucket=$(basename "${dir}")
+	echo "Restoring bucket ${bucket}"
+	(cd "${dir}" && find . -type f) | while IFS= read -r file; do
+		key="${file#./}"
+		storage_curl --upload-file "${dir}${key}" "${STORAGE_URL}/${bucket}/$(urlencode "${key}" /)"
+	done || fail "Failed to restore bucket ${bucket}"
+done
+
+echo "Restore complete"
+`
@@ -16,6 +16,7 @@ package resource_definitions
 
 import (
 	"fmt"
+	"net"
 
 	certv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	certMeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
@@ -40,7 +41,8 @@ func NewSelfSignedIssuer(cr *model.CryostatInstance) *certv1.Issuer {
 	}
 }
 
-func NewCryostatCAIssuer(gvk *schema.GroupVersionKind, cr *model.CryostatInstance) *certv1.Issuer {
+// NewCryostatCAIssuer returns an Issuer that issues certificates using the given revision of the Cryostat CA
+func NewCryostatCAIssuer(gvk *schema.GroupVersionKind, cr *model.CryostatInstance, revision string) *certv1.Issuer {
 	return &certv1.Issuer{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cr.Name + "-ca",
@@ -49,7 +51,7 @@ func NewCryostatCAIssuer(gvk *schema.GroupVersionKind, cr *model.CryostatInstanc
 		Spec: certv1.IssuerSpec{
 			IssuerConfig: certv1.IssuerConfig{
 				CA: &certv1.CAIssuer{
-					SecretName: NewCryostatCACert(gvk, cr).Spec.SecretName,
+					SecretName: NewCryostatCACertForRevision(gvk, cr, revision).Spec.SecretName,
 				},
 			},
 		},
@@ -57,14 +59,29 @@ func NewCryostatCAIssuer(gvk *schema.GroupVersionKind, cr *model.CryostatInstanc
 }
 
 func NewCryostatCACert(gvk *schema.GroupVersionKind, cr *model.CryostatInstance) *certv1.Certificate {
+	return NewCryostatCACertForRevision(gvk, cr, "")
+}
+
+// NewCryostatCACertForRevision returns the Cryostat CA certificate for the given revision.
+// The empty revision refers to the original CA.
+func NewCryostatCACertForRevision(gvk *schema.GroupVersionKind, cr *model.CryostatInstance, revision string) *certv1.Certificate {
+	name := cr.Name + "-ca"
+	secretName := common.ClusterUniqueNameWithPrefix(gvk, "ca", cr.Name, cr.InstallNamespace)
+	commonName := constants.CryostatCATLSCommonName
+	if len(revision) > 0 {
+		// Use a distinct subject, so both CAs may be trusted at once during rotation
+		name += "-" + revision
+		secretName += "-" + revision
+		commonName += "-" + revision
+	}
 	return &certv1.Certificate{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      cr.Name + "-ca",
+			Name:      name,
 			Namespace: cr.InstallNamespace,
 		},
 		Spec: certv1.CertificateSpec{
-			CommonName: constants.CryostatCATLSCommonName,
-			SecretName: common.ClusterUniqueNameWithPrefix(gvk, "ca", cr.Name, cr.InstallNamespace),
+			CommonName: commonName,
+			SecretName: secretName,
 			IssuerRef: certMeta.ObjectReference{
 				Name: cr.Name + "-self-signed",
 			},
@@ -133,6 +150,32 @@ func NewReportsCert(cr *model.CryostatInstance) *certv1.Certificate {
 	}
 }
 
+func NewGrafanaCert(cr *model.CryostatInstance) *certv1.Certificate {
+	return &certv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name + "-grafana",
+			Namespace: cr.InstallNamespace,
+		},
+		Spec: certv1.CertificateSpec{
+			CommonName: constants.GrafanaTLSCommonName,
+			DNSNames: []string{
+				cr.Name + "-grafana",
+				fmt.Sprintf("%s-grafana.%s.svc", cr.Name, cr.InstallNamespace),
+				fmt.Sprintf("%s-grafana.%s.svc.cluster.local", cr.Name, cr.InstallNamespace),
+				// Grafana connects to the JFR datasource within the same pod
+				"localhost",
+			},
+			SecretName: cr.Name + "-grafana-tls",
+			IssuerRef: certMeta.ObjectReference{
+				Name: cr.Name + "-ca",
+			},
+			Usages: append(certv1.DefaultKeyUsages(),
+				certv1.UsageServerAuth,
+			),
+		},
+	}
+}
+
 func NewDatabaseCert(cr *model.CryostatInstance) *certv1.Certificate {
 	return &certv1.Certificate{
 		ObjectMeta: metav1.ObjectMeta{
@@ -207,21 +250,32 @@ func NewAgentCert(cr *model.CryostatInstance, namespace string, gvk *schema.Grou
 	}
 }
 
-func NewAgentProxyCert(cr *model.CryostatInstance) *certv1.Certificate {
+func NewAgentProxyCert(cr *model.CryostatInstance, externalHosts []string) *certv1.Certificate {
 	svcName := common.AgentGatewayServiceName(cr)
+	dnsNames := []string{
+		svcName,
+		fmt.Sprintf("%s.%s.svc", svcName, cr.InstallNamespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", svcName, cr.InstallNamespace),
+	}
+	// Add any hosts used to reach the agent gateway from outside the cluster
+	var ipAddresses []string
+	for _, host := range externalHosts {
+		if net.ParseIP(host) != nil {
+			ipAddresses = append(ipAddresses, host)
+		} else {
+			dnsNames = append(dnsNames, host)
+		}
+	}
 	return &certv1.Certificate{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cr.Name + "-agent-proxy",
 			Namespace: cr.InstallNamespace,
 		},
 		Spec: certv1.CertificateSpec{
-			CommonName: constants.AgentAuthProxyTLSCommonName,
-			DNSNames: []string{
-				svcName,
-				fmt.Sprintf("%s.%s.svc", svcName, cr.InstallNamespace),
-				fmt.Sprintf("%s.%s.svc.cluster.local", svcName, cr.InstallNamespace),
-			},
-			SecretName: cr.Name + "-agent-tls",
+			CommonName:  constants.AgentAuthProxyTLSCommonName,
+			DNSNames:    dnsNames,
+			IPAddresses: ipAddresses,
+			SecretName:  cr.Name + "-agent-tls",
 			IssuerRef: certMeta.ObjectReference{
 				Name: cr.Name + "-ca",
 			},
@@ -231,3 +285,27 @@ func NewAgentProxyCert(cr *model.CryostatInstance) *certv1.Certificate {
 		},
 	}
 }
+
+func NewAgentClientCert(cr *model.CryostatInstance, clientName string) *certv1.Certificate {
+	name := common.AgentClientCertificateName(cr, clientName)
+	return &certv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cr.InstallNamespace,
+			Labels: map[string]string{
+				"app":       cr.Name,
+				"component": "cryostat-agent-client",
+			},
+		},
+		Spec: certv1.CertificateSpec{
+			CommonName: clientName,
+			SecretName: name,
+			IssuerRef: certMeta.ObjectReference{
+				Name: cr.Name + "-ca",
+			},
+			Usages: append(certv1.DefaultKeyUsages(),
+				certv1.UsageClientAuth,
+			),
+		},
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource_definitions
+
+import (
+	"github.com/cryostatio/cryostat-operator/internal/controllers/common"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DatabaseVersionCheckContainerName is the name of the database pod's init container that
+// compares the PostgreSQL major version of the data with that of the database image
+const DatabaseVersionCheckContainerName = "check-data-version"
+
+const (
+	databaseDumpMount           = "/dump"
+	databaseUpgradeBackoffLimit = int32(0)
+)
+
+// Recovers the existing data if a database upgrade was interrupted after moving it aside, since
+// the upgrade Job cannot roll back if it is killed. Then reports both versions in the termination
+// message, and fails if the data was created by a different major version, rather than letting
+// PostgreSQL fail to start.
+const databaseVersionCheckScript = `set -euo pipefail
+for previous in "${PGDATA}"-[0-9]*; do
+	if [ -d "${previous}" ]; then
+		echo "Recovering database files from an interrupted upgrade in ${previous}" >&2
+		rm -rf "${PGDATA}"
+		mv "${previous}" "${PGDATA}"
+		break
+	fi
+done
+rm -rf "${PGDATA}-obsolete"
+image_version="$(postgres --version | sed -E 's/^[^0-9]*([0-9]+).*$/\1/')"
+data_version=""
+if [ -f "${PGDATA}/PG_VERSION" ]; then
+	data_version="$(cat "${PGDATA}/PG_VERSION")"
+fi
+printf '{"dataVersion":"%s","imageVersion":"%s"}' "${data_version}" "${image_version}" > /dev/termination-log
+if [ -n "${data_version}" ] && [ "${data_version}" != "${image_version}" ]; then
+	echo "Database files were created by PostgreSQL ${data_version}, which is incompatible with PostgreSQL ${image_version}" >&2
+	exit 1
+fi
+`
+
+// Starts the previous PostgreSQL version on the existing data, without network access,
+// and dumps all databases and roles. The existing data is kept until the restore succeeds.
+const databaseDumpScript = `set -euo pipefail
+previous="${PGDATA}-${FROM_VERSION}"
+if [ -d "${previous}" ]; then
+	# Recover the existing data if a previous attempt was interrupted
+	rm -rf "${PGDATA}"
+	mv "${previous}" "${PGDATA}"
+fi
+printf 'local all all trust\n' > /tmp/pg_hba.conf
+pg_ctl -D "${PGDATA}" -w start -o "-c listen_addresses='' -c unix_socket_directories=/tmp -c hba_file=/tmp/pg_hba.conf"
+# The bootstrap superuser always has OID 10
+psql -h /tmp -U "${POSTGRESQL_USER}" -d "${POSTGRESQL_DATABASE}" -Atc "SELECT rolname FROM pg_roles WHERE oid = 10" \
+	> "${DUMP_DIR}/superuser"
+pg_dumpall -h /tmp -U "$(cat "${DUMP_DIR}/superuser")" > "${DUMP_DIR}/dump.sql"
+pg_ctl -D "${PGDATA}" -w stop -m fast
+echo "Dumped PostgreSQL ${FROM_VERSION} data"
+`
+
+// Moves the existing data aside, and restores the dump into a new data directory created by the
+// new PostgreSQL version. If anything fails, the existing data is moved back. If the container is
+// killed instead, the database's version check moves it back. The existing data is renamed before it
+// is deleted, so that it is never recovered once the new data is complete.
+const databaseRestoreScript = `set -euo pipefail
+previous="${PGDATA}-${FROM_VERSION}"
+mv "${PGDATA}" "${previous}"
+rollback() {
+	pg_ctl -D "${PGDATA}" -w stop -m immediate > /dev/null 2>&1 || true
+	rm -rf "${PGDATA}"
+	mv "${previous}" "${PGDATA}"
+}
+trap rollback ERR
+superuser="$(cat "${DUMP_DIR}/superuser")"
+initdb -D "${PGDATA}" -U "${superuser}" --auth-local=trust --auth-host=scram-sha-256
+printf 'local all all trust\n' > /tmp/pg_hba.conf
+pg_ctl -D "${PGDATA}" -w start -o "-c listen_addresses='' -c unix_socket_directories=/tmp -c hba_file=/tmp/pg_hba.conf"
+# The superuser already exists in the new data directory
+psql -h /tmp -U "${superuser}" -d postgres -v ON_ERROR_STOP=1 -f <(grep -v -E "^CREATE ROLE \"?${superuser}\"?;" "${DUMP_DIR}/dump.sql")
+pg_ctl -D "${PGDATA}" -w stop -m fast
+trap - ERR
+mv "${previous}" "${PGDATA}-obsolete"
+rm -rf "${PGDATA}-obsolete"
+echo "Upgraded data from PostgreSQL ${FROM_VERSION} to ${TO_VERSION}"
+`
+
+// newDatabaseVersionCheckContainer returns an init container for the database pod,
+// that uses the same image, data volume and security context as the database container
+func newDatabaseVersionCheckContainer(container *corev1.Container) corev1.Container {
+	return corev1.Container{
+		Name:            DatabaseVersionCheckContainerName,
+		Image:           container.Image,
+		ImagePullPolicy: container.ImagePullPolicy,
+		Command:         []string{"/bin/bash", "-c", databaseVersionCheckScript},
+		Env:             newDatabaseDataEnvs(container),
+		VolumeMounts:    container.VolumeMounts[:1],
+		SecurityContext: container.SecurityContext,
+		Resources:       container.Resources,
+	}
+}
+
+// newDatabaseDataEnvs returns the environment variables of the database container that describe its data
+func newDatabaseDataEnvs(container *corev1.Container) []corev1.EnvVar {
+	envs := []corev1.EnvVar{}
+	for _, env := range container.Env {
+		if env.Name == "PGDATA" || env.Name == "POSTGRESQL_USER" || env.Name == "POSTGRESQL_DATABASE" {
+			envs = append(envs, env)
+		}
+	}
+	return envs
+}
+
+// NewDatabaseUpgradeJob returns a Job that upgrades the database's data from one PostgreSQL major version
+// to another, by dumping it with the previous database image and restoring it with the new database image.
+// The database must be scaled down first.
+func NewDatabaseUpgradeJob(cr *model.CryostatInstance, fromImage string, toImage string, fromVersion string,
+	toVersion string, fsGroup int64, openshift bool) *batchv1.Job {
+	dbContainer := NewDatabaseContainer(cr, toImage, nil)
+	var dbSc *corev1.SecurityContext
+	if cr.Spec.SecurityOptions != nil {
+		dbSc = cr.Spec.SecurityOptions.DatabaseSecurityContext
+	}
+	containerSc, podSc := newJobSecurityContexts(cr, dbSc, fsGroup, openshift)
+
+	envs := append(newDatabaseDataEnvs(&dbContainer),
+		corev1.EnvVar{
+			Name:  "DUMP_DIR",
+			Value: databaseDumpMount,
+		},
+		corev1.EnvVar{
+			Name:  "FROM_VERSION",
+			Value: fromVersion,
+		},
+		corev1.EnvVar{
+			Name:  "TO_VERSION",
+			Value: toVersion,
+		},
+	)
+	mounts := []corev1.VolumeMount{
+		dbContainer.VolumeMounts[0],
+		{
+			Name:      "dump",
+			MountPath: databaseDumpMount,
+		},
+	}
+	volumes := append(newVolumeForDatabase(cr), corev1.Volume{
+		Name: "dump",
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+
+	backoffLimit := databaseUpgradeBackoffLimit
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name + "-database-upgrade-" + toVersion,
+			Namespace: cr.InstallNamespace,
+			Labels:    DatabaseUpgradePodLabels(cr),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: DatabaseUpgradePodLabels(cr),
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					InitContainers: []corev1.Container{
+						{
+							Name:                     "dump",
+							Image:                    fromImage,
+							ImagePullPolicy:          common.GetPullPolicy(fromImage),
+							Command:                  []string{"/bin/bash", "-c", databaseDumpScript},
+							Env:                      envs,
+							VolumeMounts:             mounts,
+							SecurityContext:          containerSc,
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:                     "restore",
+							Image:                    toImage,
+							ImagePullPolicy:          common.GetPullPolicy(toImage),
+							Command:                  []string{"/bin/bash", "-c", databaseRestoreScript},
+							Env:                      envs,
+							VolumeMounts:             mounts,
+							SecurityContext:          containerSc,
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+						},
+					},
+					SecurityContext:  podSc,
+					Volumes:          volumes,
+					ImagePullSecrets: cr.Spec.ImagePullSecrets,
+				},
+			},
+		},
+	}
+}
+
+// DatabaseUpgradePodLabels are the labels of pods running Jobs that upgrade the database's data
+func DatabaseUpgradePodLabels(cr *model.CryostatInstance) map[string]string {
+	return map[string]string{
+		"app":       cr.Name,
+		"kind":      "cryostat",
+		"component": "database-upgrade",
+	}
+}
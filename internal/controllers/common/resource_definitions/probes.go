@@ -0,0 +1,132 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource_definitions
+
+import (
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// Upper bound for how much the default probe allowances are extended for small CPU requests
+	maxProbeScaleFactor int32 = 4
+	// Kubernetes default failure threshold for probes that do not set one
+	defaultProbeFailureThreshold int32 = 3
+)
+
+func getProbeOptions(cr *model.CryostatInstance) *operatorv1beta2.ProbeOptions {
+	if cr.Spec.ProbeOptions == nil {
+		return &operatorv1beta2.ProbeOptions{}
+	}
+	return cr.Spec.ProbeOptions
+}
+
+func getContainerProbeConfig(config *operatorv1beta2.PodProbeConfiguration) *operatorv1beta2.ContainerProbeConfiguration {
+	if config == nil {
+		return nil
+	}
+	return &config.ContainerProbeConfiguration
+}
+
+func getTerminationGracePeriod(config *operatorv1beta2.PodProbeConfiguration) *int64 {
+	if config == nil {
+		return nil
+	}
+	return config.TerminationGracePeriodSeconds
+}
+
+// getProbeScaleFactor returns how many times slower a container is expected to respond
+// than with its default CPU request, within [1, maxProbeScaleFactor]
+func getProbeScaleFactor(resources *corev1.ResourceRequirements, defaultCpu string) int32 {
+	baseline := resource.MustParse(defaultCpu)
+	requested := resources.Requests.Cpu().MilliValue()
+	if requested <= 0 {
+		return maxProbeScaleFactor
+	}
+	// Round up, so any request below the default gets more time
+	factor := (baseline.MilliValue() + requested - 1) / requested
+	if factor < 1 {
+		return 1
+	}
+	if factor > int64(maxProbeScaleFactor) {
+		return maxProbeScaleFactor
+	}
+	return int32(factor)
+}
+
+// applyProbeOptions adjusts the probes of a container for its CPU request, then applies
+// the probe settings from the Cryostat CR. The container's resources must already be populated.
+func applyProbeOptions(container *corev1.Container, config *operatorv1beta2.ContainerProbeConfiguration, defaultCpu string) {
+	// The database only has a readiness probe, which takes the place of the liveness probe
+	healthProbe := container.LivenessProbe
+	if healthProbe == nil {
+		healthProbe = container.ReadinessProbe
+	}
+
+	if config != nil && config.StartupProbe != nil {
+		if config.StartupProbe.Disabled {
+			container.StartupProbe = nil
+		} else if container.StartupProbe == nil && healthProbe != nil {
+			container.StartupProbe = &corev1.Probe{
+				ProbeHandler: *healthProbe.ProbeHandler.DeepCopy(),
+			}
+		}
+	}
+
+	// Containers with less CPU than they are tuned for respond and start more slowly
+	factor := getProbeScaleFactor(&container.Resources, defaultCpu)
+	if factor > 1 {
+		if healthProbe != nil {
+			healthProbe.TimeoutSeconds = factor
+		}
+		if container.StartupProbe != nil {
+			threshold := container.StartupProbe.FailureThreshold
+			if threshold == 0 {
+				threshold = defaultProbeFailureThreshold
+			}
+			container.StartupProbe.FailureThreshold = threshold * factor
+		}
+	}
+
+	if config == nil {
+		return
+	}
+	if healthProbe != nil {
+		applyProbeSettings(healthProbe, config.LivenessProbe)
+	}
+	if container.StartupProbe != nil && config.StartupProbe != nil {
+		applyProbeSettings(container.StartupProbe, &config.StartupProbe.ProbeSettings)
+	}
+}
+
+func applyProbeSettings(probe *corev1.Probe, settings *operatorv1beta2.ProbeSettings) {
+	if settings == nil {
+		return
+	}
+	if settings.InitialDelaySeconds != nil {
+		probe.InitialDelaySeconds = *settings.InitialDelaySeconds
+	}
+	if settings.PeriodSeconds != nil {
+		probe.PeriodSeconds = *settings.PeriodSeconds
+	}
+	if settings.TimeoutSeconds != nil {
+		probe.TimeoutSeconds = *settings.TimeoutSeconds
+	}
+	if settings.FailureThreshold != nil {
+		probe.FailureThreshold = *settings.FailureThreshold
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource_definitions
+
+import (
+	"github.com/cryostatio/cryostat-operator/internal/controllers/common"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	pvcMigrationSourceMount  = "/source"
+	pvcMigrationTargetMount  = "/target"
+	pvcMigrationBackoffLimit = int32(2)
+)
+
+// Copies the contents of the source volume, preserving permissions and timestamps
+const pvcMigrationScript = `set -euo pipefail
+cp -a "${SOURCE_DIR}/." "${TARGET_DIR}/"
+echo "Copied $(du -sh "${TARGET_DIR}" | cut -f1) to the new Persistent Volume Claim"
+`
+
+// PersistentVolumeClaimName returns the name of the Persistent Volume Claim mounted by the
+// database or object storage component, which changes once its data is migrated to a new claim
+func PersistentVolumeClaimName(cr *model.CryostatInstance, component string) string {
+	if cr.Status != nil {
+		for _, claim := range cr.Status.PersistentVolumeClaims {
+			if claim.Component == component && len(claim.ClaimName) > 0 {
+				return claim.ClaimName
+			}
+		}
+	}
+	return cr.Name + "-" + component
+}
+
+// PVCMigrationPodLabels are the labels of pods running Jobs that migrate
+// a component's data to a new Persistent Volume Claim
+func PVCMigrationPodLabels(cr *model.CryostatInstance, component string) map[string]string {
+	return map[string]string{
+		"app":       cr.Name,
+		"kind":      "cryostat",
+		"component": component + "-migration",
+	}
+}
+
+// NewPVCMigrationJob returns a Job that copies a component's data from its current
+// Persistent Volume Claim to the target claim. The component must be scaled down first.
+func NewPVCMigrationJob(cr *model.CryostatInstance, component string, sourceClaim string, targetClaim string,
+	imageTag string, fsGroup int64, openshift bool) *batchv1.Job {
+	var componentSc *corev1.SecurityContext
+	if cr.Spec.SecurityOptions != nil {
+		if component == "database" {
+			componentSc = cr.Spec.SecurityOptions.DatabaseSecurityContext
+		} else {
+			componentSc = cr.Spec.SecurityOptions.StorageSecurityContext
+		}
+	}
+	containerSc, podSc := newJobSecurityContexts(cr, componentSc, fsGroup, openshift)
+
+	backoffLimit := pvcMigrationBackoffLimit
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      targetClaim + "-migration",
+			Namespace: cr.InstallNamespace,
+			Labels:    PVCMigrationPodLabels(cr, component),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: PVCMigrationPodLabels(cr, component),
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:            "migration",
+							Image:           imageTag,
+							ImagePullPolicy: common.GetPullPolicy(imageTag),
+							Command:         []string{"/bin/bash", "-c", pvcMigrationScript},
+							// Report why the copy failed in the Cryostat's condition
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+							Env: []corev1.EnvVar{
+								{
+									Name:  "SOURCE_DIR",
+									Value: pvcMigrationSourceMount,
+								},
+								{
+									Name:  "TARGET_DIR",
+									Value: pvcMigrationTargetMount,
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "source",
+									MountPath: pvcMigrationSourceMount,
+									ReadOnly:  true,
+								},
+								{
+									Name:      "target",
+									MountPath: pvcMigrationTargetMount,
+								},
+							},
+							SecurityContext: containerSc,
+						},
+					},
+					SecurityContext:  podSc,
+					ImagePullSecrets: cr.Spec.ImagePullSecrets,
+					Volumes: []corev1.Volume{
+						{
+							Name: "source",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: sourceClaim,
+									ReadOnly:  true,
+								},
+							},
+						},
+						{
+							Name: "target",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: targetClaim,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
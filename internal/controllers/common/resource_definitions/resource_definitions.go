@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"net/url"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -29,6 +30,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	authzv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -54,6 +56,22 @@ type ServiceSpecs struct {
 	InsightsURL  *url.URL
 	StorageURL   *url.URL
 	DatabaseURL  *url.URL
+	// Only set when Grafana runs in a separate deployment
+	GrafanaURL    *url.URL
+	DatasourceURL *url.URL
+}
+
+// GrafanaConfig contains Grafana configuration resolved from the Cryostat CR
+type GrafanaConfig struct {
+	// Names of the Config Maps containing dashboards to provision in Grafana
+	DashboardConfigMaps []string
+	// Name of the Config Map containing the Grafana provisioning file generated by the operator
+	// to load dashboards, or empty if there are none
+	ProvisioningConfigMap string
+	// Name of the Secret containing the Grafana provisioning file generated by the operator
+	// to add datasources, or empty if there are none. A Secret is used, since the datasources
+	// may include credentials.
+	DatasourcesSecret string
 }
 
 // TLSConfig contains TLS-related information useful when creating other objects
@@ -62,6 +80,8 @@ type TLSConfig struct {
 	CryostatSecret string
 	// Name of the TLS secret for Reports Generator
 	ReportsSecret string
+	// Name of the TLS secret for Grafana, when it runs in a separate deployment
+	GrafanaSecret string
 	// Name of the TLS secret for Database
 	DatabaseSecret string
 	// Name of the TLS secret for Storage
@@ -70,8 +90,12 @@ type TLSConfig struct {
 	AgentProxySecret string
 	// Name of the secret containing the password for the keystore in CryostatSecret
 	KeystorePassSecret string
-	// PEM-encoded X.509 certificate for the Cryostat CA
+	// Name of the secret containing the bundle of trusted Cryostat CA certificates
+	CABundleSecret string
+	// PEM-encoded X.509 certificates for the trusted Cryostat CAs
 	CACert []byte
+	// TLS protocol versions and cipher suites to be used by all components
+	Profile *common.TLSProfile
 }
 
 const (
@@ -115,6 +139,59 @@ func createMetadataCopy(in *operatorv1beta2.ResourceMetadata) operatorv1beta2.Re
 	}
 }
 
+// newComponentMetadata returns copies of the user-defined deployment and pod metadata for a component.
+// Metadata configured for the component takes precedence over metadata configured for all components.
+func newComponentMetadata(cr *model.CryostatInstance, component string) *operatorv1beta2.ComponentMetadata {
+	deploymentMeta := operatorv1beta2.ResourceMetadata{}
+	podMeta := operatorv1beta2.ResourceMetadata{}
+	if cr.Spec.OperandMetadata != nil {
+		deploymentMeta = createMetadataCopy(cr.Spec.OperandMetadata.DeploymentMetadata)
+		podMeta = createMetadataCopy(cr.Spec.OperandMetadata.PodMetadata)
+
+		componentMeta := getComponentMetadata(cr.Spec.OperandMetadata, component)
+		if componentMeta != nil {
+			mergeMetadata(&deploymentMeta, componentMeta.DeploymentMetadata)
+			mergeMetadata(&podMeta, componentMeta.PodMetadata)
+		}
+	}
+	return &operatorv1beta2.ComponentMetadata{
+		DeploymentMetadata: &deploymentMeta,
+		PodMetadata:        &podMeta,
+	}
+}
+
+func getComponentMetadata(meta *operatorv1beta2.OperandMetadata, component string) *operatorv1beta2.ComponentMetadata {
+	switch component {
+	case "core":
+		return meta.Core
+	case "reports":
+		return meta.Reports
+	case "database":
+		return meta.Database
+	case "storage":
+		return meta.Storage
+	}
+	return nil
+}
+
+func mergeMetadata(dest *operatorv1beta2.ResourceMetadata, src *operatorv1beta2.ResourceMetadata) {
+	if src == nil {
+		return
+	}
+	if dest.Labels == nil {
+		dest.Labels = map[string]string{}
+	}
+	if dest.Annotations == nil {
+		dest.Annotations = map[string]string{}
+	}
+	for k, v := range src.Labels {
+		dest.Labels[k] = v
+	}
+	for k, v := range src.Annotations {
+		dest.Annotations[k] = v
+	}
+}
+
 func CorePodLabels(cr *model.CryostatInstance) map[string]string {
 	return map[string]string{
 		"app":       cr.Name,
@@ -124,7 +201,7 @@ func CorePodLabels(cr *model.CryostatInstance) map[string]string {
 }
 
 func NewDeploymentForCR(cr *model.CryostatInstance, specs *ServiceSpecs, imageTags *ImageTags,
-	tls *TLSConfig, fsGroup int64, openshift bool) (*appsv1.Deployment, error) {
+	tls *TLSConfig, grafana *GrafanaConfig, fsGroup int64, openshift bool) (*appsv1.Deployment, error) {
 	// Force one replica to avoid lock file and PVC contention
 	replicas := int32(1)
 
@@ -138,18 +215,7 @@ func NewDeploymentForCR(cr *model.CryostatInstance, specs *ServiceSpecs, imageTa
 		"app.openshift.io/connects-to": constants.OperatorDeploymentName,
 	}
 	defaultPodLabels := CorePodLabels(cr)
-	operandMeta := operatorv1beta2.OperandMetadata{
-		DeploymentMetadata: &operatorv1beta2.ResourceMetadata{},
-		PodMetadata:        &operatorv1beta2.ResourceMetadata{},
-	}
-	if cr.Spec.OperandMetadata != nil && cr.Spec.OperandMetadata.DeploymentMetadata != nil {
-		deploymentCopy := createMetadataCopy(cr.Spec.OperandMetadata.DeploymentMetadata)
-		operandMeta.DeploymentMetadata = &deploymentCopy
-	}
-	if cr.Spec.OperandMetadata != nil && cr.Spec.OperandMetadata.PodMetadata != nil {
-		podCopy := createMetadataCopy(cr.Spec.OperandMetadata.PodMetadata)
-		operandMeta.PodMetadata = &podCopy
-	}
+	operandMeta := newComponentMetadata(cr, "core")
 
 	// First set the user defined labels and annotation in the meta, so that the default ones can override them
 	deploymentMeta := metav1.ObjectMeta{
@@ -168,7 +234,7 @@ func NewDeploymentForCR(cr *model.CryostatInstance, specs *ServiceSpecs, imageTa
 	}
 	common.MergeLabelsAndAnnotations(&podTemplateMeta, defaultPodLabels, nil)
 
-	pod, err := NewPodForCR(cr, specs, imageTags, tls, fsGroup, openshift)
+	pod, err := NewPodForCR(cr, specs, imageTags, tls, grafana, fsGroup, openshift)
 	if err != nil {
 		return nil, err
 	}
@@ -217,18 +283,7 @@ func NewDeploymentForDatabase(cr *model.CryostatInstance, imageTags *ImageTags,
 		"app.openshift.io/connects-to": cr.Name,
 	}
 	defaultPodLabels := DatabasePodLabels(cr)
-	operandMeta := operatorv1beta2.OperandMetadata{
-		DeploymentMetadata: &operatorv1beta2.ResourceMetadata{},
-		PodMetadata:        &operatorv1beta2.ResourceMetadata{},
-	}
-	if cr.Spec.OperandMetadata != nil && cr.Spec.OperandMetadata.DeploymentMetadata != nil {
-		deploymentCopy := createMetadataCopy(cr.Spec.OperandMetadata.DeploymentMetadata)
-		operandMeta.DeploymentMetadata = &deploymentCopy
-	}
-	if cr.Spec.OperandMetadata != nil && cr.Spec.OperandMetadata.PodMetadata != nil {
-		podCopy := createMetadataCopy(cr.Spec.OperandMetadata.PodMetadata)
-		operandMeta.PodMetadata = &podCopy
-	}
+	operandMeta := newComponentMetadata(cr, "database")
 
 	// First set the user defined labels and annotation in the meta, so that the default ones can override them
 	deploymentMeta := metav1.ObjectMeta{
@@ -291,18 +346,7 @@ func NewDeploymentForStorage(cr *model.CryostatInstance, imageTags *ImageTags, t
 		"app.openshift.io/connects-to": cr.Name,
 	}
 	defaultPodLabels := StoragePodLabels(cr)
-	operandMeta := operatorv1beta2.OperandMetadata{
-		DeploymentMetadata: &operatorv1beta2.ResourceMetadata{},
-		PodMetadata:        &operatorv1beta2.ResourceMetadata{},
-	}
-	if cr.Spec.OperandMetadata != nil && cr.Spec.OperandMetadata.DeploymentMetadata != nil {
-		deploymentCopy := createMetadataCopy(cr.Spec.OperandMetadata.DeploymentMetadata)
-		operandMeta.DeploymentMetadata = &deploymentCopy
-	}
-	if cr.Spec.OperandMetadata != nil && cr.Spec.OperandMetadata.PodMetadata != nil {
-		podCopy := createMetadataCopy(cr.Spec.OperandMetadata.PodMetadata)
-		operandMeta.PodMetadata = &podCopy
-	}
+	operandMeta := newComponentMetadata(cr, "storage")
 
 	// First set the user defined labels and annotation in the meta, so that the default ones can override them
 	deploymentMeta := metav1.ObjectMeta{
@@ -369,18 +413,7 @@ func NewDeploymentForReports(cr *model.CryostatInstance, imageTags *ImageTags, t
 		"app.openshift.io/connects-to": cr.Name,
 	}
 	defaultPodLabels := ReportsPodLabels(cr)
-	operandMeta := operatorv1beta2.OperandMetadata{
-		DeploymentMetadata: &operatorv1beta2.ResourceMetadata{},
-		PodMetadata:        &operatorv1beta2.ResourceMetadata{},
-	}
-	if cr.Spec.OperandMetadata != nil && cr.Spec.OperandMetadata.DeploymentMetadata != nil {
-		deploymentCopy := createMetadataCopy(cr.Spec.OperandMetadata.DeploymentMetadata)
-		operandMeta.DeploymentMetadata = &deploymentCopy
-	}
-	if cr.Spec.OperandMetadata != nil && cr.Spec.OperandMetadata.PodMetadata != nil {
-		podCopy := createMetadataCopy(cr.Spec.OperandMetadata.PodMetadata)
-		operandMeta.PodMetadata = &podCopy
-	}
+	operandMeta := newComponentMetadata(cr, "reports")
 
 	// First set the user defined labels and annotation in the meta, so that the default ones can override them
 	deploymentMeta := metav1.ObjectMeta{
@@ -419,19 +452,90 @@ func NewDeploymentForReports(cr *model.CryostatInstance, imageTags *ImageTags, t
 	}
 }
 
+func GrafanaPodLabels(cr *model.CryostatInstance) map[string]string {
+	return map[string]string{
+		"app":       cr.Name,
+		"kind":      "cryostat",
+		"component": "grafana",
+	}
+}
+
+func NewDeploymentForGrafana(cr *model.CryostatInstance, imageTags *ImageTags, tls *TLSConfig,
+	grafana *GrafanaConfig, openshift bool) *appsv1.Deployment {
+	// The JFR datasource keeps uploaded recordings in memory, so Grafana must query the same replica
+	replicas := int32(1)
+
+	defaultDeploymentLabels := map[string]string{
+		"app":                    cr.Name,
+		"kind":                   "cryostat",
+		"component":              "grafana",
+		"app.kubernetes.io/name": "cryostat-grafana",
+	}
+	defaultDeploymentAnnotations := map[string]string{
+		"app.openshift.io/connects-to": cr.Name,
+	}
+	defaultPodLabels := GrafanaPodLabels(cr)
+	operandMeta := newComponentMetadata(cr, "grafana")
+
+	// First set the user defined labels and annotation in the meta, so that the default ones can override them
+	deploymentMeta := metav1.ObjectMeta{
+		Name:        cr.Name + "-grafana",
+		Namespace:   cr.InstallNamespace,
+		Labels:      operandMeta.DeploymentMetadata.Labels,
+		Annotations: operandMeta.DeploymentMetadata.Annotations,
+	}
+	common.MergeLabelsAndAnnotations(&deploymentMeta, defaultDeploymentLabels, defaultDeploymentAnnotations)
+
+	podTemplateMeta := metav1.ObjectMeta{
+		Name:        cr.Name + "-grafana",
+		Namespace:   cr.InstallNamespace,
+		Labels:      operandMeta.PodMetadata.Labels,
+		Annotations: operandMeta.PodMetadata.Annotations,
+	}
+	common.MergeLabelsAndAnnotations(&podTemplateMeta, defaultPodLabels, nil)
+
+	return &appsv1.Deployment{
+		ObjectMeta: deploymentMeta,
+		Spec: appsv1.DeploymentSpec{
+			// Selector is immutable, avoid modifying if possible
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app":       cr.Name,
+					"kind":      "cryostat",
+					"component": "grafana",
+				},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: podTemplateMeta,
+				Spec:       *NewPodForGrafana(cr, imageTags, tls, grafana, openshift),
+			},
+			Replicas: &replicas,
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RecreateDeploymentStrategyType,
+			},
+		},
+	}
+}
+
 func NewPodForCR(cr *model.CryostatInstance, specs *ServiceSpecs, imageTags *ImageTags,
-	tls *TLSConfig, fsGroup int64, openshift bool) (*corev1.PodSpec, error) {
+	tls *TLSConfig, grafana *GrafanaConfig, fsGroup int64, openshift bool) (*corev1.PodSpec, error) {
 	authProxy, err := NewAuthProxyContainer(cr, specs, imageTags.OAuth2ProxyImageTag, imageTags.OpenShiftOAuthProxyImageTag, tls, openshift)
 	if err != nil {
 		return nil, err
 	}
 	containers := []corev1.Container{
 		NewCoreContainer(cr, specs, imageTags.CoreImageTag, tls, openshift),
-		NewGrafanaContainer(cr, imageTags.GrafanaImageTag, tls),
-		NewJfrDatasourceContainer(cr, imageTags.DatasourceImageTag),
+	}
+	if hasGrafanaSidecars(cr) {
+		containers = append(containers,
+			NewGrafanaContainer(cr, imageTags.GrafanaImageTag, tls, grafana),
+			NewJfrDatasourceContainer(cr, imageTags.DatasourceImageTag, tls),
+		)
+	}
+	containers = append(containers,
 		*authProxy,
 		newAgentProxyContainer(cr, imageTags.AgentProxyImageTag, tls),
-	}
+	)
 
 	volumes := []corev1.Volume{}
 	volSources := []corev1.VolumeProjection{}
@@ -464,10 +568,10 @@ func NewPodForCR(cr *model.CryostatInstance, specs *ServiceSpecs, imageTags *Ima
 
 	if tls != nil {
 		volSources = append(volSources, corev1.VolumeProjection{
-			// Add Cryostat self-signed CA
+			// Add Cryostat self-signed CA, including any CA being rotated to or from
 			Secret: &corev1.SecretProjection{
 				LocalObjectReference: corev1.LocalObjectReference{
-					Name: tls.CryostatSecret,
+					Name: tls.CABundleSecret,
 				},
 				Items: []corev1.KeyToPath{
 					{
@@ -498,6 +602,15 @@ func NewPodForCR(cr *model.CryostatInstance, specs *ServiceSpecs, imageTags *Ima
 					},
 				},
 			},
+			corev1.Volume{
+				Name: "ca-bundle",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName:  tls.CABundleSecret,
+						DefaultMode: &readOnlyMode,
+					},
+				},
+			},
 			corev1.Volume{
 				Name: "keystore",
 				VolumeSource: corev1.VolumeSource{
@@ -644,6 +757,11 @@ func NewPodForCR(cr *model.CryostatInstance, specs *ServiceSpecs, imageTags *Ima
 		volumes = append(volumes, eventTemplateVolume)
 	}
 
+	// Add any Grafana dashboards and provisioning files as volumes
+	if hasGrafanaSidecars(cr) {
+		volumes = append(volumes, newGrafanaVolumes(grafana)...)
+	}
+
 	var podSc *corev1.PodSecurityContext
 	if cr.Spec.SecurityOptions != nil && cr.Spec.SecurityOptions.PodSecurityContext != nil {
 		podSc = cr.Spec.SecurityOptions.PodSecurityContext
@@ -657,38 +775,24 @@ func NewPodForCR(cr *model.CryostatInstance, specs *ServiceSpecs, imageTags *Ima
 		}
 	}
 
-	var nodeSelector map[string]string
-	var affinity *corev1.Affinity
-	var tolerations []corev1.Toleration
-
-	if cr.Spec.SchedulingOptions != nil {
-		nodeSelector = cr.Spec.SchedulingOptions.NodeSelector
-
-		if cr.Spec.SchedulingOptions.Affinity != nil {
-			affinity = &corev1.Affinity{
-				NodeAffinity:    cr.Spec.SchedulingOptions.Affinity.NodeAffinity,
-				PodAffinity:     cr.Spec.SchedulingOptions.Affinity.PodAffinity,
-				PodAntiAffinity: cr.Spec.SchedulingOptions.Affinity.PodAntiAffinity,
-			}
-		}
-		tolerations = cr.Spec.SchedulingOptions.Tolerations
-	}
-
 	automountSAToken := true
-	return &corev1.PodSpec{
-		ServiceAccountName:           cr.Name,
-		Volumes:                      volumes,
-		Containers:                   containers,
-		SecurityContext:              podSc,
-		AutomountServiceAccountToken: &automountSAToken,
-		NodeSelector:                 nodeSelector,
-		Affinity:                     affinity,
-		Tolerations:                  tolerations,
-	}, nil
+	podSpec := &corev1.PodSpec{
+		ServiceAccountName:            cr.Name,
+		Volumes:                       volumes,
+		Containers:                    containers,
+		SecurityContext:               podSc,
+		AutomountServiceAccountToken:  &automountSAToken,
+		ImagePullSecrets:              cr.Spec.ImagePullSecrets,
+		TerminationGracePeriodSeconds: getTerminationGracePeriod(getProbeOptions(cr).Core),
+	}
+	applySchedulingOptions(podSpec, cr.Spec.SchedulingOptions)
+	return podSpec, nil
 }
 
 func NewPodForDatabase(cr *model.CryostatInstance, imageTags *ImageTags, tls *TLSConfig, openshift bool, fsGroup int64) *corev1.PodSpec {
 	container := []corev1.Container{NewDatabaseContainer(cr, imageTags.DatabaseImageTag, tls)}
+	// Detect data from an incompatible PostgreSQL version before the database starts
+	initContainers := []corev1.Container{newDatabaseVersionCheckContainer(&container[0])}
 
 	volumes := newVolumeForDatabase(cr)
 
@@ -719,31 +823,16 @@ func NewPodForDatabase(cr *model.CryostatInstance, imageTags *ImageTags, tls *TL
 		}
 	}
 
-	var nodeSelector map[string]string
-	var affinity *corev1.Affinity
-	var tolerations []corev1.Toleration
-
-	if cr.Spec.SchedulingOptions != nil {
-		nodeSelector = cr.Spec.SchedulingOptions.NodeSelector
-
-		if cr.Spec.SchedulingOptions.Affinity != nil {
-			affinity = &corev1.Affinity{
-				NodeAffinity:    cr.Spec.SchedulingOptions.Affinity.NodeAffinity,
-				PodAffinity:     cr.Spec.SchedulingOptions.Affinity.PodAffinity,
-				PodAntiAffinity: cr.Spec.SchedulingOptions.Affinity.PodAntiAffinity,
-			}
-		}
-		tolerations = cr.Spec.SchedulingOptions.Tolerations
-	}
-
-	return &corev1.PodSpec{
-		Containers:      container,
-		NodeSelector:    nodeSelector,
-		Affinity:        affinity,
-		Tolerations:     tolerations,
-		SecurityContext: podSc,
-		Volumes:         volumes,
+	podSpec := &corev1.PodSpec{
+		InitContainers:                initContainers,
+		Containers:                    container,
+		SecurityContext:               podSc,
+		Volumes:                       volumes,
+		ImagePullSecrets:              cr.Spec.ImagePullSecrets,
+		TerminationGracePeriodSeconds: getTerminationGracePeriod(getProbeOptions(cr).Database),
 	}
+	applySchedulingOptions(podSpec, getDatabaseSchedulingOptions(cr))
+	return podSpec
 }
 
 func NewPodForStorage(cr *model.CryostatInstance, imageTags *ImageTags, tls *TLSConfig, openshift bool, fsGroup int64) *corev1.PodSpec {
@@ -778,31 +867,15 @@ func NewPodForStorage(cr *model.CryostatInstance, imageTags *ImageTags, tls *TLS
 		}
 	}
 
-	var nodeSelector map[string]string
-	var affinity *corev1.Affinity
-	var tolerations []corev1.Toleration
-
-	if cr.Spec.SchedulingOptions != nil {
-		nodeSelector = cr.Spec.SchedulingOptions.NodeSelector
-
-		if cr.Spec.SchedulingOptions.Affinity != nil {
-			affinity = &corev1.Affinity{
-				NodeAffinity:    cr.Spec.SchedulingOptions.Affinity.NodeAffinity,
-				PodAffinity:     cr.Spec.SchedulingOptions.Affinity.PodAffinity,
-				PodAntiAffinity: cr.Spec.SchedulingOptions.Affinity.PodAntiAffinity,
-			}
-		}
-		tolerations = cr.Spec.SchedulingOptions.Tolerations
-	}
-
-	return &corev1.PodSpec{
-		Containers:      container,
-		NodeSelector:    nodeSelector,
-		Affinity:        affinity,
-		Tolerations:     tolerations,
-		SecurityContext: podSc,
-		Volumes:         volumes,
+	podSpec := &corev1.PodSpec{
+		Containers:                    container,
+		SecurityContext:               podSc,
+		Volumes:                       volumes,
+		ImagePullSecrets:              cr.Spec.ImagePullSecrets,
+		TerminationGracePeriodSeconds: getTerminationGracePeriod(getProbeOptions(cr).Storage),
 	}
+	applySchedulingOptions(podSpec, getStorageSchedulingOptions(cr))
+	return podSpec
 }
 
 func NewReportContainerResource(cr *model.CryostatInstance) *corev1.ResourceRequirements {
@@ -858,6 +931,7 @@ func NewPodForReports(cr *model.CryostatInstance, imageTags *ImageTags, tls *TLS
 				Value: "disabled",
 			},
 		}
+		tlsEnvs = append(tlsEnvs, newQuarkusTLSProfileEnvs("QUARKUS_HTTP_SSL", tls.Profile)...)
 
 		tlsSecretMount := corev1.VolumeMount{
 			Name:      "reports-tls-secret",
@@ -920,24 +994,12 @@ func NewPodForReports(cr *model.CryostatInstance, imageTags *ImageTags, tls *TLS
 		}
 	}
 
-	var nodeSelector map[string]string
-	var affinity *corev1.Affinity
-	var tolerations []corev1.Toleration
-
-	if cr.Spec.ReportOptions != nil && cr.Spec.ReportOptions.SchedulingOptions != nil {
-		schedulingOptions := cr.Spec.ReportOptions.SchedulingOptions
-		nodeSelector = schedulingOptions.NodeSelector
-		if schedulingOptions.Affinity != nil {
-			affinity = &corev1.Affinity{
-				NodeAffinity:    schedulingOptions.Affinity.NodeAffinity,
-				PodAffinity:     schedulingOptions.Affinity.PodAffinity,
-				PodAntiAffinity: schedulingOptions.Affinity.PodAntiAffinity,
-			}
-		}
-		tolerations = schedulingOptions.Tolerations
+	var schedulingOptions *operatorv1beta2.SchedulingConfiguration
+	if cr.Spec.ReportOptions != nil {
+		schedulingOptions = cr.Spec.ReportOptions.SchedulingOptions
 	}
 
-	return &corev1.PodSpec{
+	podSpec := &corev1.PodSpec{
 		Containers: []corev1.Container{
 			{
 				Name:            cr.Name + "-reports",
@@ -960,12 +1022,162 @@ func NewPodForReports(cr *model.CryostatInstance, imageTags *ImageTags, tls *TLS
 				SecurityContext: containerSc,
 			},
 		},
-		Volumes:         volumes,
-		NodeSelector:    nodeSelector,
-		Affinity:        affinity,
-		Tolerations:     tolerations,
-		SecurityContext: podSc,
+		Volumes:                       volumes,
+		SecurityContext:               podSc,
+		ImagePullSecrets:              cr.Spec.ImagePullSecrets,
+		TerminationGracePeriodSeconds: getTerminationGracePeriod(getProbeOptions(cr).Reports),
+	}
+	applyProbeOptions(&podSpec.Containers[0], getContainerProbeConfig(getProbeOptions(cr).Reports), defaultReportCpuRequest)
+	applySchedulingOptions(podSpec, schedulingOptions)
+	return podSpec
+}
+
+func NewPodForGrafana(cr *model.CryostatInstance, imageTags *ImageTags, tls *TLSConfig,
+	grafana *GrafanaConfig, openshift bool) *corev1.PodSpec {
+	volumes := newGrafanaVolumes(grafana)
+	if tls != nil {
+		readOnlyMode := int32(0440)
+		volumes = append(volumes,
+			corev1.Volume{
+				Name: "grafana-tls-secret",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName:  tls.GrafanaSecret,
+						DefaultMode: &readOnlyMode,
+					},
+				},
+			},
+			corev1.Volume{
+				Name: "ca-bundle",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName:  tls.CABundleSecret,
+						DefaultMode: &readOnlyMode,
+					},
+				},
+			},
+		)
+	}
+
+	var podSc *corev1.PodSecurityContext
+	if cr.Spec.SecurityOptions != nil && cr.Spec.SecurityOptions.PodSecurityContext != nil {
+		podSc = cr.Spec.SecurityOptions.PodSecurityContext
+	} else {
+		nonRoot := true
+		podSc = &corev1.PodSecurityContext{
+			RunAsNonRoot:   &nonRoot,
+			SeccompProfile: common.SeccompProfile(openshift),
+		}
+	}
+
+	podSpec := &corev1.PodSpec{
+		Containers: []corev1.Container{
+			NewGrafanaContainer(cr, imageTags.GrafanaImageTag, tls, grafana),
+			NewJfrDatasourceContainer(cr, imageTags.DatasourceImageTag, tls),
+		},
+		Volumes:                       volumes,
+		SecurityContext:               podSc,
+		ImagePullSecrets:              cr.Spec.ImagePullSecrets,
+		TerminationGracePeriodSeconds: getTerminationGracePeriod(getProbeOptions(cr).Grafana),
+	}
+	applySchedulingOptions(podSpec, getGrafanaSchedulingOptions(cr))
+	return podSpec
+}
+
+// getGrafanaDashboardsVolumeName returns the name of the volume for the dashboard Config Map at index i.
+// Config Map names are not always valid volume names, which are limited to DNS labels.
+func getGrafanaDashboardsVolumeName(i int) string {
+	return fmt.Sprintf("dashboards-%d", i)
+}
+
+// newGrafanaVolumes returns volumes for the user-provided Grafana dashboards and the provisioning files that load them
+func newGrafanaVolumes(grafana *GrafanaConfig) []corev1.Volume {
+	volumes := []corev1.Volume{}
+	if grafana == nil {
+		return volumes
+	}
+	readOnlyMode := int32(0440)
+	// Config Maps named in the Cryostat CR may not exist yet, and are loaded once created
+	optional := true
+	for i, dashboards := range grafana.DashboardConfigMaps {
+		volumes = append(volumes, corev1.Volume{
+			Name: getGrafanaDashboardsVolumeName(i),
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: dashboards,
+					},
+					DefaultMode: &readOnlyMode,
+					Optional:    &optional,
+				},
+			},
+		})
+	}
+	if len(grafana.ProvisioningConfigMap) > 0 {
+		volumes = append(volumes, corev1.Volume{
+			Name: "grafana-provisioning",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: grafana.ProvisioningConfigMap,
+					},
+					DefaultMode: &readOnlyMode,
+				},
+			},
+		})
+	}
+	if len(grafana.DatasourcesSecret) > 0 {
+		volumes = append(volumes, corev1.Volume{
+			Name: "grafana-datasources",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName:  grafana.DatasourcesSecret,
+					DefaultMode: &readOnlyMode,
+				},
+			},
+		})
+	}
+	return volumes
+}
+
+// applySchedulingOptions configures where and how the pod is scheduled
+func applySchedulingOptions(podSpec *corev1.PodSpec, options *operatorv1beta2.SchedulingConfiguration) {
+	if options == nil {
+		return
+	}
+	podSpec.NodeSelector = options.NodeSelector
+	if options.Affinity != nil {
+		podSpec.Affinity = &corev1.Affinity{
+			NodeAffinity:    options.Affinity.NodeAffinity,
+			PodAffinity:     options.Affinity.PodAffinity,
+			PodAntiAffinity: options.Affinity.PodAntiAffinity,
+		}
+	}
+	podSpec.Tolerations = options.Tolerations
+	podSpec.TopologySpreadConstraints = options.TopologySpreadConstraints
+	podSpec.PriorityClassName = options.PriorityClassName
+	podSpec.RuntimeClassName = options.RuntimeClassName
+}
+
+func getDatabaseSchedulingOptions(cr *model.CryostatInstance) *operatorv1beta2.SchedulingConfiguration {
+	if cr.Spec.DatabaseOptions != nil && cr.Spec.DatabaseOptions.SchedulingOptions != nil {
+		return cr.Spec.DatabaseOptions.SchedulingOptions
 	}
+	return cr.Spec.SchedulingOptions
+}
+
+func getGrafanaSchedulingOptions(cr *model.CryostatInstance) *operatorv1beta2.SchedulingConfiguration {
+	if cr.Spec.GrafanaOptions != nil && cr.Spec.GrafanaOptions.SchedulingOptions != nil {
+		return cr.Spec.GrafanaOptions.SchedulingOptions
+	}
+	return cr.Spec.SchedulingOptions
+}
+
+func getStorageSchedulingOptions(cr *model.CryostatInstance) *operatorv1beta2.SchedulingConfiguration {
+	if cr.Spec.ObjectStorageOptions != nil && cr.Spec.ObjectStorageOptions.SchedulingOptions != nil {
+		return cr.Spec.ObjectStorageOptions.SchedulingOptions
+	}
+	return cr.Spec.SchedulingOptions
 }
 
 func NewAuthProxyContainerResource(cr *model.CryostatInstance) *corev1.ResourceRequirements {
@@ -1005,11 +1217,27 @@ func NewOpenShiftAuthProxyContainer(cr *model.CryostatInstance, specs *ServiceSp
 		"--pass-user-bearer-token=false",
 		"--pass-basic-auth=false",
 		fmt.Sprintf("--upstream=http://localhost:%d/", constants.CryostatHTTPContainerPort),
-		fmt.Sprintf("--upstream=http://localhost:%d/grafana/", constants.GrafanaContainerPort),
+	}
+	volumeMounts := []corev1.VolumeMount{}
+	if specs.GrafanaURL != nil {
+		args = append(args, fmt.Sprintf("--upstream=%s/grafana/", specs.GrafanaURL.String()))
+		if tls != nil {
+			// Trust the Cryostat CA that issued Grafana's certificate
+			args = append(args, fmt.Sprintf("--upstream-ca=%s", path.Join(SecretMountPrefix, tls.CABundleSecret, constants.CAKey)))
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{
+				Name:      "ca-bundle",
+				MountPath: path.Join(SecretMountPrefix, tls.CABundleSecret),
+				ReadOnly:  true,
+			})
+		}
+	} else if !IsGrafanaDisabled(cr) {
+		args = append(args, fmt.Sprintf("--upstream=http://localhost:%d/grafana/", constants.GrafanaContainerPort))
+	}
+	args = append(args,
 		fmt.Sprintf("--openshift-service-account=%s", cr.Name),
 		"--proxy-websockets=true",
 		"--proxy-prefix=/oauth2",
-	}
+	)
 	if isOpenShiftAuthProxyDisabled(cr) {
 		args = append(args, "--bypass-auth-for=.*")
 	} else {
@@ -1030,8 +1258,6 @@ func NewOpenShiftAuthProxyContainer(cr *model.CryostatInstance, specs *ServiceSp
 	}
 	args = append(args, fmt.Sprintf("--openshift-delegate-urls=%s", string(tokenReviewJson)))
 
-	volumeMounts := []corev1.VolumeMount{}
-
 	if isBasicAuthEnabled(cr) {
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
 			Name:      cr.Name + "-auth-proxy-htpasswd",
@@ -1052,6 +1278,13 @@ func NewOpenShiftAuthProxyContainer(cr *model.CryostatInstance, specs *ServiceSp
 			fmt.Sprintf("--tls-cert=%s", path.Join(SecretMountPrefix, tls.CryostatSecret, corev1.TLSCertKey)),
 			fmt.Sprintf("--tls-key=%s", path.Join(SecretMountPrefix, tls.CryostatSecret, corev1.TLSPrivateKeyKey)),
 		)
+		if tls.Profile != nil {
+			// The proxy accepts Go's names for TLS versions, such as "VersionTLS12"
+			args = append(args, "--tls-min-version=VersionTLS"+strings.ReplaceAll(tls.Profile.MinVersionNumber(), ".", ""))
+			for _, cipher := range tls.Profile.GoCiphers() {
+				args = append(args, "--tls-cipher-suite="+cipher)
+			}
+		}
 
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
 			Name:      "auth-proxy-tls-secret",
@@ -1068,7 +1301,7 @@ func NewOpenShiftAuthProxyContainer(cr *model.CryostatInstance, specs *ServiceSp
 	}
 
 	cookieOptional := false
-	return &corev1.Container{
+	container := &corev1.Container{
 		Name:            cr.Name + "-auth-proxy",
 		Image:           imageTag,
 		ImagePullPolicy: common.GetPullPolicy(imageTag),
@@ -1100,7 +1333,9 @@ func NewOpenShiftAuthProxyContainer(cr *model.CryostatInstance, specs *ServiceSp
 		},
 		SecurityContext: containerSc,
 		Args:            args,
-	}, nil
+	}
+	applyProbeOptions(container, getProbeOptions(cr).AuthProxy, defaultAuthProxyCpuRequest)
+	return container, nil
 }
 
 func isOpenShiftAuthProxyDisabled(cr *model.CryostatInstance) bool {
@@ -1172,6 +1407,19 @@ func NewOAuth2ProxyContainer(cr *model.CryostatInstance, specs *ServiceSpecs, im
 		})
 
 		livenessProbeScheme = corev1.URISchemeHTTPS
+
+		if specs.GrafanaURL != nil {
+			// Trust the Cryostat CA that issued Grafana's certificate, in addition to the system CAs
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{
+				Name:      "ca-bundle",
+				MountPath: path.Join(SecretMountPrefix, tls.CABundleSecret),
+				ReadOnly:  true,
+			})
+			envs = append(envs, corev1.EnvVar{
+				Name:  "SSL_CERT_DIR",
+				Value: path.Join(SecretMountPrefix, tls.CABundleSecret),
+			})
+		}
 	}
 
 	if isBasicAuthEnabled(cr) {
@@ -1202,7 +1450,7 @@ func NewOAuth2ProxyContainer(cr *model.CryostatInstance, specs *ServiceSpecs, im
 	}
 
 	cookieOptional := false
-	return &corev1.Container{
+	container := &corev1.Container{
 		Name:            cr.Name + "-auth-proxy",
 		Image:           imageTag,
 		ImagePullPolicy: common.GetPullPolicy(imageTag),
@@ -1237,7 +1485,9 @@ func NewOAuth2ProxyContainer(cr *model.CryostatInstance, specs *ServiceSpecs, im
 		Args: []string{
 			fmt.Sprintf("--alpha-config=%s", path.Join(OAuth2ConfigFilePath, OAuth2ConfigFileName)),
 		},
-	}, nil
+	}
+	applyProbeOptions(container, getProbeOptions(cr).AuthProxy, defaultAuthProxyCpuRequest)
+	return container, nil
 }
 
 func NewCoreContainerResource(cr *model.CryostatInstance) *corev1.ResourceRequirements {
@@ -1476,13 +1726,23 @@ func NewCoreContainer(cr *model.CryostatInstance, specs *ServiceSpecs, imageTag
 	}...,
 	)
 
-	grafanaVars := []corev1.EnvVar{
-		{
+	// Grafana and the JFR datasource are reached through their service, if they run in their own deployment
+	grafanaDatasourceURL := datasourceURL
+	if specs.DatasourceURL != nil {
+		grafanaDatasourceURL = specs.DatasourceURL.String()
+	}
+	grafanaDashboardURL := getInternalDashboardURL()
+	if specs.GrafanaURL != nil {
+		grafanaDashboardURL = specs.GrafanaURL.String()
+	}
+	grafanaVars := []corev1.EnvVar{}
+	if !IsGrafanaDisabled(cr) {
+		grafanaVars = append(grafanaVars, corev1.EnvVar{
 			Name:  "GRAFANA_DATASOURCE_URL",
-			Value: datasourceURL,
-		},
+			Value: grafanaDatasourceURL,
+		})
 	}
-	if specs.AuthProxyURL != nil {
+	if specs.AuthProxyURL != nil && !IsGrafanaDisabled(cr) {
 		grafanaVars = append(grafanaVars,
 			corev1.EnvVar{
 				Name:  "GRAFANA_DASHBOARD_EXT_URL",
@@ -1490,7 +1750,7 @@ func NewCoreContainer(cr *model.CryostatInstance, specs *ServiceSpecs, imageTag
 			},
 			corev1.EnvVar{
 				Name:  "GRAFANA_DASHBOARD_URL",
-				Value: getInternalDashboardURL(),
+				Value: grafanaDashboardURL,
 			},
 		)
 	}
@@ -1538,6 +1798,8 @@ func NewCoreContainer(cr *model.CryostatInstance, specs *ServiceSpecs, imageTag
 			Name:  "QUARKUS_DATASOURCE_JDBC_URL",
 			Value: fmt.Sprintf("jdbc:postgresql://%s-database.%s.svc.cluster.local:5432/cryostat?ssl=true&sslmode=verify-full&sslcert=&sslrootcert=%s/%s", cr.Name, cr.InstallNamespace, tlsPath, constants.CAKey),
 		})
+		// Apply the TLS profile to Cryostat's connections to other components
+		envs = append(envs, newQuarkusTLSProfileEnvs("QUARKUS_TLS", tls.Profile)...)
 	} else {
 		envs = append(envs, corev1.EnvVar{
 			Name:  "QUARKUS_DATASOURCE_JDBC_URL",
@@ -1545,6 +1807,17 @@ func NewCoreContainer(cr *model.CryostatInstance, specs *ServiceSpecs, imageTag
 		})
 	}
 
+	// Add any environment variables requested in the Cryostat CR, unless already set above
+	var envFrom []corev1.EnvFromSource
+	if cr.Spec.CoreOptions != nil {
+		for _, env := range newCoreUserEnvs(cr) {
+			if findEnvVar(envs, env.Name) == nil {
+				envs = append(envs, env)
+			}
+		}
+		envFrom = cr.Spec.CoreOptions.EnvFrom
+	}
+
 	probeHandler := corev1.ProbeHandler{
 		HTTPGet: &corev1.HTTPGetAction{
 			Port:   intstr.IntOrString{IntVal: constants.CryostatHTTPContainerPort},
@@ -1566,7 +1839,7 @@ func NewCoreContainer(cr *model.CryostatInstance, specs *ServiceSpecs, imageTag
 		}
 	}
 
-	return corev1.Container{
+	container := corev1.Container{
 		Name:            cr.Name,
 		Image:           imageTag,
 		ImagePullPolicy: common.GetPullPolicy(imageTag),
@@ -1577,17 +1850,67 @@ func NewCoreContainer(cr *model.CryostatInstance, specs *ServiceSpecs, imageTag
 			},
 		},
 		Env:       envs,
+		EnvFrom:   envFrom,
 		Resources: *NewCoreContainerResource(cr),
 		LivenessProbe: &corev1.Probe{
 			ProbeHandler: probeHandler,
 		},
-		// Expect probe to succeed within 3 minutes
+		// Expect probe to succeed within 3 minutes with the default CPU request
 		StartupProbe: &corev1.Probe{
 			ProbeHandler:     probeHandler,
 			FailureThreshold: 18,
 		},
 		SecurityContext: containerSc,
 	}
+	applyProbeOptions(&container, getContainerProbeConfig(getProbeOptions(cr).Core), defaultCoreCpuRequest)
+	return container
+}
+
+// newCoreUserEnvs returns the environment variables for the Cryostat application container
+// that were requested in the Cryostat CR
+func newCoreUserEnvs(cr *model.CryostatInstance) []corev1.EnvVar {
+	envs := []corev1.EnvVar{}
+	if cr.Spec.CoreOptions == nil {
+		return envs
+	}
+	if len(cr.Spec.CoreOptions.JavaOptions) > 0 {
+		envs = append(envs, corev1.EnvVar{
+			Name:  "JAVA_OPTS_APPEND",
+			Value: cr.Spec.CoreOptions.JavaOptions,
+		})
+	}
+	return append(envs, cr.Spec.CoreOptions.Env...)
+}
+
+// GetCoreEnvConflicts returns the names of environment variables requested in the Cryostat CR
+// that were not added to the Cryostat application container, because the operator already sets them
+func GetCoreEnvConflicts(cr *model.CryostatInstance, podSpec *corev1.PodSpec) []string {
+	conflicts := []string{}
+	var container *corev1.Container
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name == cr.Name {
+			container = &podSpec.Containers[i]
+		}
+	}
+	if container == nil {
+		return conflicts
+	}
+	for _, env := range newCoreUserEnvs(cr) {
+		existing := findEnvVar(container.Env, env.Name)
+		if existing != nil && !equality.Semantic.DeepEqual(*existing, env) {
+			conflicts = append(conflicts, env.Name)
+		}
+	}
+	return conflicts
+}
+
+func findEnvVar(envs []corev1.EnvVar, name string) *corev1.EnvVar {
+	for i := range envs {
+		if envs[i].Name == name {
+			return &envs[i]
+		}
+	}
+	return nil
 }
 
 func NewGrafanaContainerResource(cr *model.CryostatInstance) *corev1.ResourceRequirements {
@@ -1599,7 +1922,7 @@ func NewGrafanaContainerResource(cr *model.CryostatInstance) *corev1.ResourceReq
 	return resources
 }
 
-func NewGrafanaContainer(cr *model.CryostatInstance, imageTag string, tls *TLSConfig) corev1.Container {
+func NewGrafanaContainer(cr *model.CryostatInstance, imageTag string, tls *TLSConfig, grafana *GrafanaConfig) corev1.Container {
 	envs := []corev1.EnvVar{
 		{
 			Name:  "GF_AUTH_ANONYMOUS_ENABLED",
@@ -1615,13 +1938,93 @@ func NewGrafanaContainer(cr *model.CryostatInstance, imageTag string, tls *TLSCo
 		},
 		{
 			Name:  "JFR_DATASOURCE_URL",
-			Value: datasourceURL,
+			Value: getGrafanaDatasourceURL(cr, tls),
 		},
 		{
 			Name:  "GF_SERVER_ROOT_URL",
 			Value: fmt.Sprintf("%s://localhost:%d/grafana/", "http", constants.AuthProxyHttpContainerPort),
 		},
 	}
+	mounts := []corev1.VolumeMount{}
+
+	// In its own deployment, Grafana serves HTTPS using its certificate, and trusts the Cryostat CA
+	// for connections to the JFR datasource
+	livenessProbeScheme := corev1.URISchemeHTTP
+	if IsGrafanaSeparate(cr) && tls != nil {
+		envs = append(envs,
+			corev1.EnvVar{
+				Name:  "GF_SERVER_PROTOCOL",
+				Value: "https",
+			},
+			corev1.EnvVar{
+				Name:  "GF_SERVER_CERT_FILE",
+				Value: path.Join(SecretMountPrefix, tls.GrafanaSecret, corev1.TLSCertKey),
+			},
+			corev1.EnvVar{
+				Name:  "GF_SERVER_CERT_KEY",
+				Value: path.Join(SecretMountPrefix, tls.GrafanaSecret, corev1.TLSPrivateKeyKey),
+			},
+			corev1.EnvVar{
+				Name:  "SSL_CERT_DIR",
+				Value: path.Join(SecretMountPrefix, tls.CABundleSecret),
+			},
+		)
+		// Grafana only supports a minimum version of TLS 1.2 or above
+		if tls.Profile != nil && (tls.Profile.MinTLSVersion == operatorv1beta2.VersionTLS12 ||
+			tls.Profile.MinTLSVersion == operatorv1beta2.VersionTLS13) {
+			envs = append(envs, corev1.EnvVar{
+				Name:  "GF_SERVER_MIN_TLS_VERSION",
+				Value: "TLS" + tls.Profile.MinVersionNumber(),
+			})
+		}
+		mounts = append(mounts,
+			corev1.VolumeMount{
+				Name:      "grafana-tls-secret",
+				MountPath: path.Join(SecretMountPrefix, tls.GrafanaSecret),
+				ReadOnly:  true,
+			},
+			corev1.VolumeMount{
+				Name:      "ca-bundle",
+				MountPath: path.Join(SecretMountPrefix, tls.CABundleSecret),
+				ReadOnly:  true,
+			},
+		)
+		livenessProbeScheme = corev1.URISchemeHTTPS
+	}
+
+	// Add Grafana settings from the Cryostat CR, unless already set above
+	for _, env := range newGrafanaUserEnvs(cr) {
+		if findEnvVar(envs, env.Name) == nil {
+			envs = append(envs, env)
+		}
+	}
+
+	// Mount user-provided dashboards, and the provisioning files that load them into Grafana
+	if grafana != nil {
+		for i, dashboards := range grafana.DashboardConfigMaps {
+			mounts = append(mounts, corev1.VolumeMount{
+				Name:      getGrafanaDashboardsVolumeName(i),
+				MountPath: path.Join(constants.GrafanaDashboardsPath, dashboards),
+				ReadOnly:  true,
+			})
+		}
+		if len(grafana.ProvisioningConfigMap) > 0 {
+			mounts = append(mounts, corev1.VolumeMount{
+				Name:      "grafana-provisioning",
+				MountPath: path.Join(constants.GrafanaProvisioningPath, "dashboards", constants.GrafanaDashboardProvidersFileName),
+				SubPath:   constants.GrafanaDashboardProvidersFileName,
+				ReadOnly:  true,
+			})
+		}
+		if len(grafana.DatasourcesSecret) > 0 {
+			mounts = append(mounts, corev1.VolumeMount{
+				Name:      "grafana-datasources",
+				MountPath: path.Join(constants.GrafanaProvisioningPath, "datasources", constants.GrafanaDatasourcesFileName),
+				SubPath:   constants.GrafanaDatasourcesFileName,
+				ReadOnly:  true,
+			})
+		}
+	}
 
 	var containerSc *corev1.SecurityContext
 	if cr.Spec.SecurityOptions != nil && cr.Spec.SecurityOptions.GrafanaSecurityContext != nil {
@@ -1636,7 +2039,7 @@ func NewGrafanaContainer(cr *model.CryostatInstance, imageTag string, tls *TLSCo
 		}
 	}
 
-	return corev1.Container{
+	container := corev1.Container{
 		Name:            cr.Name + "-grafana",
 		Image:           imageTag,
 		ImagePullPolicy: common.GetPullPolicy(imageTag),
@@ -1645,19 +2048,91 @@ func NewGrafanaContainer(cr *model.CryostatInstance, imageTag string, tls *TLSCo
 				ContainerPort: constants.GrafanaContainerPort,
 			},
 		},
-		Env: envs,
+		Env:          envs,
+		VolumeMounts: mounts,
 		LivenessProbe: &corev1.Probe{
 			ProbeHandler: corev1.ProbeHandler{
 				HTTPGet: &corev1.HTTPGetAction{
 					Port:   intstr.IntOrString{IntVal: 3000},
 					Path:   "/api/health",
-					Scheme: corev1.URISchemeHTTP,
+					Scheme: livenessProbeScheme,
 				},
 			},
 		},
 		SecurityContext: containerSc,
 		Resources:       *NewGrafanaContainerResource(cr),
 	}
+	applyProbeOptions(&container, getContainerProbeConfig(getProbeOptions(cr).Grafana), defaultGrafanaCpuRequest)
+	return container
+}
+
+// newGrafanaUserEnvs returns the Grafana settings requested in the Cryostat CR as environment variables
+func newGrafanaUserEnvs(cr *model.CryostatInstance) []corev1.EnvVar {
+	envs := []corev1.EnvVar{}
+	options := cr.Spec.GrafanaOptions
+	if options == nil {
+		return envs
+	}
+	if len(options.Plugins) > 0 {
+		envs = append(envs, corev1.EnvVar{
+			Name:  "GF_INSTALL_PLUGINS",
+			Value: strings.Join(options.Plugins, ","),
+		})
+	}
+	for _, env := range options.Env {
+		if IsGrafanaSetting(env.Name) {
+			envs = append(envs, env)
+		}
+	}
+	// Secret values of datasource settings are substituted into the provisioning file by Grafana
+	for i, datasource := range options.Datasources {
+		keys := make([]string, 0, len(datasource.SecureJSONDataFrom))
+		for key := range datasource.SecureJSONDataFrom {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			selector := datasource.SecureJSONDataFrom[key]
+			envs = append(envs, corev1.EnvVar{
+				Name: GrafanaDatasourceSecretEnvName(i, key),
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &selector,
+				},
+			})
+		}
+	}
+	return envs
+}
+
+// GrafanaDatasourceSecretEnvName returns the name of the environment variable holding the value of the
+// secure setting key, read from a Secret, for the datasource at index i in the Grafana options
+func GrafanaDatasourceSecretEnvName(i int, key string) string {
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, key)
+	return fmt.Sprintf("CRYOSTAT_DATASOURCE_%d_%s", i, strings.ToUpper(name))
+}
+
+// IsGrafanaSetting returns whether the environment variable name corresponds to a Grafana configuration setting
+func IsGrafanaSetting(name string) bool {
+	return strings.HasPrefix(name, "GF_")
+}
+
+// IsGrafanaDisabled returns whether the Grafana dashboard and JFR datasource should be removed
+func IsGrafanaDisabled(cr *model.CryostatInstance) bool {
+	return cr.Spec.GrafanaOptions != nil && cr.Spec.GrafanaOptions.Disabled
+}
+
+// IsGrafanaSeparate returns whether the Grafana dashboard and JFR datasource run in their own deployment
+func IsGrafanaSeparate(cr *model.CryostatInstance) bool {
+	return !IsGrafanaDisabled(cr) && cr.Spec.GrafanaOptions != nil && cr.Spec.GrafanaOptions.SeparateDeployment
+}
+
+func hasGrafanaSidecars(cr *model.CryostatInstance) bool {
+	return !IsGrafanaDisabled(cr) && !IsGrafanaSeparate(cr)
 }
 
 func NewStorageContainerResource(cr *model.CryostatInstance) *corev1.ResourceRequirements {
@@ -1733,6 +2208,18 @@ func NewStorageContainer(cr *model.CryostatInstance, imageTag string, tls *TLSCo
 			fmt.Sprintf("-s3.key.file=%s", path.Join(SecretMountPrefix, tls.StorageSecret, corev1.TLSPrivateKeyKey)),
 			fmt.Sprintf("-s3.cert.file=%s", path.Join(SecretMountPrefix, tls.StorageSecret, corev1.TLSCertKey)),
 		)
+		if tls.Profile != nil {
+			envs = append(envs, corev1.EnvVar{
+				Name:  "WEED_TLS_MIN_VERSION",
+				Value: "TLS " + tls.Profile.MinVersionNumber(),
+			})
+			if ciphers := tls.Profile.GoCiphers(); len(ciphers) > 0 {
+				envs = append(envs, corev1.EnvVar{
+					Name:  "WEED_TLS_CIPHER_SUITES",
+					Value: strings.Join(ciphers, ","),
+				})
+			}
+		}
 
 		tlsSecretMount := corev1.VolumeMount{
 			Name:      "storage-tls-secret",
@@ -1764,7 +2251,7 @@ func NewStorageContainer(cr *model.CryostatInstance, imageTag string, tls *TLSCo
 		},
 	}
 
-	return corev1.Container{
+	container := corev1.Container{
 		Name:            cr.Name + "-storage",
 		Image:           imageTag,
 		ImagePullPolicy: common.GetPullPolicy(imageTag),
@@ -1783,6 +2270,8 @@ func NewStorageContainer(cr *model.CryostatInstance, imageTag string, tls *TLSCo
 		},
 		Resources: *NewStorageContainerResource(cr),
 	}
+	applyProbeOptions(&container, getContainerProbeConfig(getProbeOptions(cr).Storage), defaultStorageCpuRequest)
+	return container
 }
 
 func NewDatabaseContainerResource(cr *model.CryostatInstance) *corev1.ResourceRequirements {
@@ -1876,9 +2365,15 @@ func NewDatabaseContainer(cr *model.CryostatInstance, imageTag string, tls *TLSC
 			"-c", fmt.Sprintf("ssl_cert_file=%s", path.Join(tlsPath, corev1.TLSCertKey)),
 			"-c", fmt.Sprintf("ssl_key_file=%s", path.Join(tlsPath, corev1.TLSPrivateKeyKey)),
 		)
+		if tls.Profile != nil {
+			args = append(args, "-c", fmt.Sprintf("ssl_min_protocol_version=%s", tls.Profile.MinProtocol()))
+			if ciphers := tls.Profile.OpenSSLCiphers(); len(ciphers) > 0 {
+				args = append(args, "-c", fmt.Sprintf("ssl_ciphers=%s", strings.Join(ciphers, ":")))
+			}
+		}
 	}
 
-	return corev1.Container{
+	container := corev1.Container{
 		Name:            cr.Name + "-db",
 		Image:           imageTag,
 		ImagePullPolicy: common.GetPullPolicy(imageTag),
@@ -1900,6 +2395,8 @@ func NewDatabaseContainer(cr *model.CryostatInstance, imageTag string, tls *TLSC
 		},
 		Resources: *NewDatabaseContainerResource(cr),
 	}
+	applyProbeOptions(&container, getContainerProbeConfig(getProbeOptions(cr).Database), defaultDatabaseCpuRequest)
+	return container
 }
 
 // datasourceURL contains the fixed URL to jfr-datasource's web server
@@ -1914,7 +2411,7 @@ func NewJfrDatasourceContainerResource(cr *model.CryostatInstance) *corev1.Resou
 	return resources
 }
 
-func NewJfrDatasourceContainer(cr *model.CryostatInstance, imageTag string) corev1.Container {
+func NewJfrDatasourceContainer(cr *model.CryostatInstance, imageTag string, tls *TLSConfig) corev1.Container {
 	var containerSc *corev1.SecurityContext
 	if cr.Spec.SecurityOptions != nil && cr.Spec.SecurityOptions.DataSourceSecurityContext != nil {
 		containerSc = cr.Spec.SecurityOptions.DataSourceSecurityContext
@@ -1928,36 +2425,94 @@ func NewJfrDatasourceContainer(cr *model.CryostatInstance, imageTag string) core
 		}
 	}
 
-	return corev1.Container{
-		Name:            cr.Name + "-jfr-datasource",
-		Image:           imageTag,
-		ImagePullPolicy: common.GetPullPolicy(imageTag),
-		Ports: []corev1.ContainerPort{
-			{
-				ContainerPort: constants.DatasourceContainerPort,
+	envs := []corev1.EnvVar{}
+	mounts := []corev1.VolumeMount{}
+	// Can't use HTTP probe since the port is not exposed over the network
+	livenessProbe := &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			Exec: &corev1.ExecAction{
+				Command: []string{"curl", "--fail", datasourceURL},
 			},
 		},
-		Env: []corev1.EnvVar{
-			{
+	}
+	if IsGrafanaSeparate(cr) {
+		// Cryostat uploads recordings to the datasource through the Grafana service
+		envs = append(envs, corev1.EnvVar{
+			Name:  "QUARKUS_HTTP_HOST",
+			Value: "0.0.0.0",
+		})
+		livenessProbeScheme := corev1.URISchemeHTTP
+		if tls != nil {
+			envs = append(envs,
+				corev1.EnvVar{
+					Name:  "QUARKUS_HTTP_SSL_PORT",
+					Value: strconv.Itoa(int(constants.DatasourceContainerPort)),
+				},
+				corev1.EnvVar{
+					Name:  "QUARKUS_HTTP_SSL_CERTIFICATE_KEY_FILES",
+					Value: path.Join(SecretMountPrefix, tls.GrafanaSecret, corev1.TLSPrivateKeyKey),
+				},
+				corev1.EnvVar{
+					Name:  "QUARKUS_HTTP_SSL_CERTIFICATE_FILES",
+					Value: path.Join(SecretMountPrefix, tls.GrafanaSecret, corev1.TLSCertKey),
+				},
+				corev1.EnvVar{
+					Name:  "QUARKUS_HTTP_INSECURE_REQUESTS",
+					Value: "disabled",
+				},
+			)
+			envs = append(envs, newQuarkusTLSProfileEnvs("QUARKUS_HTTP_SSL", tls.Profile)...)
+			mounts = append(mounts, corev1.VolumeMount{
+				Name:      "grafana-tls-secret",
+				MountPath: path.Join(SecretMountPrefix, tls.GrafanaSecret),
+				ReadOnly:  true,
+			})
+			livenessProbeScheme = corev1.URISchemeHTTPS
+		} else {
+			envs = append(envs, corev1.EnvVar{
+				Name:  "QUARKUS_HTTP_PORT",
+				Value: strconv.Itoa(int(constants.DatasourceContainerPort)),
+			})
+		}
+		livenessProbe = &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Port:   intstr.IntOrString{IntVal: constants.DatasourceContainerPort},
+					Path:   "/",
+					Scheme: livenessProbeScheme,
+				},
+			},
+		}
+	} else {
+		envs = append(envs,
+			corev1.EnvVar{
 				Name:  "QUARKUS_HTTP_HOST",
 				Value: constants.LoopbackAddress,
 			},
-			{
+			corev1.EnvVar{
 				Name:  "QUARKUS_HTTP_PORT",
 				Value: strconv.Itoa(int(constants.DatasourceContainerPort)),
 			},
-		},
-		// Can't use HTTP probe since the port is not exposed over the network
-		LivenessProbe: &corev1.Probe{
-			ProbeHandler: corev1.ProbeHandler{
-				Exec: &corev1.ExecAction{
-					Command: []string{"curl", "--fail", datasourceURL},
-				},
+		)
+	}
+
+	container := corev1.Container{
+		Name:            cr.Name + "-jfr-datasource",
+		Image:           imageTag,
+		ImagePullPolicy: common.GetPullPolicy(imageTag),
+		Ports: []corev1.ContainerPort{
+			{
+				ContainerPort: constants.DatasourceContainerPort,
 			},
 		},
+		Env:             envs,
+		VolumeMounts:    mounts,
+		LivenessProbe:   livenessProbe,
 		SecurityContext: containerSc,
 		Resources:       *NewJfrDatasourceContainerResource(cr),
 	}
+	applyProbeOptions(&container, getProbeOptions(cr).Datasource, defaultJfrDatasourceCpuRequest)
+	return container
 }
 
 func newAgentProxyContainer(cr *model.CryostatInstance, imageTag string, tls *TLSConfig) corev1.Container {
@@ -1983,15 +2538,19 @@ func newAgentProxyContainer(cr *model.CryostatInstance, imageTag string, tls *TL
 		},
 	}
 	if tls != nil {
-		// Mount the TLS secret for the agent proxy
+		// Mount the TLS secret for the agent proxy, and the CAs trusted to issue agent certificates
 		mounts = append(mounts, corev1.VolumeMount{
 			Name:      "agent-proxy-tls-secret",
 			MountPath: path.Join(SecretMountPrefix, tls.AgentProxySecret),
 			ReadOnly:  true,
+		}, corev1.VolumeMount{
+			Name:      "ca-bundle",
+			MountPath: path.Join(SecretMountPrefix, tls.CABundleSecret),
+			ReadOnly:  true,
 		})
 	}
 
-	return corev1.Container{
+	container := corev1.Container{
 		Name:            cr.Name + "-agent-proxy",
 		Image:           imageTag,
 		ImagePullPolicy: common.GetPullPolicy(imageTag),
@@ -2023,6 +2582,8 @@ func newAgentProxyContainer(cr *model.CryostatInstance, imageTag string, tls *TL
 		Resources:       *newAgentProxyContainerResource(cr),
 		VolumeMounts:    mounts,
 	}
+	applyProbeOptions(&container, nil, defaultAgentProxyCpuRequest)
+	return container
 }
 
 func newAgentProxyContainerResource(cr *model.CryostatInstance) *corev1.ResourceRequirements {
@@ -2034,10 +2595,40 @@ func newAgentProxyContainerResource(cr *model.CryostatInstance) *corev1.Resource
 	return resources
 }
 
+// newQuarkusTLSProfileEnvs returns environment variables that apply the TLS profile
+// to a Quarkus TLS configuration with the given property prefix
+func newQuarkusTLSProfileEnvs(prefix string, profile *common.TLSProfile) []corev1.EnvVar {
+	if profile == nil {
+		return nil
+	}
+	envs := []corev1.EnvVar{
+		{
+			Name:  prefix + "_PROTOCOLS",
+			Value: strings.Join(profile.Protocols(), ","),
+		},
+	}
+	if ciphers := profile.IANACiphers(); len(ciphers) > 0 {
+		envs = append(envs, corev1.EnvVar{
+			Name:  prefix + "_CIPHER_SUITES",
+			Value: strings.Join(ciphers, ","),
+		})
+	}
+	return envs
+}
+
 func getInternalDashboardURL() string {
 	return fmt.Sprintf("http://localhost:%d", constants.GrafanaContainerPort)
 }
 
+// getGrafanaDatasourceURL returns the URL Grafana uses to reach the JFR datasource in its pod
+func getGrafanaDatasourceURL(cr *model.CryostatInstance, tls *TLSConfig) string {
+	if IsGrafanaSeparate(cr) && tls != nil {
+		// The datasource's certificate is valid for localhost
+		return fmt.Sprintf("https://localhost:%d", constants.DatasourceContainerPort)
+	}
+	return datasourceURL
+}
+
 func newVolumeForDatabase(cr *model.CryostatInstance) []corev1.Volume {
 	var volumeSource corev1.VolumeSource
 
@@ -2065,7 +2656,7 @@ func newVolumeForDatabase(cr *model.CryostatInstance) []corev1.Volume {
 	} else {
 		volumeSource = corev1.VolumeSource{
 			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-				ClaimName: cr.Name + "-database",
+				ClaimName: PersistentVolumeClaimName(cr, "database"),
 			},
 		}
 	}
@@ -2105,7 +2696,7 @@ func newVolumeForStorage(cr *model.CryostatInstance) []corev1.Volume {
 	} else {
 		volumeSource = corev1.VolumeSource{
 			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-				ClaimName: cr.Name + "-storage",
+				ClaimName: PersistentVolumeClaimName(cr, "storage"),
 			},
 		}
 	}
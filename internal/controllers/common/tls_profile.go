@@ -0,0 +1,197 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"crypto/tls"
+	"slices"
+	"strings"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+// TLSProfile contains the TLS protocol versions and cipher suites
+// to be used by all Cryostat components
+type TLSProfile struct {
+	// Cipher suites using their OpenSSL names, with TLS 1.3
+	// cipher suites using their IANA names
+	Ciphers []string
+	// Minimum version of the TLS protocol
+	MinTLSVersion operatorv1beta2.TLSProtocolVersion
+}
+
+// Supported TLS protocol versions, in increasing order
+var tlsProtocolVersions = []operatorv1beta2.TLSProtocolVersion{
+	operatorv1beta2.VersionTLS10,
+	operatorv1beta2.VersionTLS11,
+	operatorv1beta2.VersionTLS12,
+	operatorv1beta2.VersionTLS13,
+}
+
+// Protocol names used by OpenSSL, Java and PostgreSQL
+var tlsProtocolNames = map[operatorv1beta2.TLSProtocolVersion]string{
+	operatorv1beta2.VersionTLS10: "TLSv1",
+	operatorv1beta2.VersionTLS11: "TLSv1.1",
+	operatorv1beta2.VersionTLS12: "TLSv1.2",
+	operatorv1beta2.VersionTLS13: "TLSv1.3",
+}
+
+var tlsProtocolNumbers = map[operatorv1beta2.TLSProtocolVersion]string{
+	operatorv1beta2.VersionTLS10: "1.0",
+	operatorv1beta2.VersionTLS11: "1.1",
+	operatorv1beta2.VersionTLS12: "1.2",
+	operatorv1beta2.VersionTLS13: "1.3",
+}
+
+// Mapping of OpenSSL cipher suite names to their IANA names, covering
+// those cipher suites used by the predefined TLS profiles
+var openSSLToIANACiphers = map[string]string{
+	"TLS_AES_128_GCM_SHA256":        "TLS_AES_128_GCM_SHA256",
+	"TLS_AES_256_GCM_SHA384":        "TLS_AES_256_GCM_SHA384",
+	"TLS_CHACHA20_POLY1305_SHA256":  "TLS_CHACHA20_POLY1305_SHA256",
+	"ECDHE-ECDSA-AES128-GCM-SHA256": "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+	"ECDHE-RSA-AES128-GCM-SHA256":   "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+	"ECDHE-ECDSA-AES256-GCM-SHA384": "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+	"ECDHE-RSA-AES256-GCM-SHA384":   "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+	"ECDHE-ECDSA-CHACHA20-POLY1305": "TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256",
+	"ECDHE-RSA-CHACHA20-POLY1305":   "TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256",
+	"DHE-RSA-AES128-GCM-SHA256":     "TLS_DHE_RSA_WITH_AES_128_GCM_SHA256",
+	"DHE-RSA-AES256-GCM-SHA384":     "TLS_DHE_RSA_WITH_AES_256_GCM_SHA384",
+	"DHE-RSA-CHACHA20-POLY1305":     "TLS_DHE_RSA_WITH_CHACHA20_POLY1305_SHA256",
+	"ECDHE-ECDSA-AES128-SHA256":     "TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA256",
+	"ECDHE-RSA-AES128-SHA256":       "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA256",
+	"ECDHE-ECDSA-AES128-SHA":        "TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA",
+	"ECDHE-RSA-AES128-SHA":          "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA",
+	"ECDHE-ECDSA-AES256-SHA384":     "TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA384",
+	"ECDHE-RSA-AES256-SHA384":       "TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA384",
+	"ECDHE-ECDSA-AES256-SHA":        "TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA",
+	"ECDHE-RSA-AES256-SHA":          "TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA",
+	"DHE-RSA-AES128-SHA256":         "TLS_DHE_RSA_WITH_AES_128_CBC_SHA256",
+	"DHE-RSA-AES256-SHA256":         "TLS_DHE_RSA_WITH_AES_256_CBC_SHA256",
+	"AES128-GCM-SHA256":             "TLS_RSA_WITH_AES_128_GCM_SHA256",
+	"AES256-GCM-SHA384":             "TLS_RSA_WITH_AES_256_GCM_SHA384",
+	"AES128-SHA256":                 "TLS_RSA_WITH_AES_128_CBC_SHA256",
+	"AES256-SHA256":                 "TLS_RSA_WITH_AES_256_CBC_SHA256",
+	"AES128-SHA":                    "TLS_RSA_WITH_AES_128_CBC_SHA",
+	"AES256-SHA":                    "TLS_RSA_WITH_AES_256_CBC_SHA",
+	"DES-CBC3-SHA":                  "TLS_RSA_WITH_3DES_EDE_CBC_SHA",
+}
+
+// NewTLSProfile returns the TLS profile for the given profile type. If the type is
+// Custom, the custom profile is used. Unknown types use the Intermediate profile.
+func NewTLSProfile(profileType operatorv1beta2.TLSProfileType, custom *operatorv1beta2.CustomTLSProfile) *TLSProfile {
+	if profileType == operatorv1beta2.TLSProfileCustom && custom != nil {
+		return &TLSProfile{
+			Ciphers:       slices.Clone(custom.Ciphers),
+			MinTLSVersion: custom.MinTLSVersion,
+		}
+	}
+	spec, pres := configv1.TLSProfiles[configv1.TLSProfileType(profileType)]
+	if !pres {
+		spec = configv1.TLSProfiles[configv1.TLSProfileIntermediateType]
+	}
+	return &TLSProfile{
+		Ciphers:       slices.Clone(spec.Ciphers),
+		MinTLSVersion: operatorv1beta2.TLSProtocolVersion(spec.MinTLSVersion),
+	}
+}
+
+// NewTLSProfileFromSecurityProfile returns the TLS profile equivalent to an
+// OpenShift TLSSecurityProfile, such as that of the cluster's API server.
+func NewTLSProfileFromSecurityProfile(profile *configv1.TLSSecurityProfile) *TLSProfile {
+	if profile == nil {
+		return NewTLSProfile(operatorv1beta2.TLSProfileIntermediate, nil)
+	}
+	var custom *operatorv1beta2.CustomTLSProfile
+	if profile.Custom != nil {
+		custom = &operatorv1beta2.CustomTLSProfile{
+			Ciphers:       profile.Custom.Ciphers,
+			MinTLSVersion: operatorv1beta2.TLSProtocolVersion(profile.Custom.MinTLSVersion),
+		}
+	}
+	return NewTLSProfile(operatorv1beta2.TLSProfileType(profile.Type), custom)
+}
+
+// Protocols returns the names of all TLS protocol versions permitted by
+// this profile, in increasing order (e.g. "TLSv1.2", "TLSv1.3")
+func (p *TLSProfile) Protocols() []string {
+	protocols := []string{}
+	idx := slices.Index(tlsProtocolVersions, p.MinTLSVersion)
+	if idx < 0 {
+		idx = slices.Index(tlsProtocolVersions, operatorv1beta2.VersionTLS12)
+	}
+	for _, version := range tlsProtocolVersions[idx:] {
+		protocols = append(protocols, tlsProtocolNames[version])
+	}
+	return protocols
+}
+
+// MinProtocol returns the name of the minimum TLS protocol version (e.g. "TLSv1.2")
+func (p *TLSProfile) MinProtocol() string {
+	return p.Protocols()[0]
+}
+
+// MinVersionNumber returns the number of the minimum TLS protocol version (e.g. "1.2")
+func (p *TLSProfile) MinVersionNumber() string {
+	number, pres := tlsProtocolNumbers[p.MinTLSVersion]
+	if !pres {
+		return tlsProtocolNumbers[operatorv1beta2.VersionTLS12]
+	}
+	return number
+}
+
+// OpenSSLCiphers returns the OpenSSL names of the cipher suites for TLS 1.2 and
+// earlier. TLS 1.3 cipher suites are omitted, since OpenSSL configures them separately.
+func (p *TLSProfile) OpenSSLCiphers() []string {
+	ciphers := []string{}
+	for _, cipher := range p.Ciphers {
+		if !strings.HasPrefix(cipher, "TLS_") {
+			ciphers = append(ciphers, cipher)
+		}
+	}
+	return ciphers
+}
+
+// IANACiphers returns the IANA names of the cipher suites, as used by Java.
+// Unrecognized cipher suites are omitted.
+func (p *TLSProfile) IANACiphers() []string {
+	ciphers := []string{}
+	for _, cipher := range p.Ciphers {
+		if iana, pres := openSSLToIANACiphers[cipher]; pres {
+			ciphers = append(ciphers, iana)
+		}
+	}
+	return ciphers
+}
+
+// GoCiphers returns the IANA names of the cipher suites supported by Go's crypto/tls
+// package. TLS 1.3 cipher suites are omitted, since they are not configurable in Go.
+func (p *TLSProfile) GoCiphers() []string {
+	supported := map[string]bool{}
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		if !slices.Equal(suite.SupportedVersions, []uint16{tls.VersionTLS13}) {
+			supported[suite.Name] = true
+		}
+	}
+
+	ciphers := []string{}
+	for _, cipher := range p.IANACiphers() {
+		if supported[cipher] {
+			ciphers = append(ciphers, cipher)
+		}
+	}
+	return ciphers
+}
@@ -21,15 +21,22 @@ import (
 	"errors"
 	"fmt"
 	"path"
+	"slices"
+	"strings"
 	"text/template"
 
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
 	resources "github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
 	"github.com/cryostatio/cryostat-operator/internal/controllers/constants"
 	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 func (r *Reconciler) reconcileLockConfigMap(ctx context.Context, cr *model.CryostatInstance) error {
@@ -39,7 +46,7 @@ func (r *Reconciler) reconcileLockConfigMap(ctx context.Context, cr *model.Cryos
 			Namespace: cr.InstallNamespace,
 		},
 	}
-	return r.createOrUpdateConfigMap(ctx, cm, cr.Object, nil)
+	return r.createOrUpdateConfigMap(ctx, cm, cr, nil)
 }
 
 type oauth2ProxyAlphaConfig struct {
@@ -55,8 +62,10 @@ type alphaConfigServer struct {
 }
 
 type proxyTLS struct {
-	Key  tlsSecretSource `json:"Key,omitempty"`
-	Cert tlsSecretSource `json:"Cert,omitempty"`
+	Key          tlsSecretSource `json:"Key,omitempty"`
+	Cert         tlsSecretSource `json:"Cert,omitempty"`
+	MinVersion   string          `json:"MinVersion,omitempty"`
+	CipherSuites []string        `json:"CipherSuites,omitempty"`
 }
 
 type tlsSecretSource struct {
@@ -85,31 +94,39 @@ type alphaConfigUpstream struct {
 	ProxyWebSockets *bool  `json:"proxyWebSockets,omitempty"`
 }
 
-func (r *Reconciler) reconcileOAuth2ProxyConfig(ctx context.Context, cr *model.CryostatInstance, tls *resources.TLSConfig) error {
+func (r *Reconciler) reconcileOAuth2ProxyConfig(ctx context.Context, cr *model.CryostatInstance, tls *resources.TLSConfig,
+	specs *resources.ServiceSpecs) error {
 	bindHost := "0.0.0.0"
+	upstreams := []alphaConfigUpstream{
+		{
+			Id:   "cryostat",
+			Path: "/",
+			Uri:  fmt.Sprintf("http://localhost:%d", constants.CryostatHTTPContainerPort),
+		},
+	}
+	if !resources.IsGrafanaDisabled(cr) {
+		grafanaURI := fmt.Sprintf("http://localhost:%d", constants.GrafanaContainerPort)
+		if specs.GrafanaURL != nil {
+			grafanaURI = specs.GrafanaURL.String()
+		}
+		upstreams = append(upstreams, alphaConfigUpstream{
+			Id:   "grafana",
+			Path: "/grafana/",
+			Uri:  grafanaURI,
+		})
+	}
+	upstreams = append(upstreams, alphaConfigUpstream{
+		Id:              "storage",
+		Path:            "^/storage/(.*)$",
+		RewriteTarget:   "/$1",
+		Uri:             fmt.Sprintf("http://localhost:%d", constants.StoragePort),
+		PassHostHeader:  &[]bool{false}[0],
+		ProxyWebSockets: &[]bool{false}[0],
+	})
 	cfg := &oauth2ProxyAlphaConfig{
-		Server: alphaConfigServer{},
-		UpstreamConfig: alphaConfigUpstreamConfig{ProxyRawPath: true, Upstreams: []alphaConfigUpstream{
-			{
-				Id:   "cryostat",
-				Path: "/",
-				Uri:  fmt.Sprintf("http://localhost:%d", constants.CryostatHTTPContainerPort),
-			},
-			{
-				Id:   "grafana",
-				Path: "/grafana/",
-				Uri:  fmt.Sprintf("http://localhost:%d", constants.GrafanaContainerPort),
-			},
-			{
-				Id:              "storage",
-				Path:            "^/storage/(.*)$",
-				RewriteTarget:   "/$1",
-				Uri:             fmt.Sprintf("http://localhost:%d", constants.StoragePort),
-				PassHostHeader:  &[]bool{false}[0],
-				ProxyWebSockets: &[]bool{false}[0],
-			},
-		}},
-		Providers: []alphaConfigProvider{{Id: "dummy", Name: "Unused - Sign In Below", ClientId: "CLIENT_ID", ClientSecret: "CLIENT_SECRET", Provider: "google"}},
+		Server:         alphaConfigServer{},
+		UpstreamConfig: alphaConfigUpstreamConfig{ProxyRawPath: true, Upstreams: upstreams},
+		Providers:      []alphaConfigProvider{{Id: "dummy", Name: "Unused - Sign In Below", ClientId: "CLIENT_ID", ClientSecret: "CLIENT_SECRET", Provider: "google"}},
 	}
 
 	if tls != nil {
@@ -121,6 +138,12 @@ func (r *Reconciler) reconcileOAuth2ProxyConfig(ctx context.Context, cr *model.C
 			Cert: tlsSecretSource{
 				FromFile: path.Join(resources.SecretMountPrefix, tls.CryostatSecret, corev1.TLSCertKey),
 			},
+			CipherSuites: tls.Profile.GoCiphers(),
+		}
+		// oauth2-proxy only supports a minimum version of TLS 1.2 or above
+		if tls.Profile.MinTLSVersion == operatorv1beta2.VersionTLS12 ||
+			tls.Profile.MinTLSVersion == operatorv1beta2.VersionTLS13 {
+			cfg.Server.TLS.MinVersion = "TLS" + tls.Profile.MinVersionNumber()
 		}
 	} else {
 		cfg.Server.BindAddress = fmt.Sprintf("http://%s:%d", bindHost, constants.AuthProxyHttpContainerPort)
@@ -144,7 +167,7 @@ func (r *Reconciler) reconcileOAuth2ProxyConfig(ctx context.Context, cr *model.C
 			resources.OAuth2ConfigFileName: string(json),
 		}
 
-		return r.createOrUpdateConfigMap(ctx, cm, cr.Object, data)
+		return r.createOrUpdateConfigMap(ctx, cm, cr, data)
 	}
 }
 
@@ -161,6 +184,10 @@ type nginxConfParams struct {
 	CACertFile string
 	// Diffie-Hellman parameters file
 	DHParamFile string
+	// Space-separated list of permitted TLS protocol versions
+	TLSProtocols string
+	// Colon-separated list of permitted TLS 1.2 and below cipher suites
+	TLSCiphers string
 	// Nginx proxy container port
 	ContainerPort int32
 	// Nginx health container port
@@ -214,9 +241,11 @@ http {
 
 		ssl_dhparam {{ .DHParamFile }};
 
-		# intermediate configuration
-		ssl_protocols TLSv1.2 TLSv1.3;
-		ssl_ciphers ECDHE-ECDSA-AES128-GCM-SHA256:ECDHE-RSA-AES128-GCM-SHA256:ECDHE-ECDSA-AES256-GCM-SHA384:ECDHE-RSA-AES256-GCM-SHA384:ECDHE-ECDSA-CHACHA20-POLY1305:ECDHE-RSA-CHACHA20-POLY1305:DHE-RSA-AES128-GCM-SHA256:DHE-RSA-AES256-GCM-SHA384:DHE-RSA-CHACHA20-POLY1305;
+		# configured TLS profile
+		ssl_protocols {{ .TLSProtocols }};
+		{{ if .TLSCiphers -}}
+		ssl_ciphers {{ .TLSCiphers }};
+		{{ end -}}
 		ssl_prefer_server_ciphers off;
 
 		# HSTS (ngx_http_headers_module is required) (63072000 seconds)
@@ -309,8 +338,10 @@ func (r *Reconciler) reconcileAgentProxyConfig(ctx context.Context, cr *model.Cr
 		params.TLSEnabled = true
 		params.TLSCertFile = path.Join(resources.SecretMountPrefix, tls.AgentProxySecret, corev1.TLSCertKey)
 		params.TLSKeyFile = path.Join(resources.SecretMountPrefix, tls.AgentProxySecret, corev1.TLSPrivateKeyKey)
-		params.CACertFile = path.Join(resources.SecretMountPrefix, tls.AgentProxySecret, constants.CAKey)
+		params.CACertFile = path.Join(resources.SecretMountPrefix, tls.CABundleSecret, constants.CAKey)
 		params.DHParamFile = path.Join(constants.AgentProxyConfigFilePath, dhFileName)
+		params.TLSProtocols = strings.Join(tls.Profile.Protocols(), " ")
+		params.TLSCiphers = strings.Join(tls.Profile.OpenSSLCiphers(), ":")
 
 		// Add Diffie-Hellman parameters to config map
 		data[dhFileName] = dhParams
@@ -327,12 +358,235 @@ func (r *Reconciler) reconcileAgentProxyConfig(ctx context.Context, cr *model.Cr
 	// Add generated nginx.conf to config map
 	data[constants.AgentProxyConfigFileName] = buf.String()
 
-	return r.createOrUpdateConfigMap(ctx, cm, cr.Object, data)
+	return r.createOrUpdateConfigMap(ctx, cm, cr, data)
+}
+
+type grafanaDashboardProviders struct {
+	APIVersion int                        `json:"apiVersion"`
+	Providers  []grafanaDashboardProvider `json:"providers"`
+}
+
+type grafanaDashboardProvider struct {
+	Name            string                          `json:"name"`
+	Type            string                          `json:"type"`
+	DisableDeletion bool                            `json:"disableDeletion"`
+	AllowUIUpdates  bool                            `json:"allowUiUpdates"`
+	Options         grafanaDashboardProviderOptions `json:"options"`
+}
+
+type grafanaDashboardProviderOptions struct {
+	Path                      string `json:"path"`
+	FoldersFromFilesStructure bool   `json:"foldersFromFilesStructure"`
+}
+
+type grafanaDatasources struct {
+	APIVersion  int                 `json:"apiVersion"`
+	Datasources []grafanaDatasource `json:"datasources"`
+}
+
+type grafanaDatasource struct {
+	Name           string            `json:"name"`
+	Type           string            `json:"type"`
+	Access         string            `json:"access"`
+	URL            string            `json:"url,omitempty"`
+	IsDefault      bool              `json:"isDefault"`
+	Editable       bool              `json:"editable"`
+	JSONData       map[string]string `json:"jsonData,omitempty"`
+	SecureJSONData map[string]string `json:"secureJsonData,omitempty"`
+}
+
+// reconcileGrafanaProvisioning finds the Config Maps containing dashboards requested in the Cryostat CR,
+// and creates the Grafana provisioning files that load these dashboards and any additional datasources
+func (r *Reconciler) reconcileGrafanaProvisioning(ctx context.Context, cr *model.CryostatInstance) (*resources.GrafanaConfig, error) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name + "-grafana-provisioning",
+			Namespace: cr.InstallNamespace,
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name + "-grafana-datasources",
+			Namespace: cr.InstallNamespace,
+		},
+	}
+	config := &resources.GrafanaConfig{}
+
+	options := cr.Spec.GrafanaOptions
+	if options == nil || options.Disabled {
+		err := r.deleteConfigMap(ctx, cm)
+		if err != nil {
+			return nil, err
+		}
+		return config, r.deleteSecret(ctx, secret)
+	}
+	dashboards, err := r.getGrafanaDashboardConfigMaps(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	config.DashboardConfigMaps = dashboards
+
+	// Grafana loads each dashboard Config Map, mounted in its own directory, into a folder of the same name
+	if len(dashboards) == 0 {
+		err = r.deleteConfigMap(ctx, cm)
+	} else {
+		err = r.createOrUpdateDashboardProviders(ctx, cm, cr)
+		config.ProvisioningConfigMap = cm.Name
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Datasources may contain credentials, so their provisioning file is kept in a Secret
+	if len(options.Datasources) == 0 {
+		err = r.deleteSecret(ctx, secret)
+	} else {
+		err = r.createOrUpdateDatasources(ctx, secret, cr)
+		config.DatasourcesSecret = secret.Name
+	}
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (r *Reconciler) createOrUpdateDashboardProviders(ctx context.Context, cm *corev1.ConfigMap, cr *model.CryostatInstance) error {
+	providers := &grafanaDashboardProviders{
+		APIVersion: 1,
+		Providers: []grafanaDashboardProvider{
+			{
+				Name:            "cryostat-user-dashboards",
+				Type:            "file",
+				DisableDeletion: true,
+				Options: grafanaDashboardProviderOptions{
+					Path:                      constants.GrafanaDashboardsPath,
+					FoldersFromFilesStructure: true,
+				},
+			},
+		},
+	}
+	// Grafana accepts JSON provisioning files, since JSON is a subset of YAML
+	providersJSON, err := json.MarshalIndent(providers, "", "  ")
+	if err != nil {
+		return err
+	}
+	return r.createOrUpdateConfigMap(ctx, cm, cr, map[string]string{
+		constants.GrafanaDashboardProvidersFileName: string(providersJSON),
+	})
+}
+
+func (r *Reconciler) createOrUpdateDatasources(ctx context.Context, secret *corev1.Secret, cr *model.CryostatInstance) error {
+	datasources := &grafanaDatasources{
+		APIVersion:  1,
+		Datasources: []grafanaDatasource{},
+	}
+	for i, datasource := range cr.Spec.GrafanaOptions.Datasources {
+		access := datasource.Access
+		if len(access) == 0 {
+			access = "proxy"
+		}
+		// Grafana substitutes the values read from Secrets into the file from its environment
+		var secureJSONData map[string]string
+		if len(datasource.SecureJSONData) > 0 || len(datasource.SecureJSONDataFrom) > 0 {
+			secureJSONData = map[string]string{}
+			for key, value := range datasource.SecureJSONData {
+				secureJSONData[key] = value
+			}
+			for key := range datasource.SecureJSONDataFrom {
+				secureJSONData[key] = fmt.Sprintf("$__env{%s}", resources.GrafanaDatasourceSecretEnvName(i, key))
+			}
+		}
+		datasources.Datasources = append(datasources.Datasources, grafanaDatasource{
+			Name:           datasource.Name,
+			Type:           datasource.Type,
+			Access:         access,
+			URL:            datasource.URL,
+			IsDefault:      datasource.IsDefault,
+			JSONData:       datasource.JSONData,
+			SecureJSONData: secureJSONData,
+		})
+	}
+	datasourcesJSON, err := json.MarshalIndent(datasources, "", "  ")
+	if err != nil {
+		return err
+	}
+	return r.createOrUpdateSecret(ctx, secret, cr.Object, func() error {
+		mergeResourceMetadata(cr, &secret.ObjectMeta)
+		secret.Data = map[string][]byte{
+			constants.GrafanaDatasourcesFileName: datasourcesJSON,
+		}
+		return nil
+	})
+}
+
+// getGrafanaDashboardConfigMaps returns the names of Config Maps containing dashboards, either listed
+// by name or matching the dashboard selector. Config Maps created by the operator are never selected.
+func (r *Reconciler) getGrafanaDashboardConfigMaps(ctx context.Context, cr *model.CryostatInstance) ([]string, error) {
+	options := cr.Spec.GrafanaOptions
+	names := []string{}
+	for _, name := range options.DashboardConfigMaps {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	if options.DashboardSelector == nil {
+		return names, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(options.DashboardSelector)
+	if err != nil {
+		return nil, err
+	}
+	cms := &corev1.ConfigMapList{}
+	err = r.Client.List(ctx, cms, client.InNamespace(cr.InstallNamespace), client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		return nil, err
+	}
+	selected := []string{}
+	for i := range cms.Items {
+		cm := &cms.Items[i]
+		if !metav1.IsControlledBy(cm, cr.Object) && !slices.Contains(names, cm.Name) {
+			selected = append(selected, cm.Name)
+		}
+	}
+	slices.Sort(selected)
+	return append(names, selected...), nil
+}
+
+// mapFromDashboardConfigMap enqueues the Cryostats in the Config Map's namespace that load it as
+// Grafana dashboards, either by name or with a dashboard selector matching its labels
+func (r *Reconciler) mapFromDashboardConfigMap(ctx context.Context, obj client.Object) []reconcile.Request {
+	cryostats := &operatorv1beta2.CryostatList{}
+	err := r.Client.List(ctx, cryostats, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		r.Log.Error(err, "failed to list Cryostats", "ConfigMap", obj.GetName())
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, cr := range cryostats.Items {
+		options := cr.Spec.GrafanaOptions
+		if options == nil || options.Disabled || metav1.IsControlledBy(obj, &cr) {
+			continue
+		}
+		matches := slices.Contains(options.DashboardConfigMaps, obj.GetName())
+		if !matches && options.DashboardSelector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(options.DashboardSelector)
+			matches = err == nil && selector.Matches(labels.Set(obj.GetLabels()))
+		}
+		if matches {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Name:      cr.Name,
+				Namespace: cr.Namespace,
+			}})
+		}
+	}
+	return requests
 }
 
 var errConfigMapImmutableModified error = errors.New("config map is immutable and should not be")
 
-func (r *Reconciler) createOrUpdateConfigMap(ctx context.Context, cm *corev1.ConfigMap, owner metav1.Object,
+func (r *Reconciler) createOrUpdateConfigMap(ctx context.Context, cm *corev1.ConfigMap, cr *model.CryostatInstance,
 	data map[string]string) error {
 	cmCopy := cm.DeepCopy()
 	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
@@ -340,8 +594,9 @@ func (r *Reconciler) createOrUpdateConfigMap(ctx context.Context, cm *corev1.Con
 		if isImmutable(cm) && !isImmutable(cmCopy) {
 			return errConfigMapImmutableModified
 		}
+		mergeResourceMetadata(cr, &cm.ObjectMeta)
 		// Set the Cryostat CR as controller
-		if err := controllerutil.SetControllerReference(owner, cm, r.Scheme); err != nil {
+		if err := controllerutil.SetControllerReference(cr.Object, cm, r.Scheme); err != nil {
 			return err
 		}
 		cm.Data = data
@@ -349,7 +604,7 @@ func (r *Reconciler) createOrUpdateConfigMap(ctx context.Context, cm *corev1.Con
 	})
 	if err != nil {
 		if err == errConfigMapImmutableModified {
-			return r.recreateConfigMap(ctx, cmCopy, owner, data)
+			return r.recreateConfigMap(ctx, cmCopy, cr, data)
 		}
 		return err
 	}
@@ -357,13 +612,13 @@ func (r *Reconciler) createOrUpdateConfigMap(ctx context.Context, cm *corev1.Con
 	return nil
 }
 
-func (r *Reconciler) recreateConfigMap(ctx context.Context, cm *corev1.ConfigMap, owner metav1.Object,
+func (r *Reconciler) recreateConfigMap(ctx context.Context, cm *corev1.ConfigMap, cr *model.CryostatInstance,
 	data map[string]string) error {
 	err := r.deleteConfigMap(ctx, cm)
 	if err != nil {
 		return err
 	}
-	return r.createOrUpdateConfigMap(ctx, cm, owner, data)
+	return r.createOrUpdateConfigMap(ctx, cm, cr, data)
 }
 
 func isImmutable(cm *corev1.ConfigMap) bool {
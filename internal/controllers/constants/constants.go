@@ -53,6 +53,12 @@ const (
 	AgentProxyConfigFilePath string = "/etc/nginx-cryostat"
 	AgentProxyConfigFileName string = "nginx.conf"
 
+	// Locations of user-provided Grafana dashboards and the provisioning files that load them
+	GrafanaDashboardsPath             string = "/opt/cryostat.d/grafana/dashboards"
+	GrafanaProvisioningPath           string = "/etc/grafana/provisioning"
+	GrafanaDashboardProvidersFileName string = "cryostat-user-dashboards.yaml"
+	GrafanaDatasourcesFileName        string = "cryostat-user-datasources.yaml"
+
 	// Labels applied by operator to track cross-namespace ownership
 	targetNamespaceCRLabelPrefix    = "operator.cryostat.io/"
 	TargetNamespaceCRNameLabel      = targetNamespaceCRLabelPrefix + "name"
@@ -76,6 +82,7 @@ const (
 	DatabaseTLSCommonName       = "cryostat-db"
 	StorageTLSCommonName        = "cryostat-storage"
 	ReportsTLSCommonName        = "cryostat-reports"
+	GrafanaTLSCommonName        = "cryostat-grafana"
 	AgentsTLSCommonName         = "cryostat-agent"
 	AgentAuthProxyTLSCommonName = "cryostat-agent-proxy"
 
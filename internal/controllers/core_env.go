@@ -0,0 +1,173 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	resources "github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// setCoreEnvCondition reports whether the environment variables in the core options were added to the
+// Cryostat application container of podSpec. The condition is written along with the rest of the
+// core status, and a warning event is emitted only when the variables that were ignored change.
+func (r *Reconciler) setCoreEnvCondition(ctx context.Context, reqLogger logr.Logger, cr *model.CryostatInstance,
+	podSpec *corev1.PodSpec) error {
+	if cr.Spec.CoreOptions == nil || (len(cr.Spec.CoreOptions.Env) == 0 && len(cr.Spec.CoreOptions.EnvFrom) == 0) {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, string(operatorv1beta2.ConditionTypeCoreEnvApplied))
+		return nil
+	}
+
+	conflicts := resources.GetCoreEnvConflicts(cr, podSpec)
+	envFromConflicts, err := r.getCoreEnvFromConflicts(ctx, cr, podSpec, conflicts)
+	if err != nil {
+		return err
+	}
+	conflicts = append(conflicts, envFromConflicts...)
+	if len(conflicts) == 0 {
+		meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+			Type:               string(operatorv1beta2.ConditionTypeCoreEnvApplied),
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: cr.Object.GetGeneration(),
+			Reason:             reasonCoreEnvApplied,
+			Message:            "All environment variables in spec.coreOptions were added to the Cryostat container.",
+		})
+		return nil
+	}
+
+	message := "Environment variables in spec.coreOptions are set by the operator and were ignored: " +
+		strings.Join(conflicts, ", ")
+	previous := meta.FindStatusCondition(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeCoreEnvApplied))
+	if previous == nil || previous.Reason != reasonCoreEnvConflict || previous.Message != message {
+		reqLogger.Info("Ignoring environment variables set by the operator", "variables", strings.Join(conflicts, ", "))
+		r.EventRecorder.Event(cr.Object, corev1.EventTypeWarning, eventCoreEnvConflictType, message)
+	}
+	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+		Type:               string(operatorv1beta2.ConditionTypeCoreEnvApplied),
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: cr.Object.GetGeneration(),
+		Reason:             reasonCoreEnvConflict,
+		Message:            message,
+	})
+	return nil
+}
+
+// getCoreEnvFromConflicts returns the entries of the Secrets and Config Maps referenced by the core options'
+// envFrom, that are hidden by environment variables set by the operator. Each is described with its source.
+// Entries hidden by the core options' env are expected, and are not included.
+func (r *Reconciler) getCoreEnvFromConflicts(ctx context.Context, cr *model.CryostatInstance, podSpec *corev1.PodSpec,
+	envConflicts []string) ([]string, error) {
+	var container *corev1.Container
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name == cr.Name {
+			container = &podSpec.Containers[i]
+		}
+	}
+	if container == nil {
+		return nil, nil
+	}
+
+	// Variables from env that were added to the container take precedence over envFrom
+	userEnvs := map[string]bool{}
+	for _, env := range cr.Spec.CoreOptions.Env {
+		userEnvs[env.Name] = true
+	}
+	for _, name := range envConflicts {
+		delete(userEnvs, name)
+	}
+	operatorEnvs := map[string]bool{}
+	for _, env := range container.Env {
+		if !userEnvs[env.Name] {
+			operatorEnvs[env.Name] = true
+		}
+	}
+
+	conflicts := []string{}
+	for _, source := range cr.Spec.CoreOptions.EnvFrom {
+		var keys []string
+		var kind, name string
+		if source.SecretRef != nil {
+			kind, name = "Secret", source.SecretRef.Name
+			secret := &corev1.Secret{}
+			err := r.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: cr.InstallNamespace}, secret)
+			if err != nil && !kerrors.IsNotFound(err) {
+				return nil, err
+			}
+			for key := range secret.Data {
+				keys = append(keys, key)
+			}
+		} else if source.ConfigMapRef != nil {
+			kind, name = "ConfigMap", source.ConfigMapRef.Name
+			cm := &corev1.ConfigMap{}
+			err := r.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: cr.InstallNamespace}, cm)
+			if err != nil && !kerrors.IsNotFound(err) {
+				return nil, err
+			}
+			for key := range cm.Data {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if operatorEnvs[source.Prefix+key] {
+				conflicts = append(conflicts, fmt.Sprintf("%s (from %s %s)", source.Prefix+key, kind, name))
+			}
+		}
+	}
+	return conflicts, nil
+}
+
+// mapFromCoreEnvSource enqueues the Cryostats in the Secret or Config Map's namespace whose core options
+// load environment variables from it, so that conflicts with the operator's variables are reported again
+func (r *Reconciler) mapFromCoreEnvSource(ctx context.Context, obj client.Object) []reconcile.Request {
+	cryostats := &operatorv1beta2.CryostatList{}
+	err := r.Client.List(ctx, cryostats, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		r.Log.Error(err, "failed to list Cryostats", "name", obj.GetName(), "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	_, isSecret := obj.(*corev1.Secret)
+	requests := []reconcile.Request{}
+	for _, cr := range cryostats.Items {
+		if cr.Spec.CoreOptions == nil {
+			continue
+		}
+		for _, source := range cr.Spec.CoreOptions.EnvFrom {
+			if (isSecret && source.SecretRef != nil && source.SecretRef.Name == obj.GetName()) ||
+				(!isSecret && source.ConfigMapRef != nil && source.ConfigMapRef.Name == obj.GetName()) {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+					Name:      cr.Name,
+					Namespace: cr.Namespace,
+				}})
+				break
+			}
+		}
+	}
+	return requests
+}
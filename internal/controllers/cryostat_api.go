@@ -0,0 +1,121 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/constants"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
+	"github.com/cryostatio/cryostat-operator/internal/cryostatclient"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// errNamespaceNotTargeted is returned when a resource refers to a Cryostat
+// instance that does not include the resource's namespace in its target namespaces
+var errNamespaceNotTargeted = errors.New("namespace is not a target namespace of the Cryostat instance")
+
+// errCryostatPaused is returned when a resource refers to a Cryostat instance
+// that is scaled down by its paused property, so its API is unavailable
+var errCryostatPaused = errors.New("the Cryostat instance is paused")
+
+// errCryostatReconcilePaused is returned when a resource refers to a Cryostat instance
+// whose reconciliation is paused, so the operator must not modify it through its API
+var errCryostatReconcilePaused = errors.New("reconciliation of the Cryostat instance is paused")
+
+// getTargetingCryostat looks up the referenced Cryostat instance, and verifies that the
+// namespace is one of its target namespaces
+func (c *ReconcilerConfig) getTargetingCryostat(ctx context.Context, ref *operatorv1beta2.CryostatReference,
+	namespace string) (*model.CryostatInstance, error) {
+	cr := &operatorv1beta2.Cryostat{}
+	err := c.Client.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: getCryostatNamespace(ref, namespace)}, cr)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(cr.Status.TargetNamespaces, namespace) {
+		return nil, errNamespaceNotTargeted
+	}
+	if err := checkCryostatPaused(cr); err != nil {
+		return nil, err
+	}
+	return model.FromCryostat(cr), nil
+}
+
+// checkCryostatPaused returns an error if the operator must not use the API of the Cryostat instance
+func checkCryostatPaused(cr *operatorv1beta2.Cryostat) error {
+	if cr.Spec.ReconcilePaused {
+		return errCryostatReconcilePaused
+	}
+	if cr.Spec.Paused {
+		return errCryostatPaused
+	}
+	return nil
+}
+
+// isCryostatPaused returns whether err was returned because the Cryostat instance is paused
+func isCryostatPaused(err error) bool {
+	return errors.Is(err, errCryostatPaused) || errors.Is(err, errCryostatReconcilePaused)
+}
+
+// getCryostatPausedCondition returns the condition reason and message for a resource
+// whose Cryostat instance is paused
+func getCryostatPausedCondition(err error, namespace string, name string) (string, string) {
+	if errors.Is(err, errCryostatReconcilePaused) {
+		return reasonCryostatReconcilePaused, fmt.Sprintf("Reconciliation of Cryostat %s/%s is paused", namespace, name)
+	}
+	return reasonCryostatPaused, fmt.Sprintf("Cryostat %s/%s is paused", namespace, name)
+}
+
+// newCryostatAPIClient creates a client for the HTTP API of the Cryostat instance,
+// which connects through its Service from within the cluster
+func (c *ReconcilerConfig) newCryostatAPIClient(ctx context.Context, cr *model.CryostatInstance) (cryostatclient.Client, error) {
+	if c.NewCryostatClient == nil {
+		return nil, errors.New("no Cryostat API client is configured")
+	}
+	scheme := "http"
+	var caCert []byte
+	if c.IsCertManagerEnabled(cr) {
+		scheme = "https"
+		// Trust all CAs that may have issued Cryostat's certificate
+		secret := newCABundleSecret(cr)
+		err := c.Client.Get(ctx, types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, secret)
+		if err != nil {
+			return nil, err
+		}
+		caCert = secret.Data[constants.CAKey]
+	}
+	config := &cryostatclient.Config{
+		BaseURL: &url.URL{
+			Scheme: scheme,
+			Host:   fmt.Sprintf("%s.%s.svc:%d", cr.Name, cr.InstallNamespace, *configureCoreService(cr).HTTPPort),
+		},
+		CACert: caCert,
+	}
+	return c.NewCryostatClient(config)
+}
+
+// getCryostatNamespace returns the namespace of the referenced Cryostat instance,
+// which defaults to the namespace of the referring resource
+func getCryostatNamespace(ref *operatorv1beta2.CryostatReference, namespace string) string {
+	if len(ref.Namespace) > 0 {
+		return ref.Namespace
+	}
+	return namespace
+}
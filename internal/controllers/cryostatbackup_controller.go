@@ -0,0 +1,377 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	resources "github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Verify that *CryostatBackupReconciler implements CommonReconciler.
+var _ CommonReconciler = (*CryostatBackupReconciler)(nil)
+
+// CryostatBackupReconciler reconciles a CryostatBackup object
+type CryostatBackupReconciler struct {
+	*ReconcilerConfig
+}
+
+// How long to wait before checking again for a Cryostat that does not exist
+const backupCryostatRetryPeriod = time.Minute
+
+// How long to wait before checking again for a Cryostat whose database and storage are unavailable
+const backupWaitRetryPeriod = 10 * time.Second
+
+// Reasons for CryostatBackup and CryostatRestore Conditions
+const (
+	reasonBackupSucceeded    = "BackupSucceeded"
+	reasonBackupFailed       = "BackupFailed"
+	reasonBackupInProgress   = "BackupInProgress"
+	reasonInvalidDestination = "InvalidDestination"
+)
+
+func NewCryostatBackupReconciler(config *ReconcilerConfig) (*CryostatBackupReconciler, error) {
+	return &CryostatBackupReconciler{
+		ReconcilerConfig: config,
+	}, nil
+}
+
+// +kubebuilder:rbac:groups=operator.cryostat.io,resources=cryostatbackups,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=operator.cryostat.io,resources=cryostatbackups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=operator.cryostat.io,resources=cryostatbackups/finalizers,verbs=update
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=create;get;list;watch;delete
+
+// Reconcile processes a CryostatBackup CR and runs a Job that backs up the Cryostat instance
+func (r *CryostatBackupReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+
+	reqLogger.Info("Reconciling CryostatBackup")
+
+	// Fetch the CryostatBackup instance
+	backup := &operatorv1beta2.CryostatBackup{}
+	err := r.Client.Get(ctx, request.NamespacedName, backup)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			reqLogger.Info("CryostatBackup instance not found")
+			return reconcile.Result{}, nil
+		}
+		reqLogger.Error(err, "Error reading CryostatBackup instance")
+		return reconcile.Result{}, err
+	}
+
+	// A backup is only taken once
+	if isBackupFinished(backup.Status.Conditions) {
+		return reconcile.Result{}, nil
+	}
+
+	// The Job has already been started, so only its progress is needed
+	if len(backup.Status.JobName) > 0 {
+		return r.checkBackupJob(ctx, backup)
+	}
+
+	err = validateBackupLocation(&backup.Spec.Destination)
+	if err != nil {
+		return reconcile.Result{}, r.updateBackupCondition(ctx, backup, metav1.ConditionFalse,
+			reasonInvalidDestination, err.Error())
+	}
+
+	cr, result, reason, message, err := r.getBackupCryostat(ctx, backup.Spec.CryostatName, backup.Namespace)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if cr == nil {
+		return result, r.updateBackupCondition(ctx, backup, metav1.ConditionFalse, reason, message)
+	}
+
+	config, err := r.newBackupJobConfig(ctx, cr)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	backup.Status.ArchiveName = fmt.Sprintf("%s-%s.tar.gz", cr.Name, backup.Name)
+	job := resources.NewBackupJob(cr, backup, config)
+	err = r.createBackupJob(ctx, backup, job)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	reqLogger.Info("Started backup", "Job", job.Name)
+
+	now := metav1.Now()
+	backup.Status.JobName = job.Name
+	backup.Status.StartTime = &now
+	return reconcile.Result{}, r.updateBackupCondition(ctx, backup, metav1.ConditionFalse, reasonBackupInProgress,
+		fmt.Sprintf("Job %s is backing up Cryostat %s", job.Name, cr.Name))
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CryostatBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c := r.NewControllerBuilder(mgr)
+	c = c.For(&operatorv1beta2.CryostatBackup{})
+	c = c.Owns(&batchv1.Job{})
+	// Start waiting backups once the Cryostat they refer to is created or becomes available
+	c = c.Watches(&operatorv1beta2.Cryostat{}, c.EnqueueRequestsFromMapFunc(r.backupsForCryostat))
+	return c.Complete(r)
+}
+
+func (r *CryostatBackupReconciler) GetConfig() *ReconcilerConfig {
+	return r.ReconcilerConfig
+}
+
+func (r *CryostatBackupReconciler) backupsForCryostat(ctx context.Context, obj client.Object) []reconcile.Request {
+	backups := &operatorv1beta2.CryostatBackupList{}
+	err := r.Client.List(ctx, backups, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		r.Log.Error(err, "failed to list CryostatBackups", "Cryostat", obj.GetName())
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, backup := range backups.Items {
+		if backup.Spec.CryostatName == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Name:      backup.Name,
+				Namespace: backup.Namespace,
+			}})
+		}
+	}
+	return requests
+}
+
+func (r *CryostatBackupReconciler) checkBackupJob(ctx context.Context, backup *operatorv1beta2.CryostatBackup) (ctrl.Result, error) {
+	job, finished, succeeded, message, err := r.getBackupJobResult(ctx, backup.Status.JobName, backup.Namespace)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if job == nil {
+		return reconcile.Result{}, r.updateBackupCondition(ctx, backup, metav1.ConditionFalse, reasonBackupFailed,
+			fmt.Sprintf("Job %s was deleted before the backup completed", backup.Status.JobName))
+	}
+	if !finished {
+		// Reconciled again when the Job changes
+		return reconcile.Result{}, nil
+	}
+
+	backup.Status.CompletionTime = getJobCompletionTime(job)
+	if !succeeded {
+		r.Log.Info("Backup failed", "CryostatBackup.Namespace", backup.Namespace, "CryostatBackup.Name", backup.Name,
+			"Job", job.Name)
+		return reconcile.Result{}, r.updateBackupCondition(ctx, backup, metav1.ConditionFalse, reasonBackupFailed,
+			fmt.Sprintf("Job %s failed: %s", job.Name, message))
+	}
+
+	result := &backupJobResult{}
+	err = json.Unmarshal([]byte(message), result)
+	if err != nil {
+		return reconcile.Result{}, r.updateBackupCondition(ctx, backup, metav1.ConditionFalse, reasonBackupFailed,
+			fmt.Sprintf("Job %s completed without reporting the backup archive: %s", job.Name, err.Error()))
+	}
+	size := resource.NewQuantity(result.Size, resource.BinarySI)
+	backup.Status.Size = size
+	backup.Status.Checksum = result.Checksum
+	return reconcile.Result{}, r.updateBackupCondition(ctx, backup, metav1.ConditionTrue, reasonBackupSucceeded,
+		fmt.Sprintf("Backup archive %s is stored in the destination", backup.Status.ArchiveName))
+}
+
+func (r *CryostatBackupReconciler) updateBackupCondition(ctx context.Context, backup *operatorv1beta2.CryostatBackup,
+	status metav1.ConditionStatus, reason string, message string) error {
+	meta.SetStatusCondition(&backup.Status.Conditions, metav1.Condition{
+		Type:               string(operatorv1beta2.ConditionTypeBackupComplete),
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: backup.Generation,
+	})
+	err := r.Client.Status().Update(ctx, backup)
+	if err != nil {
+		r.Log.Error(err, "failed to update condition", "CryostatBackup.Namespace", backup.Namespace,
+			"CryostatBackup.Name", backup.Name)
+	}
+	return err
+}
+
+// backupJobResult is written by a successful backup Job to its termination message
+type backupJobResult struct {
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum"`
+}
+
+// isBackupFinished returns whether a backup has either succeeded or failed
+func isBackupFinished(conditions []metav1.Condition) bool {
+	condition := meta.FindStatusCondition(conditions, string(operatorv1beta2.ConditionTypeBackupComplete))
+	return condition != nil && (condition.Status == metav1.ConditionTrue || condition.Reason == reasonBackupFailed)
+}
+
+func validateBackupLocation(location *operatorv1beta2.BackupLocation) error {
+	if (location.PVC == nil) == (location.S3 == nil) {
+		return fmt.Errorf("exactly one of pvc or s3 must be specified")
+	}
+	if location.PVC != nil && strings.Contains(location.PVC.Path, "..") {
+		return fmt.Errorf("path %q must not refer to a parent directory", location.PVC.Path)
+	}
+	return nil
+}
+
+// getBackupCryostat returns the Cryostat instance to back up or restore into. If the instance
+// does not exist or is unavailable, the returned instance is nil, and the result, reason and message
+// describe why.
+func (r *ReconcilerConfig) getBackupCryostat(ctx context.Context, name string, namespace string) (*model.CryostatInstance,
+	ctrl.Result, string, string, error) {
+	cryostat := &operatorv1beta2.Cryostat{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, cryostat)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, reconcile.Result{RequeueAfter: backupCryostatRetryPeriod}, reasonCryostatNotFound,
+				fmt.Sprintf("Cryostat %s/%s does not exist", namespace, name), nil
+		}
+		return nil, reconcile.Result{}, "", "", err
+	}
+	// Reconciled again once the Cryostat is resumed
+	if err := checkCryostatPaused(cryostat); err != nil {
+		reason, message := getCryostatPausedCondition(err, namespace, name)
+		return nil, reconcile.Result{}, reason, message, nil
+	}
+
+	for _, condType := range []operatorv1beta2.CryostatConditionType{
+		operatorv1beta2.ConditionTypeDatabaseDeploymentAvailable,
+		operatorv1beta2.ConditionTypeStorageDeploymentAvailable,
+	} {
+		if !meta.IsStatusConditionTrue(cryostat.Status.Conditions, string(condType)) {
+			return nil, reconcile.Result{RequeueAfter: backupWaitRetryPeriod}, reasonWaitingForCryostat,
+				fmt.Sprintf("Waiting for the database and storage of Cryostat %s/%s to become available",
+					namespace, name), nil
+		}
+	}
+	return model.FromCryostat(cryostat), reconcile.Result{}, "", "", nil
+}
+
+// newBackupJobConfig returns the parameters that connect backup and restore Jobs to
+// the database and object storage of the Cryostat instance
+func (r *ReconcilerConfig) newBackupJobConfig(ctx context.Context, cr *model.CryostatInstance) (*resources.BackupJobConfig, error) {
+	fsGroup, err := r.getFSGroup(ctx, cr.InstallNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := "http"
+	caSecretName := ""
+	if r.IsCertManagerEnabled(cr) {
+		scheme = "https"
+		caSecretName = newCABundleSecret(cr).Name
+	}
+	storageConfig := configureStorageService(cr, nil)
+	return &resources.BackupJobConfig{
+		ImageTag:     r.getImageTags(cr).DatabaseImageTag,
+		DatabasePort: *configureDatabaseService(cr).DatabasePort,
+		StorageURL: &url.URL{
+			Scheme: scheme,
+			Host:   fmt.Sprintf("%s-storage.%s.svc.cluster.local:%d", cr.Name, cr.InstallNamespace, *storageConfig.HTTPPort),
+		},
+		CASecretName: caSecretName,
+		FSGroup:      *fsGroup,
+		OpenShift:    r.IsOpenShift,
+	}, nil
+}
+
+// createBackupJob creates the Job owned by the backup or restore, unless it already exists
+func (r *ReconcilerConfig) createBackupJob(ctx context.Context, owner client.Object, job *batchv1.Job) error {
+	err := r.Client.Get(ctx, types.NamespacedName{Name: job.Name, Namespace: job.Namespace}, &batchv1.Job{})
+	if err == nil {
+		return nil
+	}
+	if !kerrors.IsNotFound(err) {
+		return err
+	}
+
+	err = controllerutil.SetControllerReference(owner, job, r.Scheme)
+	if err != nil {
+		return err
+	}
+	err = r.Client.Create(ctx, job)
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// getBackupJobResult returns the Job of a backup or restore, or nil if it no longer exists. If the Job has
+// finished, the termination message of its containers is returned with whether it succeeded.
+func (r *ReconcilerConfig) getBackupJobResult(ctx context.Context, name string, namespace string) (job *batchv1.Job,
+	finished bool, succeeded bool, message string, err error) {
+	job = &batchv1.Job{}
+	err = r.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, job)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, false, false, "", nil
+		}
+		return nil, false, false, "", err
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		if condition.Type == batchv1.JobComplete {
+			finished, succeeded = true, true
+		} else if condition.Type == batchv1.JobFailed {
+			finished, message = true, condition.Message
+		}
+	}
+	if !finished {
+		return job, false, false, "", nil
+	}
+
+	pods := &corev1.PodList{}
+	err = r.APIReader.List(ctx, pods, client.InNamespace(namespace), client.MatchingLabels{"job-name": name})
+	if err != nil {
+		return nil, false, false, "", err
+	}
+	for _, pod := range pods.Items {
+		statuses := append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			terminated := status.State.Terminated
+			if terminated == nil || len(terminated.Message) == 0 || (terminated.ExitCode == 0) != succeeded {
+				continue
+			}
+			message = strings.TrimSpace(terminated.Message)
+		}
+	}
+	return job, true, succeeded, message, nil
+}
+
+func getJobCompletionTime(job *batchv1.Job) *metav1.Time {
+	if job.Status.CompletionTime != nil {
+		return job.Status.CompletionTime
+	}
+	now := metav1.Now()
+	return &now
+}
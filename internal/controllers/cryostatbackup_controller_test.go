@@ -0,0 +1,394 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers_test
+
+import (
+	"context"
+	"time"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controllers"
+	"github.com/cryostatio/cryostat-operator/internal/test"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type cryostatBackupTestInput struct {
+	controller *controllers.CryostatBackupReconciler
+	backup     *operatorv1beta2.CryostatBackup
+	*test.ControllerTestInput
+}
+
+var _ = Describe("CryostatBackupController", func() {
+	var t *cryostatBackupTestInput
+
+	BeforeEach(func() {
+		t = &cryostatBackupTestInput{
+			ControllerTestInput: test.NewControllerTestInput(),
+		}
+		t.backup = t.NewCryostatBackup()
+		t.Objs = []ctrlclient.Object{
+			t.NewNamespace(),
+			t.NewCryostatWithBackendsAvailable().Object,
+		}
+	})
+
+	JustBeforeEach(func() {
+		t.Objs = append(t.Objs, t.backup)
+		var err error
+		t.controller, err = controllers.NewCryostatBackupReconciler(t.NewReconcilerConfig(&operatorv1beta2.CryostatBackup{}, &batchv1.Job{}))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Describe("reconciling a request", func() {
+		Context("with an available Cryostat", func() {
+			JustBeforeEach(func() {
+				t.reconcileCryostatBackup()
+			})
+
+			It("should create a Job owned by the backup", func() {
+				job := t.getBackupJob("my-backup-backup")
+				Expect(job.Labels).To(Equal(map[string]string{
+					"app":       t.Name,
+					"kind":      "cryostat",
+					"component": "backup",
+				}))
+				Expect(job.Spec.Template.Labels).To(Equal(job.Labels))
+				Expect(metav1.IsControlledBy(job, t.getCryostatBackup())).To(BeTrue())
+				Expect(job.Spec.Template.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+			})
+
+			It("should run the database image", func() {
+				job := t.getBackupJob("my-backup-backup")
+				Expect(job.Spec.Template.Spec.Containers).To(HaveLen(1))
+				container := job.Spec.Template.Spec.Containers[0]
+				Expect(container.Image).To(HavePrefix("quay.io/cryostat/cryostat-db:"))
+				Expect(container.Command).To(HaveLen(3))
+				Expect(container.Command[2]).To(ContainSubstring("pg_dump"))
+			})
+
+			It("should connect to the database and storage", func() {
+				envs := t.getBackupJob("my-backup-backup").Spec.Template.Spec.Containers[0].Env
+				Expect(envs).To(ContainElements(
+					corev1.EnvVar{Name: "PGHOST", Value: "cryostat-database.test.svc.cluster.local"},
+					corev1.EnvVar{Name: "PGPORT", Value: "5432"},
+					corev1.EnvVar{Name: "PGSSLMODE", Value: "verify-full"},
+					corev1.EnvVar{Name: "PGSSLROOTCERT", Value: "/var/run/secrets/operator.cryostat.io/cryostat-ca-bundle/ca.crt"},
+					corev1.EnvVar{Name: "STORAGE_URL", Value: "https://cryostat-storage.test.svc.cluster.local:8333"},
+					corev1.EnvVar{Name: "ARCHIVE_NAME", Value: "cryostat-my-backup.tar.gz"},
+					corev1.EnvVar{Name: "LOCATION_DIR", Value: "/backup/cryostat"},
+				))
+			})
+
+			It("should mount the destination PVC", func() {
+				volumes := t.getBackupJob("my-backup-backup").Spec.Template.Spec.Volumes
+				Expect(volumes).To(ContainElement(corev1.Volume{
+					Name: "backup-location",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: "my-backups",
+						},
+					},
+				}))
+			})
+
+			It("should report the backup in progress", func() {
+				backup := t.getCryostatBackup()
+				Expect(backup.Status.JobName).To(Equal("my-backup-backup"))
+				Expect(backup.Status.ArchiveName).To(Equal("cryostat-my-backup.tar.gz"))
+				Expect(backup.Status.StartTime).ToNot(BeNil())
+				t.expectCondition(backup, metav1.ConditionFalse, "BackupInProgress")
+			})
+
+			Context("when the Job completes", func() {
+				JustBeforeEach(func() {
+					t.finishJob("my-backup-backup", batchv1.JobComplete, 0,
+						`{"size":1048576,"checksum":"sha256:0123456789abcdef"}`)
+					t.reconcileCryostatBackup()
+				})
+
+				It("should report the archive", func() {
+					backup := t.getCryostatBackup()
+					Expect(backup.Status.Size).ToNot(BeNil())
+					Expect(backup.Status.Size.Cmp(resource.MustParse("1Mi"))).To(Equal(0))
+					Expect(backup.Status.Checksum).To(Equal("sha256:0123456789abcdef"))
+					Expect(backup.Status.CompletionTime).ToNot(BeNil())
+					t.expectCondition(backup, metav1.ConditionTrue, "BackupSucceeded")
+				})
+
+				It("should not start another Job after the original is deleted", func() {
+					err := t.Client.Delete(context.Background(), t.getBackupJob("my-backup-backup"))
+					Expect(err).ToNot(HaveOccurred())
+					t.reconcileCryostatBackup()
+					t.expectCondition(t.getCryostatBackup(), metav1.ConditionTrue, "BackupSucceeded")
+				})
+			})
+
+			Context("when the Job fails", func() {
+				JustBeforeEach(func() {
+					t.finishJob("my-backup-backup", batchv1.JobFailed, 1, "Failed to dump database")
+					t.reconcileCryostatBackup()
+				})
+
+				It("should report the failure", func() {
+					backup := t.getCryostatBackup()
+					Expect(backup.Status.Size).To(BeNil())
+					condition := t.expectCondition(backup, metav1.ConditionFalse, "BackupFailed")
+					Expect(condition.Message).To(ContainSubstring("Failed to dump database"))
+				})
+			})
+
+			Context("when the Job is deleted before completing", func() {
+				JustBeforeEach(func() {
+					err := t.Client.Delete(context.Background(), t.getBackupJob("my-backup-backup"))
+					Expect(err).ToNot(HaveOccurred())
+					t.reconcileCryostatBackup()
+				})
+
+				It("should report the failure", func() {
+					t.expectCondition(t.getCryostatBackup(), metav1.ConditionFalse, "BackupFailed")
+				})
+			})
+		})
+
+		Context("with an S3 destination", func() {
+			BeforeEach(func() {
+				t.backup = t.NewCryostatBackupToS3()
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileCryostatBackup()
+			})
+
+			It("should upload to the bucket", func() {
+				job := t.getBackupJob("my-backup-backup")
+				envs := job.Spec.Template.Spec.Containers[0].Env
+				Expect(envs).To(ContainElements(
+					corev1.EnvVar{Name: "LOCATION_S3_URL", Value: "https://s3.example.com/my-bucket"},
+					corev1.EnvVar{Name: "LOCATION_S3_PREFIX", Value: "cryostat/"},
+					corev1.EnvVar{Name: "LOCATION_S3_REGION", Value: "eu-west-1"},
+				))
+				for _, env := range envs {
+					if env.Name == "AWS_ACCESS_KEY_ID" || env.Name == "AWS_SECRET_ACCESS_KEY" {
+						Expect(env.ValueFrom.SecretKeyRef.Name).To(Equal("my-s3-credentials"))
+						Expect(env.ValueFrom.SecretKeyRef.Key).To(Equal(env.Name))
+					}
+				}
+				for _, volume := range job.Spec.Template.Spec.Volumes {
+					Expect(volume.Name).ToNot(Equal("backup-location"))
+				}
+			})
+		})
+
+		Context("with TLS disabled", func() {
+			BeforeEach(func() {
+				cr := t.NewCryostatCertManagerDisabled()
+				*cr.Status = *t.NewCryostatWithBackendsAvailable().Status
+				t.Objs[1] = cr.Object
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileCryostatBackup()
+			})
+
+			It("should connect without TLS", func() {
+				job := t.getBackupJob("my-backup-backup")
+				envs := job.Spec.Template.Spec.Containers[0].Env
+				Expect(envs).To(ContainElement(corev1.EnvVar{Name: "STORAGE_URL",
+					Value: "http://cryostat-storage.test.svc.cluster.local:8333"}))
+				for _, env := range envs {
+					Expect(env.Name).ToNot(Equal("PGSSLMODE"))
+				}
+				for _, volume := range job.Spec.Template.Spec.Volumes {
+					Expect(volume.Name).ToNot(Equal("ca-bundle"))
+				}
+			})
+		})
+
+		Context("with both destinations", func() {
+			BeforeEach(func() {
+				t.backup.Spec.Destination.S3 = t.NewCryostatBackupToS3().Spec.Destination.S3
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileCryostatBackup()
+			})
+
+			It("should report the invalid destination", func() {
+				t.expectCondition(t.getCryostatBackup(), metav1.ConditionFalse, "InvalidDestination")
+				t.expectNoBackupJob("my-backup-backup")
+			})
+		})
+
+		Context("with a missing Cryostat", func() {
+			BeforeEach(func() {
+				t.Objs = []ctrlclient.Object{t.NewNamespace()}
+			})
+
+			It("should report the missing Cryostat and requeue", func() {
+				result, err := t.reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: time.Minute}))
+				t.expectCondition(t.getCryostatBackup(), metav1.ConditionFalse, "CryostatNotFound")
+				t.expectNoBackupJob("my-backup-backup")
+			})
+		})
+
+		Context("with an unavailable Cryostat", func() {
+			BeforeEach(func() {
+				t.Objs = []ctrlclient.Object{t.NewNamespace(), t.NewCryostat().Object}
+			})
+
+			It("should wait for the Cryostat", func() {
+				result, err := t.reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: 10 * time.Second}))
+				t.expectCondition(t.getCryostatBackup(), metav1.ConditionFalse, "WaitingForCryostat")
+				t.expectNoBackupJob("my-backup-backup")
+			})
+		})
+
+		Context("with a paused Cryostat", func() {
+			BeforeEach(func() {
+				cr := t.NewCryostatWithBackendsAvailable()
+				cr.Spec.Paused = true
+				t.Objs = []ctrlclient.Object{t.NewNamespace(), cr.Object}
+			})
+
+			It("should wait for the Cryostat to resume", func() {
+				result, err := t.reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{}))
+				t.expectCondition(t.getCryostatBackup(), metav1.ConditionFalse, "CryostatPaused")
+				t.expectNoBackupJob("my-backup-backup")
+			})
+		})
+
+		Context("with reconciliation of Cryostat paused", func() {
+			BeforeEach(func() {
+				cr := t.NewCryostatWithBackendsAvailable()
+				cr.Spec.ReconcilePaused = true
+				t.Objs = []ctrlclient.Object{t.NewNamespace(), cr.Object}
+			})
+
+			It("should wait for reconciliation to resume", func() {
+				result, err := t.reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{}))
+				t.expectCondition(t.getCryostatBackup(), metav1.ConditionFalse, "CryostatReconcilePaused")
+				t.expectNoBackupJob("my-backup-backup")
+			})
+		})
+
+		Context("with a deleted CryostatBackup", func() {
+			It("should not return an error", func() {
+				err := t.Client.Delete(context.Background(), t.backup)
+				Expect(err).ToNot(HaveOccurred())
+				t.reconcileCryostatBackup()
+			})
+		})
+	})
+
+	Describe("setting up the controller", func() {
+		JustBeforeEach(func() {
+			err := t.controller.SetupWithManager(nil)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should own backup Jobs", func() {
+			Expect(t.ControllerBuilder.OwnsCalls).To(HaveLen(1))
+			Expect(t.ControllerBuilder.OwnsCalls[0].Object).To(BeAssignableToTypeOf(&batchv1.Job{}))
+		})
+
+		It("should reconcile CryostatBackups when their Cryostat changes", func() {
+			builder := t.ControllerBuilder
+			Expect(builder.MapFuncs).To(HaveLen(1))
+			other := t.NewCryostatBackup()
+			other.Name = "other-backup"
+			other.Spec.CryostatName = "other"
+			Expect(t.Client.Create(context.Background(), other)).To(Succeed())
+
+			requests := builder.MapFuncs[0](context.Background(), t.NewCryostat().Object)
+			Expect(requests).To(ConsistOf(reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: t.backup.Name, Namespace: t.backup.Namespace},
+			}))
+		})
+	})
+})
+
+func (t *cryostatBackupTestInput) reconcile() (reconcile.Result, error) {
+	return t.Reconcile(t.controller, t.backup)
+}
+
+func (t *cryostatBackupTestInput) reconcileCryostatBackup() {
+	t.ExpectReconcile(t.controller, t.backup)
+}
+
+func (t *cryostatBackupTestInput) getCryostatBackup() *operatorv1beta2.CryostatBackup {
+	backup := t.backup.DeepCopy()
+	t.Get(backup)
+	return backup
+}
+
+func (t *cryostatBackupTestInput) getBackupJob(name string) *batchv1.Job {
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: t.Namespace}}
+	t.Get(job)
+	return job
+}
+
+func (t *cryostatBackupTestInput) expectNoBackupJob(name string) {
+	t.ExpectNotFound(&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: t.Namespace}})
+}
+
+func (t *cryostatBackupTestInput) finishJob(name string, condType batchv1.JobConditionType, exitCode int32, message string) {
+	finishBackupJob(t.Client, t.TestResources, t.getBackupJob(name), condType, exitCode, message)
+}
+
+func (t *cryostatBackupTestInput) expectCondition(backup *operatorv1beta2.CryostatBackup, status metav1.ConditionStatus, reason string) *metav1.Condition {
+	condition := meta.FindStatusCondition(backup.Status.Conditions, string(operatorv1beta2.ConditionTypeBackupComplete))
+	Expect(condition).ToNot(BeNil())
+	Expect(condition.Status).To(Equal(status))
+	Expect(condition.Reason).To(Equal(reason))
+	Expect(condition.ObservedGeneration).To(Equal(backup.Generation))
+	return condition
+}
+
+// finishBackupJob simulates the Job controller finishing a backup or restore Job
+func finishBackupJob(client ctrlclient.Client, resources *test.TestResources, job *batchv1.Job,
+	condType batchv1.JobConditionType, exitCode int32, message string) {
+	now := metav1.Now()
+	job.Status.Conditions = []batchv1.JobCondition{
+		{
+			Type:    condType,
+			Status:  corev1.ConditionTrue,
+			Message: string(condType),
+		},
+	}
+	if condType == batchv1.JobComplete {
+		job.Status.CompletionTime = &now
+	}
+	err := client.Status().Update(context.Background(), job)
+	Expect(err).ToNot(HaveOccurred())
+	err = client.Create(context.Background(), resources.NewBackupJobPod(job.Name, exitCode, message))
+	Expect(err).ToNot(HaveOccurred())
+}
@@ -0,0 +1,300 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	resources "github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Verify that *CryostatRestoreReconciler implements CommonReconciler.
+var _ CommonReconciler = (*CryostatRestoreReconciler)(nil)
+
+// CryostatRestoreReconciler reconciles a CryostatRestore object
+type CryostatRestoreReconciler struct {
+	*ReconcilerConfig
+}
+
+// Reasons for CryostatRestore Conditions
+const (
+	reasonRestoreSucceeded  = "RestoreSucceeded"
+	reasonRestoreFailed     = "RestoreFailed"
+	reasonRestoreInProgress = "RestoreInProgress"
+	reasonBackupNotFound    = "BackupNotFound"
+	reasonWaitingForBackup  = "WaitingForBackup"
+	reasonInvalidSource     = "InvalidSource"
+	// The restore is ready to start, and is waiting for the Cryostat components
+	// that use the database to be scaled down
+	reasonWaitingForScaleDown = "WaitingForScaleDown"
+)
+
+func NewCryostatRestoreReconciler(config *ReconcilerConfig) (*CryostatRestoreReconciler, error) {
+	return &CryostatRestoreReconciler{
+		ReconcilerConfig: config,
+	}, nil
+}
+
+// +kubebuilder:rbac:groups=operator.cryostat.io,resources=cryostatrestores,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=operator.cryostat.io,resources=cryostatrestores/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=operator.cryostat.io,resources=cryostatrestores/finalizers,verbs=update
+
+// Reconcile processes a CryostatRestore CR and runs a Job that restores the backup into the Cryostat instance
+func (r *CryostatRestoreReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+
+	reqLogger.Info("Reconciling CryostatRestore")
+
+	// Fetch the CryostatRestore instance
+	restore := &operatorv1beta2.CryostatRestore{}
+	err := r.Client.Get(ctx, request.NamespacedName, restore)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			reqLogger.Info("CryostatRestore instance not found")
+			return reconcile.Result{}, nil
+		}
+		reqLogger.Error(err, "Error reading CryostatRestore instance")
+		return reconcile.Result{}, err
+	}
+
+	// A restore is only performed once
+	if isRestoreFinished(restore.Status.Conditions) {
+		return reconcile.Result{}, nil
+	}
+
+	// The Job has already been started, so only its progress is needed
+	if len(restore.Status.JobName) > 0 {
+		return r.checkRestoreJob(ctx, restore)
+	}
+
+	source, reason, message, err := r.getRestoreSource(ctx, restore)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if source == nil {
+		// Reconciled again once the CryostatBackup is created or completes
+		return reconcile.Result{}, r.updateRestoreCondition(ctx, restore, metav1.ConditionFalse, reason, message)
+	}
+
+	cr, result, reason, message, err := r.getBackupCryostat(ctx, restore.Spec.CryostatName, restore.Namespace)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if cr == nil {
+		return result, r.updateRestoreCondition(ctx, restore, metav1.ConditionFalse, reason, message)
+	}
+
+	// The restore replaces the database contents, so Cryostat must not be using it.
+	// The Cryostat reconciler scales down its components while this restore is waiting.
+	scaledDown, err := r.isScaledDownForRestore(ctx, cr)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !scaledDown {
+		return reconcile.Result{RequeueAfter: backupWaitRetryPeriod}, r.updateRestoreCondition(ctx, restore,
+			metav1.ConditionFalse, reasonWaitingForScaleDown, fmt.Sprintf("Waiting for Cryostat %s to scale down", cr.Name))
+	}
+
+	config, err := r.newBackupJobConfig(ctx, cr)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	job := resources.NewRestoreJob(cr, restore, source, config)
+	err = r.createBackupJob(ctx, restore, job)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	reqLogger.Info("Started restore", "Job", job.Name)
+
+	now := metav1.Now()
+	restore.Status.JobName = job.Name
+	restore.Status.StartTime = &now
+	return reconcile.Result{}, r.updateRestoreCondition(ctx, restore, metav1.ConditionFalse, reasonRestoreInProgress,
+		fmt.Sprintf("Job %s is restoring backup archive %s into Cryostat %s", job.Name, source.ArchiveName, cr.Name))
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CryostatRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c := r.NewControllerBuilder(mgr)
+	c = c.For(&operatorv1beta2.CryostatRestore{})
+	c = c.Owns(&batchv1.Job{})
+	// Start waiting restores once the backup they refer to completes
+	c = c.Watches(&operatorv1beta2.CryostatBackup{}, c.EnqueueRequestsFromMapFunc(r.restoresForBackup))
+	// Start waiting restores once the Cryostat they refer to is created or becomes available
+	c = c.Watches(&operatorv1beta2.Cryostat{}, c.EnqueueRequestsFromMapFunc(r.restoresForCryostat))
+	return c.Complete(r)
+}
+
+func (r *CryostatRestoreReconciler) GetConfig() *ReconcilerConfig {
+	return r.ReconcilerConfig
+}
+
+func (r *CryostatRestoreReconciler) restoresForBackup(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.restoresMatching(ctx, obj, func(restore *operatorv1beta2.CryostatRestore) bool {
+		return restore.Spec.BackupName == obj.GetName()
+	})
+}
+
+func (r *CryostatRestoreReconciler) restoresForCryostat(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.restoresMatching(ctx, obj, func(restore *operatorv1beta2.CryostatRestore) bool {
+		return restore.Spec.CryostatName == obj.GetName()
+	})
+}
+
+func (r *CryostatRestoreReconciler) restoresMatching(ctx context.Context, obj client.Object,
+	matches func(*operatorv1beta2.CryostatRestore) bool) []reconcile.Request {
+	restores := &operatorv1beta2.CryostatRestoreList{}
+	err := r.Client.List(ctx, restores, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		r.Log.Error(err, "failed to list CryostatRestores", "Name", obj.GetName())
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for i := range restores.Items {
+		restore := &restores.Items[i]
+		if matches(restore) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Name:      restore.Name,
+				Namespace: restore.Namespace,
+			}})
+		}
+	}
+	return requests
+}
+
+// getRestoreSource returns the location of the backup archive to restore. If the archive is not
+// available, the returned source is nil, and the reason and message describe why.
+func (r *CryostatRestoreReconciler) getRestoreSource(ctx context.Context,
+	restore *operatorv1beta2.CryostatRestore) (*operatorv1beta2.BackupSource, string, string, error) {
+	if len(restore.Spec.BackupName) == 0 {
+		if restore.Spec.Source == nil {
+			return nil, reasonInvalidSource, "Either backupName or source must be specified", nil
+		}
+		err := validateBackupLocation(&restore.Spec.Source.BackupLocation)
+		if err == nil && len(restore.Spec.Source.ArchiveName) == 0 {
+			err = fmt.Errorf("archiveName must be specified")
+		}
+		if err != nil {
+			return nil, reasonInvalidSource, err.Error(), nil
+		}
+		return restore.Spec.Source, "", "", nil
+	}
+
+	backup := &operatorv1beta2.CryostatBackup{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: restore.Spec.BackupName, Namespace: restore.Namespace}, backup)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, reasonBackupNotFound, fmt.Sprintf("CryostatBackup %s does not exist", restore.Spec.BackupName), nil
+		}
+		return nil, "", "", err
+	}
+	if !meta.IsStatusConditionTrue(backup.Status.Conditions, string(operatorv1beta2.ConditionTypeBackupComplete)) {
+		return nil, reasonWaitingForBackup, fmt.Sprintf("Waiting for CryostatBackup %s to complete",
+			restore.Spec.BackupName), nil
+	}
+	return &operatorv1beta2.BackupSource{
+		BackupLocation: backup.Spec.Destination,
+		ArchiveName:    backup.Status.ArchiveName,
+		Checksum:       backup.Status.Checksum,
+	}, "", "", nil
+}
+
+// isScaledDownForRestore returns whether the Cryostat components that use the database have no pods
+func (r *CryostatRestoreReconciler) isScaledDownForRestore(ctx context.Context, cr *model.CryostatInstance) (bool, error) {
+	for _, name := range []string{cr.Name, cr.Name + "-reports"} {
+		deploy := &appsv1.Deployment{}
+		err := r.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: cr.InstallNamespace}, deploy)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return false, err
+		}
+		if deploy.Spec.Replicas == nil || *deploy.Spec.Replicas > 0 || deploy.Status.Replicas > 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (r *CryostatRestoreReconciler) checkRestoreJob(ctx context.Context, restore *operatorv1beta2.CryostatRestore) (ctrl.Result, error) {
+	job, finished, succeeded, message, err := r.getBackupJobResult(ctx, restore.Status.JobName, restore.Namespace)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if job == nil {
+		return reconcile.Result{}, r.updateRestoreCondition(ctx, restore, metav1.ConditionFalse, reasonRestoreFailed,
+			fmt.Sprintf("Job %s was deleted before the restore completed", restore.Status.JobName))
+	}
+	if !finished {
+		// Reconciled again when the Job changes
+		return reconcile.Result{}, nil
+	}
+
+	restore.Status.CompletionTime = getJobCompletionTime(job)
+	if !succeeded {
+		r.Log.Info("Restore failed", "CryostatRestore.Namespace", restore.Namespace, "CryostatRestore.Name", restore.Name,
+			"Job", job.Name)
+		return reconcile.Result{}, r.updateRestoreCondition(ctx, restore, metav1.ConditionFalse, reasonRestoreFailed,
+			fmt.Sprintf("Job %s failed: %s", job.Name, message))
+	}
+	return reconcile.Result{}, r.updateRestoreCondition(ctx, restore, metav1.ConditionTrue, reasonRestoreSucceeded,
+		fmt.Sprintf("Backup was restored into Cryostat %s", restore.Spec.CryostatName))
+}
+
+func (r *CryostatRestoreReconciler) updateRestoreCondition(ctx context.Context, restore *operatorv1beta2.CryostatRestore,
+	status metav1.ConditionStatus, reason string, message string) error {
+	meta.SetStatusCondition(&restore.Status.Conditions, metav1.Condition{
+		Type:               string(operatorv1beta2.ConditionTypeRestoreComplete),
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: restore.Generation,
+	})
+	err := r.Client.Status().Update(ctx, restore)
+	if err != nil {
+		r.Log.Error(err, "failed to update condition", "CryostatRestore.Namespace", restore.Namespace,
+			"CryostatRestore.Name", restore.Name)
+	}
+	return err
+}
+
+// isRestoreFinished returns whether a restore has either succeeded or failed
+func isRestoreFinished(conditions []metav1.Condition) bool {
+	condition := meta.FindStatusCondition(conditions, string(operatorv1beta2.ConditionTypeRestoreComplete))
+	return condition != nil && (condition.Status == metav1.ConditionTrue || condition.Reason == reasonRestoreFailed)
+}
+
+// isRestoreActive returns whether a restore is about to replace, or is replacing, the contents of the
+// Cryostat instance's database, during which the components that use the database must be scaled down
+func isRestoreActive(restore *operatorv1beta2.CryostatRestore) bool {
+	condition := meta.FindStatusCondition(restore.Status.Conditions, string(operatorv1beta2.ConditionTypeRestoreComplete))
+	return condition != nil && condition.Status == metav1.ConditionFalse &&
+		(condition.Reason == reasonWaitingForScaleDown || condition.Reason == reasonRestoreInProgress)
+}
@@ -0,0 +1,340 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers_test
+
+import (
+	"context"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controllers"
+	"github.com/cryostatio/cryostat-operator/internal/test"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type cryostatRestoreTestInput struct {
+	controller *controllers.CryostatRestoreReconciler
+	restore    *operatorv1beta2.CryostatRestore
+	*test.ControllerTestInput
+}
+
+var _ = Describe("CryostatRestoreController", func() {
+	var t *cryostatRestoreTestInput
+
+	BeforeEach(func() {
+		t = &cryostatRestoreTestInput{
+			ControllerTestInput: test.NewControllerTestInput(),
+		}
+		t.restore = t.NewCryostatRestore()
+		t.Objs = []ctrlclient.Object{
+			t.NewNamespace(),
+			t.NewCryostatWithBackendsAvailable().Object,
+			t.NewCompletedCryostatBackup(),
+		}
+	})
+
+	JustBeforeEach(func() {
+		t.Objs = append(t.Objs, t.restore)
+		var err error
+		t.controller, err = controllers.NewCryostatRestoreReconciler(t.NewReconcilerConfig(&operatorv1beta2.CryostatBackup{},
+			&operatorv1beta2.CryostatRestore{}, &batchv1.Job{}))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Describe("reconciling a request", func() {
+		Context("with a completed backup", func() {
+			JustBeforeEach(func() {
+				t.reconcileCryostatRestore()
+			})
+
+			It("should create a Job owned by the restore", func() {
+				job := t.getRestoreJob()
+				Expect(metav1.IsControlledBy(job, t.getCryostatRestore())).To(BeTrue())
+				Expect(job.Spec.Template.Labels).To(HaveKeyWithValue("component", "backup"))
+				Expect(job.Spec.Template.Spec.Containers[0].Command[2]).To(ContainSubstring("pg_restore"))
+			})
+
+			It("should restore the backup's archive", func() {
+				job := t.getRestoreJob()
+				Expect(job.Spec.Template.Spec.Containers[0].Env).To(ContainElements(
+					corev1.EnvVar{Name: "ARCHIVE_NAME", Value: "cryostat-my-backup.tar.gz"},
+					corev1.EnvVar{Name: "EXPECTED_CHECKSUM", Value: "sha256:0123456789abcdef"},
+					corev1.EnvVar{Name: "LOCATION_DIR", Value: "/backup/cryostat"},
+				))
+				Expect(job.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+					Name: "backup-location",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: "my-backups",
+						},
+					},
+				}))
+			})
+
+			It("should report the restore in progress", func() {
+				restore := t.getCryostatRestore()
+				Expect(restore.Status.JobName).To(Equal("my-restore-restore"))
+				Expect(restore.Status.StartTime).ToNot(BeNil())
+				t.expectCondition(restore, metav1.ConditionFalse, "RestoreInProgress")
+			})
+
+			Context("when the Job completes", func() {
+				JustBeforeEach(func() {
+					finishBackupJob(t.Client, t.TestResources, t.getRestoreJob(), batchv1.JobComplete, 0, "")
+					t.reconcileCryostatRestore()
+				})
+
+				It("should report success", func() {
+					restore := t.getCryostatRestore()
+					Expect(restore.Status.CompletionTime).ToNot(BeNil())
+					t.expectCondition(restore, metav1.ConditionTrue, "RestoreSucceeded")
+				})
+			})
+
+			Context("when the Job fails", func() {
+				JustBeforeEach(func() {
+					finishBackupJob(t.Client, t.TestResources, t.getRestoreJob(), batchv1.JobFailed, 1,
+						"Checksum of backup archive is sha256:fedcba, expected sha256:0123456789abcdef")
+					t.reconcileCryostatRestore()
+				})
+
+				It("should report the failure", func() {
+					condition := t.expectCondition(t.getCryostatRestore(), metav1.ConditionFalse, "RestoreFailed")
+					Expect(condition.Message).To(ContainSubstring("Checksum of backup archive"))
+				})
+
+				It("should not retry", func() {
+					err := t.Client.Delete(context.Background(), t.getRestoreJob())
+					Expect(err).ToNot(HaveOccurred())
+					t.reconcileCryostatRestore()
+					t.expectNoRestoreJob()
+				})
+			})
+		})
+
+		Context("with Cryostat running", func() {
+			BeforeEach(func() {
+				t.Objs = append(t.Objs, t.newDeployment(t.Name, 1), t.newDeployment(t.Name+"-reports", 0))
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileCryostatRestore()
+			})
+
+			It("should wait for Cryostat to scale down", func() {
+				t.expectCondition(t.getCryostatRestore(), metav1.ConditionFalse, "WaitingForScaleDown")
+				t.expectNoRestoreJob()
+			})
+
+			Context("then scaled down", func() {
+				JustBeforeEach(func() {
+					deploy := &appsv1.Deployment{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+					Expect(err).ToNot(HaveOccurred())
+					replicas := int32(0)
+					deploy.Spec.Replicas = &replicas
+					Expect(t.Client.Update(context.Background(), deploy)).To(Succeed())
+					deploy.Status.Replicas = 0
+					Expect(t.Client.Status().Update(context.Background(), deploy)).To(Succeed())
+					t.reconcileCryostatRestore()
+				})
+
+				It("should start the restore", func() {
+					t.getRestoreJob()
+					t.expectCondition(t.getCryostatRestore(), metav1.ConditionFalse, "RestoreInProgress")
+				})
+			})
+		})
+
+		Context("with reconciliation of Cryostat paused", func() {
+			BeforeEach(func() {
+				cr := t.NewCryostatWithBackendsAvailable()
+				cr.Spec.ReconcilePaused = true
+				t.Objs[1] = cr.Object
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileCryostatRestore()
+			})
+
+			It("should wait for reconciliation to resume", func() {
+				t.expectCondition(t.getCryostatRestore(), metav1.ConditionFalse, "CryostatReconcilePaused")
+				t.expectNoRestoreJob()
+			})
+		})
+
+		Context("with a backup in progress", func() {
+			BeforeEach(func() {
+				backup := t.NewCompletedCryostatBackup()
+				backup.Status.Conditions[0].Status = metav1.ConditionFalse
+				backup.Status.Conditions[0].Reason = "BackupInProgress"
+				t.Objs[2] = backup
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileCryostatRestore()
+			})
+
+			It("should wait for the backup", func() {
+				t.expectCondition(t.getCryostatRestore(), metav1.ConditionFalse, "WaitingForBackup")
+				t.expectNoRestoreJob()
+			})
+		})
+
+		Context("with a missing backup", func() {
+			BeforeEach(func() {
+				t.Objs = t.Objs[:2]
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileCryostatRestore()
+			})
+
+			It("should report the missing backup", func() {
+				t.expectCondition(t.getCryostatRestore(), metav1.ConditionFalse, "BackupNotFound")
+				t.expectNoRestoreJob()
+			})
+		})
+
+		Context("with a source location", func() {
+			BeforeEach(func() {
+				t.restore = t.NewCryostatRestoreFromSource()
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileCryostatRestore()
+			})
+
+			It("should download the archive from the bucket", func() {
+				Expect(t.getRestoreJob().Spec.Template.Spec.Containers[0].Env).To(ContainElements(
+					corev1.EnvVar{Name: "ARCHIVE_NAME", Value: "other-cryostat-my-backup.tar.gz"},
+					corev1.EnvVar{Name: "EXPECTED_CHECKSUM", Value: ""},
+					corev1.EnvVar{Name: "LOCATION_S3_URL", Value: "https://s3.example.com/my-bucket"},
+					corev1.EnvVar{Name: "LOCATION_S3_REGION", Value: "us-east-1"},
+				))
+			})
+		})
+
+		Context("without a backup or source", func() {
+			BeforeEach(func() {
+				t.restore.Spec.BackupName = ""
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileCryostatRestore()
+			})
+
+			It("should report the invalid source", func() {
+				t.expectCondition(t.getCryostatRestore(), metav1.ConditionFalse, "InvalidSource")
+				t.expectNoRestoreJob()
+			})
+		})
+
+		Context("with an unavailable Cryostat", func() {
+			BeforeEach(func() {
+				t.Objs[1] = t.NewCryostat().Object
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileCryostatRestore()
+			})
+
+			It("should wait for the Cryostat", func() {
+				t.expectCondition(t.getCryostatRestore(), metav1.ConditionFalse, "WaitingForCryostat")
+				t.expectNoRestoreJob()
+			})
+		})
+	})
+
+	Describe("setting up the controller", func() {
+		JustBeforeEach(func() {
+			err := t.controller.SetupWithManager(nil)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should reconcile CryostatRestores when their backup changes", func() {
+			builder := t.ControllerBuilder
+			Expect(builder.MapFuncs).To(HaveLen(2))
+			other := t.NewCryostatRestore()
+			other.Name = "other-restore"
+			other.Spec.BackupName = "other-backup"
+			Expect(t.Client.Create(context.Background(), other)).To(Succeed())
+
+			requests := builder.MapFuncs[0](context.Background(), t.NewCompletedCryostatBackup())
+			Expect(requests).To(ConsistOf(reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: t.restore.Name, Namespace: t.restore.Namespace},
+			}))
+		})
+
+		It("should reconcile CryostatRestores when their Cryostat changes", func() {
+			requests := t.ControllerBuilder.MapFuncs[1](context.Background(), t.NewCryostat().Object)
+			Expect(requests).To(ConsistOf(reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: t.restore.Name, Namespace: t.restore.Namespace},
+			}))
+		})
+	})
+})
+
+func (t *cryostatRestoreTestInput) reconcileCryostatRestore() {
+	t.ExpectReconcile(t.controller, t.restore)
+}
+
+func (t *cryostatRestoreTestInput) getCryostatRestore() *operatorv1beta2.CryostatRestore {
+	restore := t.restore.DeepCopy()
+	t.Get(restore)
+	return restore
+}
+
+func (t *cryostatRestoreTestInput) getRestoreJob() *batchv1.Job {
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "my-restore-restore", Namespace: t.Namespace}}
+	t.Get(job)
+	return job
+}
+
+func (t *cryostatRestoreTestInput) expectNoRestoreJob() {
+	t.ExpectNotFound(&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "my-restore-restore", Namespace: t.Namespace}})
+}
+
+func (t *cryostatRestoreTestInput) newDeployment(name string, replicas int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: t.Namespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+		},
+		Status: appsv1.DeploymentStatus{
+			Replicas: replicas,
+		},
+	}
+}
+
+func (t *cryostatRestoreTestInput) expectCondition(restore *operatorv1beta2.CryostatRestore, status metav1.ConditionStatus, reason string) *metav1.Condition {
+	condition := meta.FindStatusCondition(restore.Status.Conditions, string(operatorv1beta2.ConditionTypeRestoreComplete))
+	Expect(condition).ToNot(BeNil())
+	Expect(condition.Status).To(Equal(status))
+	Expect(condition.Reason).To(Equal(reason))
+	Expect(condition.ObservedGeneration).To(Equal(restore.Generation))
+	return condition
+}
@@ -0,0 +1,342 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	resources "github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Event types to inform users of the progress of database upgrades
+const (
+	eventDatabaseUpgradeStartedType   = "DatabaseUpgradeStarted"
+	eventDatabaseUpgradeSucceededType = "DatabaseUpgradeSucceeded"
+	eventDatabaseUpgradeFailedType    = "DatabaseUpgradeFailed"
+)
+
+// How often to check on the progress of a database upgrade
+const databaseUpgradeRetryPeriod = 10 * time.Second
+
+// databaseVersionCheck is the termination message of the database pod's init container
+// that compares the PostgreSQL major versions of the data and the database image
+type databaseVersionCheck struct {
+	// Empty if the database has no data yet
+	DataVersion  string `json:"dataVersion"`
+	ImageVersion string `json:"imageVersion"`
+}
+
+func (c *databaseVersionCheck) compatible() bool {
+	return len(c.DataVersion) == 0 || c.DataVersion == c.ImageVersion
+}
+
+// reconcileDatabaseUpgrade upgrades the database's data when the database image uses a new PostgreSQL
+// major version. It returns the database image to deploy, which is the previous image if the upgrade
+// failed, and whether the database must be scaled down while the upgrade is in progress.
+func (r *Reconciler) reconcileDatabaseUpgrade(ctx context.Context, cr *model.CryostatInstance, imageTag string,
+	fsGroup int64) (string, bool, error) {
+	if cr.Status.Database == nil {
+		cr.Status.Database = &operatorv1beta2.DatabaseStatus{}
+	}
+	status := cr.Status.Database
+
+	if !isDatabaseUpgradeInProgress(cr) {
+		err := r.seedDatabaseImage(ctx, cr, imageTag)
+		if err != nil {
+			return imageTag, false, err
+		}
+		status = cr.Status.Database
+		incompatible, err := r.checkDatabaseVersion(ctx, cr, imageTag)
+		if err != nil {
+			return imageTag, false, err
+		}
+		if status.Upgrade != nil && status.Upgrade.TargetImage == imageTag {
+			// The upgrade failed, so keep using the previous image until a different one is provided
+			return getDatabaseRollbackImage(status, imageTag), false, nil
+		}
+		status.Upgrade = nil
+		if incompatible == nil {
+			return imageTag, false, nil
+		}
+		err = r.startDatabaseUpgrade(ctx, cr, imageTag, incompatible)
+		if err != nil {
+			return imageTag, false, err
+		}
+		if status.Upgrade.Phase == operatorv1beta2.DatabaseUpgradePhaseFailed {
+			return imageTag, false, nil
+		}
+	}
+
+	// Status updates replace the status fields
+	status = cr.Status.Database
+	if status.Upgrade.Phase == operatorv1beta2.DatabaseUpgradePhaseScalingDown {
+		image := status.Image
+		return image, true, r.startDatabaseUpgradeJob(ctx, cr, fsGroup)
+	}
+	return r.checkDatabaseUpgradeJob(ctx, cr)
+}
+
+// seedDatabaseImage records the image of the existing database deployment, if no image is known to have
+// started with the database's data. This is the case for databases deployed by operator versions that did
+// not check the data's version, whose pods cannot report the image. The image must be recorded before the
+// deployment is updated to imageTag, so that the data can be upgraded, or the database rolled back.
+func (r *Reconciler) seedDatabaseImage(ctx context.Context, cr *model.CryostatInstance, imageTag string) error {
+	status := cr.Status.Database
+	if len(status.Image) > 0 {
+		return nil
+	}
+	deploy := &appsv1.Deployment{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: cr.Name + "-database", Namespace: cr.InstallNamespace}, deploy)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	image := ""
+	for _, container := range deploy.Spec.Template.Spec.Containers {
+		if container.Name == cr.Name+"-db" {
+			image = container.Image
+		}
+	}
+	if len(image) == 0 || image == imageTag {
+		return nil
+	}
+
+	// Persist the image right away, since the deployment no longer runs it once updated
+	r.Log.Info("Recording the image of the existing database", "image", image)
+	status.Image = image
+	return r.Client.Status().Update(ctx, cr.Object)
+}
+
+// checkDatabaseVersion records the most recent image that started with the database's data, using the
+// results of the version check in the database pods. If the data is incompatible with imageTag, the
+// result of the check is returned.
+func (r *Reconciler) checkDatabaseVersion(ctx context.Context, cr *model.CryostatInstance, imageTag string) (*databaseVersionCheck, error) {
+	pods := &corev1.PodList{}
+	err := r.APIReader.List(ctx, pods, client.InNamespace(cr.InstallNamespace),
+		client.MatchingLabels(resources.DatabasePodLabels(cr)))
+	if err != nil {
+		return nil, err
+	}
+
+	var incompatible *databaseVersionCheck
+	for _, pod := range pods.Items {
+		image, check := getDatabaseVersionCheck(&pod)
+		if check == nil {
+			continue
+		}
+		if check.compatible() {
+			cr.Status.Database.Image = image
+			cr.Status.Database.DataVersion = check.ImageVersion
+		} else if image == imageTag {
+			incompatible = check
+		}
+	}
+	return incompatible, nil
+}
+
+// getDatabaseVersionCheck returns the image of the database pod's version check, and its result if it has run
+func getDatabaseVersionCheck(pod *corev1.Pod) (string, *databaseVersionCheck) {
+	image := ""
+	for _, container := range pod.Spec.InitContainers {
+		if container.Name == resources.DatabaseVersionCheckContainerName {
+			image = container.Image
+		}
+	}
+	for _, status := range pod.Status.InitContainerStatuses {
+		if status.Name != resources.DatabaseVersionCheckContainerName {
+			continue
+		}
+		terminated := status.State.Terminated
+		if terminated == nil {
+			// Restarting after a failed check
+			terminated = status.LastTerminationState.Terminated
+		}
+		if terminated == nil || len(terminated.Message) == 0 {
+			return image, nil
+		}
+		check := &databaseVersionCheck{}
+		if err := json.Unmarshal([]byte(terminated.Message), check); err != nil || len(check.ImageVersion) == 0 {
+			return image, nil
+		}
+		return image, check
+	}
+	return image, nil
+}
+
+func (r *Reconciler) startDatabaseUpgrade(ctx context.Context, cr *model.CryostatInstance, imageTag string,
+	check *databaseVersionCheck) error {
+	status := cr.Status.Database
+	now := metav1.Now()
+	status.Upgrade = &operatorv1beta2.DatabaseUpgradeStatus{
+		TargetImage: imageTag,
+		FromVersion: check.DataVersion,
+		ToVersion:   check.ImageVersion,
+		Phase:       operatorv1beta2.DatabaseUpgradePhaseScalingDown,
+		StartTime:   &now,
+	}
+	if len(status.Image) == 0 || status.Image == imageTag {
+		// Dumping the data requires the image that created it
+		return r.failDatabaseUpgrade(ctx, cr, fmt.Sprintf("the database image for PostgreSQL %s is unknown, "+
+			"so the data cannot be upgraded automatically", check.DataVersion))
+	}
+
+	r.Log.Info("Starting database upgrade", "from", status.Image, "to", imageTag)
+	r.EventRecorder.Eventf(cr.Object, corev1.EventTypeNormal, eventDatabaseUpgradeStartedType,
+		"Upgrading the database from PostgreSQL %s to %s", check.DataVersion, check.ImageVersion)
+	return r.Client.Status().Update(ctx, cr.Object)
+}
+
+// startDatabaseUpgradeJob creates the Job upgrading the database's data once the database has scaled down
+func (r *Reconciler) startDatabaseUpgradeJob(ctx context.Context, cr *model.CryostatInstance, fsGroup int64) error {
+	deploy := &appsv1.Deployment{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: cr.Name + "-database", Namespace: cr.InstallNamespace}, deploy)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return err
+		}
+	} else if deploy.Status.Replicas > 0 {
+		// Wait for the database pods to stop using the data
+		return nil
+	}
+
+	status := cr.Status.Database
+	upgrade := status.Upgrade
+	job := resources.NewDatabaseUpgradeJob(cr, status.Image, upgrade.TargetImage, upgrade.FromVersion,
+		upgrade.ToVersion, fsGroup, r.IsOpenShift)
+	err = controllerutil.SetControllerReference(cr.Object, job, r.Scheme)
+	if err != nil {
+		return err
+	}
+	err = r.Client.Create(ctx, job)
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		return err
+	}
+	r.Log.Info("Started database upgrade", "Job", job.Name)
+
+	upgrade.Phase = operatorv1beta2.DatabaseUpgradePhaseUpgrading
+	upgrade.JobName = job.Name
+	return r.Client.Status().Update(ctx, cr.Object)
+}
+
+// checkDatabaseUpgradeJob switches the database to the new image once the Job has upgraded its data,
+// or rolls it back to the previous image if the Job failed
+func (r *Reconciler) checkDatabaseUpgradeJob(ctx context.Context, cr *model.CryostatInstance) (string, bool, error) {
+	status := cr.Status.Database
+	upgrade := status.Upgrade
+	job, finished, succeeded, message, err := r.getBackupJobResult(ctx, upgrade.JobName, cr.InstallNamespace)
+	if err != nil {
+		return status.Image, true, err
+	}
+	if job == nil {
+		finished, message = true, fmt.Sprintf("Job %s was deleted before the upgrade completed", upgrade.JobName)
+	}
+	if !finished {
+		return status.Image, true, nil
+	}
+
+	if !succeeded {
+		image := status.Image
+		return image, false, r.failDatabaseUpgrade(ctx, cr, message)
+	}
+
+	r.Log.Info("Completed database upgrade", "from", status.Image, "to", upgrade.TargetImage)
+	r.EventRecorder.Eventf(cr.Object, corev1.EventTypeNormal, eventDatabaseUpgradeSucceededType,
+		"Upgraded the database from PostgreSQL %s to %s", upgrade.FromVersion, upgrade.ToVersion)
+	image := upgrade.TargetImage
+	status.Image = image
+	status.DataVersion = upgrade.ToVersion
+	status.Upgrade = nil
+	return image, false, r.Client.Status().Update(ctx, cr.Object)
+}
+
+// failDatabaseUpgrade deletes any Job that attempted the upgrade, which restored the data to its previous
+// state when it failed. The upgrade is not retried until the operator provides another database image.
+func (r *Reconciler) failDatabaseUpgrade(ctx context.Context, cr *model.CryostatInstance, message string) error {
+	upgrade := cr.Status.Database.Upgrade
+	if len(upgrade.JobName) > 0 {
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      upgrade.JobName,
+				Namespace: cr.InstallNamespace,
+			},
+		}
+		err := r.Client.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	upgrade.Phase = operatorv1beta2.DatabaseUpgradePhaseFailed
+	upgrade.FailureMessage = message
+	r.Log.Info("Database upgrade failed", "Job", upgrade.JobName)
+	r.EventRecorder.Eventf(cr.Object, corev1.EventTypeWarning, eventDatabaseUpgradeFailedType,
+		"Failed to upgrade the database from PostgreSQL %s to %s: %s", upgrade.FromVersion, upgrade.ToVersion, message)
+	return r.Client.Status().Update(ctx, cr.Object)
+}
+
+// updateDatabaseUpgradeCondition reports any database upgrade using the DatabaseDeploymentProgressing condition,
+// in place of the condition from the database Deployment
+func (r *Reconciler) updateDatabaseUpgradeCondition(ctx context.Context, cr *model.CryostatInstance) error {
+	if cr.Status.Database == nil || cr.Status.Database.Upgrade == nil {
+		return nil
+	}
+	status := cr.Status.Database
+	upgrade := status.Upgrade
+	if upgrade.Phase == operatorv1beta2.DatabaseUpgradePhaseFailed {
+		message := fmt.Sprintf("Failed to upgrade the database from PostgreSQL %s to %s: %s.", upgrade.FromVersion,
+			upgrade.ToVersion, upgrade.FailureMessage)
+		if rollback := getDatabaseRollbackImage(status, upgrade.TargetImage); rollback != upgrade.TargetImage {
+			message += fmt.Sprintf(" The database was rolled back to image %s.", rollback)
+		}
+		return r.updateCondition(ctx, cr, operatorv1beta2.ConditionTypeDatabaseDeploymentProgressing, metav1.ConditionFalse,
+			reasonDatabaseUpgradeFailed, message)
+	}
+	message := fmt.Sprintf("Scaling down the database to upgrade it from PostgreSQL %s to %s.", upgrade.FromVersion,
+		upgrade.ToVersion)
+	if upgrade.Phase == operatorv1beta2.DatabaseUpgradePhaseUpgrading {
+		message = fmt.Sprintf("Job %s is upgrading the database from PostgreSQL %s to %s.", upgrade.JobName,
+			upgrade.FromVersion, upgrade.ToVersion)
+	}
+	return r.updateCondition(ctx, cr, operatorv1beta2.ConditionTypeDatabaseDeploymentProgressing, metav1.ConditionTrue,
+		reasonDatabaseUpgradeInProgress, message)
+}
+
+// getDatabaseRollbackImage returns the image to use after an upgrade to imageTag failed
+func getDatabaseRollbackImage(status *operatorv1beta2.DatabaseStatus, imageTag string) string {
+	if len(status.Image) > 0 {
+		return status.Image
+	}
+	return imageTag
+}
+
+func isDatabaseUpgradeInProgress(cr *model.CryostatInstance) bool {
+	return cr.Status.Database != nil && cr.Status.Database.Upgrade != nil &&
+		cr.Status.Database.Upgrade.Phase != operatorv1beta2.DatabaseUpgradePhaseFailed
+}
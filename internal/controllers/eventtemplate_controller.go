@@ -0,0 +1,424 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
+	"github.com/cryostatio/cryostat-operator/internal/cryostatclient"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Verify that *EventTemplateReconciler implements CommonReconciler.
+var _ CommonReconciler = (*EventTemplateReconciler)(nil)
+
+// EventTemplateReconciler uploads event templates from labeled ConfigMaps to Cryostat
+type EventTemplateReconciler struct {
+	*ReconcilerConfig
+}
+
+const (
+	// How often uploaded templates are checked for removal from Cryostat
+	eventTemplateSyncPeriod = time.Minute
+	// How often to check whether Cryostat is available to upload templates
+	eventTemplateRetryPeriod = 10 * time.Second
+	// Suffix of ConfigMap keys containing event templates
+	eventTemplateFileSuffix = ".jfc"
+)
+
+// Reasons for the EventTemplatesSynchronized Condition
+const (
+	reasonEventTemplatesUploaded       = "EventTemplatesUploaded"
+	reasonInvalidEventTemplates        = "InvalidEventTemplates"
+	reasonInvalidEventTemplateSelector = "InvalidEventTemplateSelector"
+	reasonWaitingForCryostat           = "WaitingForCryostat"
+)
+
+func NewEventTemplateReconciler(config *ReconcilerConfig) (*EventTemplateReconciler, error) {
+	return &EventTemplateReconciler{
+		ReconcilerConfig: config,
+	}, nil
+}
+
+// Reconcile discovers the event templates selected by a Cryostat CR and uploads them to Cryostat
+func (r *EventTemplateReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+
+	// Fetch the Cryostat instance
+	cr := &operatorv1beta2.Cryostat{}
+	err := r.Client.Get(ctx, request.NamespacedName, cr)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		reqLogger.Error(err, "Error reading Cryostat instance")
+		return reconcile.Result{}, err
+	}
+
+	// Uploaded templates are removed along with Cryostat
+	if cr.GetDeletionTimestamp() != nil {
+		return reconcile.Result{}, nil
+	}
+
+	// The Cryostat API must not be used while paused. Reconciled again once resumed.
+	if checkCryostatPaused(cr) != nil {
+		return reconcile.Result{}, nil
+	}
+
+	if cr.Spec.EventTemplateSelector == nil && len(cr.Status.EventTemplates) == 0 {
+		// Nothing to upload or clean up
+		if meta.RemoveStatusCondition(&cr.Status.Conditions, string(operatorv1beta2.ConditionTypeEventTemplatesSynchronized)) {
+			return reconcile.Result{}, r.Client.Status().Update(ctx, cr)
+		}
+		return reconcile.Result{}, nil
+	}
+
+	reqLogger.Info("Reconciling event templates")
+
+	if !meta.IsStatusConditionTrue(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeMainDeploymentAvailable)) {
+		return reconcile.Result{RequeueAfter: eventTemplateRetryPeriod}, r.updateEventTemplateCondition(ctx, cr,
+			metav1.ConditionFalse, reasonWaitingForCryostat, "Waiting for Cryostat to become available")
+	}
+
+	discovered, err := r.discoverEventTemplates(ctx, cr)
+	if err != nil {
+		if errors.Is(err, errInvalidEventTemplateSelector) {
+			return reconcile.Result{}, r.updateEventTemplateCondition(ctx, cr, metav1.ConditionFalse,
+				reasonInvalidEventTemplateSelector, err.Error())
+		}
+		return reconcile.Result{}, err
+	}
+
+	apiClient, err := r.newCryostatAPIClient(ctx, model.FromCryostat(cr))
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	statuses, err := r.synchronizeEventTemplates(ctx, reqLogger, apiClient, cr, discovered)
+	if err != nil {
+		reqLogger.Error(err, "Failed to upload event templates to Cryostat")
+		updateErr := r.updateEventTemplateCondition(ctx, cr, metav1.ConditionFalse,
+			reasonCryostatAPIError, err.Error())
+		if updateErr != nil {
+			return reconcile.Result{}, updateErr
+		}
+		return reconcile.Result{}, err
+	}
+	cr.Status.EventTemplates = statuses
+
+	if cr.Spec.EventTemplateSelector == nil {
+		// All previously uploaded templates have been removed
+		meta.RemoveStatusCondition(&cr.Status.Conditions, string(operatorv1beta2.ConditionTypeEventTemplatesSynchronized))
+		return reconcile.Result{}, r.Client.Status().Update(ctx, cr)
+	}
+
+	failed := 0
+	for _, status := range statuses {
+		if len(status.Error) > 0 {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return reconcile.Result{RequeueAfter: eventTemplateSyncPeriod}, r.updateEventTemplateCondition(ctx, cr,
+			metav1.ConditionFalse, reasonInvalidEventTemplates,
+			fmt.Sprintf("%d of %d event template(s) could not be uploaded, see status.eventTemplates for details",
+				failed, len(statuses)))
+	}
+	return reconcile.Result{RequeueAfter: eventTemplateSyncPeriod}, r.updateEventTemplateCondition(ctx, cr,
+		metav1.ConditionTrue, reasonEventTemplatesUploaded,
+		fmt.Sprintf("%d event template(s) uploaded to Cryostat", len(statuses)))
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *EventTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c := r.NewControllerBuilder(mgr)
+	// The Cryostat controller already uses the default name
+	c = c.Named("eventtemplate")
+	c = c.For(&operatorv1beta2.Cryostat{})
+	// Upload templates whenever a selected ConfigMap changes
+	c = c.Watches(&corev1.ConfigMap{}, c.EnqueueRequestsFromMapFunc(r.cryostatsForConfigMap))
+	return c.Complete(r)
+}
+
+func (r *EventTemplateReconciler) GetConfig() *ReconcilerConfig {
+	return r.ReconcilerConfig
+}
+
+func (r *EventTemplateReconciler) cryostatsForConfigMap(ctx context.Context, obj client.Object) []reconcile.Request {
+	cryostats := &operatorv1beta2.CryostatList{}
+	err := r.Client.List(ctx, cryostats)
+	if err != nil {
+		r.Log.Error(err, "failed to list Cryostats", "ConfigMap", obj.GetName())
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, cr := range cryostats.Items {
+		if cr.Spec.EventTemplateSelector == nil || !slices.Contains(getEventTemplateNamespaces(&cr), obj.GetNamespace()) {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(cr.Spec.EventTemplateSelector)
+		if err != nil || !selector.Matches(labels.Set(obj.GetLabels())) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Name:      cr.Name,
+			Namespace: cr.Namespace,
+		}})
+	}
+	return requests
+}
+
+var errInvalidEventTemplateSelector = errors.New("spec.eventTemplateSelector is invalid")
+
+// eventTemplateFile is an event template file found within a ConfigMap
+type eventTemplateFile struct {
+	operatorv1beta2.EventTemplateStatus
+	content []byte
+}
+
+// discoverEventTemplates returns all template files within the ConfigMaps selected by
+// the Cryostat CR, sorted by namespace, ConfigMap name and filename
+func (r *EventTemplateReconciler) discoverEventTemplates(ctx context.Context,
+	cr *operatorv1beta2.Cryostat) ([]*eventTemplateFile, error) {
+	if cr.Spec.EventTemplateSelector == nil {
+		return nil, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(cr.Spec.EventTemplateSelector)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidEventTemplateSelector, err.Error())
+	}
+
+	templates := []*eventTemplateFile{}
+	for _, namespace := range getEventTemplateNamespaces(cr) {
+		configMaps := &corev1.ConfigMapList{}
+		err := r.Client.List(ctx, configMaps, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector})
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(configMaps.Items, func(i, j int) bool {
+			return configMaps.Items[i].Name < configMaps.Items[j].Name
+		})
+		for _, configMap := range configMaps.Items {
+			filenames := []string{}
+			for filename := range configMap.Data {
+				if strings.HasSuffix(filename, eventTemplateFileSuffix) {
+					filenames = append(filenames, filename)
+				}
+			}
+			sort.Strings(filenames)
+			for _, filename := range filenames {
+				content := []byte(configMap.Data[filename])
+				digest := sha256.Sum256(content)
+				templates = append(templates, &eventTemplateFile{
+					EventTemplateStatus: operatorv1beta2.EventTemplateStatus{
+						Namespace:     namespace,
+						ConfigMapName: configMap.Name,
+						Filename:      filename,
+						Digest:        hex.EncodeToString(digest[:]),
+					},
+					content: content,
+				})
+			}
+		}
+	}
+	return templates, nil
+}
+
+// synchronizeEventTemplates uploads new and modified templates to Cryostat, and deletes
+// templates it previously uploaded that are no longer selected
+func (r *EventTemplateReconciler) synchronizeEventTemplates(ctx context.Context, reqLogger logr.Logger,
+	apiClient cryostatclient.Client, cr *operatorv1beta2.Cryostat,
+	discovered []*eventTemplateFile) ([]operatorv1beta2.EventTemplateStatus, error) {
+	previous := map[string]*operatorv1beta2.EventTemplateStatus{}
+	for i := range cr.Status.EventTemplates {
+		status := &cr.Status.EventTemplates[i]
+		previous[eventTemplateKey(status)] = status
+	}
+
+	templates, err := apiClient.ListEventTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]bool{}
+	for _, template := range templates {
+		if template.Type == cryostatclient.EventTemplateTypeCustom {
+			existing[template.Name] = true
+		}
+	}
+
+	result := []operatorv1beta2.EventTemplateStatus{}
+	desired := map[string]bool{}
+	for _, template := range discovered {
+		status := template.EventTemplateStatus
+		name, err := parseEventTemplate(template.content)
+		if err != nil {
+			status.Error = fmt.Sprintf("invalid event template: %s", err.Error())
+			result = append(result, status)
+			continue
+		}
+		status.TemplateName = name
+		if desired[name] {
+			status.Error = fmt.Sprintf("another selected event template is also named %q", name)
+			result = append(result, status)
+			continue
+		}
+		desired[name] = true
+
+		prev := previous[eventTemplateKey(&status)]
+		if prev != nil && len(prev.Error) == 0 && prev.TemplateName == name && prev.Digest == status.Digest && existing[name] {
+			// Already uploaded
+			result = append(result, status)
+			continue
+		}
+
+		// Cryostat does not support modifying a template, so replace it
+		if existing[name] {
+			err := apiClient.DeleteEventTemplate(ctx, name)
+			if err != nil && !cryostatclient.IsNotFound(err) {
+				return nil, err
+			}
+		}
+		err = apiClient.UploadEventTemplate(ctx, template.Filename, template.content)
+		if err != nil {
+			if !isRejectedByCryostat(err) {
+				return nil, err
+			}
+			status.Error = err.Error()
+		} else {
+			reqLogger.Info("Uploaded event template", "Template", name, "ConfigMap.Namespace", status.Namespace,
+				"ConfigMap.Name", status.ConfigMapName)
+			existing[name] = true
+		}
+		result = append(result, status)
+	}
+
+	// Remove templates that were uploaded from ConfigMaps that are no longer selected
+	for _, prev := range cr.Status.EventTemplates {
+		if len(prev.Error) > 0 || len(prev.TemplateName) == 0 || desired[prev.TemplateName] || !existing[prev.TemplateName] {
+			continue
+		}
+		err := apiClient.DeleteEventTemplate(ctx, prev.TemplateName)
+		if err != nil && !cryostatclient.IsNotFound(err) {
+			return nil, err
+		}
+		reqLogger.Info("Deleted event template", "Template", prev.TemplateName)
+	}
+	return result, nil
+}
+
+func (r *EventTemplateReconciler) updateEventTemplateCondition(ctx context.Context, cr *operatorv1beta2.Cryostat,
+	status metav1.ConditionStatus, reason string, message string) error {
+	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+		Type:               string(operatorv1beta2.ConditionTypeEventTemplatesSynchronized),
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: cr.Generation,
+	})
+	err := r.Client.Status().Update(ctx, cr)
+	if err != nil {
+		r.Log.Error(err, "failed to update condition", "Cryostat.Namespace", cr.Namespace, "Cryostat.Name", cr.Name)
+	}
+	return err
+}
+
+// getEventTemplateNamespaces returns the namespaces where event template ConfigMaps are discovered
+func getEventTemplateNamespaces(cr *operatorv1beta2.Cryostat) []string {
+	namespaces := []string{cr.Namespace}
+	for _, namespace := range cr.Status.TargetNamespaces {
+		if !slices.Contains(namespaces, namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
+
+func eventTemplateKey(status *operatorv1beta2.EventTemplateStatus) string {
+	return status.Namespace + "/" + status.ConfigMapName + "/" + status.Filename
+}
+
+// isRejectedByCryostat returns whether Cryostat refused the request itself,
+// rather than the request failing to reach Cryostat
+func isRejectedByCryostat(err error) bool {
+	apiErr := &cryostatclient.APIError{}
+	return errors.As(err, &apiErr) && apiErr.StatusCode >= http.StatusBadRequest &&
+		apiErr.StatusCode < http.StatusInternalServerError
+}
+
+// jfcConfiguration is the root element of a .jfc event template
+type jfcConfiguration struct {
+	XMLName xml.Name
+	Label   string `xml:"label,attr"`
+	Events  []struct {
+		Name string `xml:"name,attr"`
+	} `xml:"event"`
+}
+
+// parseEventTemplate validates the contents of a .jfc file, and returns the name of the template
+func parseEventTemplate(content []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	config := &jfcConfiguration{}
+	err := decoder.Decode(config)
+	if err != nil {
+		return "", err
+	}
+	// Ensure the rest of the document is well-formed
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if config.XMLName.Local != "configuration" {
+		return "", fmt.Errorf("root element must be <configuration>, not <%s>", config.XMLName.Local)
+	}
+	if len(config.Label) == 0 {
+		return "", errors.New("<configuration> element must have a label attribute")
+	}
+	for _, event := range config.Events {
+		if len(event.Name) == 0 {
+			return "", errors.New("each <event> element must have a name attribute")
+		}
+	}
+	return config.Label, nil
+}
@@ -0,0 +1,360 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controllers"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
+	"github.com/cryostatio/cryostat-operator/internal/test"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type eventTemplateTestInput struct {
+	controller *controllers.EventTemplateReconciler
+	cr         *model.CryostatInstance
+	*test.ControllerTestInput
+}
+
+var _ = Describe("EventTemplateController", func() {
+	var t *eventTemplateTestInput
+	appNamespace := "my-apps"
+
+	BeforeEach(func() {
+		t = &eventTemplateTestInput{
+			ControllerTestInput: test.NewControllerTestInput(),
+		}
+		t.cr = t.NewCryostatWithEventTemplateSelector(appNamespace)
+		t.Objs = []ctrlclient.Object{
+			t.NewNamespace(),
+			t.NewOtherNamespace(appNamespace),
+			t.NewCABundleSecret(),
+			t.NewEventTemplateConfigMap("templates", t.Namespace, map[string]string{
+				"first.jfc":  t.NewEventTemplateFile("First"),
+				"second.jfc": t.NewEventTemplateFile("Second"),
+				"README.md":  "Not a template",
+			}),
+			t.NewEventTemplateConfigMap("app-templates", appNamespace, map[string]string{
+				"app.jfc": t.NewEventTemplateFile("App"),
+			}),
+			// Not selected
+			t.NewTemplateConfigMap(),
+			// Not in a target namespace
+			t.NewEventTemplateConfigMap("other-templates", "other-namespace", map[string]string{
+				"other.jfc": t.NewEventTemplateFile("Other"),
+			}),
+		}
+	})
+
+	JustBeforeEach(func() {
+		t.Objs = append(t.Objs, t.cr.Object)
+		var err error
+		t.controller, err = controllers.NewEventTemplateReconciler(t.NewReconcilerConfig())
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Describe("reconciling a request", func() {
+		Context("with selected ConfigMaps", func() {
+			JustBeforeEach(func() {
+				t.reconcileEventTemplates()
+			})
+
+			It("should upload templates from the installation and target namespaces", func() {
+				Expect(t.CryostatClient.EventTemplates).To(Equal(map[string]string{
+					"First":  t.NewEventTemplateFile("First"),
+					"Second": t.NewEventTemplateFile("Second"),
+					"App":    t.NewEventTemplateFile("App"),
+				}))
+			})
+
+			It("should report the templates in the status", func() {
+				cr := t.getCryostat()
+				Expect(cr.Status.EventTemplates).To(HaveLen(3))
+				t.expectTemplateStatus(cr.Status.EventTemplates[0], t.Namespace, "templates", "first.jfc", "First")
+				t.expectTemplateStatus(cr.Status.EventTemplates[1], t.Namespace, "templates", "second.jfc", "Second")
+				t.expectTemplateStatus(cr.Status.EventTemplates[2], appNamespace, "app-templates", "app.jfc", "App")
+				t.expectCondition(cr, metav1.ConditionTrue, "EventTemplatesUploaded")
+			})
+
+			It("should requeue to detect removal", func() {
+				result, err := t.reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: time.Minute}))
+			})
+
+			It("should not upload unchanged templates again", func() {
+				t.reconcileEventTemplates()
+				Expect(t.CryostatClient.TemplateUploads).To(Equal(3))
+			})
+
+			Context("when a template changes", func() {
+				JustBeforeEach(func() {
+					configMap := t.getConfigMap("app-templates", appNamespace)
+					configMap.Data["app.jfc"] = t.NewEventTemplateFile("App") + "<!-- updated -->\n"
+					Expect(t.Client.Update(context.Background(), configMap)).To(Succeed())
+					t.reconcileEventTemplates()
+				})
+
+				It("should replace the template", func() {
+					Expect(t.CryostatClient.EventTemplates["App"]).To(HaveSuffix("<!-- updated -->\n"))
+					Expect(t.CryostatClient.TemplateUploads).To(Equal(4))
+				})
+			})
+
+			Context("when a template is renamed", func() {
+				JustBeforeEach(func() {
+					configMap := t.getConfigMap("app-templates", appNamespace)
+					configMap.Data["app.jfc"] = t.NewEventTemplateFile("Renamed")
+					Expect(t.Client.Update(context.Background(), configMap)).To(Succeed())
+					t.reconcileEventTemplates()
+				})
+
+				It("should replace the template", func() {
+					Expect(t.CryostatClient.EventTemplates).To(HaveKey("Renamed"))
+					Expect(t.CryostatClient.EventTemplates).ToNot(HaveKey("App"))
+				})
+			})
+
+			Context("when a template is removed from Cryostat", func() {
+				JustBeforeEach(func() {
+					delete(t.CryostatClient.EventTemplates, "First")
+					t.reconcileEventTemplates()
+				})
+
+				It("should upload the template again", func() {
+					Expect(t.CryostatClient.EventTemplates).To(HaveKey("First"))
+					Expect(t.CryostatClient.TemplateUploads).To(Equal(4))
+				})
+			})
+
+			Context("when a ConfigMap is deleted", func() {
+				JustBeforeEach(func() {
+					Expect(t.Client.Delete(context.Background(), t.getConfigMap("templates", t.Namespace))).To(Succeed())
+					t.reconcileEventTemplates()
+				})
+
+				It("should delete its templates", func() {
+					Expect(t.CryostatClient.EventTemplates).To(HaveLen(1))
+					Expect(t.CryostatClient.EventTemplates).To(HaveKey("App"))
+					Expect(t.getCryostat().Status.EventTemplates).To(HaveLen(1))
+				})
+			})
+
+			Context("when the selector is removed", func() {
+				JustBeforeEach(func() {
+					cr := t.getCryostat()
+					cr.Spec.EventTemplateSelector = nil
+					Expect(t.Client.Update(context.Background(), cr)).To(Succeed())
+					t.reconcileEventTemplates()
+				})
+
+				It("should delete all uploaded templates", func() {
+					Expect(t.CryostatClient.EventTemplates).To(BeEmpty())
+					cr := t.getCryostat()
+					Expect(cr.Status.EventTemplates).To(BeEmpty())
+					Expect(meta.FindStatusCondition(cr.Status.Conditions,
+						string(operatorv1beta2.ConditionTypeEventTemplatesSynchronized))).To(BeNil())
+				})
+			})
+		})
+
+		Context("with an invalid template", func() {
+			BeforeEach(func() {
+				t.Objs = append(t.Objs, t.NewEventTemplateConfigMap("invalid", t.Namespace, map[string]string{
+					"malformed.jfc": "<configuration label=\"Malformed\"><event name=\"jdk.CPULoad\"></configuration>",
+					"unlabeled.jfc": "<configuration version=\"2.0\"></configuration>",
+					"wrong.jfc":     "<settings label=\"Wrong\"></settings>",
+				}))
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileEventTemplates()
+			})
+
+			It("should upload only the valid templates", func() {
+				Expect(t.CryostatClient.EventTemplates).To(HaveLen(3))
+			})
+
+			It("should report the errors in the status", func() {
+				cr := t.getCryostat()
+				Expect(cr.Status.EventTemplates).To(HaveLen(6))
+				for _, status := range cr.Status.EventTemplates[:3] {
+					Expect(status.ConfigMapName).To(Equal("invalid"))
+					Expect(status.Error).To(HavePrefix("invalid event template: "))
+				}
+				t.expectCondition(cr, metav1.ConditionFalse, "InvalidEventTemplates")
+			})
+		})
+
+		Context("with duplicate template names", func() {
+			BeforeEach(func() {
+				t.Objs = append(t.Objs, t.NewEventTemplateConfigMap("duplicate", appNamespace, map[string]string{
+					"duplicate.jfc": t.NewEventTemplateFile("First"),
+				}))
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileEventTemplates()
+			})
+
+			It("should upload the first template with that name", func() {
+				Expect(t.CryostatClient.EventTemplates["First"]).To(Equal(t.NewEventTemplateFile("First")))
+				cr := t.getCryostat()
+				Expect(cr.Status.EventTemplates).To(HaveLen(4))
+				Expect(cr.Status.EventTemplates[3].ConfigMapName).To(Equal("duplicate"))
+				Expect(cr.Status.EventTemplates[3].Error).To(ContainSubstring("also named \"First\""))
+			})
+		})
+
+		Context("before Cryostat is available", func() {
+			BeforeEach(func() {
+				t.cr.Status.Conditions = nil
+			})
+
+			It("should wait for Cryostat", func() {
+				result, err := t.reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: 10 * time.Second}))
+				Expect(t.CryostatClient.Configs).To(BeEmpty())
+				t.expectCondition(t.getCryostat(), metav1.ConditionFalse, "WaitingForCryostat")
+			})
+		})
+
+		expectPaused := func() {
+			It("should leave Cryostat and its status unchanged", func() {
+				resourceVersion := t.getCryostat().ResourceVersion
+				result, err := t.reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{}))
+				Expect(t.CryostatClient.Configs).To(BeEmpty())
+				Expect(t.getCryostat().ResourceVersion).To(Equal(resourceVersion))
+			})
+		}
+
+		Context("when paused", func() {
+			BeforeEach(func() {
+				t.cr.Spec.Paused = true
+			})
+
+			expectPaused()
+		})
+
+		Context("with reconciliation paused", func() {
+			BeforeEach(func() {
+				t.cr.Spec.ReconcilePaused = true
+			})
+
+			expectPaused()
+		})
+
+		Context("without a selector", func() {
+			BeforeEach(func() {
+				t.cr.Spec.EventTemplateSelector = nil
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileEventTemplates()
+			})
+
+			It("should not contact Cryostat", func() {
+				Expect(t.CryostatClient.Configs).To(BeEmpty())
+				Expect(meta.FindStatusCondition(t.getCryostat().Status.Conditions,
+					string(operatorv1beta2.ConditionTypeEventTemplatesSynchronized))).To(BeNil())
+			})
+		})
+
+		Context("when the Cryostat API fails", func() {
+			BeforeEach(func() {
+				t.CryostatClient.Err = errors.New("connection refused")
+			})
+
+			It("should return an error and report it", func() {
+				_, err := t.reconcile()
+				Expect(err).To(HaveOccurred())
+				t.expectCondition(t.getCryostat(), metav1.ConditionFalse, "CryostatAPIError")
+			})
+		})
+	})
+
+	Describe("setting up the controller", func() {
+		JustBeforeEach(func() {
+			err := t.controller.SetupWithManager(nil)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should use a separate name from the Cryostat controller", func() {
+			Expect(t.ControllerBuilder.Name).To(Equal("eventtemplate"))
+		})
+
+		It("should reconcile Cryostats when a selected ConfigMap changes", func() {
+			builder := t.ControllerBuilder
+			Expect(builder.MapFuncs).To(HaveLen(1))
+			expected := reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: t.Name, Namespace: t.Namespace},
+			}
+			Expect(builder.MapFuncs[0](context.Background(), t.getConfigMap("app-templates", appNamespace))).To(ConsistOf(expected))
+			Expect(builder.MapFuncs[0](context.Background(), t.getConfigMap("other-templates", "other-namespace"))).To(BeEmpty())
+			Expect(builder.MapFuncs[0](context.Background(), t.NewTemplateConfigMap())).To(BeEmpty())
+		})
+	})
+})
+
+func (t *eventTemplateTestInput) reconcile() (reconcile.Result, error) {
+	return t.Reconcile(t.controller, t.cr.Object)
+}
+
+func (t *eventTemplateTestInput) reconcileEventTemplates() {
+	t.ExpectReconcile(t.controller, t.cr.Object)
+}
+
+func (t *eventTemplateTestInput) getCryostat() *operatorv1beta2.Cryostat {
+	cr := &operatorv1beta2.Cryostat{}
+	cr.Name, cr.Namespace = t.cr.Name, t.cr.InstallNamespace
+	t.Get(cr)
+	return cr
+}
+
+func (t *eventTemplateTestInput) getConfigMap(name string, namespace string) *corev1.ConfigMap {
+	configMap := &corev1.ConfigMap{}
+	configMap.Name, configMap.Namespace = name, namespace
+	t.Get(configMap)
+	return configMap
+}
+
+func (t *eventTemplateTestInput) expectTemplateStatus(status operatorv1beta2.EventTemplateStatus, namespace string,
+	configMapName string, filename string, templateName string) {
+	Expect(status.Namespace).To(Equal(namespace))
+	Expect(status.ConfigMapName).To(Equal(configMapName))
+	Expect(status.Filename).To(Equal(filename))
+	Expect(status.TemplateName).To(Equal(templateName))
+	Expect(status.Digest).To(HaveLen(64))
+	Expect(status.Error).To(BeEmpty())
+}
+
+func (t *eventTemplateTestInput) expectCondition(cr *operatorv1beta2.Cryostat, status metav1.ConditionStatus, reason string) {
+	test.ExpectCondition(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeEventTemplatesSynchronized),
+		status, reason, cr.Generation)
+}
@@ -0,0 +1,382 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	common "github.com/cryostatio/cryostat-operator/internal/controllers/common"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/constants"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+)
+
+func newCoreHTTPRoute(cr *model.CryostatInstance) *gatewayv1.HTTPRoute {
+	return &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name,
+			Namespace: cr.InstallNamespace,
+		},
+	}
+}
+
+func newCoreBackendTLSPolicy(cr *model.CryostatInstance) *gatewayv1alpha3.BackendTLSPolicy {
+	return &gatewayv1alpha3.BackendTLSPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name,
+			Namespace: cr.InstallNamespace,
+		},
+	}
+}
+
+func newCoreGatewayCAConfigMap(cr *model.CryostatInstance) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name + "-gateway-ca",
+			Namespace: cr.InstallNamespace,
+		},
+	}
+}
+
+func isCoreHTTPRouteRequested(cr *model.CryostatInstance) bool {
+	return cr.Spec.NetworkOptions != nil && cr.Spec.NetworkOptions.CoreConfig != nil &&
+		cr.Spec.NetworkOptions.CoreConfig.HTTPRoute != nil
+}
+
+func (r *Reconciler) reconcileCoreHTTPRoute(ctx context.Context, svc *corev1.Service, cr *model.CryostatInstance,
+	tls *resource_definitions.TLSConfig, specs *resource_definitions.ServiceSpecs) error {
+	route := newCoreHTTPRoute(cr)
+
+	if !isCoreHTTPRouteRequested(cr) {
+		// User has not requested an HTTPRoute, delete if it exists
+		err := r.deleteHTTPRoute(ctx, route)
+		if err != nil {
+			return err
+		}
+		return r.deleteCoreBackendTLSPolicy(ctx, cr)
+	}
+	coreConfig := configureCoreHTTPRoute(cr)
+	url, err := r.reconcileHTTPRoute(ctx, route, svc, cr, tls, coreConfig)
+	if err != nil {
+		return err
+	}
+	specs.AuthProxyURL = url
+	specs.CoreURL = url
+	return nil
+}
+
+func (r *Reconciler) reconcileHTTPRoute(ctx context.Context, route *gatewayv1.HTTPRoute, svc *corev1.Service,
+	cr *model.CryostatInstance, tls *resource_definitions.TLSConfig, config *operatorv1beta2.NetworkConfiguration) (*url.URL, error) {
+	port, err := GetHTTPPort(svc)
+	if err != nil {
+		return nil, err
+	}
+	route, err = r.createOrUpdateHTTPRoute(ctx, route, cr.Object, svc, port, config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.reconcileBackendTLSPolicy(ctx, cr, svc, port, tls, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.getHTTPRouteURL(ctx, route, cr)
+}
+
+func (r *Reconciler) createOrUpdateHTTPRoute(ctx context.Context, route *gatewayv1.HTTPRoute, owner metav1.Object,
+	svc *corev1.Service, exposePort *corev1.ServicePort, config *operatorv1beta2.NetworkConfiguration) (*gatewayv1.HTTPRoute, error) {
+	parentRef := config.HTTPRoute.ParentRef.DeepCopy()
+	// Fill in defaults that the API server would otherwise apply, to avoid
+	// needless updates
+	if parentRef.Group == nil {
+		group := gatewayv1.Group(gatewayv1.GroupName)
+		parentRef.Group = &group
+	}
+	if parentRef.Kind == nil {
+		kind := gatewayv1.Kind("Gateway")
+		parentRef.Kind = &kind
+	}
+	pathType := gatewayv1.PathMatchPathPrefix
+	pathValue := "/"
+	backendGroup := gatewayv1.Group("")
+	backendKind := gatewayv1.Kind("Service")
+	backendPort := gatewayv1.PortNumber(exposePort.Port)
+	weight := int32(1)
+
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, route, func() error {
+		// Set labels and annotations from CR
+		common.MergeLabelsAndAnnotations(&route.ObjectMeta, config.Labels, config.Annotations)
+
+		// Set the Cryostat CR as controller
+		if err := controllerutil.SetControllerReference(owner, route, r.Scheme); err != nil {
+			return err
+		}
+		// Update HTTPRoute spec
+		route.Spec.ParentRefs = []gatewayv1.ParentReference{*parentRef}
+		route.Spec.Hostnames = config.HTTPRoute.Hostnames
+		route.Spec.Rules = []gatewayv1.HTTPRouteRule{
+			{
+				Matches: []gatewayv1.HTTPRouteMatch{
+					{
+						Path: &gatewayv1.HTTPPathMatch{
+							Type:  &pathType,
+							Value: &pathValue,
+						},
+					},
+				},
+				BackendRefs: []gatewayv1.HTTPBackendRef{
+					{
+						BackendRef: gatewayv1.BackendRef{
+							BackendObjectReference: gatewayv1.BackendObjectReference{
+								Group: &backendGroup,
+								Kind:  &backendKind,
+								Name:  gatewayv1.ObjectName(svc.Name),
+								Port:  &backendPort,
+							},
+							Weight: &weight,
+						},
+					},
+				},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.Log.Info(fmt.Sprintf("HTTPRoute %s", op), "name", route.Name, "namespace", route.Namespace)
+	return route, nil
+}
+
+func (r *Reconciler) reconcileBackendTLSPolicy(ctx context.Context, cr *model.CryostatInstance, svc *corev1.Service,
+	exposePort *corev1.ServicePort, tls *resource_definitions.TLSConfig, config *operatorv1beta2.NetworkConfiguration) error {
+	policyConfig := config.HTTPRoute.BackendTLSPolicy
+	if tls == nil || (policyConfig != nil && policyConfig.Disabled != nil && *policyConfig.Disabled) {
+		return r.deleteCoreBackendTLSPolicy(ctx, cr)
+	}
+	if !r.IsBackendTLSPolicyInstalled {
+		r.Log.Info("BackendTLSPolicy API is not installed, traffic from the Gateway will not be re-encrypted",
+			"name", cr.Name, "namespace", cr.InstallNamespace)
+		return nil
+	}
+
+	// BackendTLSPolicy can only reference a CA certificate within a ConfigMap
+	cm := newCoreGatewayCAConfigMap(cr)
+	err := r.createOrUpdateConfigMap(ctx, cm, cr, map[string]string{
+		constants.CAKey: string(tls.CACert),
+	})
+	if err != nil {
+		return err
+	}
+
+	policy := newCoreBackendTLSPolicy(cr)
+	sectionName := gatewayv1alpha2.SectionName(exposePort.Name)
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, policy, func() error {
+		// Set labels and annotations from CR
+		common.MergeLabelsAndAnnotations(&policy.ObjectMeta, policyConfig.Labels, policyConfig.Annotations)
+
+		// Set the Cryostat CR as controller
+		if err := controllerutil.SetControllerReference(cr.Object, policy, r.Scheme); err != nil {
+			return err
+		}
+		// Update BackendTLSPolicy spec
+		policy.Spec.TargetRefs = []gatewayv1alpha2.LocalPolicyTargetReferenceWithSectionName{
+			{
+				LocalPolicyTargetReference: gatewayv1alpha2.LocalPolicyTargetReference{
+					Group: "",
+					Kind:  "Service",
+					Name:  gatewayv1.ObjectName(svc.Name),
+				},
+				SectionName: &sectionName,
+			},
+		}
+		policy.Spec.Validation = gatewayv1alpha3.BackendTLSPolicyValidation{
+			CACertificateRefs: []gatewayv1.LocalObjectReference{
+				{
+					Group: "",
+					Kind:  "ConfigMap",
+					Name:  gatewayv1.ObjectName(cm.Name),
+				},
+			},
+			// Must match a DNS name in the Cryostat certificate
+			Hostname: gatewayv1.PreciseHostname(fmt.Sprintf("%s.%s.svc", svc.Name, svc.Namespace)),
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	r.Log.Info(fmt.Sprintf("BackendTLSPolicy %s", op), "name", policy.Name, "namespace", policy.Namespace)
+	return nil
+}
+
+func (r *Reconciler) getHTTPRouteURL(ctx context.Context, route *gatewayv1.HTTPRoute,
+	cr *model.CryostatInstance) (*url.URL, error) {
+	parentRef := route.Spec.ParentRefs[0]
+	if parentRef.Kind != nil && *parentRef.Kind != "Gateway" {
+		return nil, fmt.Errorf("unsupported parent kind for HTTPRoute %s: %s", route.Name, *parentRef.Kind)
+	}
+	namespace := cr.InstallNamespace
+	if parentRef.Namespace != nil {
+		namespace = string(*parentRef.Namespace)
+	}
+
+	gateway := &gatewayv1.Gateway{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: string(parentRef.Name), Namespace: namespace}, gateway)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			r.Log.Info("Waiting for gateway to become available", "name", parentRef.Name, "namespace", namespace)
+			return nil, ErrIngressNotReady
+		}
+		return nil, err
+	}
+
+	listener := selectGatewayListener(gateway, &parentRef)
+	if listener == nil {
+		return nil, fmt.Errorf("no suitable listener found in gateway %s for HTTPRoute %s", gateway.Name, route.Name)
+	}
+
+	host := getHTTPRouteHost(route, listener, gateway)
+	if len(host) == 0 {
+		r.Log.Info("Waiting for gateway address to become available", "name", gateway.Name, "namespace", gateway.Namespace)
+		return nil, ErrIngressNotReady
+	}
+
+	scheme := "http"
+	defaultPort := gatewayv1.PortNumber(80)
+	if listener.Protocol == gatewayv1.HTTPSProtocolType {
+		scheme = "https"
+		defaultPort = gatewayv1.PortNumber(443)
+	}
+	if listener.Port != defaultPort {
+		host = host + ":" + strconv.Itoa(int(listener.Port))
+	}
+	return &url.URL{
+		Scheme: scheme,
+		Host:   host,
+	}, nil
+}
+
+func selectGatewayListener(gateway *gatewayv1.Gateway, parentRef *gatewayv1.ParentReference) *gatewayv1.Listener {
+	var selected *gatewayv1.Listener
+	for i, listener := range gateway.Spec.Listeners {
+		if listener.Protocol != gatewayv1.HTTPProtocolType && listener.Protocol != gatewayv1.HTTPSProtocolType {
+			continue
+		}
+		if parentRef.SectionName != nil && listener.Name != *parentRef.SectionName {
+			continue
+		}
+		if parentRef.Port != nil && listener.Port != *parentRef.Port {
+			continue
+		}
+		// Prefer HTTPS listeners if the parent reference is ambiguous
+		if selected == nil || (selected.Protocol != gatewayv1.HTTPSProtocolType &&
+			listener.Protocol == gatewayv1.HTTPSProtocolType) {
+			selected = &gateway.Spec.Listeners[i]
+		}
+	}
+	return selected
+}
+
+func getHTTPRouteHost(route *gatewayv1.HTTPRoute, listener *gatewayv1.Listener, gateway *gatewayv1.Gateway) string {
+	for _, hostname := range route.Spec.Hostnames {
+		if !strings.HasPrefix(string(hostname), "*") {
+			return string(hostname)
+		}
+	}
+	if listener.Hostname != nil && !strings.HasPrefix(string(*listener.Hostname), "*") {
+		return string(*listener.Hostname)
+	}
+	if len(gateway.Status.Addresses) > 0 {
+		return gateway.Status.Addresses[0].Value
+	}
+	return ""
+}
+
+func configureCoreHTTPRoute(cr *model.CryostatInstance) *operatorv1beta2.NetworkConfiguration {
+	config := cr.Spec.NetworkOptions.CoreConfig
+	configureHTTPRoute(config, cr.Name, "cryostat")
+	return config
+}
+
+func configureHTTPRoute(config *operatorv1beta2.NetworkConfiguration, appLabel string, componentLabel string) {
+	if config.Labels == nil {
+		config.Labels = map[string]string{}
+	}
+	if config.Annotations == nil {
+		config.Annotations = map[string]string{}
+	}
+	if config.HTTPRoute.BackendTLSPolicy == nil {
+		config.HTTPRoute.BackendTLSPolicy = &operatorv1beta2.BackendTLSPolicyConfiguration{}
+	}
+	policyConfig := config.HTTPRoute.BackendTLSPolicy
+	if policyConfig.Labels == nil {
+		policyConfig.Labels = map[string]string{}
+	}
+	if policyConfig.Annotations == nil {
+		policyConfig.Annotations = map[string]string{}
+	}
+
+	// Add required labels, overriding any user-specified labels with the same keys
+	config.Labels["app"] = appLabel
+	config.Labels["component"] = componentLabel
+	policyConfig.Labels["app"] = appLabel
+	policyConfig.Labels["component"] = componentLabel
+}
+
+func (r *Reconciler) deleteCoreBackendTLSPolicy(ctx context.Context, cr *model.CryostatInstance) error {
+	if !r.IsBackendTLSPolicyInstalled {
+		return nil
+	}
+	err := r.deleteBackendTLSPolicy(ctx, newCoreBackendTLSPolicy(cr))
+	if err != nil {
+		return err
+	}
+	return r.deleteConfigMap(ctx, newCoreGatewayCAConfigMap(cr))
+}
+
+func (r *Reconciler) deleteHTTPRoute(ctx context.Context, route *gatewayv1.HTTPRoute) error {
+	err := r.Client.Delete(ctx, route)
+	if err != nil && !kerrors.IsNotFound(err) {
+		r.Log.Error(err, "Could not delete HTTPRoute", "name", route.Name, "namespace", route.Namespace)
+		return err
+	}
+	r.Log.Info("HTTPRoute deleted", "name", route.Name, "namespace", route.Namespace)
+	return nil
+}
+
+func (r *Reconciler) deleteBackendTLSPolicy(ctx context.Context, policy *gatewayv1alpha3.BackendTLSPolicy) error {
+	err := r.Client.Delete(ctx, policy)
+	if err != nil && !kerrors.IsNotFound(err) {
+		r.Log.Error(err, "Could not delete BackendTLSPolicy", "name", policy.Name, "namespace", policy.Namespace)
+		return err
+	}
+	r.Log.Info("BackendTLSPolicy deleted", "name", policy.Name, "namespace", policy.Namespace)
+	return nil
+}
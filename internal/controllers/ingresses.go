@@ -29,14 +29,18 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
-func (r *Reconciler) reconcileCoreIngress(ctx context.Context, cr *model.CryostatInstance,
-	specs *resource_definitions.ServiceSpecs) error {
-	ingress := &netv1.Ingress{
+func newCoreIngress(cr *model.CryostatInstance) *netv1.Ingress {
+	return &netv1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cr.Name,
 			Namespace: cr.InstallNamespace,
 		},
 	}
+}
+
+func (r *Reconciler) reconcileCoreIngress(ctx context.Context, cr *model.CryostatInstance,
+	specs *resource_definitions.ServiceSpecs) error {
+	ingress := newCoreIngress(cr)
 
 	if cr.Spec.NetworkOptions == nil || cr.Spec.NetworkOptions.CoreConfig == nil ||
 		cr.Spec.NetworkOptions.CoreConfig.IngressSpec == nil {
@@ -105,6 +105,21 @@ func (r *Reconciler) reconcileCoreNetworkPolicy(ctx context.Context, cr *model.C
 				},
 			},
 		}
+		if isAgentGatewayExposed(cr) {
+			// allow ingress to the agent gateway from outside the cluster
+			agentIngress := networkingv1.NetworkPolicyIngressRule{
+				Ports: []networkingv1.NetworkPolicyPort{
+					{
+						Port: &intstr.IntOrString{IntVal: constants.AgentProxyContainerPort},
+					},
+				},
+			}
+			if r.IsOpenShift && !isAgentGatewayLoadBalancerRequested(cr) {
+				// only the Route is used, so restrict to the OpenShift router
+				agentIngress.From = []networkingv1.NetworkPolicyPeer{RouteSelector}
+			}
+			networkPolicy.Spec.Ingress = append(networkPolicy.Spec.Ingress, agentIngress)
+		}
 		return nil
 	})
 }
@@ -134,6 +149,12 @@ func (r *Reconciler) reconcileDatabaseNetworkPolicy(ctx context.Context, cr *mod
 								MatchLabels: resources.CorePodLabels(cr),
 							},
 						},
+						{
+							NamespaceSelector: installationNamespaceSelector(cr),
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: resources.BackupPodLabels(cr),
+							},
+						},
 					},
 					Ports: []networkingv1.NetworkPolicyPort{
 						{
@@ -173,6 +194,12 @@ func (r *Reconciler) reconcileStorageNetworkPolicy(ctx context.Context, cr *mode
 								MatchLabels: resources.CorePodLabels(cr),
 							},
 						},
+						{
+							NamespaceSelector: installationNamespaceSelector(cr),
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: resources.BackupPodLabels(cr),
+							},
+						},
 					},
 					Ports: []networkingv1.NetworkPolicyPort{
 						{
@@ -225,6 +252,51 @@ func (r *Reconciler) reconcileReportsNetworkPolicy(ctx context.Context, cr *mode
 	})
 }
 
+func (r *Reconciler) reconcileGrafanaNetworkPolicy(ctx context.Context, cr *model.CryostatInstance) error {
+	networkPolicy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-grafana-internal-ingress", cr.Name),
+			Namespace: cr.InstallNamespace,
+		},
+	}
+
+	if !resources.IsGrafanaSeparate(cr) || (cr.Spec.NetworkPolicies != nil && cr.Spec.NetworkPolicies.GrafanaConfig != nil &&
+		cr.Spec.NetworkPolicies.GrafanaConfig.Disabled != nil && *cr.Spec.NetworkPolicies.GrafanaConfig.Disabled) {
+		return r.deletePolicy(ctx, networkPolicy)
+	}
+
+	return r.createOrUpdatePolicy(ctx, networkPolicy, cr.Object, func() error {
+		networkPolicy.Spec = networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: resources.GrafanaPodLabels(cr),
+			},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				// allow ingress to the dashboard from the auth proxy, and to the datasource from Cryostat,
+				// both of which run in the Cryostat pod
+				{
+					From: []networkingv1.NetworkPolicyPeer{
+						{
+							NamespaceSelector: installationNamespaceSelector(cr),
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: resources.CorePodLabels(cr),
+							},
+						},
+					},
+					Ports: []networkingv1.NetworkPolicyPort{
+						{
+							Port: &intstr.IntOrString{IntVal: constants.GrafanaContainerPort},
+						},
+						{
+							Port: &intstr.IntOrString{IntVal: constants.DatasourceContainerPort},
+						},
+					},
+				},
+			},
+		}
+		return nil
+	})
+}
+
 func (r *Reconciler) createOrUpdatePolicy(ctx context.Context, networkPolicy *networkingv1.NetworkPolicy, owner metav1.Object,
 	delegate controllerutil.MutateFn) error {
 	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, networkPolicy, func() error {
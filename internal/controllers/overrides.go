@@ -0,0 +1,333 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+const (
+	overrideComponentCore     = "core"
+	overrideComponentReports  = "reports"
+	overrideComponentDatabase = "database"
+	overrideComponentStorage  = "storage"
+	overrideComponentGrafana  = "grafana"
+)
+
+// Keys used to match list items when comparing pod templates, in order of preference.
+// These correspond to the patch merge keys of the lists in a pod template.
+var overrideListMergeKeys = []string{"name", "mountPath", "containerPort", "devicePath", "ip"}
+
+// overrideResults collects the outcome of applying the pod template overrides for each component
+type overrideResults struct {
+	// Properties set by the operator that were changed by an override
+	conflicts []string
+	// Reasons why overrides could not be applied
+	invalid []string
+}
+
+// applyPodTemplateOverride strategically merges the partial pod template for a component over
+// the pod template of its deployment. If the merged pod template is invalid, the deployment is
+// left unchanged.
+func applyPodTemplateOverride(deploy *appsv1.Deployment, component string, override *corev1.PodTemplateSpec,
+	results *overrideResults) {
+	if override == nil {
+		return
+	}
+	merged, conflicts, err := mergePodTemplate(&deploy.Spec.Template, override)
+	if err != nil {
+		results.invalid = append(results.invalid, fmt.Sprintf("%s: %s", component, err.Error()))
+		return
+	}
+	problems := validatePodTemplate(merged, deploy.Spec.Selector)
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			results.invalid = append(results.invalid, fmt.Sprintf("%s: %s", component, problem))
+		}
+		return
+	}
+	for _, conflict := range conflicts {
+		results.conflicts = append(results.conflicts, fmt.Sprintf("%s: %s", component, conflict))
+	}
+	deploy.Spec.Template = *merged
+}
+
+func getPodTemplateOverride(cr *model.CryostatInstance, component string) *corev1.PodTemplateSpec {
+	overrides := cr.Spec.Overrides
+	if overrides == nil {
+		return nil
+	}
+	switch component {
+	case overrideComponentCore:
+		return overrides.Core
+	case overrideComponentReports:
+		return overrides.Reports
+	case overrideComponentDatabase:
+		return overrides.Database
+	case overrideComponentStorage:
+		return overrides.Storage
+	case overrideComponentGrafana:
+		return overrides.Grafana
+	}
+	return nil
+}
+
+func hasPodTemplateOverrides(cr *model.CryostatInstance) bool {
+	overrides := cr.Spec.Overrides
+	return overrides != nil && (overrides.Core != nil || overrides.Reports != nil ||
+		overrides.Database != nil || overrides.Storage != nil || overrides.Grafana != nil)
+}
+
+// mergePodTemplate returns the result of strategically merging the override over the original
+// pod template, along with the paths of any properties in the original that were changed or removed
+func mergePodTemplate(original *corev1.PodTemplateSpec, override *corev1.PodTemplateSpec) (*corev1.PodTemplateSpec, []string, error) {
+	originalJSON, err := json.Marshal(original)
+	if err != nil {
+		return nil, nil, err
+	}
+	patch, err := toUnstructuredMap(override)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Unset fields in the override are serialized as null, which would otherwise delete them
+	patchJSON, err := json.Marshal(removeNulls(patch))
+	if err != nil {
+		return nil, nil, err
+	}
+	mergedJSON, err := strategicpatch.StrategicMergePatch(originalJSON, patchJSON, corev1.PodTemplateSpec{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to merge pod template: %w", err)
+	}
+	merged := &corev1.PodTemplateSpec{}
+	err = json.Unmarshal(mergedJSON, merged)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Compare the generic representations to find properties that were overridden
+	originalMap, err := toUnstructuredMap(original)
+	if err != nil {
+		return nil, nil, err
+	}
+	mergedMap, err := toUnstructuredMap(merged)
+	if err != nil {
+		return nil, nil, err
+	}
+	return merged, findOverriddenPaths("", originalMap, mergedMap), nil
+}
+
+func toUnstructuredMap(obj interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]interface{}{}
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func removeNulls(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, elem := range v {
+			if elem == nil {
+				delete(v, key)
+			} else {
+				v[key] = removeNulls(elem)
+			}
+		}
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = removeNulls(elem)
+		}
+	}
+	return value
+}
+
+func findOverriddenPaths(path string, original interface{}, merged interface{}) []string {
+	switch orig := original.(type) {
+	case map[string]interface{}:
+		mergedMap, ok := merged.(map[string]interface{})
+		if !ok {
+			return []string{path}
+		}
+		keys := make([]string, 0, len(orig))
+		for key := range orig {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		result := []string{}
+		for _, key := range keys {
+			if orig[key] == nil {
+				continue
+			}
+			result = append(result, findOverriddenPaths(joinPath(path, key), orig[key], mergedMap[key])...)
+		}
+		return result
+	case []interface{}:
+		mergedList, ok := merged.([]interface{})
+		if !ok {
+			return []string{path}
+		}
+		mergeKey := getListMergeKey(orig)
+		if len(mergeKey) == 0 {
+			// Lists without a merge key are replaced as a whole
+			if !reflect.DeepEqual(orig, mergedList) {
+				return []string{path}
+			}
+			return []string{}
+		}
+		result := []string{}
+		for _, elem := range orig {
+			key := elem.(map[string]interface{})[mergeKey]
+			itemPath := fmt.Sprintf("%s[%v]", path, key)
+			mergedElem := findListItem(mergedList, mergeKey, key)
+			if mergedElem == nil {
+				result = append(result, itemPath)
+				continue
+			}
+			result = append(result, findOverriddenPaths(itemPath, elem, mergedElem)...)
+		}
+		return result
+	default:
+		if !reflect.DeepEqual(original, merged) {
+			return []string{path}
+		}
+		return []string{}
+	}
+}
+
+// getListMergeKey returns the key that identifies the items in the list, or an empty string
+// if the items are not objects sharing one of the known merge keys
+func getListMergeKey(list []interface{}) string {
+	for _, key := range overrideListMergeKeys {
+		found := len(list) > 0
+		for _, elem := range list {
+			elemMap, ok := elem.(map[string]interface{})
+			if !ok || elemMap[key] == nil {
+				found = false
+				break
+			}
+		}
+		if found {
+			return key
+		}
+	}
+	return ""
+}
+
+func findListItem(list []interface{}, mergeKey string, key interface{}) interface{} {
+	for _, elem := range list {
+		elemMap, ok := elem.(map[string]interface{})
+		if ok && reflect.DeepEqual(elemMap[mergeKey], key) {
+			return elem
+		}
+	}
+	return nil
+}
+
+func joinPath(path string, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+	return path + "." + key
+}
+
+// validatePodTemplate checks the merged pod template for mistakes that would prevent
+// the deployment from being created or its pods from starting
+func validatePodTemplate(template *corev1.PodTemplateSpec, selector *metav1.LabelSelector) []string {
+	problems := []string{}
+	if selector != nil && !labels.SelectorFromSet(selector.MatchLabels).Matches(labels.Set(template.Labels)) {
+		problems = append(problems, "pod template labels must match the deployment selector")
+	}
+
+	volumes := map[string]bool{}
+	for _, volume := range template.Spec.Volumes {
+		if len(volume.Name) == 0 {
+			problems = append(problems, "volumes must have a name")
+		} else if volumes[volume.Name] {
+			problems = append(problems, fmt.Sprintf("volume %s is defined more than once", volume.Name))
+		}
+		volumes[volume.Name] = true
+	}
+
+	containerNames := map[string]bool{}
+	containers := append(append([]corev1.Container{}, template.Spec.InitContainers...), template.Spec.Containers...)
+	for _, container := range containers {
+		if len(container.Name) == 0 {
+			problems = append(problems, "containers must have a name")
+			continue
+		}
+		if containerNames[container.Name] {
+			problems = append(problems, fmt.Sprintf("container %s is defined more than once", container.Name))
+		}
+		containerNames[container.Name] = true
+		if len(container.Image) == 0 {
+			problems = append(problems, fmt.Sprintf("container %s must have an image", container.Name))
+		}
+		for _, mount := range container.VolumeMounts {
+			if !volumes[mount.Name] {
+				problems = append(problems, fmt.Sprintf("container %s mounts volume %s, which does not exist",
+					container.Name, mount.Name))
+			}
+		}
+		for _, env := range container.Env {
+			if len(env.Value) > 0 && env.ValueFrom != nil {
+				problems = append(problems, fmt.Sprintf("environment variable %s of container %s cannot have both value and valueFrom",
+					env.Name, container.Name))
+			}
+		}
+	}
+	return problems
+}
+
+// updateOverridesCondition reports whether the pod template overrides were applied to each component
+func (r *Reconciler) updateOverridesCondition(ctx context.Context, cr *model.CryostatInstance, results *overrideResults) error {
+	if !hasPodTemplateOverrides(cr) {
+		// Only update the status to remove a condition reported for previous overrides
+		if meta.RemoveStatusCondition(&cr.Status.Conditions, string(operatorv1beta2.ConditionTypeOverridesApplied)) {
+			return r.Client.Status().Update(ctx, cr.Object)
+		}
+		return nil
+	}
+	if len(results.invalid) > 0 {
+		return r.updateCondition(ctx, cr, operatorv1beta2.ConditionTypeOverridesApplied, metav1.ConditionFalse,
+			reasonOverridesInvalid, "Pod template overrides were not applied: "+strings.Join(results.invalid, "; "))
+	}
+	if len(results.conflicts) > 0 {
+		return r.updateCondition(ctx, cr, operatorv1beta2.ConditionTypeOverridesApplied, metav1.ConditionTrue,
+			reasonOverridesConflict, "Pod template overrides replace properties set by the operator, "+
+				"which may prevent Cryostat from working correctly: "+strings.Join(results.conflicts, ", "))
+	}
+	return r.updateCondition(ctx, cr, operatorv1beta2.ConditionTypeOverridesApplied, metav1.ConditionTrue,
+		reasonOverridesApplied, "Pod template overrides were applied.")
+}
@@ -0,0 +1,77 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const reconcilePausedMessage = "Reconciliation is paused. The operator will not modify any resources for this Cryostat " +
+	"until reconcilePaused is unset."
+
+// updatePausedCondition reports whether the Cryostat components are scaled down by the paused property.
+// Since this Cryostat is being reconciled, any ReconcilePaused condition is also removed.
+func (r *Reconciler) updatePausedCondition(ctx context.Context, cr *model.CryostatInstance) error {
+	removeConditionIfPresent(cr, operatorv1beta2.ConditionTypeReconcilePaused)
+	if cr.Spec.Paused {
+		return r.updateCondition(ctx, cr, operatorv1beta2.ConditionTypePaused, metav1.ConditionTrue, reasonPaused,
+			"All Cryostat components are scaled down. Persistent data and configuration are retained.")
+	}
+	return r.updateCondition(ctx, cr, operatorv1beta2.ConditionTypePaused, metav1.ConditionFalse, reasonNotPaused,
+		"Cryostat components are scaled up.")
+}
+
+// scaleDownDeployment removes all pods of the deployment, while keeping the deployment itself
+func scaleDownDeployment(deploy *appsv1.Deployment) {
+	replicas := int32(0)
+	deploy.Spec.Replicas = &replicas
+}
+
+// isRestoringBackup returns whether a CryostatRestore is about to replace, or is replacing,
+// the contents of this Cryostat's database
+func (r *Reconciler) isRestoringBackup(ctx context.Context, cr *model.CryostatInstance) (bool, error) {
+	restores := &operatorv1beta2.CryostatRestoreList{}
+	err := r.Client.List(ctx, restores, client.InNamespace(cr.InstallNamespace))
+	if err != nil {
+		return false, err
+	}
+	for i := range restores.Items {
+		restore := &restores.Items[i]
+		if restore.Spec.CryostatName == cr.Name && isRestoreActive(restore) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// mapFromRestore enqueues the Cryostat that a CryostatRestore restores into
+func (r *Reconciler) mapFromRestore(ctx context.Context, obj client.Object) []reconcile.Request {
+	restore, ok := obj.(*operatorv1beta2.CryostatRestore)
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{
+		Name:      restore.Spec.CryostatName,
+		Namespace: restore.Namespace,
+	}}}
+}
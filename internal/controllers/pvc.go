@@ -17,94 +17,199 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
 	"github.com/cryostatio/cryostat-operator/internal/controllers/common"
+	resources "github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
 	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 const (
 	// Event type to inform users of invalid PVC specs
 	eventPersistentVolumeClaimInvalidType = "PersistentVolumeClaimInvalid"
-	mib                                   = 1024 * 1024
-	gib                                   = 1024 * mib
-	DefaultDatabasePVCSize                = 500 * mib
-	DefaultStoragePVCSize                 = 10 * gib
+	// Event types to inform users of PVCs kept after the Cryostat was deleted
+	eventPersistentVolumeClaimRetainedType = "PersistentVolumeClaimRetained"
+	eventPersistentVolumeClaimAdoptedType  = "PersistentVolumeClaimAdopted"
+	// Event types to inform users of snapshots taken before PVCs are deleted
+	eventVolumeSnapshotCreatedType     = "VolumeSnapshotCreated"
+	eventVolumeSnapshotFailedType      = "VolumeSnapshotFailed"
+	eventVolumeSnapshotUnavailableType = "VolumeSnapshotUnavailable"
+	mib                                = 1024 * 1024
+	gib                                = 1024 * mib
+	DefaultDatabasePVCSize             = 500 * mib
+	DefaultStoragePVCSize              = 10 * gib
 )
 
-func (r *Reconciler) reconcilePVC(ctx context.Context, cr *model.CryostatInstance, storageConfiguration *operatorv1beta2.StorageConfiguration, defaultSize resource.Quantity, nameSuffix *string) error {
+// Annotation naming the deleted Cryostat that retained a PVC, which is removed once
+// a new Cryostat with that name adopts the PVC
+const pvcRetainedByAnnotation = "operator.cryostat.io/retained-by"
+
+const pvcRetainedOnDeletionMessage = "Persistent Volume Claim %s was retained after the Cryostat was deleted"
+
+// How often to check whether the VolumeSnapshots of a deleted Cryostat's PVCs are ready
+const volumeSnapshotRetryPeriod = 10 * time.Second
+
+var volumeSnapshotGVK = schema.GroupVersionKind{
+	Group:   "snapshot.storage.k8s.io",
+	Version: "v1",
+	Kind:    "VolumeSnapshot",
+}
+
+// reconcilePVC creates or updates the Persistent Volume Claim of the database or storage component,
+// and returns whether the component must be scaled down while its data is migrated to a new claim
+func (r *Reconciler) reconcilePVC(ctx context.Context, cr *model.CryostatInstance, storageConfiguration *operatorv1beta2.StorageConfiguration,
+	defaultSize resource.Quantity, component string) (bool, error) {
 	emptyDir := storageConfiguration != nil && storageConfiguration.EmptyDir != nil && storageConfiguration.EmptyDir.Enabled
 	if emptyDir {
 		// If user requested an emptyDir volume, then do nothing.
 		// Don't delete the PVC to prevent accidental data loss
 		// depending on the reclaim policy.
-		return nil
+		return false, nil
 	}
-	var name string
-	if nameSuffix == nil {
-		name = cr.Name
-	} else {
-		name = fmt.Sprintf("%s-%s", cr.Name, *nameSuffix)
+	if findPVCStatus(cr, component) == nil {
+		// Use any PVC retained from a previous Cryostat with the same name, which may have been migrated
+		retained, err := r.findRetainedPVC(ctx, cr, component)
+		if err != nil {
+			return false, err
+		}
+		if retained != nil {
+			getPVCStatus(cr, component).ClaimName = retained.Name
+		}
 	}
+	status := getPVCStatus(cr, component)
 	pvc := &corev1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
+			Name:      status.ClaimName,
 			Namespace: cr.InstallNamespace,
 		},
 	}
 
 	// Look up PVC configuration, applying defaults where needed
-	config := configurePVC(cr.Name, storageConfiguration, defaultSize)
+	config := configurePVC(cr.Name, component, storageConfiguration, defaultSize)
 
-	err := r.createOrUpdatePVC(ctx, pvc, cr.Object, config)
+	err := r.createOrUpdatePVC(ctx, pvc, cr, config)
 	if err != nil {
 		// If the API server says the PVC is invalid, emit a warning event
 		// to inform the user.
 		if kerrors.IsInvalid(err) {
 			r.EventRecorder.Event(cr.Object, corev1.EventTypeWarning, eventPersistentVolumeClaimInvalidType, err.Error())
 		}
-		return err
+		return false, err
 	}
-	return nil
+	return r.reconcilePVCMigration(ctx, cr, status, pvc, config, storageConfiguration)
 }
 
-func (r *Reconciler) reconcileDatabasePVC(ctx context.Context, cr *model.CryostatInstance) error {
-	name := "database"
-	var cfg *operatorv1beta2.StorageConfiguration
-	if cr.Spec.StorageOptions != nil {
-		cfg = cr.Spec.StorageOptions.Database
-		if cfg == nil {
-			cfg = (*operatorv1beta2.StorageConfiguration)(&cr.Spec.StorageOptions.LegacyStorageConfiguration)
+// getPVCStatus returns the status of the component's Persistent Volume Claim,
+// adding it to the Cryostat CR's status if not already present
+func getPVCStatus(cr *model.CryostatInstance, component string) *operatorv1beta2.PersistentVolumeClaimStatus {
+	if status := findPVCStatus(cr, component); status != nil {
+		return status
+	}
+	cr.Status.PersistentVolumeClaims = append(cr.Status.PersistentVolumeClaims, operatorv1beta2.PersistentVolumeClaimStatus{
+		Component: component,
+		ClaimName: resources.PersistentVolumeClaimName(cr, component),
+	})
+	return &cr.Status.PersistentVolumeClaims[len(cr.Status.PersistentVolumeClaims)-1]
+}
+
+// findRetainedPVC returns the newest of the component's PVCs retained when a previous Cryostat
+// with the same name was deleted, or nil if there are none
+func (r *Reconciler) findRetainedPVC(ctx context.Context, cr *model.CryostatInstance,
+	component string) (*corev1.PersistentVolumeClaim, error) {
+	pvcs := &corev1.PersistentVolumeClaimList{}
+	err := r.Client.List(ctx, pvcs, client.InNamespace(cr.InstallNamespace),
+		client.MatchingLabels{"app": cr.Name, "component": component})
+	if err != nil {
+		return nil, err
+	}
+	var retained *corev1.PersistentVolumeClaim
+	for i, pvc := range pvcs.Items {
+		if pvc.Annotations[pvcRetainedByAnnotation] != cr.Name {
+			continue
+		}
+		// A migrated PVC's name extends the default name with the time of the migration,
+		// so the PVC the Cryostat used last sorts after any it was migrated from
+		if retained == nil || pvc.Name > retained.Name {
+			retained = &pvcs.Items[i]
 		}
 	}
-	return r.reconcilePVC(ctx, cr, cfg, *resource.NewQuantity(DefaultDatabasePVCSize, resource.BinarySI), &name)
+	return retained, nil
 }
 
-func (r *Reconciler) reconcileStoragePVC(ctx context.Context, cr *model.CryostatInstance) error {
-	name := "storage"
-	var cfg *operatorv1beta2.StorageConfiguration
-	if cr.Spec.StorageOptions != nil {
-		cfg = cr.Spec.StorageOptions.ObjectStorage
-		if cfg == nil {
-			cfg = (*operatorv1beta2.StorageConfiguration)(&cr.Spec.StorageOptions.LegacyStorageConfiguration)
+func findPVCStatus(cr *model.CryostatInstance, component string) *operatorv1beta2.PersistentVolumeClaimStatus {
+	for i := range cr.Status.PersistentVolumeClaims {
+		if cr.Status.PersistentVolumeClaims[i].Component == component {
+			return &cr.Status.PersistentVolumeClaims[i]
 		}
 	}
-	return r.reconcilePVC(ctx, cr, cfg, *resource.NewQuantity(DefaultStoragePVCSize, resource.BinarySI), &name)
+	return nil
+}
+
+func (r *Reconciler) reconcileDatabasePVC(ctx context.Context, cr *model.CryostatInstance) (bool, error) {
+	cfg := getDatabaseStorageConfiguration(cr.Spec.StorageOptions)
+	return r.reconcilePVC(ctx, cr, cfg, *resource.NewQuantity(DefaultDatabasePVCSize, resource.BinarySI), "database")
+}
+
+func (r *Reconciler) reconcileStoragePVC(ctx context.Context, cr *model.CryostatInstance) (bool, error) {
+	cfg := getObjectStorageConfiguration(cr.Spec.StorageOptions)
+	return r.reconcilePVC(ctx, cr, cfg, *resource.NewQuantity(DefaultStoragePVCSize, resource.BinarySI), "storage")
+}
+
+func getDatabaseStorageConfiguration(options *operatorv1beta2.StorageConfigurations) *operatorv1beta2.StorageConfiguration {
+	if options == nil {
+		return nil
+	}
+	return storageConfigurationOrLegacy(options, options.Database)
+}
+
+func getObjectStorageConfiguration(options *operatorv1beta2.StorageConfigurations) *operatorv1beta2.StorageConfiguration {
+	if options == nil {
+		return nil
+	}
+	return storageConfigurationOrLegacy(options, options.ObjectStorage)
+}
+
+// storageConfigurationOrLegacy falls back to the deprecated configuration
+// shared by the database and object storage, if cfg is not specified
+func storageConfigurationOrLegacy(options *operatorv1beta2.StorageConfigurations,
+	cfg *operatorv1beta2.StorageConfiguration) *operatorv1beta2.StorageConfiguration {
+	if cfg != nil {
+		return cfg
+	}
+	return &operatorv1beta2.StorageConfiguration{
+		PVC:      options.PVC,
+		EmptyDir: options.EmptyDir,
+	}
 }
 
 func (r *Reconciler) createOrUpdatePVC(ctx context.Context, pvc *corev1.PersistentVolumeClaim,
-	owner metav1.Object, config *operatorv1beta2.PersistentVolumeClaimConfig) error {
+	cr *model.CryostatInstance, config *operatorv1beta2.PersistentVolumeClaimConfig) error {
 	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, pvc, func() error {
-		// Merge labels and annotations to prevent overriding any set by Kubernetes
+		// Merge labels and annotations to prevent overriding any set by Kubernetes.
+		// Those configured for this PVC take precedence over those for all resources.
+		mergeResourceMetadata(cr, &pvc.ObjectMeta)
 		common.MergeLabelsAndAnnotations(&pvc.ObjectMeta, config.Labels, config.Annotations)
 
+		// Adopt a PVC retained when a previous Cryostat with the same name was deleted
+		if retainedBy, found := pvc.Annotations[pvcRetainedByAnnotation]; found {
+			delete(pvc.Annotations, pvcRetainedByAnnotation)
+			r.EventRecorder.Eventf(cr.Object, corev1.EventTypeNormal, eventPersistentVolumeClaimAdoptedType,
+				"Using Persistent Volume Claim %s retained from the previously deleted Cryostat %s", pvc.Name, retainedBy)
+		}
+
 		// Set the Cryostat CR as controller
-		if err := controllerutil.SetControllerReference(owner, pvc, r.Scheme); err != nil {
+		if err := controllerutil.SetControllerReference(cr.Object, pvc, r.Scheme); err != nil {
 			return err
 		}
 
@@ -130,7 +235,7 @@ func (r *Reconciler) createOrUpdatePVC(ctx context.Context, pvc *corev1.Persiste
 	return nil
 }
 
-func configurePVC(name string, cfg *operatorv1beta2.StorageConfiguration, defaultSize resource.Quantity) *operatorv1beta2.PersistentVolumeClaimConfig {
+func configurePVC(name string, component string, cfg *operatorv1beta2.StorageConfiguration, defaultSize resource.Quantity) *operatorv1beta2.PersistentVolumeClaimConfig {
 	var config *operatorv1beta2.PersistentVolumeClaimConfig
 	if cfg == nil || cfg.PVC == nil {
 		config = &operatorv1beta2.PersistentVolumeClaimConfig{}
@@ -148,8 +253,10 @@ func configurePVC(name string, cfg *operatorv1beta2.StorageConfiguration, defaul
 		config.Spec = &corev1.PersistentVolumeClaimSpec{}
 	}
 
-	// Add "app" label. This will override any user-specified "app" label.
+	// Add "app" and "component" labels. These will override any user-specified labels with the same keys.
+	// The "component" label identifies a retained PVC, which may have been migrated to a different name.
 	config.Labels["app"] = name
+	config.Labels["component"] = component
 
 	// Apply any applicable spec defaults. Don't apply a default storage class name, since nil
 	// may be intentionally specified.
@@ -164,3 +271,165 @@ func configurePVC(name string, cfg *operatorv1beta2.StorageConfiguration, defaul
 
 	return config
 }
+
+// finalizePVCs applies the deletion policy of each of the Cryostat's PVCs, and returns
+// whether they are ready to be garbage collected along with the Cryostat
+func (r *Reconciler) finalizePVCs(ctx context.Context, cr *model.CryostatInstance) (bool, error) {
+	done := true
+	pvcs := []struct {
+		component string
+		cfg       *operatorv1beta2.StorageConfiguration
+	}{
+		{"database", getDatabaseStorageConfiguration(cr.Spec.StorageOptions)},
+		{"storage", getObjectStorageConfiguration(cr.Spec.StorageOptions)},
+	}
+	for _, claim := range pvcs {
+		component, cfg := claim.component, claim.cfg
+		if cfg == nil || (cfg.EmptyDir != nil && cfg.EmptyDir.Enabled) {
+			continue
+		}
+		if cfg.DeletionPolicy != operatorv1beta2.PVCDeletionPolicyRetain &&
+			cfg.DeletionPolicy != operatorv1beta2.PVCDeletionPolicySnapshot {
+			continue
+		}
+
+		pvc := &corev1.PersistentVolumeClaim{}
+		err := r.Client.Get(ctx, types.NamespacedName{Name: resources.PersistentVolumeClaimName(cr, component),
+			Namespace: cr.InstallNamespace}, pvc)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return false, err
+		}
+		if !metav1.IsControlledBy(pvc, cr.Object) {
+			// Already retained, or not created by this Cryostat
+			continue
+		}
+
+		if cfg.DeletionPolicy == operatorv1beta2.PVCDeletionPolicyRetain {
+			err = r.retainPVC(ctx, cr, pvc, pvcRetainedOnDeletionMessage)
+			if err != nil {
+				return false, err
+			}
+			continue
+		}
+		ready, err := r.snapshotPVC(ctx, cr, pvc, cfg.VolumeSnapshotClassName)
+		if err != nil {
+			return false, err
+		}
+		done = done && ready
+	}
+	return done, nil
+}
+
+// retainPVC removes the Cryostat as the PVC's owner, so that it is not garbage collected.
+// The message of the emitted Event is formatted with the name of the PVC.
+func (r *Reconciler) retainPVC(ctx context.Context, cr *model.CryostatInstance, pvc *corev1.PersistentVolumeClaim,
+	eventMessage string) error {
+	err := controllerutil.RemoveControllerReference(cr.Object, pvc, r.Scheme)
+	if err != nil {
+		return err
+	}
+	if pvc.Annotations == nil {
+		pvc.Annotations = map[string]string{}
+	}
+	pvc.Annotations[pvcRetainedByAnnotation] = cr.Name
+	err = r.Client.Update(ctx, pvc)
+	if err != nil {
+		return err
+	}
+	r.Log.Info("Retained Persistent Volume Claim", "name", pvc.Name, "namespace", pvc.Namespace)
+	r.EventRecorder.Eventf(cr.Object, corev1.EventTypeNormal, eventPersistentVolumeClaimRetainedType, eventMessage, pvc.Name)
+	return nil
+}
+
+// snapshotPVC takes a VolumeSnapshot of the PVC, and returns whether it is ready. If a snapshot
+// cannot be taken, the PVC is retained instead, so that no data is lost.
+func (r *Reconciler) snapshotPVC(ctx context.Context, cr *model.CryostatInstance, pvc *corev1.PersistentVolumeClaim,
+	snapshotClassName *string) (bool, error) {
+	available, err := r.volumeSnapshotAvailable()
+	if err != nil {
+		return false, err
+	}
+	if !available {
+		r.EventRecorder.Eventf(cr.Object, corev1.EventTypeWarning, eventVolumeSnapshotUnavailableType,
+			"The VolumeSnapshot API is not available, retaining Persistent Volume Claim %s instead", pvc.Name)
+		return true, r.retainPVC(ctx, cr, pvc, pvcRetainedOnDeletionMessage)
+	}
+
+	// Name the snapshot after the deletion, so it is the same for each reconcile
+	name := fmt.Sprintf("%s-%s", pvc.Name, cr.Object.GetDeletionTimestamp().UTC().Format("20060102150405"))
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	err = r.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: pvc.Namespace}, snapshot)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return false, err
+		}
+		// The snapshot is not owned by the Cryostat, so that it outlives it
+		snapshot = newVolumeSnapshot(name, pvc, cr.Name, snapshotClassName)
+		err = r.Client.Create(ctx, snapshot)
+		if err != nil {
+			return false, err
+		}
+		r.Log.Info("Created VolumeSnapshot", "name", name, "namespace", pvc.Namespace)
+		r.EventRecorder.Eventf(cr.Object, corev1.EventTypeNormal, eventVolumeSnapshotCreatedType,
+			"Created VolumeSnapshot %s of Persistent Volume Claim %s", name, pvc.Name)
+		return false, nil
+	}
+
+	ready, _, err := unstructured.NestedBool(snapshot.Object, "status", "readyToUse")
+	if err != nil {
+		return false, err
+	}
+	if ready {
+		return true, nil
+	}
+	message, found, err := unstructured.NestedString(snapshot.Object, "status", "error", "message")
+	if err != nil {
+		return false, err
+	}
+	if found {
+		r.EventRecorder.Eventf(cr.Object, corev1.EventTypeWarning, eventVolumeSnapshotFailedType,
+			"VolumeSnapshot %s failed, retaining Persistent Volume Claim %s instead: %s", name, pvc.Name, message)
+		return true, r.retainPVC(ctx, cr, pvc, pvcRetainedOnDeletionMessage)
+	}
+	return false, nil
+}
+
+func (r *Reconciler) volumeSnapshotAvailable() (bool, error) {
+	_, err := r.RESTMapper.RESTMapping(volumeSnapshotGVK.GroupKind(), volumeSnapshotGVK.Version)
+	if err != nil {
+		// No matches for VolumeSnapshot GVK
+		if meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func newVolumeSnapshot(name string, pvc *corev1.PersistentVolumeClaim, crName string,
+	snapshotClassName *string) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": pvc.Name,
+		},
+	}
+	if snapshotClassName != nil {
+		spec["volumeSnapshotClassName"] = *snapshotClassName
+	}
+	snapshot := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": spec,
+		},
+	}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	snapshot.SetName(name)
+	snapshot.SetNamespace(pvc.Namespace)
+	snapshot.SetLabels(map[string]string{
+		"app": crName,
+	})
+	return snapshot
+}
@@ -0,0 +1,331 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	resources "github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// Annotation on the Cryostat CR listing the components, "database" and/or "storage",
+	// whose data should be migrated to a new PVC. It is removed once the migration starts.
+	pvcMigrationAnnotation = "operator.cryostat.io/migrate-pvcs"
+	// Event types to inform users of the progress of PVC migrations
+	eventPVCMigrationStartedType   = "PersistentVolumeClaimMigrationStarted"
+	eventPVCMigrationSucceededType = "PersistentVolumeClaimMigrationSucceeded"
+	eventPVCMigrationFailedType    = "PersistentVolumeClaimMigrationFailed"
+)
+
+// How often to check on the progress of a PVC migration
+const pvcMigrationRetryPeriod = 10 * time.Second
+
+// reconcilePVCMigration starts a migration of the component's data to a new PVC if its spec cannot be
+// updated in place, or if requested, and advances any migration in progress. It returns whether
+// the component must be scaled down.
+func (r *Reconciler) reconcilePVCMigration(ctx context.Context, cr *model.CryostatInstance,
+	status *operatorv1beta2.PersistentVolumeClaimStatus, pvc *corev1.PersistentVolumeClaim,
+	config *operatorv1beta2.PersistentVolumeClaimConfig, cfg *operatorv1beta2.StorageConfiguration) (bool, error) {
+	component := status.Component
+	if !isPVCMigrationInProgress(status) {
+		reason := getPVCMigrationReason(cr, status, pvc, config.Spec)
+		if len(reason) == 0 {
+			return false, nil
+		}
+		err := r.startPVCMigration(ctx, cr, component, reason)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	// Look up the status again, since updating the Cryostat CR replaces it
+	status = getPVCStatus(cr, component)
+	target := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      status.Migration.TargetClaimName,
+			Namespace: cr.InstallNamespace,
+		},
+	}
+	err := r.createOrUpdatePVC(ctx, target, cr, config)
+	if err != nil {
+		if kerrors.IsInvalid(err) {
+			r.EventRecorder.Event(cr.Object, corev1.EventTypeWarning, eventPersistentVolumeClaimInvalidType, err.Error())
+		}
+		return true, err
+	}
+
+	if status.Migration.Phase == operatorv1beta2.PVCMigrationPhaseScalingDown {
+		return r.startPVCMigrationJob(ctx, cr, component)
+	}
+	return r.checkPVCMigrationJob(ctx, cr, component, pvc, cfg)
+}
+
+func (r *Reconciler) startPVCMigration(ctx context.Context, cr *model.CryostatInstance, component string, reason string) error {
+	status := getPVCStatus(cr, component)
+	now := metav1.Now()
+	target := fmt.Sprintf("%s-%s-%s", cr.Name, component, now.UTC().Format("20060102150405"))
+	status.Migration = &operatorv1beta2.PVCMigrationStatus{
+		TargetClaimName: target,
+		Phase:           operatorv1beta2.PVCMigrationPhaseScalingDown,
+		Reason:          reason,
+		StartTime:       &now,
+	}
+	r.Log.Info("Starting Persistent Volume Claim migration", "component", component, "source", status.ClaimName,
+		"target", target, "reason", reason)
+	r.EventRecorder.Eventf(cr.Object, corev1.EventTypeNormal, eventPVCMigrationStartedType,
+		"Migrating %s data from Persistent Volume Claim %s to %s: %s", component, status.ClaimName, target, reason)
+	return r.updatePVCMigrationCondition(ctx, cr, reasonPVCMigrationInProgress,
+		fmt.Sprintf("Scaling down the %s to copy its data to Persistent Volume Claim %s.", component, target))
+}
+
+// startPVCMigrationJob creates the Job copying the component's data once the component has scaled down
+func (r *Reconciler) startPVCMigrationJob(ctx context.Context, cr *model.CryostatInstance, component string) (bool, error) {
+	deploy := &appsv1.Deployment{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: cr.Name + "-" + component, Namespace: cr.InstallNamespace}, deploy)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return true, err
+		}
+	} else if deploy.Status.Replicas > 0 {
+		// Wait for the component's pods to release the PVC
+		return true, nil
+	}
+
+	fsGroup, err := r.getFSGroup(ctx, cr.InstallNamespace)
+	if err != nil {
+		return true, err
+	}
+	status := getPVCStatus(cr, component)
+	job := resources.NewPVCMigrationJob(cr, component, status.ClaimName, status.Migration.TargetClaimName,
+		r.getImageTags(cr).DatabaseImageTag, *fsGroup, r.IsOpenShift)
+	err = controllerutil.SetControllerReference(cr.Object, job, r.Scheme)
+	if err != nil {
+		return true, err
+	}
+	err = r.Client.Create(ctx, job)
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		return true, err
+	}
+	r.Log.Info("Started Persistent Volume Claim migration", "component", component, "Job", job.Name)
+
+	status.Migration.Phase = operatorv1beta2.PVCMigrationPhaseCopying
+	status.Migration.JobName = job.Name
+	return true, r.updatePVCMigrationCondition(ctx, cr, reasonPVCMigrationInProgress,
+		fmt.Sprintf("Job %s is copying the %s data from Persistent Volume Claim %s to %s.", job.Name, component,
+			status.ClaimName, status.Migration.TargetClaimName))
+}
+
+// checkPVCMigrationJob switches the component to the new PVC once the Job has copied its data,
+// and disposes of the previous PVC according to its deletion policy
+func (r *Reconciler) checkPVCMigrationJob(ctx context.Context, cr *model.CryostatInstance, component string,
+	source *corev1.PersistentVolumeClaim, cfg *operatorv1beta2.StorageConfiguration) (bool, error) {
+	status := getPVCStatus(cr, component)
+	migration := status.Migration
+	job, finished, succeeded, message, err := r.getBackupJobResult(ctx, migration.JobName, cr.InstallNamespace)
+	if err != nil {
+		return true, err
+	}
+	if job == nil {
+		finished, message = true, fmt.Sprintf("Job %s was deleted before the data was copied", migration.JobName)
+	}
+	if !finished {
+		return true, nil
+	}
+
+	if !succeeded {
+		return false, r.failPVCMigration(ctx, cr, component, message)
+	}
+
+	status.ClaimName = migration.TargetClaimName
+	status.Migration = nil
+	r.Log.Info("Completed Persistent Volume Claim migration", "component", component, "source", source.Name,
+		"target", status.ClaimName)
+	r.EventRecorder.Eventf(cr.Object, corev1.EventTypeNormal, eventPVCMigrationSucceededType,
+		"Migrated %s data from Persistent Volume Claim %s to %s", component, source.Name, status.ClaimName)
+	err = r.updatePVCMigrationCondition(ctx, cr, reasonPVCMigrationComplete,
+		fmt.Sprintf("The %s uses Persistent Volume Claim %s.", component, status.ClaimName))
+	if err != nil {
+		return false, err
+	}
+
+	// The previous PVC is no longer mounted, so keep it only if the user asked for their data to be retained
+	if cfg != nil && cfg.DeletionPolicy == operatorv1beta2.PVCDeletionPolicyRetain {
+		return false, r.retainPVC(ctx, cr, source, "Persistent Volume Claim %s was retained after its data was migrated")
+	}
+	err = r.Client.Delete(ctx, source)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return false, err
+	}
+	return false, nil
+}
+
+// failPVCMigration deletes the incomplete copy of the component's data and the Job that made it.
+// The component continues to use its existing PVC. The migration is not retried until requested with the annotation.
+func (r *Reconciler) failPVCMigration(ctx context.Context, cr *model.CryostatInstance, component string, message string) error {
+	status := getPVCStatus(cr, component)
+	target := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      status.Migration.TargetClaimName,
+			Namespace: cr.InstallNamespace,
+		},
+	}
+	err := r.Client.Delete(ctx, target)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+	// The reason for the failure is kept in the condition, so the Job is not needed to retry
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      status.Migration.JobName,
+			Namespace: cr.InstallNamespace,
+		},
+	}
+	err = r.Client.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+
+	status.Migration.Phase = operatorv1beta2.PVCMigrationPhaseFailed
+	r.Log.Info("Persistent Volume Claim migration failed", "component", component, "Job", status.Migration.JobName)
+	r.EventRecorder.Eventf(cr.Object, corev1.EventTypeWarning, eventPVCMigrationFailedType,
+		"Failed to migrate %s data to Persistent Volume Claim %s: %s", component, target.Name, message)
+	return r.updatePVCMigrationCondition(ctx, cr, reasonPVCMigrationFailed,
+		fmt.Sprintf("Failed to copy the %s data to a new Persistent Volume Claim: %s. The %s continues to use "+
+			"Persistent Volume Claim %s. To retry, add %q to the %s annotation.", component, message, component,
+			status.ClaimName, component, pvcMigrationAnnotation))
+}
+
+// updatePVCMigrationCondition persists the status of all PVC migrations and updates the condition,
+// unless the update would hide a migration of the other component that is still in progress
+func (r *Reconciler) updatePVCMigrationCondition(ctx context.Context, cr *model.CryostatInstance,
+	reason string, message string) error {
+	inProgress := isAnyPVCMigrationInProgress(cr)
+	var err error
+	if inProgress && reason != reasonPVCMigrationInProgress {
+		err = r.Client.Status().Update(ctx, cr.Object)
+	} else {
+		condStatus := metav1.ConditionFalse
+		if inProgress {
+			condStatus = metav1.ConditionTrue
+		}
+		err = r.updateCondition(ctx, cr, operatorv1beta2.ConditionTypePVCMigrationProgressing, condStatus, reason, message)
+	}
+	if err != nil {
+		return err
+	}
+	return r.clearPVCMigrationRequests(ctx, cr)
+}
+
+// clearPVCMigrationRequests removes components whose migration has started from the annotation
+// requesting it, so that the migration is not repeated once it completes
+func (r *Reconciler) clearPVCMigrationRequests(ctx context.Context, cr *model.CryostatInstance) error {
+	requested := getPVCMigrationRequests(cr)
+	remaining := slices.DeleteFunc(slices.Clone(requested), func(component string) bool {
+		status := findPVCStatus(cr, component)
+		return status != nil && isPVCMigrationInProgress(status)
+	})
+	if len(remaining) == len(requested) {
+		return nil
+	}
+
+	annotations := cr.Object.GetAnnotations()
+	if len(remaining) == 0 {
+		delete(annotations, pvcMigrationAnnotation)
+	} else {
+		annotations[pvcMigrationAnnotation] = strings.Join(remaining, ",")
+	}
+	cr.Object.SetAnnotations(annotations)
+	return r.Client.Update(ctx, cr.Object)
+}
+
+// getPVCMigrationReason returns why the component's data should be migrated to a new PVC,
+// or an empty string if it should not be migrated
+func getPVCMigrationReason(cr *model.CryostatInstance, status *operatorv1beta2.PersistentVolumeClaimStatus,
+	pvc *corev1.PersistentVolumeClaim, desired *corev1.PersistentVolumeClaimSpec) string {
+	if slices.Contains(getPVCMigrationRequests(cr), status.Component) {
+		return fmt.Sprintf("requested by the %s annotation", pvcMigrationAnnotation)
+	}
+	if status.Migration != nil {
+		// Don't repeat a failed migration until the user requests it
+		return ""
+	}
+
+	changes := []string{}
+	if desired.StorageClassName != nil && (pvc.Spec.StorageClassName == nil ||
+		*pvc.Spec.StorageClassName != *desired.StorageClassName) {
+		current := ""
+		if pvc.Spec.StorageClassName != nil {
+			current = *pvc.Spec.StorageClassName
+		}
+		changes = append(changes, fmt.Sprintf("storage class changed from %q to %q", current, *desired.StorageClassName))
+	}
+	if !sameAccessModes(pvc.Spec.AccessModes, desired.AccessModes) {
+		changes = append(changes, fmt.Sprintf("access modes changed from %v to %v", pvc.Spec.AccessModes, desired.AccessModes))
+	}
+	return strings.Join(changes, ", ")
+}
+
+func getPVCMigrationRequests(cr *model.CryostatInstance) []string {
+	value, found := cr.Object.GetAnnotations()[pvcMigrationAnnotation]
+	if !found {
+		return nil
+	}
+	requests := []string{}
+	for _, component := range strings.Split(value, ",") {
+		component = strings.TrimSpace(component)
+		if len(component) > 0 {
+			requests = append(requests, component)
+		}
+	}
+	return requests
+}
+
+func sameAccessModes(a []corev1.PersistentVolumeAccessMode, b []corev1.PersistentVolumeAccessMode) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, mode := range a {
+		if !slices.Contains(b, mode) {
+			return false
+		}
+	}
+	return true
+}
+
+func isPVCMigrationInProgress(status *operatorv1beta2.PersistentVolumeClaimStatus) bool {
+	return status.Migration != nil && status.Migration.Phase != operatorv1beta2.PVCMigrationPhaseFailed
+}
+
+// isAnyPVCMigrationInProgress returns whether the Cryostat has a component scaled down for a PVC migration
+func isAnyPVCMigrationInProgress(cr *model.CryostatInstance) bool {
+	return slices.ContainsFunc(cr.Status.PersistentVolumeClaims, func(claim operatorv1beta2.PersistentVolumeClaimStatus) bool {
+		return isPVCMigrationInProgress(&claim)
+	})
+}
@@ -49,6 +49,10 @@ func (r *Reconciler) reconcileRBAC(ctx context.Context, cr *model.CryostatInstan
 	if err != nil {
 		return err
 	}
+	err = r.reconcileAPIClientRoleBinding(ctx, cr)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -57,6 +61,10 @@ func (r *Reconciler) finalizeRBAC(ctx context.Context, cr *model.CryostatInstanc
 	if err != nil {
 		return err
 	}
+	err = r.deleteRoleBinding(ctx, newAPIClientRoleBinding(cr))
+	if err != nil {
+		return err
+	}
 	return r.finalizeRoleBindings(ctx, cr)
 }
 
@@ -93,7 +101,7 @@ func (r *Reconciler) reconcileServiceAccount(ctx context.Context, cr *model.Cryo
 
 		annotations["serviceaccounts.openshift.io/oauth-redirectreference.route"] = string(ref)
 	}
-	return r.createOrUpdateServiceAccount(ctx, sa, cr.Object, labels, annotations)
+	return r.createOrUpdateServiceAccount(ctx, sa, cr.Object, labels, annotations, cr.Spec.ImagePullSecrets)
 }
 
 func newRole(cr *model.CryostatInstance) *rbacv1.Role {
@@ -198,8 +206,45 @@ func (r *Reconciler) reconcileClusterRoleBinding(ctx context.Context, cr *model.
 	return r.createOrUpdateClusterRoleBinding(ctx, binding, cr.Object, subjects, roleRef)
 }
 
+func newAPIClientRoleBinding(cr *model.CryostatInstance) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name + "-operator-api-client",
+			Namespace: cr.InstallNamespace,
+		},
+	}
+}
+
+const apiClientRoleName = "cryostat-operator-api-client"
+
+// reconcileAPIClientRoleBinding grants the operator's service account the access
+// checked by Cryostat's auth proxy, only in the namespace where Cryostat is installed.
+func (r *Reconciler) reconcileAPIClientRoleBinding(ctx context.Context, cr *model.CryostatInstance) error {
+	if len(r.OperatorNamespace) == 0 || len(r.OperatorServiceAccount) == 0 {
+		// Operator is not running in-cluster, nothing to bind
+		return nil
+	}
+	subjects := []rbacv1.Subject{
+		{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      r.OperatorServiceAccount,
+			Namespace: r.OperatorNamespace,
+		},
+	}
+	roleRef := &rbacv1.RoleRef{
+		APIGroup: "rbac.authorization.k8s.io",
+		Kind:     "ClusterRole",
+		Name:     apiClientRoleName,
+	}
+	labels := map[string]string{
+		"app": cr.Name,
+	}
+	return r.createOrUpdateRoleBinding(ctx, newAPIClientRoleBinding(cr), cr.Object, subjects, roleRef, labels)
+}
+
 func (r *Reconciler) createOrUpdateServiceAccount(ctx context.Context, sa *corev1.ServiceAccount,
-	owner metav1.Object, labels map[string]string, annotations map[string]string) error {
+	owner metav1.Object, labels map[string]string, annotations map[string]string,
+	imagePullSecrets []corev1.LocalObjectReference) error {
 	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, sa, func() error {
 		// TODO just replace the labels and annotations we manage, once we allow the user to configure
 		// ServiceAccount annotations/labels in the CR, we can simply overwrite them all
@@ -217,7 +262,13 @@ func (r *Reconciler) createOrUpdateServiceAccount(ctx context.Context, sa *corev
 			return err
 		}
 		// AutomountServiceAccountToken specified in Pod, which takes precedence
-		// Secrets, ImagePullSecrets are modified by Kubernetes/OpenShift
+		// Secrets, ImagePullSecrets are modified by Kubernetes/OpenShift, so only add the
+		// pull secrets requested in the CR without removing any others
+		for _, secret := range imagePullSecrets {
+			if !containsLocalObjectReference(sa.ImagePullSecrets, secret.Name) {
+				sa.ImagePullSecrets = append(sa.ImagePullSecrets, secret)
+			}
+		}
 		return nil
 	})
 	if err != nil {
@@ -376,3 +427,12 @@ func containsNamespace(namespaces []string, namespace string) bool {
 	}
 	return false
 }
+
+func containsLocalObjectReference(refs []corev1.LocalObjectReference, name string) bool {
+	for _, ref := range refs {
+		if ref.Name == name {
+			return true
+		}
+	}
+	return false
+}
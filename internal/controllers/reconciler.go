@@ -29,8 +29,10 @@ import (
 	resources "github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
 	"github.com/cryostatio/cryostat-operator/internal/controllers/constants"
 	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
+	"github.com/cryostatio/cryostat-operator/internal/cryostatclient"
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
+	configv1 "github.com/openshift/api/config/v1"
 	openshiftv1 "github.com/openshift/api/route/v1"
 	securityv1 "github.com/openshift/api/security/v1"
 	appsv1 "k8s.io/api/apps/v1"
@@ -50,20 +52,31 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
 )
 
 // ReconcilerConfig contains common configuration parameters for
 // CommonReconciler implementations
 type ReconcilerConfig struct {
 	client.Client
-	Log                    logr.Logger
-	Scheme                 *runtime.Scheme
-	IsOpenShift            bool
-	IsCertManagerInstalled bool
-	EventRecorder          record.EventRecorder
-	RESTMapper             meta.RESTMapper
-	InsightsProxy          *url.URL // Only defined if Insights is enabled
-	NewControllerBuilder   func(ctrl.Manager) common.ControllerBuilder
+	// Reads directly from the API server, for objects the operator does not otherwise watch, such as pods.
+	// Listing these with the cached client would start a cluster-wide informer.
+	APIReader                   client.Reader
+	Log                         logr.Logger
+	Scheme                      *runtime.Scheme
+	IsOpenShift                 bool
+	IsCertManagerInstalled      bool
+	IsGatewayAPIInstalled       bool
+	IsBackendTLSPolicyInstalled bool
+	EventRecorder               record.EventRecorder
+	RESTMapper                  meta.RESTMapper
+	InsightsProxy               *url.URL // Only defined if Insights is enabled
+	NewControllerBuilder        func(ctrl.Manager) common.ControllerBuilder
+	NewCryostatClient           cryostatclient.Factory
+	// Identity the operator uses to authenticate to Cryostat. Only defined when running in-cluster.
+	OperatorNamespace      string
+	OperatorServiceAccount string
 	common.ReconcilerTLS
 	common.OSUtils
 }
@@ -121,12 +134,38 @@ const apiServerName = "cluster"
 
 // Reasons for Cryostat Conditions
 const (
-	reasonWaitingForCert         = "WaitingForCertificate"
-	reasonAllCertsReady          = "AllCertificatesReady"
-	reasonCertManagerUnavailable = "CertManagerUnavailable"
-	reasonCertManagerDisabled    = "CertManagerDisabled"
+	reasonWaitingForCert            = "WaitingForCertificate"
+	reasonAllCertsReady             = "AllCertificatesReady"
+	reasonCertManagerUnavailable    = "CertManagerUnavailable"
+	reasonCertManagerDisabled       = "CertManagerDisabled"
+	reasonCertsExpiringSoon         = "CertificatesExpiringSoon"
+	reasonNoCertsExpiringSoon       = "NoCertificatesExpiringSoon"
+	reasonCADistributingTrustBundle = "DistributingTrustBundle"
+	reasonCAIssuingCertificates     = "IssuingFromNewCA"
+	reasonCARotationComplete        = "RotationComplete"
+	reasonPVCMigrationInProgress    = "MigrationInProgress"
+	reasonPVCMigrationComplete      = "MigrationComplete"
+	reasonPVCMigrationFailed        = "MigrationFailed"
+	reasonDatabaseUpgradeInProgress = "DatabaseUpgradeInProgress"
+	reasonDatabaseUpgradeFailed     = "DatabaseUpgradeFailed"
+	reasonPaused                    = "Paused"
+	reasonNotPaused                 = "NotPaused"
+	reasonReconcilePaused           = "ReconcilePaused"
+	reasonAllComponentsReady        = "AllComponentsReady"
+	reasonComponentsNotReady        = "ComponentsNotReady"
+	reasonUpgradeInProgress         = "UpgradeInProgress"
+	reasonUpgradeComplete           = "UpgradeComplete"
+	reasonUpgradeRolloutFailed      = "RolloutFailed"
+	reasonNoUpgradeFailures         = "NoUpgradeFailures"
+	reasonOverridesApplied          = "OverridesApplied"
+	reasonOverridesConflict         = "OverridesConflict"
+	reasonOverridesInvalid          = "OverridesInvalid"
+	reasonCoreEnvApplied            = "CoreEnvApplied"
+	reasonCoreEnvConflict           = "CoreEnvConflict"
 )
 
+const eventCoreEnvConflictType = "CoreEnvConflict"
+
 // Map Cryostat conditions to deployment conditions
 type deploymentConditionTypeMap map[operatorv1beta2.CryostatConditionType]appsv1.DeploymentConditionType
 
@@ -150,6 +189,11 @@ var reportsDeploymentConditions = deploymentConditionTypeMap{
 	operatorv1beta2.ConditionTypeReportsDeploymentProgressing:    appsv1.DeploymentProgressing,
 	operatorv1beta2.ConditionTypeReportsDeploymentReplicaFailure: appsv1.DeploymentReplicaFailure,
 }
+var grafanaDeploymentConditions = deploymentConditionTypeMap{
+	operatorv1beta2.ConditionTypeGrafanaDeploymentAvailable:      appsv1.DeploymentAvailable,
+	operatorv1beta2.ConditionTypeGrafanaDeploymentProgressing:    appsv1.DeploymentProgressing,
+	operatorv1beta2.ConditionTypeGrafanaDeploymentReplicaFailure: appsv1.DeploymentReplicaFailure,
+}
 
 func newReconciler(config *ReconcilerConfig, objType client.Object, isNamespaced bool) (*Reconciler, error) {
 	gvk, err := apiutil.GVKForObject(objType, config.Scheme)
@@ -199,6 +243,16 @@ func (r *Reconciler) reconcileCryostat(ctx context.Context, cr *model.CryostatIn
 				}
 			}
 
+			// Retain or snapshot PVCs according to their deletion policies
+			done, err := r.finalizePVCs(ctx, cr)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			if !done {
+				// Wait for VolumeSnapshots to be ready before the PVCs are garbage collected
+				return reconcile.Result{RequeueAfter: volumeSnapshotRetryPeriod}, nil
+			}
+
 			err = common.RemoveFinalizer(ctx, r.Client, cr.Object, cryostatFinalizer)
 			if err != nil {
 				return reconcile.Result{}, err
@@ -208,6 +262,13 @@ func (r *Reconciler) reconcileCryostat(ctx context.Context, cr *model.CryostatIn
 		return reconcile.Result{}, nil
 	}
 
+	// Leave all resources as they are, so that they can be modified manually
+	if cr.Spec.ReconcilePaused {
+		reqLogger.Info("Reconciliation is paused")
+		return reconcile.Result{}, r.updateCondition(ctx, cr, operatorv1beta2.ConditionTypeReconcilePaused,
+			metav1.ConditionTrue, reasonReconcilePaused, reconcilePausedMessage)
+	}
+
 	// Add our finalizer, so we can clean up Cryostat resources upon deletion
 	if !controllerutil.ContainsFinalizer(cr.Object, cryostatFinalizer) {
 		err := common.AddFinalizer(ctx, r.Client, cr.Object, cryostatFinalizer)
@@ -261,6 +322,11 @@ func (r *Reconciler) reconcileCryostat(ctx context.Context, cr *model.CryostatIn
 			return reconcile.Result{}, err
 		}
 	} else {
+		// No certificates to report on without cert-manager
+		cr.Status.Certificates = nil
+		cr.Status.CARotation = nil
+		removeConditionIfPresent(cr, operatorv1beta2.ConditionTypeCertificatesExpiringSoon,
+			operatorv1beta2.ConditionTypeCARotationProgressing)
 		err = r.updateCondition(ctx, cr, operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionTrue,
 			reasonCertManagerDisabled, "TLS setup has been disabled.")
 		if err != nil {
@@ -268,10 +334,6 @@ func (r *Reconciler) reconcileCryostat(ctx context.Context, cr *model.CryostatIn
 		}
 	}
 
-	err = r.reconcileOAuth2ProxyConfig(ctx, cr, tlsConfig)
-	if err != nil {
-		return reconcile.Result{}, err
-	}
 	err = r.reconcileAgentProxyConfig(ctx, cr, tlsConfig)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -288,7 +350,7 @@ func (r *Reconciler) reconcileCryostat(ctx context.Context, cr *model.CryostatIn
 	if err != nil {
 		return reconcile.Result{}, err
 	}
-	err = r.reconcileAgentGatewayService(ctx, cr)
+	err = r.reconcileAgentGatewayService(ctx, cr, tlsConfig)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
@@ -297,31 +359,60 @@ func (r *Reconciler) reconcileCryostat(ctx context.Context, cr *model.CryostatIn
 		return reconcile.Result{}, err
 	}
 
-	imageTags := r.getImageTags()
+	imageTags := r.getImageTags(cr)
 	fsGroup, err := r.getFSGroup(ctx, cr.InstallNamespace)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	reportsResult, err := r.reconcileReports(ctx, reqLogger, cr, tlsConfig, imageTags, serviceSpecs)
+	// Components that use the database are scaled down while a CryostatRestore replaces its contents
+	restoring, err := r.isRestoringBackup(ctx, cr)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	overrides := &overrideResults{}
+	reportsResult, err := r.reconcileReports(ctx, reqLogger, cr, tlsConfig, imageTags, serviceSpecs, overrides, restoring)
 	if err != nil {
 		return reportsResult, err
 	}
 
-	databaseResult, err := r.reconcileDatabase(ctx, reqLogger, cr, tlsConfig, imageTags, serviceSpecs, *fsGroup)
+	databaseResult, err := r.reconcileDatabase(ctx, reqLogger, cr, tlsConfig, imageTags, serviceSpecs, *fsGroup, overrides)
 	if err != nil {
 		return databaseResult, err
 	}
 
-	storageResult, err := r.reconcileStorage(ctx, reqLogger, cr, tlsConfig, imageTags, serviceSpecs, *fsGroup)
+	storageResult, err := r.reconcileStorage(ctx, reqLogger, cr, tlsConfig, imageTags, serviceSpecs, *fsGroup, overrides)
 	if err != nil {
 		return storageResult, err
 	}
 
-	deployment, err := resources.NewDeploymentForCR(cr, serviceSpecs, imageTags, tlsConfig, *fsGroup, r.IsOpenShift)
+	grafanaConfig, err := r.reconcileGrafanaProvisioning(ctx, cr)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	grafanaResult, err := r.reconcileGrafana(ctx, reqLogger, cr, tlsConfig, imageTags, grafanaConfig, serviceSpecs, overrides)
+	if err != nil {
+		return grafanaResult, err
+	}
+
+	// The auth proxy's upstreams depend on where Grafana is deployed
+	err = r.reconcileOAuth2ProxyConfig(ctx, cr, tlsConfig, serviceSpecs)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	deployment, err := resources.NewDeploymentForCR(cr, serviceSpecs, imageTags, tlsConfig, grafanaConfig, *fsGroup, r.IsOpenShift)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	err = r.setCoreEnvCondition(ctx, reqLogger, cr, &deployment.Spec.Template.Spec)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	applyPodTemplateOverride(deployment, overrideComponentCore, getPodTemplateOverride(cr, overrideComponentCore), overrides)
+	if cr.Spec.Paused || restoring {
+		scaleDownDeployment(deployment)
+	}
 	err = r.createOrUpdateDeployment(ctx, deployment, cr.Object)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -349,8 +440,37 @@ func (r *Reconciler) reconcileCryostat(ctx context.Context, cr *model.CryostatIn
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	err = r.updatePausedCondition(ctx, cr)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	err = r.updateOverridesCondition(ctx, cr, overrides)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	err = r.updateReadyStatus(ctx, cr, serviceSpecs)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
 
 	reqLogger.Info("Successfully reconciled Cryostat")
+
+	// Check back when the current stage of any CA rotation ends, when a certificate is next close to
+	// expiring, or on the progress of PVC migrations and database upgrades
+	requeueAfter := getCARotationRequeueAfter(cr)
+	if expiryRequeue := getCertificateExpiryRequeueAfter(cr); expiryRequeue > 0 &&
+		(requeueAfter == 0 || requeueAfter > expiryRequeue) {
+		requeueAfter = expiryRequeue
+	}
+	if isAnyPVCMigrationInProgress(cr) && (requeueAfter == 0 || requeueAfter > pvcMigrationRetryPeriod) {
+		requeueAfter = pvcMigrationRetryPeriod
+	}
+	if isDatabaseUpgradeInProgress(cr) && (requeueAfter == 0 || requeueAfter > databaseUpgradeRetryPeriod) {
+		requeueAfter = databaseUpgradeRetryPeriod
+	}
+	if requeueAfter > 0 {
+		return reconcile.Result{RequeueAfter: requeueAfter}, nil
+	}
 	return reconcile.Result{}, nil
 }
 
@@ -367,6 +487,12 @@ func (r *Reconciler) setupWithManager(c common.ControllerBuilder, impl reconcile
 	if r.IsCertManagerInstalled {
 		resources = append(resources, &certv1.Issuer{}, &certv1.Certificate{})
 	}
+	if r.IsGatewayAPIInstalled {
+		resources = append(resources, &gatewayv1.HTTPRoute{})
+	}
+	if r.IsBackendTLSPolicyInstalled {
+		resources = append(resources, &gatewayv1alpha3.BackendTLSPolicy{})
+	}
 
 	for _, resource := range resources {
 		c = c.Owns(resource)
@@ -378,11 +504,27 @@ func (r *Reconciler) setupWithManager(c common.ControllerBuilder, impl reconcile
 		return err
 	}
 
+	// Apply changes to the TLS profile of the cluster's API server
+	if r.IsOpenShift {
+		c = c.Watches(&configv1.APIServer{}, c.EnqueueRequestsFromMapFunc(r.mapFromAPIServer))
+	}
+
+	// Load Grafana dashboards from Config Maps as they are created and updated
+	c = c.Watches(&corev1.ConfigMap{}, c.EnqueueRequestsFromMapFunc(r.mapFromDashboardConfigMap))
+
+	// Report conflicts again when the Secrets and Config Maps loaded by the core options' envFrom change
+	c = c.Watches(&corev1.Secret{}, c.EnqueueRequestsFromMapFunc(r.mapFromCoreEnvSource))
+	c = c.Watches(&corev1.ConfigMap{}, c.EnqueueRequestsFromMapFunc(r.mapFromCoreEnvSource))
+
+	// Scale components down and back up as CryostatRestores start and finish
+	c = c.Watches(&operatorv1beta2.CryostatRestore{}, c.EnqueueRequestsFromMapFunc(r.mapFromRestore))
+
 	return c.Complete(impl)
 }
 
 func (r *Reconciler) reconcileReports(ctx context.Context, reqLogger logr.Logger, cr *model.CryostatInstance,
-	tls *resources.TLSConfig, imageTags *resources.ImageTags, serviceSpecs *resources.ServiceSpecs) (reconcile.Result, error) {
+	tls *resources.TLSConfig, imageTags *resources.ImageTags, serviceSpecs *resources.ServiceSpecs,
+	overrides *overrideResults, restoring bool) (reconcile.Result, error) {
 	reqLogger.Info("Spec", "Reports", cr.Spec.ReportOptions)
 
 	desired := int32(0)
@@ -415,6 +557,10 @@ func (r *Reconciler) reconcileReports(ctx context.Context, reqLogger logr.Logger
 	}
 
 	if desired > 0 {
+		applyPodTemplateOverride(deployment, overrideComponentReports, getPodTemplateOverride(cr, overrideComponentReports), overrides)
+		if cr.Spec.Paused || restoring {
+			scaleDownDeployment(deployment)
+		}
 		err = r.createOrUpdateDeployment(ctx, deployment, cr.Object)
 		if err != nil {
 			return reconcile.Result{}, err
@@ -430,10 +576,65 @@ func (r *Reconciler) reconcileReports(ctx context.Context, reqLogger logr.Logger
 	return reconcile.Result{}, nil
 }
 
-func (r *Reconciler) reconcileDatabase(ctx context.Context, reqLogger logr.Logger, cr *model.CryostatInstance, tls *resources.TLSConfig, imageTags *resources.ImageTags, serviceSpecs *resources.ServiceSpecs, fsGroup int64) (reconcile.Result, error) {
+func (r *Reconciler) reconcileGrafana(ctx context.Context, reqLogger logr.Logger, cr *model.CryostatInstance,
+	tls *resources.TLSConfig, imageTags *resources.ImageTags, grafana *resources.GrafanaConfig,
+	serviceSpecs *resources.ServiceSpecs, overrides *overrideResults) (reconcile.Result, error) {
+	reqLogger.Info("Spec", "Grafana", cr.Spec.GrafanaOptions)
+
+	err := r.reconcileGrafanaService(ctx, cr, tls, serviceSpecs)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	err = r.reconcileGrafanaNetworkPolicy(ctx, cr)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	deployment := resources.NewDeploymentForGrafana(cr, imageTags, tls, grafana, r.IsOpenShift)
+	if !resources.IsGrafanaSeparate(cr) {
+		// Grafana runs in the Cryostat pod, or not at all
+		if err := r.Client.Delete(ctx, deployment); err != nil && !kerrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		if getPodTemplateOverride(cr, overrideComponentGrafana) != nil {
+			overrides.invalid = append(overrides.invalid, fmt.Sprintf("%s: Grafana does not run in its own deployment, "+
+				"use the %s override instead", overrideComponentGrafana, overrideComponentCore))
+		}
+
+		// Only update the status to remove conditions reported for a previous Grafana deployment
+		if removeConditionIfPresent(cr, operatorv1beta2.ConditionTypeGrafanaDeploymentAvailable,
+			operatorv1beta2.ConditionTypeGrafanaDeploymentProgressing,
+			operatorv1beta2.ConditionTypeGrafanaDeploymentReplicaFailure) {
+			err := r.Client.Status().Update(ctx, cr.Object)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		return reconcile.Result{}, nil
+	}
+
+	applyPodTemplateOverride(deployment, overrideComponentGrafana, getPodTemplateOverride(cr, overrideComponentGrafana), overrides)
+	if cr.Spec.Paused {
+		scaleDownDeployment(deployment)
+	}
+	err = r.createOrUpdateDeployment(ctx, deployment, cr.Object)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Check deployment status and update conditions
+	err = r.updateConditionsFromDeployment(ctx, cr, types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace},
+		grafanaDeploymentConditions)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+func (r *Reconciler) reconcileDatabase(ctx context.Context, reqLogger logr.Logger, cr *model.CryostatInstance, tls *resources.TLSConfig,
+	imageTags *resources.ImageTags, serviceSpecs *resources.ServiceSpecs, fsGroup int64, overrides *overrideResults) (reconcile.Result, error) {
 	reqLogger.Info("Spec", "Database", cr.Spec.DatabaseOptions)
 
-	err := r.reconcileDatabasePVC(ctx, cr)
+	migrating, err := r.reconcileDatabasePVC(ctx, cr)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
@@ -445,7 +646,22 @@ func (r *Reconciler) reconcileDatabase(ctx context.Context, reqLogger logr.Logge
 	if err != nil {
 		return reconcile.Result{}, err
 	}
-	deployment := resources.NewDeploymentForDatabase(cr, imageTags, tls, r.IsOpenShift, fsGroup)
+
+	// Deploy the image that is compatible with the data, and scale down while upgrading it
+	dbImageTags := *imageTags
+	upgrading := false
+	if !migrating {
+		dbImageTags.DatabaseImageTag, upgrading, err = r.reconcileDatabaseUpgrade(ctx, cr, imageTags.DatabaseImageTag, fsGroup)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	deployment := resources.NewDeploymentForDatabase(cr, &dbImageTags, tls, r.IsOpenShift, fsGroup)
+	applyPodTemplateOverride(deployment, overrideComponentDatabase, getPodTemplateOverride(cr, overrideComponentDatabase), overrides)
+	if migrating || upgrading || cr.Spec.Paused {
+		// Also releases the PVC, so that a Job can copy or upgrade its data
+		scaleDownDeployment(deployment)
+	}
 
 	err = r.createOrUpdateDeployment(ctx, deployment, cr.Object)
 	if err != nil {
@@ -458,14 +674,18 @@ func (r *Reconciler) reconcileDatabase(ctx context.Context, reqLogger logr.Logge
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	err = r.updateDatabaseUpgradeCondition(ctx, cr)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
 	return reconcile.Result{}, nil
 }
 
 func (r *Reconciler) reconcileStorage(ctx context.Context, reqLogger logr.Logger, cr *model.CryostatInstance, tls *resources.TLSConfig,
-	imageTags *resources.ImageTags, serviceSpecs *resources.ServiceSpecs, fsGroup int64) (reconcile.Result, error) {
+	imageTags *resources.ImageTags, serviceSpecs *resources.ServiceSpecs, fsGroup int64, overrides *overrideResults) (reconcile.Result, error) {
 	reqLogger.Info("Spec", "Storage", cr.Spec.StorageOptions)
 
-	err := r.reconcileStoragePVC(ctx, cr)
+	migrating, err := r.reconcileStoragePVC(ctx, cr)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
@@ -478,6 +698,11 @@ func (r *Reconciler) reconcileStorage(ctx context.Context, reqLogger logr.Logger
 		return reconcile.Result{}, err
 	}
 	deployment := resources.NewDeploymentForStorage(cr, imageTags, tls, r.IsOpenShift, fsGroup)
+	applyPodTemplateOverride(deployment, overrideComponentStorage, getPodTemplateOverride(cr, overrideComponentStorage), overrides)
+	if migrating || cr.Spec.Paused {
+		// Also releases the PVC, so that its data can be copied to the new PVC
+		scaleDownDeployment(deployment)
+	}
 
 	err = r.createOrUpdateDeployment(ctx, deployment, cr.Object)
 	if err != nil {
@@ -493,8 +718,8 @@ func (r *Reconciler) reconcileStorage(ctx context.Context, reqLogger logr.Logger
 	return reconcile.Result{}, nil
 }
 
-func (r *Reconciler) getImageTags() *resources.ImageTags {
-	return &resources.ImageTags{
+func (r *ReconcilerConfig) getImageTags(cr *model.CryostatInstance) *resources.ImageTags {
+	imageTags := &resources.ImageTags{
 		OAuth2ProxyImageTag:         r.GetEnvOrDefault(oauth2ProxyImageTagEnv, constants.DefaultOAuth2ProxyImageTag),
 		OpenShiftOAuthProxyImageTag: r.GetEnvOrDefault(openshiftOauthProxyImageTagEnv, constants.DefaultOpenShiftOAuthProxyImageTag),
 		CoreImageTag:                r.GetEnvOrDefault(coreImageTagEnv, constants.DefaultCoreImageTag),
@@ -505,12 +730,33 @@ func (r *Reconciler) getImageTags() *resources.ImageTags {
 		DatabaseImageTag:            r.GetEnvOrDefault(databaseImageTagEnv, constants.DefaultDatabaseImageTag),
 		AgentProxyImageTag:          r.GetEnvOrDefault(agentProxyImageTagEnv, constants.DefaultAgentProxyImageTag),
 	}
+
+	// Images overridden in the CR take precedence over those provided by the operator
+	images := cr.Spec.Images
+	if images != nil {
+		overrideImageTag(&imageTags.CoreImageTag, images.Core)
+		overrideImageTag(&imageTags.DatasourceImageTag, images.Datasource)
+		overrideImageTag(&imageTags.GrafanaImageTag, images.Grafana)
+		overrideImageTag(&imageTags.ReportsImageTag, images.Reports)
+		overrideImageTag(&imageTags.StorageImageTag, images.Storage)
+		overrideImageTag(&imageTags.DatabaseImageTag, images.Database)
+		overrideImageTag(&imageTags.OAuth2ProxyImageTag, images.AuthProxy)
+		overrideImageTag(&imageTags.OpenShiftOAuthProxyImageTag, images.AuthProxy)
+		overrideImageTag(&imageTags.AgentProxyImageTag, images.AgentProxy)
+	}
+	return imageTags
+}
+
+func overrideImageTag(imageTag *string, override string) {
+	if len(override) > 0 {
+		*imageTag = override
+	}
 }
 
 // fsGroup to use when not constrained
 const defaultFSGroup int64 = 18500
 
-func (r *Reconciler) getFSGroup(ctx context.Context, namespace string) (*int64, error) {
+func (r *ReconcilerConfig) getFSGroup(ctx context.Context, namespace string) (*int64, error) {
 	if r.IsOpenShift {
 		// Check namespace for supplemental groups annotation
 		ns := &corev1.Namespace{}
@@ -545,12 +791,7 @@ func parseSupGroups(supGroups string) (*int64, error) {
 func (r *Reconciler) updateCondition(ctx context.Context, cr *model.CryostatInstance,
 	condType operatorv1beta2.CryostatConditionType, status metav1.ConditionStatus, reason string, message string) error {
 	reqLogger := r.Log.WithValues("Request.Namespace", cr.InstallNamespace, "Request.Name", cr.Name)
-	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
-		Type:    string(condType),
-		Status:  status,
-		Reason:  reason,
-		Message: message,
-	})
+	setCondition(cr, condType, status, reason, message)
 	err := r.Client.Status().Update(ctx, cr.Object)
 	if err != nil {
 		reqLogger.Error(err, "failed to update condition", "type", condType)
@@ -558,6 +799,18 @@ func (r *Reconciler) updateCondition(ctx context.Context, cr *model.CryostatInst
 	return err
 }
 
+// setCondition sets a condition without updating the status of the Cryostat
+func setCondition(cr *model.CryostatInstance, condType operatorv1beta2.CryostatConditionType, status metav1.ConditionStatus,
+	reason string, message string) {
+	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+		Type:               string(condType),
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: cr.Object.GetGeneration(),
+	})
+}
+
 func (r *Reconciler) updateConditionsFromDeployment(ctx context.Context, cr *model.CryostatInstance,
 	deployKey types.NamespacedName, mapping deploymentConditionTypeMap) error {
 	reqLogger := r.Log.WithValues("Request.Namespace", cr.InstallNamespace, "Request.Name", cr.Name)
@@ -576,10 +829,11 @@ func (r *Reconciler) updateConditionsFromDeployment(ctx context.Context, cr *mod
 			removeConditionIfPresent(cr, condType)
 		} else {
 			meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
-				Type:    string(condType),
-				Status:  metav1.ConditionStatus(condition.Status),
-				Reason:  condition.Reason,
-				Message: condition.Message,
+				Type:               string(condType),
+				Status:             metav1.ConditionStatus(condition.Status),
+				Reason:             condition.Reason,
+				Message:            condition.Message,
+				ObservedGeneration: cr.Object.GetGeneration(),
 			})
 		}
 	}
@@ -636,6 +890,16 @@ func (r *Reconciler) createOrUpdateDeployment(ctx context.Context, deploy *appsv
 	return nil
 }
 
+// mergeResourceMetadata adds the user-defined labels and annotations for Persistent Volume Claims,
+// Secrets and Config Maps. Any labels managed by the operator must be merged afterwards to take precedence.
+func mergeResourceMetadata(cr *model.CryostatInstance, meta *metav1.ObjectMeta) {
+	if cr.Spec.OperandMetadata == nil || cr.Spec.OperandMetadata.ResourceMetadata == nil {
+		return
+	}
+	resourceMeta := cr.Spec.OperandMetadata.ResourceMetadata
+	common.MergeLabelsAndAnnotations(meta, resourceMeta.Labels, resourceMeta.Annotations)
+}
+
 func (r *Reconciler) recreateDeployment(ctx context.Context, deploy *appsv1.Deployment, owner metav1.Object) error {
 	// Delete and recreate deployment
 	err := r.deleteDeployment(ctx, deploy)
@@ -720,13 +984,15 @@ func requeueIfIngressNotReady(log logr.Logger, err error) (reconcile.Result, err
 	return reconcile.Result{}, err
 }
 
-func removeConditionIfPresent(cr *model.CryostatInstance, condType ...operatorv1beta2.CryostatConditionType) {
+// removeConditionIfPresent removes the conditions from the CR's status, and returns whether any were present
+func removeConditionIfPresent(cr *model.CryostatInstance, condType ...operatorv1beta2.CryostatConditionType) bool {
+	removed := false
 	for _, ct := range condType {
-		found := meta.FindStatusCondition(cr.Status.Conditions, string(ct))
-		if found != nil {
-			meta.RemoveStatusCondition(&cr.Status.Conditions, string(ct))
+		if meta.RemoveStatusCondition(&cr.Status.Conditions, string(ct)) {
+			removed = true
 		}
 	}
+	return removed
 }
 
 func findDeployCondition(conditions []appsv1.DeploymentCondition, condType appsv1.DeploymentConditionType) *appsv1.DeploymentCondition {
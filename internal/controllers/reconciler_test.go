@@ -16,8 +16,13 @@ package controllers_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"time"
 
 	certv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
@@ -28,6 +33,7 @@ import (
 	consolev1 "github.com/openshift/api/console/v1"
 	openshiftv1 "github.com/openshift/api/route/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -35,6 +41,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -49,6 +56,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
 
 	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
 	"github.com/cryostatio/cryostat-operator/internal/controllers"
@@ -97,7 +106,7 @@ func (c *controllerTest) commonJustBeforeEach(t *cryostatTestInput) {
 	err := test.SetCreationTimestamp(t.objs...)
 	Expect(err).ToNot(HaveOccurred())
 	t.Client = fake.NewClientBuilder().WithScheme(s).WithObjects(t.objs...).
-		WithStatusSubresource(&operatorv1beta2.Cryostat{}, &certv1.Certificate{}, &openshiftv1.Route{}).Build()
+		WithStatusSubresource(&operatorv1beta2.Cryostat{}, &certv1.Certificate{}, &openshiftv1.Route{}, &batchv1.Job{}).Build()
 	t.controller, err = c.constructorFunc(t.newReconcilerConfig(s, t.Client))
 	Expect(err).ToNot(HaveOccurred())
 }
@@ -121,17 +130,22 @@ func (t *cryostatTestInput) newReconcilerConfig(scheme *runtime.Scheme, client c
 		insightsURL = url
 	}
 	return &controllers.ReconcilerConfig{
-		Client:                 test.NewClientWithTimestamp(test.NewTestClient(client, t.TestResources)),
-		Scheme:                 scheme,
-		IsOpenShift:            t.OpenShift,
-		EventRecorder:          record.NewFakeRecorder(1024),
-		RESTMapper:             test.NewTESTRESTMapper(),
-		Log:                    logger,
-		ReconcilerTLS:          test.NewTestReconcilerTLS(&t.TestReconcilerConfig),
-		InsightsProxy:          insightsURL,
-		IsCertManagerInstalled: !t.CertManagerMissing,
-		NewControllerBuilder:   test.NewControllerBuilder(&t.TestReconcilerConfig),
-		OSUtils:                test.NewTestOSUtils(&t.TestReconcilerConfig),
+		Client:                      test.NewClientWithTimestamp(test.NewTestClient(client, t.TestResources)),
+		APIReader:                   client,
+		Scheme:                      scheme,
+		IsOpenShift:                 t.OpenShift,
+		EventRecorder:               record.NewFakeRecorder(1024),
+		RESTMapper:                  test.NewTESTRESTMapper(),
+		Log:                         logger,
+		ReconcilerTLS:               test.NewTestReconcilerTLS(&t.TestReconcilerConfig),
+		InsightsProxy:               insightsURL,
+		IsCertManagerInstalled:      !t.CertManagerMissing,
+		IsGatewayAPIInstalled:       t.GatewayAPIInstalled,
+		IsBackendTLSPolicyInstalled: t.BackendTLSPolicyInstalled,
+		NewControllerBuilder:        test.NewControllerBuilder(&t.TestReconcilerConfig),
+		OSUtils:                     test.NewTestOSUtils(&t.TestReconcilerConfig),
+		OperatorNamespace:           test.OperatorNamespace,
+		OperatorServiceAccount:      test.OperatorServiceAccount,
 	}
 }
 
@@ -190,10 +204,30 @@ func expectSuccessful(t **cryostatTestInput) {
 	It("should set Storage Secret in CR Status", func() {
 		(*t).expectStatusStorageSecret()
 	})
+	It("should set Components in CR Status", func() {
+		(*t).expectStatusComponents()
+	})
+	It("should set ObservedGeneration in CR Status", func() {
+		cr := (*t).getCryostatInstance()
+		Expect(cr.Status.ObservedGeneration).To(Equal(cr.Object.GetGeneration()))
+		for _, condition := range cr.Status.Conditions {
+			Expect(condition.ObservedGeneration).To(Equal(cr.Object.GetGeneration()), condition.Type)
+		}
+	})
+	It("should set Ready condition", func() {
+		(*t).checkConditionPresent(operatorv1beta2.ConditionTypeReady, metav1.ConditionFalse, "ComponentsNotReady")
+	})
 	It("should set TLSSetupComplete condition", func() {
 		(*t).checkConditionPresent(operatorv1beta2.ConditionTypeTLSSetupComplete, metav1.ConditionTrue,
 			"AllCertificatesReady")
 	})
+	It("should set Certificates in CR Status", func() {
+		(*t).expectStatusCertificates()
+	})
+	It("should set CertificatesExpiringSoon condition", func() {
+		(*t).checkConditionPresent(operatorv1beta2.ConditionTypeCertificatesExpiringSoon, metav1.ConditionFalse,
+			"NoCertificatesExpiringSoon")
+	})
 	Context("deployment is progressing", func() {
 		JustBeforeEach(func() {
 			(*t).makeDeploymentProgress((*t).Name)
@@ -216,6 +250,21 @@ func expectSuccessful(t **cryostatTestInput) {
 					"TestProgressing")
 				(*t).checkConditionAbsent(operatorv1beta2.ConditionTypeMainDeploymentReplicaFailure)
 			})
+			It("should not be ready until all components are available", func() {
+				(*t).checkConditionPresent(operatorv1beta2.ConditionTypeReady, metav1.ConditionFalse, "ComponentsNotReady")
+				condition := meta.FindStatusCondition((*t).getCryostatInstance().Status.Conditions,
+					string(operatorv1beta2.ConditionTypeReady))
+				Expect(condition.Message).To(Equal("Components not yet available: database, storage."))
+			})
+			Context("along with the other components", func() {
+				JustBeforeEach(func() {
+					(*t).makeDeploymentAvailable((*t).Name + "-database")
+					(*t).makeDeploymentAvailable((*t).Name + "-storage")
+				})
+				It("should be ready", func() {
+					(*t).checkConditionPresent(operatorv1beta2.ConditionTypeReady, metav1.ConditionTrue, "AllComponentsReady")
+				})
+			})
 		})
 		Context("then fails to roll out", func() {
 			JustBeforeEach(func() {
@@ -972,12 +1021,16 @@ func (c *controllerTest) commonTests() {
 					metav1.SetMetaDataLabel(&oldPVC.ObjectMeta, "another", "label")
 					metav1.SetMetaDataAnnotation(&oldPVC.ObjectMeta, "my/custom", "other-annotation")
 					metav1.SetMetaDataAnnotation(&oldPVC.ObjectMeta, "another/custom", "annotation")
+					// Match the fields that cannot be updated in place
+					storageClass := "cool-db-storage"
+					oldPVC.Spec.StorageClassName = &storageClass
+					oldPVC.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}
 				})
 				JustBeforeEach(func() {
 					t.reconcileCryostatFully()
 				})
 				It("should update metadata and resource requests", func() {
-					expected := t.NewDefaultPVC()
+					expected := oldPVC.DeepCopy()
 					metav1.SetMetaDataLabel(&expected.ObjectMeta, "my", "database")
 					metav1.SetMetaDataLabel(&expected.ObjectMeta, "another", "label")
 					metav1.SetMetaDataLabel(&expected.ObjectMeta, "app", t.Name)
@@ -1013,6 +1066,597 @@ func (c *controllerTest) commonTests() {
 				})
 			})
 		})
+		Context("with an existing DB PVC that cannot be updated in place", func() {
+			var cr *model.CryostatInstance
+			BeforeEach(func() {
+				cr = t.NewCryostatWithPVCSpec()
+				t.objs = append(t.objs, cr.Object, t.NewDefaultPVC())
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatUntilRequeueAfter()
+			})
+			It("should report the migration in progress", func() {
+				t.checkConditionPresent(operatorv1beta2.ConditionTypePVCMigrationProgressing, metav1.ConditionTrue,
+					"MigrationInProgress")
+				status := t.getPVCStatus("database")
+				Expect(status.ClaimName).To(Equal(t.NewDefaultPVC().Name))
+				Expect(status.Migration).ToNot(BeNil())
+				Expect(status.Migration.Phase).To(Equal(operatorv1beta2.PVCMigrationPhaseCopying))
+				Expect(status.Migration.Reason).To(ContainSubstring(`storage class changed from "" to "cool-db-storage"`))
+				Expect(status.Migration.Reason).To(ContainSubstring("access modes changed from [ReadWriteOnce] to [ReadWriteMany]"))
+			})
+			It("should not migrate the storage PVC", func() {
+				status := t.getPVCStatus("storage")
+				Expect(status.ClaimName).To(Equal(t.NewStoragePVC().Name))
+				Expect(status.Migration).To(BeNil())
+			})
+			It("should scale down the database", func() {
+				Expect(*t.getDatabaseDeployment().Spec.Replicas).To(Equal(int32(0)))
+			})
+			It("should create a PVC with the desired spec", func() {
+				pvc := t.getPVC(t.getPVCStatus("database").Migration.TargetClaimName)
+				Expect(pvc.Spec.StorageClassName).To(Equal(cr.Spec.StorageOptions.Database.PVC.Spec.StorageClassName))
+				Expect(pvc.Spec.AccessModes).To(ConsistOf(corev1.ReadWriteMany))
+				Expect(pvc.Labels).To(HaveKeyWithValue("my", "database"))
+			})
+			It("should create a Job copying the data to the new PVC", func() {
+				status := t.getPVCStatus("database")
+				job := t.getPVCMigrationJob()
+				Expect(job.Name).To(Equal(status.Migration.TargetClaimName + "-migration"))
+				Expect(metav1.IsControlledBy(job, t.getCryostatInstance().Object)).To(BeTrue())
+				volumes := job.Spec.Template.Spec.Volumes
+				Expect(volumes).To(HaveLen(2))
+				Expect(volumes[0].PersistentVolumeClaim.ClaimName).To(Equal(status.ClaimName))
+				Expect(volumes[0].PersistentVolumeClaim.ReadOnly).To(BeTrue())
+				Expect(volumes[1].PersistentVolumeClaim.ClaimName).To(Equal(status.Migration.TargetClaimName))
+				Expect(job.Spec.Template.Spec.Containers[0].Image).To(HavePrefix("quay.io/cryostat/cryostat-db:"))
+			})
+			It("should emit a PersistentVolumeClaimMigrationStarted event", func() {
+				t.expectEvent("PersistentVolumeClaimMigrationStarted")
+			})
+			Context("when the Job succeeds", func() {
+				JustBeforeEach(func() {
+					finishBackupJob(t.Client, t.TestResources, t.getPVCMigrationJob(), batchv1.JobComplete, 0, "")
+					t.reconcileCryostatFully()
+				})
+				It("should switch the database to the new PVC", func() {
+					status := t.getPVCStatus("database")
+					Expect(status.Migration).To(BeNil())
+					Expect(status.ClaimName).To(HavePrefix(t.Name + "-database-"))
+					deploy := t.getDatabaseDeployment()
+					Expect(*deploy.Spec.Replicas).To(Equal(int32(1)))
+					Expect(deploy.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal(status.ClaimName))
+					t.checkConditionPresent(operatorv1beta2.ConditionTypePVCMigrationProgressing, metav1.ConditionFalse,
+						"MigrationComplete")
+				})
+				It("should delete the previous PVC", func() {
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.NewDefaultPVC().Name, Namespace: t.Namespace},
+						&corev1.PersistentVolumeClaim{})
+					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+				})
+				It("should emit a PersistentVolumeClaimMigrationSucceeded event", func() {
+					t.expectEvent("PersistentVolumeClaimMigrationSucceeded")
+				})
+			})
+			Context("with the Retain PVC deletion policy", func() {
+				BeforeEach(func() {
+					cr.Spec.StorageOptions.Database.DeletionPolicy = operatorv1beta2.PVCDeletionPolicyRetain
+				})
+				JustBeforeEach(func() {
+					finishBackupJob(t.Client, t.TestResources, t.getPVCMigrationJob(), batchv1.JobComplete, 0, "")
+					t.reconcileCryostatFully()
+				})
+				It("should orphan the previous PVC", func() {
+					pvc := t.getPVC(t.NewDefaultPVC().Name)
+					Expect(pvc.OwnerReferences).To(BeEmpty())
+				})
+				Context("then deleted and recreated", func() {
+					var claimName string
+					JustBeforeEach(func() {
+						claimName = t.getPVCStatus("database").ClaimName
+						job := t.getPVCMigrationJob()
+						t.reconcileDeletedCryostat()
+						t.expectNoCryostat()
+						// Simulate garbage collection of the Job owned by the deleted Cryostat
+						err := t.Client.Delete(context.Background(), job)
+						Expect(err).ToNot(HaveOccurred())
+
+						recreated := t.NewCryostatWithPVCSpec()
+						recreated.Spec.StorageOptions.Database.DeletionPolicy = operatorv1beta2.PVCDeletionPolicyRetain
+						err = t.Client.Create(context.Background(), recreated.Object)
+						Expect(err).ToNot(HaveOccurred())
+						t.reconcileCryostatFully()
+					})
+					It("should adopt the migrated PVC", func() {
+						status := t.getPVCStatus("database")
+						Expect(status.ClaimName).To(Equal(claimName))
+						Expect(status.Migration).To(BeNil())
+						pvc := t.getPVC(claimName)
+						Expect(metav1.IsControlledBy(pvc, t.getCryostatInstance().Object)).To(BeTrue())
+						Expect(pvc.Annotations).ToNot(HaveKey("operator.cryostat.io/retained-by"))
+						deploy := t.getDatabaseDeployment()
+						Expect(deploy.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal(claimName))
+						t.expectEvent("PersistentVolumeClaimAdopted")
+					})
+					It("should leave the PVC the data was migrated from", func() {
+						pvc := t.getPVC(t.NewDefaultPVC().Name)
+						Expect(pvc.OwnerReferences).To(BeEmpty())
+						Expect(pvc.Annotations).To(HaveKeyWithValue("operator.cryostat.io/retained-by", t.Name))
+					})
+				})
+			})
+			Context("when the Job fails", func() {
+				var targetName string
+				JustBeforeEach(func() {
+					targetName = t.getPVCStatus("database").Migration.TargetClaimName
+					finishBackupJob(t.Client, t.TestResources, t.getPVCMigrationJob(), batchv1.JobFailed, 1,
+						"cp: error writing '/target/userdata/base': No space left on device")
+					t.reconcileCryostatFully()
+				})
+				It("should continue using the previous PVC", func() {
+					status := t.getPVCStatus("database")
+					Expect(status.ClaimName).To(Equal(t.NewDefaultPVC().Name))
+					Expect(status.Migration.Phase).To(Equal(operatorv1beta2.PVCMigrationPhaseFailed))
+					deploy := t.getDatabaseDeployment()
+					Expect(*deploy.Spec.Replicas).To(Equal(int32(1)))
+					Expect(deploy.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal(status.ClaimName))
+				})
+				It("should report the failure", func() {
+					t.checkConditionPresent(operatorv1beta2.ConditionTypePVCMigrationProgressing, metav1.ConditionFalse,
+						"MigrationFailed")
+					condition := meta.FindStatusCondition(t.getCryostatInstance().Status.Conditions,
+						string(operatorv1beta2.ConditionTypePVCMigrationProgressing))
+					Expect(condition.Message).To(ContainSubstring("No space left on device"))
+					t.expectEvent("PersistentVolumeClaimMigrationFailed")
+				})
+				It("should delete the new PVC and Job", func() {
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: targetName, Namespace: t.Namespace},
+						&corev1.PersistentVolumeClaim{})
+					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+					err = t.Client.Get(context.Background(), types.NamespacedName{Name: targetName + "-migration", Namespace: t.Namespace},
+						&batchv1.Job{})
+					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+				})
+				Context("then retried with the annotation", func() {
+					JustBeforeEach(func() {
+						t.requestPVCMigration("database")
+						t.reconcileCryostatUntilRequeueAfter()
+					})
+					It("should start another migration", func() {
+						status := t.getPVCStatus("database")
+						Expect(status.Migration.Phase).To(Equal(operatorv1beta2.PVCMigrationPhaseCopying))
+						Expect(status.Migration.Reason).To(ContainSubstring("operator.cryostat.io/migrate-pvcs"))
+						t.getPVCMigrationJob()
+					})
+					It("should remove the annotation", func() {
+						Expect(t.getCryostatInstance().Object.GetAnnotations()).ToNot(HaveKey("operator.cryostat.io/migrate-pvcs"))
+					})
+				})
+			})
+		})
+		Context("with a PVC migration requested by annotation", func() {
+			BeforeEach(func() {
+				cr := t.NewCryostat()
+				cr.Object.SetAnnotations(map[string]string{
+					"operator.cryostat.io/migrate-pvcs": "storage, other",
+				})
+				t.objs = append(t.objs, cr.Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatUntilRequeueAfter()
+			})
+			It("should migrate only the requested PVC", func() {
+				Expect(t.getPVCStatus("database").Migration).To(BeNil())
+				status := t.getPVCStatus("storage")
+				Expect(status.Migration).ToNot(BeNil())
+				Expect(status.Migration.Reason).To(ContainSubstring("requested"))
+				Expect(*t.getStorageDeployment().Spec.Replicas).To(Equal(int32(0)))
+			})
+			It("should remove the requested component from the annotation", func() {
+				Expect(t.getCryostatInstance().Object.GetAnnotations()).To(HaveKeyWithValue("operator.cryostat.io/migrate-pvcs", "other"))
+			})
+		})
+		Context("with a database image for a new PostgreSQL version", func() {
+			oldImage := "my/database:16"
+			newImage := "my/database:17"
+			BeforeEach(func() {
+				t.EnvDatabaseImageTag = &newImage
+				t.objs = append(t.objs, t.NewCryostat().Object,
+					t.NewDatabasePod("old", oldImage, "16", "16"),
+					t.NewDatabasePod("new", newImage, "16", "17"))
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatUntilRequeueAfter()
+			})
+			It("should report the upgrade in progress", func() {
+				status := t.getCryostatInstance().Status.Database
+				Expect(status.Image).To(Equal(oldImage))
+				Expect(status.Upgrade).ToNot(BeNil())
+				Expect(status.Upgrade.Phase).To(Equal(operatorv1beta2.DatabaseUpgradePhaseUpgrading))
+				Expect(status.Upgrade.TargetImage).To(Equal(newImage))
+				Expect(status.Upgrade.FromVersion).To(Equal("16"))
+				Expect(status.Upgrade.ToVersion).To(Equal("17"))
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeDatabaseDeploymentProgressing, metav1.ConditionTrue,
+					"DatabaseUpgradeInProgress")
+				t.expectEvent("DatabaseUpgradeStarted")
+			})
+			It("should scale down the database", func() {
+				Expect(*t.getDatabaseDeployment().Spec.Replicas).To(Equal(int32(0)))
+			})
+			It("should create a Job upgrading the data", func() {
+				job := t.getDatabaseUpgradeJob("17")
+				Expect(metav1.IsControlledBy(job, t.getCryostatInstance().Object)).To(BeTrue())
+				Expect(job.Spec.Template.Spec.InitContainers[0].Image).To(Equal(oldImage))
+				Expect(job.Spec.Template.Spec.Containers[0].Image).To(Equal(newImage))
+				volumes := job.Spec.Template.Spec.Volumes
+				Expect(volumes[0].PersistentVolumeClaim.ClaimName).To(Equal(t.NewDefaultPVC().Name))
+			})
+			Context("when the Job succeeds", func() {
+				JustBeforeEach(func() {
+					// The database pods stopped when scaling down
+					for _, name := range []string{"old", "new"} {
+						err := t.Client.Delete(context.Background(), t.NewDatabasePod(name, "", "", ""))
+						Expect(err).ToNot(HaveOccurred())
+					}
+					finishBackupJob(t.Client, t.TestResources, t.getDatabaseUpgradeJob("17"), batchv1.JobComplete, 0, "")
+					t.reconcileCryostatFully()
+				})
+				It("should deploy the new image", func() {
+					status := t.getCryostatInstance().Status.Database
+					Expect(status.Image).To(Equal(newImage))
+					Expect(status.DataVersion).To(Equal("17"))
+					Expect(status.Upgrade).To(BeNil())
+					deploy := t.getDatabaseDeployment()
+					Expect(*deploy.Spec.Replicas).To(Equal(int32(1)))
+					Expect(deploy.Spec.Template.Spec.Containers[0].Image).To(Equal(newImage))
+					t.expectEvent("DatabaseUpgradeSucceeded")
+				})
+			})
+			Context("when the Job fails", func() {
+				JustBeforeEach(func() {
+					finishBackupJob(t.Client, t.TestResources, t.getDatabaseUpgradeJob("17"), batchv1.JobFailed, 1,
+						`psql:<stdin>:42: ERROR:  type "abstime" does not exist`)
+					t.reconcileCryostatFully()
+				})
+				It("should roll back to the previous image", func() {
+					deploy := t.getDatabaseDeployment()
+					Expect(*deploy.Spec.Replicas).To(Equal(int32(1)))
+					Expect(deploy.Spec.Template.Spec.Containers[0].Image).To(Equal(oldImage))
+					Expect(deploy.Spec.Template.Spec.InitContainers[0].Image).To(Equal(oldImage))
+				})
+				It("should report the failure", func() {
+					status := t.getCryostatInstance().Status.Database
+					Expect(status.Upgrade.Phase).To(Equal(operatorv1beta2.DatabaseUpgradePhaseFailed))
+					Expect(status.Upgrade.FailureMessage).To(ContainSubstring(`type "abstime" does not exist`))
+					t.checkConditionPresent(operatorv1beta2.ConditionTypeDatabaseDeploymentProgressing, metav1.ConditionFalse,
+						"DatabaseUpgradeFailed")
+					condition := meta.FindStatusCondition(t.getCryostatInstance().Status.Conditions,
+						string(operatorv1beta2.ConditionTypeDatabaseDeploymentProgressing))
+					Expect(condition.Message).To(ContainSubstring("rolled back to image " + oldImage))
+					t.expectEvent("DatabaseUpgradeFailed")
+				})
+				It("should delete the Job", func() {
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-database-upgrade-17", Namespace: t.Namespace},
+						&batchv1.Job{})
+					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+				})
+			})
+			Context("when the Job is interrupted", func() {
+				var pgdata string
+
+				BeforeEach(func() {
+					// The restore container was killed after moving the existing data aside,
+					// and after initializing part of the new data directory
+					pgdata = filepath.Join(GinkgoT().TempDir(), "data")
+					writeDatabaseFiles(pgdata+"-16", "16", "existing")
+					writeDatabaseFiles(pgdata, "17", "partial")
+				})
+				JustBeforeEach(func() {
+					finishBackupJob(t.Client, t.TestResources, t.getDatabaseUpgradeJob("17"), batchv1.JobFailed, 137, "")
+					t.reconcileCryostatFully()
+				})
+				It("should recover the existing data when the database starts", func() {
+					deploy := t.getDatabaseDeployment()
+					Expect(deploy.Spec.Template.Spec.Containers[0].Image).To(Equal(oldImage))
+					output := runDatabaseVersionCheck(&deploy.Spec.Template.Spec.InitContainers[0], pgdata, "16")
+					Expect(output).To(Equal(`{"dataVersion":"16","imageVersion":"16"}`))
+					Expect(os.ReadFile(filepath.Join(pgdata, "contents"))).To(Equal([]byte("existing")))
+					Expect(pgdata + "-16").ToNot(BeADirectory())
+				})
+			})
+		})
+		Context("with a database deployed before the data version was checked", func() {
+			oldImage := "my/database:16"
+			newImage := "my/database:17"
+			BeforeEach(func() {
+				t.EnvDatabaseImageTag = &newImage
+				cr := t.NewCryostat()
+				cr.Status.Database = nil
+				deploy := &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      t.Name + "-database",
+						Namespace: t.Namespace,
+					},
+					Spec: appsv1.DeploymentSpec{
+						Selector: t.NewDatabaseDeploymentSelector(),
+						Template: corev1.PodTemplateSpec{
+							ObjectMeta: metav1.ObjectMeta{
+								Labels: t.NewDatabaseDeploymentSelector().MatchLabels,
+							},
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{Name: t.Name + "-db", Image: oldImage},
+								},
+							},
+						},
+					},
+				}
+				// The first pod to check the data runs the new image
+				t.objs = append(t.objs, cr.Object, deploy, t.NewDatabasePod("new", newImage, "16", "17"))
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatUntilRequeueAfter()
+			})
+			It("should record the image of the existing deployment", func() {
+				status := t.getCryostatInstance().Status.Database
+				Expect(status.Image).To(Equal(oldImage))
+			})
+			It("should upgrade the data from the existing image", func() {
+				status := t.getCryostatInstance().Status.Database
+				Expect(status.Upgrade).ToNot(BeNil())
+				Expect(status.Upgrade.Phase).To(Equal(operatorv1beta2.DatabaseUpgradePhaseUpgrading))
+				job := t.getDatabaseUpgradeJob("17")
+				Expect(job.Spec.Template.Spec.InitContainers[0].Image).To(Equal(oldImage))
+				Expect(job.Spec.Template.Spec.Containers[0].Image).To(Equal(newImage))
+			})
+			Context("when the Job fails", func() {
+				JustBeforeEach(func() {
+					finishBackupJob(t.Client, t.TestResources, t.getDatabaseUpgradeJob("17"), batchv1.JobFailed, 1, "upgrade failed")
+					t.reconcileCryostatFully()
+				})
+				It("should roll back to the existing image", func() {
+					deploy := t.getDatabaseDeployment()
+					Expect(deploy.Spec.Template.Spec.Containers[0].Image).To(Equal(oldImage))
+				})
+			})
+		})
+		Context("with data from an unknown PostgreSQL version", func() {
+			newImage := "my/database:17"
+			BeforeEach(func() {
+				t.EnvDatabaseImageTag = &newImage
+				t.objs = append(t.objs, t.NewCryostat().Object, t.NewDatabasePod("new", newImage, "15", "17"))
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should report that the data cannot be upgraded", func() {
+				status := t.getCryostatInstance().Status.Database
+				Expect(status.Upgrade.Phase).To(Equal(operatorv1beta2.DatabaseUpgradePhaseFailed))
+				Expect(status.Upgrade.FailureMessage).To(ContainSubstring("image for PostgreSQL 15 is unknown"))
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeDatabaseDeploymentProgressing, metav1.ConditionFalse,
+					"DatabaseUpgradeFailed")
+				t.expectEvent("DatabaseUpgradeFailed")
+			})
+			It("should not create a Job", func() {
+				jobs := &batchv1.JobList{}
+				err := t.Client.List(context.Background(), jobs, ctrlclient.InNamespace(t.Namespace))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(jobs.Items).To(BeEmpty())
+			})
+		})
+		Context("with core pods running a previous image", func() {
+			oldImage := "quay.io/cryostat/cryostat:4.0.0"
+			BeforeEach(func() {
+				cr := t.NewCryostat()
+				cr.Status.Components = []operatorv1beta2.ComponentStatus{
+					{
+						Name:               "core",
+						DeploymentName:     t.Name,
+						Image:              oldImage,
+						CurrentImage:       oldImage,
+						CurrentImageDigest: "sha256:old",
+					},
+				}
+				t.objs = append(t.objs, cr.Object, t.NewCorePod("old", oldImage, "quay.io/cryostat/cryostat@sha256:old"))
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should report the desired and current images", func() {
+				core := t.getCryostatInstance().Status.Components[0]
+				Expect(core.Image).To(Equal(t.getDeployment(t.Name).Spec.Template.Spec.Containers[0].Image))
+				Expect(core.ImageDigest).To(BeEmpty())
+				Expect(core.CurrentImage).To(Equal(oldImage))
+				Expect(core.CurrentImageDigest).To(Equal("sha256:old"))
+			})
+			It("should report the upgrade in progress", func() {
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeUpgrading, metav1.ConditionTrue, "UpgradeInProgress")
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeUpgradeFailed, metav1.ConditionFalse, "NoUpgradeFailures")
+				t.expectEvent("ComponentUpgradeStarted")
+			})
+			Context("when pods running the new image are ready", func() {
+				JustBeforeEach(func() {
+					err := t.Client.Delete(context.Background(), t.NewCorePod("old", oldImage, ""))
+					Expect(err).ToNot(HaveOccurred())
+					image := t.getDeployment(t.Name).Spec.Template.Spec.Containers[0].Image
+					err = t.Client.Create(context.Background(), t.NewCorePod("new", image, "quay.io/cryostat/cryostat@sha256:new"))
+					Expect(err).ToNot(HaveOccurred())
+					t.reconcileCryostatFully()
+				})
+				It("should report the upgrade complete", func() {
+					core := t.getCryostatInstance().Status.Components[0]
+					Expect(core.CurrentImage).To(Equal(core.Image))
+					Expect(core.ImageDigest).To(Equal("sha256:new"))
+					Expect(core.CurrentImageDigest).To(Equal("sha256:new"))
+					t.checkConditionPresent(operatorv1beta2.ConditionTypeUpgrading, metav1.ConditionFalse, "UpgradeComplete")
+					t.expectEvent("ComponentUpgradeSucceeded")
+				})
+			})
+			Context("when the rollout fails", func() {
+				JustBeforeEach(func() {
+					deploy := t.getDeployment(t.Name)
+					deploy.Status.Conditions = []appsv1.DeploymentCondition{
+						{
+							Type:    appsv1.DeploymentProgressing,
+							Status:  corev1.ConditionFalse,
+							Reason:  "ProgressDeadlineExceeded",
+							Message: "ReplicaSet has timed out progressing.",
+						},
+					}
+					err := t.Client.Status().Update(context.Background(), deploy)
+					Expect(err).ToNot(HaveOccurred())
+					t.reconcileCryostatFully()
+				})
+				It("should report the failure", func() {
+					t.checkConditionPresent(operatorv1beta2.ConditionTypeUpgradeFailed, metav1.ConditionTrue, "RolloutFailed")
+					condition := meta.FindStatusCondition(t.getCryostatInstance().Status.Conditions,
+						string(operatorv1beta2.ConditionTypeUpgradeFailed))
+					Expect(condition.Message).To(ContainSubstring("core: ReplicaSet has timed out progressing."))
+					t.expectEvent("ComponentUpgradeFailed")
+				})
+			})
+		})
+		Context("when paused", func() {
+			BeforeEach(func() {
+				t.ReportReplicas = 1
+				cr := t.NewCryostat()
+				cr.Spec.Paused = true
+				t.objs = append(t.objs, cr.Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should scale down all deployments", func() {
+				for _, name := range []string{t.Name, t.Name + "-reports", t.Name + "-database", t.Name + "-storage"} {
+					Expect(*t.getDeployment(name).Spec.Replicas).To(Equal(int32(0)), name)
+				}
+			})
+			It("should keep the PVCs and secrets", func() {
+				t.getPVC(t.NewDefaultPVC().Name)
+				t.getPVC(t.NewStoragePVC().Name)
+				t.expectDatabaseSecret()
+			})
+			It("should report that it is paused", func() {
+				t.checkConditionPresent(operatorv1beta2.ConditionTypePaused, metav1.ConditionTrue, "Paused")
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeReady, metav1.ConditionFalse, "Paused")
+			})
+			Context("then resumed", func() {
+				JustBeforeEach(func() {
+					cr := t.getCryostatInstance()
+					cr.Spec.Paused = false
+					t.updateCryostatInstance(cr)
+					t.reconcileCryostatFully()
+				})
+				It("should scale up all deployments", func() {
+					for _, name := range []string{t.Name, t.Name + "-database", t.Name + "-storage"} {
+						Expect(*t.getDeployment(name).Spec.Replicas).To(Equal(int32(1)), name)
+					}
+					t.checkReportsDeployment()
+				})
+				It("should report that it is not paused", func() {
+					t.checkConditionPresent(operatorv1beta2.ConditionTypePaused, metav1.ConditionFalse, "NotPaused")
+				})
+			})
+		})
+		Context("when restoring a backup", func() {
+			BeforeEach(func() {
+				t.ReportReplicas = 1
+				t.objs = append(t.objs, t.NewCryostat().Object, t.NewCryostatRestoreInProgress())
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should scale down the deployments that use the database", func() {
+				for _, name := range []string{t.Name, t.Name + "-reports"} {
+					Expect(*t.getDeployment(name).Spec.Replicas).To(Equal(int32(0)), name)
+				}
+			})
+			It("should keep the database and storage running", func() {
+				for _, name := range []string{t.Name + "-database", t.Name + "-storage"} {
+					Expect(*t.getDeployment(name).Spec.Replicas).To(Equal(int32(1)), name)
+				}
+			})
+			Context("for another Cryostat", func() {
+				BeforeEach(func() {
+					restore := t.objs[len(t.objs)-1].(*operatorv1beta2.CryostatRestore)
+					restore.Spec.CryostatName = "other-cryostat"
+				})
+				It("should not scale down", func() {
+					Expect(*t.getDeployment(t.Name).Spec.Replicas).To(Equal(int32(1)))
+					t.checkReportsDeployment()
+				})
+			})
+			Context("then completed", func() {
+				JustBeforeEach(func() {
+					restore := &operatorv1beta2.CryostatRestore{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: "my-restore", Namespace: t.Namespace}, restore)
+					Expect(err).ToNot(HaveOccurred())
+					meta.SetStatusCondition(&restore.Status.Conditions, metav1.Condition{
+						Type:   string(operatorv1beta2.ConditionTypeRestoreComplete),
+						Status: metav1.ConditionTrue,
+						Reason: "RestoreSucceeded",
+					})
+					Expect(t.Client.Update(context.Background(), restore)).To(Succeed())
+					t.reconcileCryostatFully()
+				})
+				It("should scale the deployments back up", func() {
+					Expect(*t.getDeployment(t.Name).Spec.Replicas).To(Equal(int32(1)))
+					t.checkReportsDeployment()
+				})
+			})
+		})
+		Context("with reconciliation paused", func() {
+			var cr *model.CryostatInstance
+			BeforeEach(func() {
+				cr = t.NewCryostat()
+				cr.Spec.ReconcilePaused = true
+				t.objs = append(t.objs, cr.Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should not create any resources", func() {
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace},
+					&appsv1.Deployment{})
+				Expect(kerrors.IsNotFound(err)).To(BeTrue())
+				Expect(t.getCryostatInstance().Object.GetFinalizers()).To(BeEmpty())
+			})
+			It("should report that reconciliation is paused", func() {
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeReconcilePaused, metav1.ConditionTrue, "ReconcilePaused")
+			})
+			Context("after the deployments were modified manually", func() {
+				BeforeEach(func() {
+					cr.Spec.ReconcilePaused = false
+				})
+				JustBeforeEach(func() {
+					deploy := t.getDatabaseDeployment()
+					deploy.Spec.Template.Spec.Containers[0].Image = "my/hotfix:latest"
+					err := t.Client.Update(context.Background(), deploy)
+					Expect(err).ToNot(HaveOccurred())
+
+					cr := t.getCryostatInstance()
+					cr.Spec.ReconcilePaused = true
+					t.updateCryostatInstance(cr)
+					t.reconcileCryostatFully()
+				})
+				It("should keep the modifications", func() {
+					Expect(t.getDatabaseDeployment().Spec.Template.Spec.Containers[0].Image).To(Equal("my/hotfix:latest"))
+					t.checkConditionPresent(operatorv1beta2.ConditionTypeReconcilePaused, metav1.ConditionTrue, "ReconcilePaused")
+				})
+				Context("then resumed", func() {
+					JustBeforeEach(func() {
+						cr := t.getCryostatInstance()
+						cr.Spec.ReconcilePaused = false
+						t.updateCryostatInstance(cr)
+						t.reconcileCryostatFully()
+					})
+					It("should restore the deployments", func() {
+						t.expectDatabaseDeployment()
+						t.checkConditionAbsent(operatorv1beta2.ConditionTypeReconcilePaused)
+					})
+				})
+			})
+		})
 		Context("with custom EmptyDir config", func() {
 			BeforeEach(func() {
 				t.objs = append(t.objs, t.NewCryostatWithDefaultEmptyDir().Object)
@@ -1257,41 +1901,729 @@ func (c *controllerTest) commonTests() {
 				})
 			})
 		})
-		Context("when deleted", func() {
+		Context("with images overridden in the CR", func() {
+			var cr *model.CryostatInstance
 			BeforeEach(func() {
-				t.objs = append(t.objs, t.NewCryostat().Object)
+				t.ReportReplicas = 1
+				cr = t.NewCryostatWithImageOverrides()
+				t.objs = append(t.objs, cr.Object)
 			})
 			JustBeforeEach(func() {
 				t.reconcileCryostatFully()
 			})
-			Context("ClusterRoleBinding exists", func() {
-				JustBeforeEach(func() {
-					t.reconcileDeletedCryostat()
+			It("should use the overridden images", func() {
+				images := cr.Spec.Images
+				t.expectDeploymentImages(t.Name, map[string]string{
+					t.Name:                     images.Core,
+					t.Name + "-grafana":        images.Grafana,
+					t.Name + "-jfr-datasource": images.Datasource,
+					t.Name + "-auth-proxy":     images.AuthProxy,
+					t.Name + "-agent-proxy":    images.AgentProxy,
 				})
-				It("should delete the ClusterRoleBinding", func() {
-					t.checkClusterRoleBindingDeleted()
+				t.expectDeploymentImages(t.Name+"-database", map[string]string{t.Name + "-db": images.Database})
+				t.expectDeploymentImages(t.Name+"-storage", map[string]string{t.Name + "-storage": images.Storage})
+				t.expectDeploymentImages(t.Name+"-reports", map[string]string{t.Name + "-reports": images.Reports})
+			})
+			It("should report the overridden images in the status", func() {
+				instance := t.getCryostatInstance()
+				expected := map[string]string{
+					"core":     cr.Spec.Images.Core,
+					"reports":  cr.Spec.Images.Reports,
+					"database": cr.Spec.Images.Database,
+					"storage":  cr.Spec.Images.Storage,
+				}
+				Expect(instance.Status.Components).To(HaveLen(len(expected)))
+				for _, component := range instance.Status.Components {
+					Expect(component.Image).To(Equal(expected[component.Name]), "Component %s", component.Name)
+				}
+				Expect(instance.Status.Version).To(Equal("custom"))
+			})
+			Context("with images also provided by the environment", func() {
+				BeforeEach(func() {
+					coreImg := "my/core-image:1.0.0"
+					t.EnvCoreImageTag = &coreImg
 				})
-				It("should delete Cryostat", func() {
-					t.expectNoCryostat()
+				It("should prefer the image from the CR", func() {
+					t.expectDeploymentImages(t.Name, map[string]string{t.Name: cr.Spec.Images.Core})
 				})
 			})
-			Context("ClusterRoleBinding does not exist", func() {
-				JustBeforeEach(func() {
-					err := t.Client.Delete(context.Background(), t.NewClusterRoleBinding())
+		})
+		Context("with image pull secrets", func() {
+			var cr *model.CryostatInstance
+			BeforeEach(func() {
+				t.ReportReplicas = 1
+				cr = t.NewCryostatWithImagePullSecrets()
+				t.objs = append(t.objs, cr.Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should add the pull secrets to each pod", func() {
+				for _, name := range []string{t.Name, t.Name + "-database", t.Name + "-storage", t.Name + "-reports"} {
+					deploy := &appsv1.Deployment{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: t.Namespace}, deploy)
 					Expect(err).ToNot(HaveOccurred())
-					t.reconcileDeletedCryostat()
+					Expect(deploy.Spec.Template.Spec.ImagePullSecrets).To(Equal(cr.Spec.ImagePullSecrets), "Deployment %s", name)
+				}
+			})
+			It("should add the pull secrets to the service account", func() {
+				sa := &corev1.ServiceAccount{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, sa)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sa.ImagePullSecrets).To(Equal(cr.Spec.ImagePullSecrets))
+			})
+			Context("with an existing service account", func() {
+				var oldSA *corev1.ServiceAccount
+				BeforeEach(func() {
+					oldSA = t.OtherServiceAccount()
+					t.objs = append(t.objs, oldSA)
 				})
-				It("should delete Cryostat", func() {
-					t.expectNoCryostat()
+				It("should keep the existing pull secrets", func() {
+					sa := &corev1.ServiceAccount{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, sa)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(sa.ImagePullSecrets).To(Equal(append(oldSA.ImagePullSecrets, cr.Spec.ImagePullSecrets...)))
 				})
 			})
-			Context("RoleBinding exists", func() {
+		})
+		Context("with Grafana options", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithGrafanaOptions().Object, t.NewGrafanaDatasourceSecret(),
+					t.NewGrafanaDashboardConfigMap("jfr-dashboards", nil),
+					t.NewGrafanaDashboardConfigMap("team-dashboards", map[string]string{"cryostat.io/dashboard": "true"}),
+					t.NewGrafanaDashboardConfigMap("other-config", map[string]string{"cryostat.io/dashboard": "false"}))
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should mount the dashboards and provisioning files in Grafana", func() {
+				container := t.getMainDeploymentContainer(t.Name + "-grafana")
+				Expect(container.VolumeMounts).To(ConsistOf(
+					corev1.VolumeMount{Name: "dashboards-0", MountPath: "/opt/cryostat.d/grafana/dashboards/jfr-dashboards", ReadOnly: true},
+					corev1.VolumeMount{Name: "dashboards-1", MountPath: "/opt/cryostat.d/grafana/dashboards/team-dashboards", ReadOnly: true},
+					corev1.VolumeMount{Name: "grafana-provisioning", MountPath: "/etc/grafana/provisioning/dashboards/cryostat-user-dashboards.yaml",
+						SubPath: "cryostat-user-dashboards.yaml", ReadOnly: true},
+					corev1.VolumeMount{Name: "grafana-datasources", MountPath: "/etc/grafana/provisioning/datasources/cryostat-user-datasources.yaml",
+						SubPath: "cryostat-user-datasources.yaml", ReadOnly: true},
+				))
+
+				deploy := &appsv1.Deployment{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+				Expect(err).ToNot(HaveOccurred())
+				volumeConfigMaps := []string{}
+				for _, volume := range deploy.Spec.Template.Spec.Volumes {
+					if volume.ConfigMap != nil {
+						volumeConfigMaps = append(volumeConfigMaps, volume.ConfigMap.Name)
+					}
+				}
+				Expect(volumeConfigMaps).To(ContainElements("jfr-dashboards", "team-dashboards", t.Name+"-grafana-provisioning"))
+				Expect(volumeConfigMaps).ToNot(ContainElement("other-config"))
+				Expect(deploy.Spec.Template.Spec.Volumes).To(ContainElement(And(
+					HaveField("Name", "grafana-datasources"),
+					HaveField("Secret.SecretName", t.Name+"-grafana-datasources"),
+				)))
+			})
+			It("should create the provisioning files", func() {
+				cm := &corev1.ConfigMap{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-grafana-provisioning", Namespace: t.Namespace}, cm)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(metav1.IsControlledBy(cm, t.getCryostatInstance().Object)).To(BeTrue())
+
+				Expect(cm.Data).To(HaveKey("cryostat-user-dashboards.yaml"))
+				providers := map[string]interface{}{}
+				err = json.Unmarshal([]byte(cm.Data["cryostat-user-dashboards.yaml"]), &providers)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(providers["providers"]).To(ConsistOf(HaveKeyWithValue("options", map[string]interface{}{
+					"path":                      "/opt/cryostat.d/grafana/dashboards",
+					"foldersFromFilesStructure": true,
+				})))
+
+				Expect(cm.Data).ToNot(HaveKey("cryostat-user-datasources.yaml"))
+			})
+			It("should create the datasources provisioning file in a Secret", func() {
+				secret := &corev1.Secret{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-grafana-datasources", Namespace: t.Namespace}, secret)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(metav1.IsControlledBy(secret, t.getCryostatInstance().Object)).To(BeTrue())
+
+				Expect(secret.Data).To(HaveKey("cryostat-user-datasources.yaml"))
+				datasources := map[string]interface{}{}
+				err = json.Unmarshal(secret.Data["cryostat-user-datasources.yaml"], &datasources)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(datasources["datasources"]).To(ConsistOf(map[string]interface{}{
+					"name":      "Prometheus",
+					"type":      "prometheus",
+					"access":    "proxy",
+					"url":       "http://prometheus.monitoring.svc:9090",
+					"isDefault": false,
+					"editable":  false,
+					"jsonData":  map[string]interface{}{"httpMethod": "POST", "httpHeaderName1": "Authorization"},
+					"secureJsonData": map[string]interface{}{
+						"httpHeaderValue1":  "$__env{CRYOSTAT_DATASOURCE_0_HTTPHEADERVALUE1}",
+						"basicAuthPassword": "hunter2",
+					},
+				}))
+			})
+			It("should read secure datasource settings from Secrets", func() {
+				container := t.getMainDeploymentContainer(t.Name + "-grafana")
+				Expect(container.Env).To(ContainElement(corev1.EnvVar{
+					Name: "CRYOSTAT_DATASOURCE_0_HTTPHEADERVALUE1",
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "prometheus-token"},
+							Key:                  "token",
+						},
+					},
+				}))
+			})
+			It("should add the Grafana settings", func() {
+				container := t.getMainDeploymentContainer(t.Name + "-grafana")
+				Expect(container.Env).To(ContainElements(
+					corev1.EnvVar{Name: "GF_INSTALL_PLUGINS", Value: "grafana-clock-panel"},
+					corev1.EnvVar{Name: "GF_LOG_LEVEL", Value: "debug"},
+					corev1.EnvVar{Name: "GF_SERVER_DOMAIN", Value: "localhost"},
+				))
+				Expect(container.Env).ToNot(ContainElement(HaveField("Name", "EXTRA_VAR")))
+				Expect(container.Env).ToNot(ContainElement(corev1.EnvVar{Name: "GF_SERVER_DOMAIN", Value: "grafana.example.com"}))
+			})
+			Context("with a dashboard Config Map that does not exist", func() {
+				JustBeforeEach(func() {
+					cr := t.getCryostatInstance()
+					cr.Spec.GrafanaOptions.DashboardConfigMaps = append(cr.Spec.GrafanaOptions.DashboardConfigMaps,
+						"missing.dashboards."+strings.Repeat("x", 60))
+					t.updateCryostatInstance(cr)
+					t.reconcileCryostatFully()
+				})
+				It("should mount the dashboards as an optional volume", func() {
+					deploy := &appsv1.Deployment{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(deploy.Spec.Template.Spec.Volumes).To(ContainElement(And(
+						HaveField("Name", "dashboards-1"),
+						HaveField("ConfigMap.Name", "missing.dashboards."+strings.Repeat("x", 60)),
+						HaveField("ConfigMap.Optional", HaveValue(BeTrue())),
+					)))
+				})
+			})
+			Context("that are removed", func() {
+				JustBeforeEach(func() {
+					cr := t.getCryostatInstance()
+					cr.Spec.GrafanaOptions = nil
+					t.updateCryostatInstance(cr)
+					t.reconcileCryostatFully()
+				})
+				It("should delete the provisioning files", func() {
+					cm := &corev1.ConfigMap{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-grafana-provisioning", Namespace: t.Namespace}, cm)
+					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+					secret := &corev1.Secret{}
+					err = t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-grafana-datasources", Namespace: t.Namespace}, secret)
+					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+				})
+				It("should not mount any dashboards", func() {
+					container := t.getMainDeploymentContainer(t.Name + "-grafana")
+					Expect(container.VolumeMounts).To(BeEmpty())
+				})
+			})
+		})
+		Context("with Grafana disabled", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithGrafanaDisabled().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should not deploy Grafana or the JFR datasource", func() {
+				deploy := &appsv1.Deployment{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(deploy.Spec.Template.Spec.Containers).ToNot(ContainElement(HaveField("Name", t.Name+"-grafana")))
+				Expect(deploy.Spec.Template.Spec.Containers).ToNot(ContainElement(HaveField("Name", t.Name+"-jfr-datasource")))
+			})
+			It("should not configure Cryostat to use Grafana", func() {
+				container := t.getCoreContainer()
+				Expect(container.Env).ToNot(ContainElement(HaveField("Name", HavePrefix("GRAFANA_"))))
+			})
+			It("should not proxy requests to Grafana", func() {
+				container := t.getMainDeploymentContainer(t.Name + "-auth-proxy")
+				Expect(container.Args).ToNot(ContainElement(ContainSubstring("/grafana/")))
+			})
+		})
+		Context("with Grafana in a separate deployment", func() {
+			var cr *model.CryostatInstance
+			BeforeEach(func() {
+				cr = t.NewCryostatWithSeparateGrafana()
+				t.objs = append(t.objs, cr.Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should create the Grafana deployment", func() {
+				deploy := t.getGrafanaDeployment()
+				Expect(metav1.IsControlledBy(deploy, t.getCryostatInstance().Object)).To(BeTrue())
+				Expect(deploy.Spec.Selector.MatchLabels).To(Equal(map[string]string{
+					"app":       t.Name,
+					"kind":      "cryostat",
+					"component": "grafana",
+				}))
+				Expect(*deploy.Spec.Replicas).To(Equal(int32(1)))
+				// Replace the single replica, rather than run two, since the JFR datasource keeps state in memory
+				Expect(deploy.Spec.Strategy).To(Equal(t.NewMainDeploymentStrategy()))
+				Expect(deploy.Spec.Template.Spec.NodeSelector).To(Equal(cr.Spec.GrafanaOptions.SchedulingOptions.NodeSelector))
+
+				containers := deploy.Spec.Template.Spec.Containers
+				Expect(containers).To(HaveLen(2))
+				Expect(containers[0].Name).To(Equal(t.Name + "-grafana"))
+				test.ExpectResourceRequirements(&containers[0].Resources, t.NewGrafanaContainerResource(cr))
+				Expect(containers[1].Name).To(Equal(t.Name + "-jfr-datasource"))
+				test.ExpectResourceRequirements(&containers[1].Resources, t.NewDatasourceContainerResource(cr))
+			})
+			It("should remove Grafana from the Cryostat pod", func() {
+				deploy := &appsv1.Deployment{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(deploy.Spec.Template.Spec.Containers).ToNot(ContainElement(HaveField("Name", t.Name+"-grafana")))
+				Expect(deploy.Spec.Template.Spec.Containers).ToNot(ContainElement(HaveField("Name", t.Name+"-jfr-datasource")))
+			})
+			It("should create the Grafana service and network policy", func() {
+				t.checkService(t.NewGrafanaService())
+				t.checkNetworkPolicy(t.NewGrafanaNetworkPolicy())
+			})
+			It("should create a certificate for Grafana", func() {
+				expected := t.NewGrafanaCert()
+				cert := &certv1.Certificate{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, cert)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cert.Spec).To(Equal(expected.Spec))
+				Expect(t.getCryostatInstance().Status.Certificates).To(ContainElement(HaveField("Name", expected.Name)))
+			})
+			It("should serve Grafana and the datasource over TLS", func() {
+				deploy := t.getGrafanaDeployment()
+				grafana := deploy.Spec.Template.Spec.Containers[0]
+				Expect(grafana.Env).To(ContainElements(
+					corev1.EnvVar{Name: "GF_SERVER_PROTOCOL", Value: "https"},
+					corev1.EnvVar{Name: "GF_SERVER_CERT_FILE", Value: "/var/run/secrets/operator.cryostat.io/" + t.Name + "-grafana-tls/tls.crt"},
+					corev1.EnvVar{Name: "GF_SERVER_CERT_KEY", Value: "/var/run/secrets/operator.cryostat.io/" + t.Name + "-grafana-tls/tls.key"},
+					corev1.EnvVar{Name: "JFR_DATASOURCE_URL", Value: "https://localhost:8989"},
+				))
+				Expect(grafana.LivenessProbe.HTTPGet.Scheme).To(Equal(corev1.URISchemeHTTPS))
+
+				datasource := deploy.Spec.Template.Spec.Containers[1]
+				Expect(datasource.Env).To(ContainElements(
+					corev1.EnvVar{Name: "QUARKUS_HTTP_HOST", Value: "0.0.0.0"},
+					corev1.EnvVar{Name: "QUARKUS_HTTP_SSL_PORT", Value: "8989"},
+					corev1.EnvVar{Name: "QUARKUS_HTTP_INSECURE_REQUESTS", Value: "disabled"},
+				))
+				Expect(datasource.LivenessProbe.HTTPGet.Scheme).To(Equal(corev1.URISchemeHTTPS))
+			})
+			It("should configure Cryostat to use the Grafana service", func() {
+				container := t.getCoreContainer()
+				Expect(container.Env).To(ContainElements(
+					corev1.EnvVar{Name: "GRAFANA_DATASOURCE_URL", Value: "https://" + t.Name + "-grafana:8989"},
+					corev1.EnvVar{Name: "GRAFANA_DASHBOARD_URL", Value: "https://" + t.Name + "-grafana:3000"},
+				))
+			})
+			It("should proxy requests to the Grafana service", func() {
+				container := t.getMainDeploymentContainer(t.Name + "-auth-proxy")
+				Expect(container.Args).To(ContainElements(
+					"--upstream=https://"+t.Name+"-grafana:3000/grafana/",
+					"--upstream-ca=/var/run/secrets/operator.cryostat.io/"+t.Name+"-ca-bundle/ca.crt",
+				))
+				Expect(container.Args).ToNot(ContainElement("--upstream=http://localhost:3000/grafana/"))
+			})
+			Context("with cert-manager disabled", func() {
+				BeforeEach(func() {
+					disable := false
+					cr.Spec.EnableCertManager = &disable
+					t.TLS = false
+				})
+				It("should configure Cryostat to use the Grafana service", func() {
+					container := t.getCoreContainer()
+					Expect(container.Env).To(ContainElements(
+						corev1.EnvVar{Name: "GRAFANA_DATASOURCE_URL", Value: "http://" + t.Name + "-grafana:8989"},
+						corev1.EnvVar{Name: "GRAFANA_DASHBOARD_URL", Value: "http://" + t.Name + "-grafana:3000"},
+					))
+				})
+				It("should serve the datasource over HTTP", func() {
+					datasource := t.getGrafanaDeployment().Spec.Template.Spec.Containers[1]
+					Expect(datasource.Env).To(ConsistOf(
+						corev1.EnvVar{Name: "QUARKUS_HTTP_HOST", Value: "0.0.0.0"},
+						corev1.EnvVar{Name: "QUARKUS_HTTP_PORT", Value: "8989"},
+					))
+				})
+			})
+			Context("with a pod template override", func() {
+				BeforeEach(func() {
+					cr.Spec.Overrides = &operatorv1beta2.PodTemplateOverrides{
+						Grafana: &corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{
+										Name: t.Name + "-grafana",
+										Env: []corev1.EnvVar{
+											{Name: "GF_LOG_LEVEL", Value: "debug"},
+										},
+									},
+								},
+							},
+						},
+					}
+				})
+				It("should merge the override into the Grafana deployment", func() {
+					grafana := t.getGrafanaDeployment().Spec.Template.Spec.Containers[0]
+					Expect(grafana.Env).To(ContainElement(corev1.EnvVar{Name: "GF_LOG_LEVEL", Value: "debug"}))
+				})
+				It("should set OverridesApplied condition", func() {
+					t.checkConditionPresent(operatorv1beta2.ConditionTypeOverridesApplied, metav1.ConditionTrue, "OverridesApplied")
+				})
+				Context("that is moved back into the Cryostat pod", func() {
+					JustBeforeEach(func() {
+						cr := t.getCryostatInstance()
+						cr.Spec.GrafanaOptions.SeparateDeployment = false
+						t.updateCryostatInstance(cr)
+						t.reconcileCryostatFully()
+					})
+					It("should set OverridesApplied condition to false", func() {
+						t.checkConditionPresent(operatorv1beta2.ConditionTypeOverridesApplied, metav1.ConditionFalse, "OverridesInvalid")
+						condition := meta.FindStatusCondition(t.getCryostatInstance().Status.Conditions,
+							string(operatorv1beta2.ConditionTypeOverridesApplied))
+						Expect(condition.Message).To(ContainSubstring("grafana: Grafana does not run in its own deployment"))
+					})
+				})
+			})
+			Context("that is moved back into the Cryostat pod", func() {
+				JustBeforeEach(func() {
+					cr := t.getCryostatInstance()
+					cr.Spec.GrafanaOptions.SeparateDeployment = false
+					t.updateCryostatInstance(cr)
+					t.reconcileCryostatFully()
+				})
+				It("should delete the Grafana deployment, service, network policy and certificate", func() {
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-grafana", Namespace: t.Namespace}, &appsv1.Deployment{})
+					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+					err = t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-grafana", Namespace: t.Namespace}, &corev1.Service{})
+					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+					t.expectNoNetworkPolicy(t.NewGrafanaNetworkPolicy().Name)
+					err = t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-grafana", Namespace: t.Namespace}, &certv1.Certificate{})
+					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+				})
+				It("should run Grafana in the Cryostat pod", func() {
+					t.getMainDeploymentContainer(t.Name + "-grafana")
+					t.getMainDeploymentContainer(t.Name + "-jfr-datasource")
+					container := t.getCoreContainer()
+					Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: "GRAFANA_DATASOURCE_URL", Value: "http://127.0.0.1:8989"}))
+				})
+			})
+		})
+		Context("with probe options", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithProbeOptions().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should tune the core probes", func() {
+				container := t.getCoreContainer()
+				Expect(container.LivenessProbe.ProbeHandler).To(Equal(t.NewCoreLivenessProbe(&container.Resources).ProbeHandler))
+				Expect(container.LivenessProbe.PeriodSeconds).To(Equal(int32(20)))
+				Expect(container.LivenessProbe.TimeoutSeconds).To(Equal(int32(5)))
+				Expect(container.LivenessProbe.FailureThreshold).To(Equal(int32(6)))
+				Expect(container.StartupProbe.InitialDelaySeconds).To(Equal(int32(30)))
+				Expect(container.StartupProbe.FailureThreshold).To(Equal(int32(60)))
+			})
+			It("should set the termination grace period of each pod", func() {
+				deploy := &appsv1.Deployment{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(deploy.Spec.Template.Spec.TerminationGracePeriodSeconds).To(Equal(&[]int64{90}[0]))
+
+				err = t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-database", Namespace: t.Namespace}, deploy)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(deploy.Spec.Template.Spec.TerminationGracePeriodSeconds).To(Equal(&[]int64{120}[0]))
+			})
+			It("should apply the liveness settings to the database readiness probe", func() {
+				deploy := &appsv1.Deployment{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-database", Namespace: t.Namespace}, deploy)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(deploy.Spec.Template.Spec.Containers[0].ReadinessProbe.InitialDelaySeconds).To(Equal(int32(10)))
+			})
+			It("should remove the storage startup probe", func() {
+				deploy := &appsv1.Deployment{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-storage", Namespace: t.Namespace}, deploy)
+				Expect(err).ToNot(HaveOccurred())
+				container := deploy.Spec.Template.Spec.Containers[0]
+				Expect(container.StartupProbe).To(BeNil())
+				Expect(container.LivenessProbe).To(Equal(t.NewStorageLivenessProbe(&container.Resources)))
+			})
+			It("should add a startup probe to the datasource", func() {
+				container := t.getMainDeploymentContainer(t.Name + "-jfr-datasource")
+				Expect(container.StartupProbe).To(Equal(&corev1.Probe{
+					ProbeHandler:     t.NewDatasourceLivenessProbe(&container.Resources).ProbeHandler,
+					PeriodSeconds:    5,
+					FailureThreshold: 24,
+				}))
+			})
+			It("should leave other probes unchanged", func() {
+				container := t.getMainDeploymentContainer(t.Name + "-grafana")
+				Expect(container.LivenessProbe).To(Equal(t.NewGrafanaLivenessProbe(&container.Resources)))
+				Expect(container.StartupProbe).To(BeNil())
+			})
+		})
+		Context("with core options", func() {
+			var cr *model.CryostatInstance
+			BeforeEach(func() {
+				cr = t.NewCryostatWithCoreOptions()
+				t.objs = append(t.objs, cr.Object, t.NewCoreEnvFromSecret())
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should add the environment variables to the core container", func() {
+				container := t.getCoreContainer()
+				Expect(container.Env).To(ContainElements(
+					corev1.EnvVar{Name: "CRYOSTAT_DISCOVERY_JDP_ENABLED", Value: "true"},
+					corev1.EnvVar{Name: "JAVA_OPTS_APPEND", Value: "-Xmx1g"},
+				))
+				Expect(container.EnvFrom).To(Equal(cr.Spec.CoreOptions.EnvFrom))
+			})
+			It("should not override variables set by the operator", func() {
+				container := t.getCoreContainer()
+				Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: "QUARKUS_HTTP_PORT", Value: "8181"}))
+				Expect(container.Env).ToNot(ContainElement(corev1.EnvVar{Name: "QUARKUS_HTTP_PORT", Value: "9000"}))
+			})
+			It("should emit a warning event listing the conflicts", func() {
+				recorder := t.controller.GetConfig().EventRecorder.(*record.FakeRecorder)
+				var eventMsg string
+				Expect(recorder.Events).To(Receive(&eventMsg))
+				Expect(eventMsg).To(Equal("Warning CoreEnvConflict Environment variables in spec.coreOptions are set by the operator and were ignored: QUARKUS_HTTP_PORT"))
+				Expect(recorder.Events).ToNot(Receive())
+			})
+			It("should report the conflicts in the CoreEnvApplied condition", func() {
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeCoreEnvApplied, metav1.ConditionFalse, "CoreEnvConflict")
+				condition := meta.FindStatusCondition(t.getCryostatInstance().Status.Conditions,
+					string(operatorv1beta2.ConditionTypeCoreEnvApplied))
+				Expect(condition.Message).To(Equal("Environment variables in spec.coreOptions are set by the operator and were ignored: QUARKUS_HTTP_PORT"))
+			})
+			Context("when reconciled again", func() {
+				JustBeforeEach(func() {
+					recorder := t.controller.GetConfig().EventRecorder.(*record.FakeRecorder)
+					Expect(recorder.Events).To(Receive())
+					t.reconcileCryostatFully()
+				})
+				It("should not emit another warning event", func() {
+					recorder := t.controller.GetConfig().EventRecorder.(*record.FakeRecorder)
+					Expect(recorder.Events).ToNot(Receive())
+				})
+			})
+			Context("when the referenced secret sets a variable set by the operator", func() {
+				JustBeforeEach(func() {
+					recorder := t.controller.GetConfig().EventRecorder.(*record.FakeRecorder)
+					Expect(recorder.Events).To(Receive())
+
+					secret := t.NewCoreEnvFromSecret()
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: secret.Name, Namespace: t.Namespace}, secret)
+					Expect(err).ToNot(HaveOccurred())
+					secret.Data["CRYOSTAT_CONNECTIONS_MAX_OPEN"] = []byte("1000")
+					err = t.Client.Update(context.Background(), secret)
+					Expect(err).ToNot(HaveOccurred())
+					t.reconcileCryostatFully()
+				})
+				It("should report the variable in the CoreEnvApplied condition", func() {
+					condition := meta.FindStatusCondition(t.getCryostatInstance().Status.Conditions,
+						string(operatorv1beta2.ConditionTypeCoreEnvApplied))
+					Expect(condition).ToNot(BeNil())
+					Expect(condition.Message).To(Equal("Environment variables in spec.coreOptions are set by the operator and were ignored: " +
+						"QUARKUS_HTTP_PORT, CRYOSTAT_CONNECTIONS_MAX_OPEN (from Secret cryostat-extra-env)"))
+				})
+				It("should emit a warning event listing the new conflicts", func() {
+					recorder := t.controller.GetConfig().EventRecorder.(*record.FakeRecorder)
+					var eventMsg string
+					Expect(recorder.Events).To(Receive(&eventMsg))
+					Expect(eventMsg).To(Equal("Warning CoreEnvConflict Environment variables in spec.coreOptions are set by the operator and were ignored: " +
+						"QUARKUS_HTTP_PORT, CRYOSTAT_CONNECTIONS_MAX_OPEN (from Secret cryostat-extra-env)"))
+				})
+			})
+			Context("when the conflicting variables are removed", func() {
+				JustBeforeEach(func() {
+					cr := t.getCryostatInstance()
+					env := []corev1.EnvVar{}
+					for _, e := range cr.Spec.CoreOptions.Env {
+						if e.Name != "QUARKUS_HTTP_PORT" {
+							env = append(env, e)
+						}
+					}
+					cr.Spec.CoreOptions.Env = env
+					t.updateCryostatInstance(cr)
+					t.reconcileCryostatFully()
+				})
+				It("should set CoreEnvApplied condition to true", func() {
+					t.checkConditionPresent(operatorv1beta2.ConditionTypeCoreEnvApplied, metav1.ConditionTrue, "CoreEnvApplied")
+				})
+			})
+			Context("when the referenced secret is updated", func() {
+				var oldHash string
+				JustBeforeEach(func() {
+					deploy := &appsv1.Deployment{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+					Expect(err).ToNot(HaveOccurred())
+					oldHash = deploy.Spec.Template.Annotations["io.cryostat/secret-hash"]
+
+					secret := t.NewCoreEnvFromSecret()
+					err = t.Client.Get(context.Background(), types.NamespacedName{Name: secret.Name, Namespace: t.Namespace}, secret)
+					Expect(err).ToNot(HaveOccurred())
+					secret.Data["QUARKUS_LOG_LEVEL"] = []byte("TRACE")
+					err = t.Client.Update(context.Background(), secret)
+					Expect(err).ToNot(HaveOccurred())
+					t.reconcileCryostatFully()
+				})
+				It("should update the secret hash of the core deployment", func() {
+					deploy := &appsv1.Deployment{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(deploy.Spec.Template.Annotations["io.cryostat/secret-hash"]).ToNot(Equal(oldHash))
+				})
+			})
+		})
+		Context("with pod template overrides", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithPodTemplateOverrides().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should merge the overrides into the core deployment", func() {
+				deploy := &appsv1.Deployment{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+				Expect(err).ToNot(HaveOccurred())
+
+				containers := deploy.Spec.Template.Spec.Containers
+				Expect(containers[0].Name).To(Equal(t.Name))
+				Expect(containers[0].Image).To(HavePrefix("quay.io/cryostat/cryostat:"))
+				Expect(containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "QUARKUS_LOG_LEVEL", Value: "DEBUG"}))
+				Expect(containers).To(ContainElement(And(
+					HaveField("Name", "log-shipper"),
+					HaveField("Image", "registry.example.com/log-shipper:latest"),
+					HaveField("VolumeMounts", ConsistOf(corev1.VolumeMount{
+						Name: "log-shipper-config", MountPath: "/etc/log-shipper",
+					})),
+				)))
+				Expect(deploy.Spec.Template.Spec.Volumes).To(ContainElement(HaveField("Name", "log-shipper-config")))
+				Expect(deploy.Spec.Template.Spec.Volumes).To(ContainElement(HaveField("Name", "cert-secrets")))
+			})
+			It("should merge the overrides into the storage deployment", func() {
+				deploy := &appsv1.Deployment{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-storage", Namespace: t.Namespace}, deploy)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(deploy.Spec.Template.Annotations).To(HaveKeyWithValue("backup.example.com/exclude", "true"))
+				Expect(deploy.Spec.Template.Labels).To(HaveKeyWithValue("component", "storage"))
+				Expect(deploy.Spec.Template.Spec.Containers).To(HaveLen(1))
+				Expect(deploy.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "CRYOSTAT_STORAGE_DEBUG", Value: "true"}))
+			})
+			It("should set OverridesApplied condition", func() {
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeOverridesApplied, metav1.ConditionTrue, "OverridesApplied")
+			})
+			Context("that are removed", func() {
+				JustBeforeEach(func() {
+					cr := t.getCryostatInstance()
+					cr.Spec.Overrides = nil
+					t.updateCryostatInstance(cr)
+					t.reconcileCryostatFully()
+				})
+				It("should restore the core deployment", func() {
+					deploy := &appsv1.Deployment{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(deploy.Spec.Template.Spec.Containers).ToNot(ContainElement(HaveField("Name", "log-shipper")))
+					Expect(deploy.Spec.Template.Spec.Volumes).ToNot(ContainElement(HaveField("Name", "log-shipper-config")))
+				})
+				It("should remove OverridesApplied condition", func() {
+					t.checkConditionAbsent(operatorv1beta2.ConditionTypeOverridesApplied)
+				})
+			})
+		})
+		Context("with pod template overrides that conflict with the operator", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithConflictingPodTemplateOverrides().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should apply the overrides", func() {
+				t.expectDeploymentImages(t.Name, map[string]string{
+					t.Name: "mirror.example.com/cryostat/cryostat:custom",
+				})
+			})
+			It("should report the conflicts in the OverridesApplied condition", func() {
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeOverridesApplied, metav1.ConditionTrue, "OverridesConflict")
+				condition := meta.FindStatusCondition(t.getCryostatInstance().Status.Conditions,
+					string(operatorv1beta2.ConditionTypeOverridesApplied))
+				Expect(condition.Message).To(ContainSubstring(fmt.Sprintf("core: spec.containers[%s].image", t.Name)))
+			})
+		})
+		Context("with invalid pod template overrides", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithInvalidPodTemplateOverrides().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should not apply the overrides", func() {
+				deploy := &appsv1.Deployment{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-database", Namespace: t.Namespace}, deploy)
+				Expect(err).ToNot(HaveOccurred())
+				for _, container := range deploy.Spec.Template.Spec.Containers {
+					Expect(container.VolumeMounts).ToNot(ContainElement(HaveField("Name", "missing-volume")))
+				}
+			})
+			It("should set OverridesApplied condition to false", func() {
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeOverridesApplied, metav1.ConditionFalse, "OverridesInvalid")
+				condition := meta.FindStatusCondition(t.getCryostatInstance().Status.Conditions,
+					string(operatorv1beta2.ConditionTypeOverridesApplied))
+				Expect(condition.Message).To(ContainSubstring("mounts volume missing-volume, which does not exist"))
+			})
+		})
+		Context("when deleted", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostat().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			Context("ClusterRoleBinding exists", func() {
+				JustBeforeEach(func() {
+					t.reconcileDeletedCryostat()
+				})
+				It("should delete the ClusterRoleBinding", func() {
+					t.checkClusterRoleBindingDeleted()
+				})
+				It("should delete Cryostat", func() {
+					t.expectNoCryostat()
+				})
+			})
+			Context("ClusterRoleBinding does not exist", func() {
+				JustBeforeEach(func() {
+					err := t.Client.Delete(context.Background(), t.NewClusterRoleBinding())
+					Expect(err).ToNot(HaveOccurred())
+					t.reconcileDeletedCryostat()
+				})
+				It("should delete Cryostat", func() {
+					t.expectNoCryostat()
+				})
+			})
+			Context("RoleBinding exists", func() {
 				JustBeforeEach(func() {
 					t.reconcileDeletedCryostat()
 				})
 				It("should delete the RoleBinding", func() {
 					t.checkRoleBindingsDeleted()
 				})
+				It("should delete the operator's RoleBinding", func() {
+					expected := t.NewAPIClientRoleBinding()
+					binding := &rbacv1.RoleBinding{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, binding)
+					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+				})
 				It("should delete Cryostat", func() {
 					t.expectNoCryostat()
 				})
@@ -1306,6 +2638,146 @@ func (c *controllerTest) commonTests() {
 					t.expectNoCryostat()
 				})
 			})
+			It("should leave the PVCs to be garbage collected", func() {
+				t.reconcileDeletedCryostat()
+				t.expectNoCryostat()
+				for _, pvc := range []*corev1.PersistentVolumeClaim{t.NewDatabasePVC(), t.NewStoragePVC()} {
+					Expect(t.getPVC(pvc.Name).OwnerReferences).To(HaveLen(1))
+				}
+			})
+		})
+		Context("when deleted with the Retain PVC deletion policy", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithPVCDeletionPolicy(operatorv1beta2.PVCDeletionPolicyRetain).Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+				t.reconcileDeletedCryostat()
+			})
+			It("should delete Cryostat", func() {
+				t.expectNoCryostat()
+			})
+			It("should orphan the PVCs", func() {
+				for _, pvc := range []*corev1.PersistentVolumeClaim{t.NewDatabasePVC(), t.NewStoragePVC()} {
+					actual := t.getPVC(pvc.Name)
+					Expect(actual.OwnerReferences).To(BeEmpty())
+					Expect(actual.Annotations).To(HaveKeyWithValue("operator.cryostat.io/retained-by", t.Name))
+				}
+			})
+			It("should emit PersistentVolumeClaimRetained Events", func() {
+				recorder := t.controller.GetConfig().EventRecorder.(*record.FakeRecorder)
+				var eventMsg string
+				Expect(recorder.Events).To(Receive(&eventMsg))
+				Expect(eventMsg).To(ContainSubstring("PersistentVolumeClaimRetained"))
+				Expect(eventMsg).To(ContainSubstring(t.NewDatabasePVC().Name))
+				Expect(recorder.Events).To(Receive(&eventMsg))
+				Expect(eventMsg).To(ContainSubstring(t.NewStoragePVC().Name))
+			})
+			Context("then recreated", func() {
+				JustBeforeEach(func() {
+					err := t.Client.Create(context.Background(), t.NewCryostat().Object)
+					Expect(err).ToNot(HaveOccurred())
+					t.reconcileCryostatFully()
+				})
+				It("should adopt the retained PVCs", func() {
+					t.expectPVC(t.NewDatabasePVC())
+					t.expectPVC(t.NewStoragePVC())
+					for _, pvc := range []*corev1.PersistentVolumeClaim{t.NewDatabasePVC(), t.NewStoragePVC()} {
+						Expect(t.getPVC(pvc.Name).Annotations).ToNot(HaveKey("operator.cryostat.io/retained-by"))
+					}
+				})
+			})
+		})
+		Context("when deleted with the Snapshot PVC deletion policy", func() {
+			var result reconcile.Result
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithPVCDeletionPolicy(operatorv1beta2.PVCDeletionPolicySnapshot).Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+				cr := t.getCryostatInstance()
+				err := t.Client.Delete(context.Background(), cr.Object)
+				Expect(err).ToNot(HaveOccurred())
+				result, err = t.reconcile()
+				Expect(err).ToNot(HaveOccurred())
+			})
+			It("should create VolumeSnapshots", func() {
+				snapshots := t.getVolumeSnapshots()
+				Expect(snapshots).To(HaveLen(2))
+				for _, snapshot := range snapshots {
+					Expect(snapshot.GetOwnerReferences()).To(BeEmpty())
+					source, _, err := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName")
+					Expect(err).ToNot(HaveOccurred())
+					Expect(snapshot.GetName()).To(HavePrefix(source + "-"))
+					className, found, err := unstructured.NestedString(snapshot.Object, "spec", "volumeSnapshotClassName")
+					Expect(err).ToNot(HaveOccurred())
+					if source == t.NewDatabasePVC().Name {
+						Expect(className).To(Equal("my-snapshot-class"))
+					} else {
+						Expect(found).To(BeFalse())
+					}
+				}
+			})
+			It("should wait for the VolumeSnapshots", func() {
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: 10 * time.Second}))
+				cr := t.getCryostatInstance()
+				Expect(controllerutil.ContainsFinalizer(cr.Object, "operator.cryostat.io/cryostat.finalizer")).To(BeTrue())
+			})
+			Context("when the VolumeSnapshots are ready", func() {
+				JustBeforeEach(func() {
+					t.setVolumeSnapshotStatus(map[string]interface{}{"readyToUse": true})
+					t.reconcileCryostatFully()
+				})
+				It("should delete Cryostat", func() {
+					t.expectNoCryostat()
+				})
+				It("should leave the PVCs to be garbage collected", func() {
+					for _, pvc := range []*corev1.PersistentVolumeClaim{t.NewDatabasePVC(), t.NewStoragePVC()} {
+						Expect(t.getPVC(pvc.Name).OwnerReferences).To(HaveLen(1))
+					}
+				})
+			})
+			Context("when the VolumeSnapshots fail", func() {
+				JustBeforeEach(func() {
+					t.setVolumeSnapshotStatus(map[string]interface{}{
+						"error": map[string]interface{}{"message": "snapshot failed"},
+					})
+					t.reconcileCryostatFully()
+				})
+				It("should delete Cryostat", func() {
+					t.expectNoCryostat()
+				})
+				It("should retain the PVCs", func() {
+					for _, pvc := range []*corev1.PersistentVolumeClaim{t.NewDatabasePVC(), t.NewStoragePVC()} {
+						Expect(t.getPVC(pvc.Name).OwnerReferences).To(BeEmpty())
+					}
+				})
+			})
+		})
+		Context("when deleted with the Snapshot PVC deletion policy without the VolumeSnapshot API", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithPVCDeletionPolicy(operatorv1beta2.PVCDeletionPolicySnapshot).Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+				t.controller.GetConfig().RESTMapper = meta.NewDefaultRESTMapper([]schema.GroupVersion{})
+				t.reconcileDeletedCryostat()
+			})
+			It("should delete Cryostat", func() {
+				t.expectNoCryostat()
+			})
+			It("should retain the PVCs", func() {
+				Expect(t.getVolumeSnapshots()).To(BeEmpty())
+				for _, pvc := range []*corev1.PersistentVolumeClaim{t.NewDatabasePVC(), t.NewStoragePVC()} {
+					Expect(t.getPVC(pvc.Name).OwnerReferences).To(BeEmpty())
+				}
+			})
+			It("should emit a VolumeSnapshotUnavailable Event", func() {
+				recorder := t.controller.GetConfig().EventRecorder.(*record.FakeRecorder)
+				var eventMsg string
+				Expect(recorder.Events).To(Receive(&eventMsg))
+				Expect(eventMsg).To(ContainSubstring("VolumeSnapshotUnavailable"))
+			})
 		})
 		Context("on OpenShift", func() {
 			BeforeEach(func() {
@@ -1362,84 +2834,276 @@ func (c *controllerTest) commonTests() {
 						t.NewCryostat().Object, t.NewNamespaceWithSCCSupGroups(), t.NewApiServer(),
 					}
 				})
-				It("should set fsGroup to value derived from namespace", func() {
-					deploy := &appsv1.Deployment{}
-					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+				It("should set fsGroup to value derived from namespace", func() {
+					deploy := &appsv1.Deployment{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+					Expect(err).ToNot(HaveOccurred())
+					sc := deploy.Spec.Template.Spec.SecurityContext
+					Expect(sc).ToNot(BeNil())
+					Expect(sc.FSGroup).ToNot(BeNil())
+					Expect(*sc.FSGroup).To(Equal(int64(1000130000)))
+				})
+			})
+			Context("when deleted", func() {
+				Context("ConsoleLink exists", func() {
+					JustBeforeEach(func() {
+						t.reconcileDeletedCryostat()
+					})
+					It("should delete the ConsoleLink", func() {
+						link := &consolev1.ConsoleLink{}
+						expectedLink := t.NewConsoleLink()
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: expectedLink.Name}, link)
+						Expect(kerrors.IsNotFound(err)).To(BeTrue())
+					})
+					It("should remove the application url from APIServer AdditionalCORSAllowedOrigins", func() {
+						apiServer := &configv1.APIServer{}
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cluster"}, apiServer)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(apiServer.Spec.AdditionalCORSAllowedOrigins).ToNot(ContainElement(fmt.Sprintf("https://%s\\.example\\.com", t.Name)))
+						Expect(apiServer.Spec.AdditionalCORSAllowedOrigins).To(ContainElement("https://an-existing-user-specified\\.allowed\\.origin\\.com"))
+					})
+					It("should delete Cryostat", func() {
+						t.expectNoCryostat()
+					})
+				})
+				Context("ConsoleLink does not exist", func() {
+					JustBeforeEach(func() {
+						err := t.Client.Delete(context.Background(), t.NewConsoleLink())
+						Expect(err).ToNot(HaveOccurred())
+						t.reconcileDeletedCryostat()
+					})
+					It("should delete Cryostat", func() {
+						t.expectNoCryostat()
+					})
+				})
+			})
+			Context("with Insights enabled", func() {
+				BeforeEach(func() {
+					t.InsightsURL = "http://insights-proxy.foo.svc.cluster.local"
+				})
+				JustBeforeEach(func() {
+					t.reconcileCryostatFully()
+				})
+				It("should create deployment", func() {
+					t.expectMainDeployment()
+				})
+			})
+		})
+		Context("with cert-manager disabled in CR", func() {
+			BeforeEach(func() {
+				t.TLS = false
+				t.objs = append(t.objs, t.NewCryostatCertManagerDisabled().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should create deployment and set owner", func() {
+				t.expectMainDeployment()
+			})
+			It("should not create certificates", func() {
+				certs := &certv1.CertificateList{}
+				t.Client.List(context.Background(), certs, &ctrlclient.ListOptions{
+					Namespace: t.Namespace,
+				})
+				Expect(certs.Items).To(BeEmpty())
+			})
+			It("should create routes with edge TLS termination", func() {
+				t.expectRoutes()
+			})
+			It("should create the agent proxy config map", func() {
+				t.expectAgentProxyConfigMap()
+			})
+			It("should not report certificates in CR Status", func() {
+				cr := t.getCryostatInstance()
+				Expect(cr.Status.Certificates).To(BeEmpty())
+				t.checkConditionAbsent(operatorv1beta2.ConditionTypeCertificatesExpiringSoon)
+			})
+		})
+		Context("with a certificate close to expiring", func() {
+			var notAfter metav1.Time
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostat().Object)
+				notAfter = metav1.NewTime(time.Now().Add(24 * time.Hour).Truncate(time.Second))
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+
+				// Simulate cert-manager failing to renew the CA certificate
+				cert := &certv1.Certificate{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.NewCACert().Name, Namespace: t.Namespace}, cert)
+				Expect(err).ToNot(HaveOccurred())
+				cert.Status.NotAfter = &notAfter
+				err = t.Client.Status().Update(context.Background(), cert)
+				Expect(err).ToNot(HaveOccurred())
+
+				t.reconcileCryostatFully()
+			})
+			It("should report the expiry in CR Status", func() {
+				expected := t.NewCertificateStatuses()
+				expected[0].NotAfter = &notAfter
+				cr := t.getCryostatInstance()
+				Expect(cr.Status.Certificates).To(ConsistOf(expected))
+			})
+			It("should set CertificatesExpiringSoon condition", func() {
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeCertificatesExpiringSoon, metav1.ConditionTrue,
+					"CertificatesExpiringSoon")
+			})
+			It("should emit a CertificatesExpiringSoon Event", func() {
+				recorder := t.controller.GetConfig().EventRecorder.(*record.FakeRecorder)
+				var eventMsg string
+				Expect(recorder.Events).To(Receive(&eventMsg))
+				Expect(eventMsg).To(ContainSubstring("CertificatesExpiringSoon"))
+				Expect(eventMsg).To(ContainSubstring(t.NewCACert().Name))
+			})
+			It("should not emit the Event again while the certificate is unchanged", func() {
+				recorder := t.controller.GetConfig().EventRecorder.(*record.FakeRecorder)
+				Expect(recorder.Events).To(Receive())
+				t.reconcileCryostatFully()
+				Expect(recorder.Events).ToNot(Receive(ContainSubstring("CertificatesExpiringSoon")))
+			})
+			Context("then renewed", func() {
+				JustBeforeEach(func() {
+					cert := &certv1.Certificate{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.NewCACert().Name, Namespace: t.Namespace}, cert)
+					Expect(err).ToNot(HaveOccurred())
+					cert.Status.NotAfter = &metav1.Time{Time: test.CertNotAfter}
+					err = t.Client.Status().Update(context.Background(), cert)
+					Expect(err).ToNot(HaveOccurred())
+
+					t.reconcileCryostatFully()
+				})
+				It("should update CR Status", func() {
+					t.expectStatusCertificates()
+				})
+				It("should set CertificatesExpiringSoon condition", func() {
+					t.checkConditionPresent(operatorv1beta2.ConditionTypeCertificatesExpiringSoon, metav1.ConditionFalse,
+						"NoCertificatesExpiringSoon")
+				})
+				It("should requeue for when the certificates are next close to expiring", func() {
+					result, err := t.reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.RequeueAfter).To(BeNumerically("~", time.Until(test.CertNotAfter.Add(-7*24*time.Hour)), time.Minute))
+				})
+			})
+		})
+		Context("with a CA rotation requested", func() {
+			var overlap time.Duration
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostat().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+
+				cr := t.getCryostatInstance()
+				cr.Spec.CARotation = t.NewCryostatWithCARotation("r2", overlap).Spec.CARotation
+				t.updateCryostatInstance(cr)
+			})
+			Context("within the overlap period", func() {
+				var result reconcile.Result
+				BeforeEach(func() {
+					overlap = time.Hour
+				})
+				JustBeforeEach(func() {
+					result = t.reconcileCryostatUntilRequeueAfter()
+				})
+				It("should create the new CA certificate", func() {
+					t.checkCertificate(t.NewCACertForRevision("r2"))
+					t.checkCertificate(t.NewCACert())
+				})
+				It("should continue issuing from the current CA", func() {
+					issuer := &certv1.Issuer{}
+					expected := t.NewCryostatCAIssuer()
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, issuer)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(issuer.Spec).To(Equal(expected.Spec))
+				})
+				It("should distribute a bundle of both CAs", func() {
+					secret := &corev1.Secret{}
+					expected := t.NewCABundleSecret()
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, secret)
 					Expect(err).ToNot(HaveOccurred())
-					sc := deploy.Spec.Template.Spec.SecurityContext
-					Expect(sc).ToNot(BeNil())
-					Expect(sc.FSGroup).ToNot(BeNil())
-					Expect(*sc.FSGroup).To(Equal(int64(1000130000)))
+					Expect(string(secret.Data["ca.crt"])).To(Equal(t.Name + "-ca-bytes\n" + t.Name + "-ca-r2-bytes"))
 				})
-			})
-			Context("when deleted", func() {
-				Context("ConsoleLink exists", func() {
-					JustBeforeEach(func() {
-						t.reconcileDeletedCryostat()
-					})
-					It("should delete the ConsoleLink", func() {
-						link := &consolev1.ConsoleLink{}
-						expectedLink := t.NewConsoleLink()
-						err := t.Client.Get(context.Background(), types.NamespacedName{Name: expectedLink.Name}, link)
-						Expect(kerrors.IsNotFound(err)).To(BeTrue())
-					})
-					It("should remove the application url from APIServer AdditionalCORSAllowedOrigins", func() {
-						apiServer := &configv1.APIServer{}
-						err := t.Client.Get(context.Background(), types.NamespacedName{Name: "cluster"}, apiServer)
-						Expect(err).ToNot(HaveOccurred())
-						Expect(apiServer.Spec.AdditionalCORSAllowedOrigins).ToNot(ContainElement(fmt.Sprintf("https://%s\\.example\\.com", t.Name)))
-						Expect(apiServer.Spec.AdditionalCORSAllowedOrigins).To(ContainElement("https://an-existing-user-specified\\.allowed\\.origin\\.com"))
-					})
-					It("should delete Cryostat", func() {
-						t.expectNoCryostat()
-					})
+				It("should report the rotation in CR Status", func() {
+					cr := t.getCryostatInstance()
+					Expect(cr.Status.CARotation).ToNot(BeNil())
+					Expect(cr.Status.CARotation.ActiveRevision).To(BeEmpty())
+					Expect(cr.Status.CARotation.PendingRevision).To(Equal(&[]string{"r2"}[0]))
+					Expect(cr.Status.CARotation.PreviousRevision).To(BeNil())
+					Expect(cr.Status.CARotation.StageStartTime).ToNot(BeNil())
+					t.checkConditionPresent(operatorv1beta2.ConditionTypeCARotationProgressing, metav1.ConditionTrue,
+						"DistributingTrustBundle")
 				})
-				Context("ConsoleLink does not exist", func() {
-					JustBeforeEach(func() {
-						err := t.Client.Delete(context.Background(), t.NewConsoleLink())
-						Expect(err).ToNot(HaveOccurred())
-						t.reconcileDeletedCryostat()
-					})
-					It("should delete Cryostat", func() {
-						t.expectNoCryostat()
-					})
+				It("should requeue at the end of the overlap period", func() {
+					Expect(result.RequeueAfter).To(BeNumerically("~", overlap, time.Minute))
 				})
 			})
-			Context("with Insights enabled", func() {
+			Context("with no overlap period", func() {
 				BeforeEach(func() {
-					t.InsightsURL = "http://insights-proxy.foo.svc.cluster.local"
+					overlap = 0
 				})
 				JustBeforeEach(func() {
 					t.reconcileCryostatFully()
 				})
-				It("should create deployment", func() {
-					t.expectMainDeployment()
+				It("should issue certificates from the new CA", func() {
+					issuer := &certv1.Issuer{}
+					expected := t.NewCryostatCAIssuerForRevision("r2")
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, issuer)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(issuer.Spec).To(Equal(expected.Spec))
+				})
+				It("should delete the previous CA", func() {
+					expected := t.NewCACert()
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, &certv1.Certificate{})
+					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+					err = t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Spec.SecretName, Namespace: expected.Namespace}, &corev1.Secret{})
+					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+				})
+				It("should only distribute the new CA", func() {
+					secret := &corev1.Secret{}
+					expected := t.NewCABundleSecret()
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, secret)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(string(secret.Data["ca.crt"])).To(Equal(t.Name + "-ca-r2-bytes"))
+				})
+				It("should report the completed rotation in CR Status", func() {
+					cr := t.getCryostatInstance()
+					Expect(cr.Status.CARotation).To(Equal(&operatorv1beta2.CARotationStatus{ActiveRevision: "r2"}))
+					t.checkConditionPresent(operatorv1beta2.ConditionTypeCARotationProgressing, metav1.ConditionFalse,
+						"RotationComplete")
+				})
+				It("should emit CARotation Events", func() {
+					recorder := t.controller.GetConfig().EventRecorder.(*record.FakeRecorder)
+					var eventMsg string
+					Expect(recorder.Events).To(Receive(&eventMsg))
+					Expect(eventMsg).To(ContainSubstring("Distributing trust bundle"))
+					Expect(recorder.Events).To(Receive(&eventMsg))
+					Expect(eventMsg).To(ContainSubstring("Switching certificate issuance"))
+					Expect(recorder.Events).To(Receive(&eventMsg))
+					Expect(eventMsg).To(ContainSubstring("Removed CA revision"))
 				})
 			})
 		})
-		Context("with cert-manager disabled in CR", func() {
+		Context("with a CA revision on a new installation", func() {
 			BeforeEach(func() {
-				t.TLS = false
-				t.objs = append(t.objs, t.NewCryostatCertManagerDisabled().Object)
+				t.objs = append(t.objs, t.NewCryostatWithCARotation("r1", time.Hour).Object)
 			})
 			JustBeforeEach(func() {
 				t.reconcileCryostatFully()
 			})
-			It("should create deployment and set owner", func() {
-				t.expectMainDeployment()
-			})
-			It("should not create certificates", func() {
-				certs := &certv1.CertificateList{}
-				t.Client.List(context.Background(), certs, &ctrlclient.ListOptions{
-					Namespace: t.Namespace,
-				})
-				Expect(certs.Items).To(BeEmpty())
-			})
-			It("should create routes with edge TLS termination", func() {
-				t.expectRoutes()
+			It("should issue certificates from the CA revision", func() {
+				t.checkCertificate(t.NewCACertForRevision("r1"))
+				issuer := &certv1.Issuer{}
+				expected := t.NewCryostatCAIssuerForRevision("r1")
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, issuer)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(issuer.Spec).To(Equal(expected.Spec))
 			})
-			It("should create the agent proxy config map", func() {
-				t.expectAgentProxyConfigMap()
+			It("should not start a rotation", func() {
+				cr := t.getCryostatInstance()
+				Expect(cr.Status.CARotation).To(Equal(&operatorv1beta2.CARotationStatus{ActiveRevision: "r1"}))
+				t.checkConditionPresent(operatorv1beta2.ConditionTypeCARotationProgressing, metav1.ConditionFalse,
+					"RotationComplete")
 			})
 		})
 		Context("with cert-manager not configured in CR", func() {
@@ -1725,6 +3389,11 @@ func (c *controllerTest) commonTests() {
 				It("should create expected deployment", func() {
 					t.expectMainDeployment()
 				})
+				It("should allow more time for the core probes", func() {
+					container := t.getCoreContainer()
+					Expect(container.LivenessProbe.TimeoutSeconds).To(Equal(int32(4)))
+					Expect(container.StartupProbe.FailureThreshold).To(Equal(int32(72)))
+				})
 			})
 		})
 		Context("with network options", func() {
@@ -1761,6 +3430,105 @@ func (c *controllerTest) commonTests() {
 					})
 				})
 			})
+			Context("containing agent gateway config", func() {
+				var cr *model.CryostatInstance
+				BeforeEach(func() {
+					cr = t.NewCryostatWithAgentGatewayNetwork()
+					t.objs = append(t.objs, cr.Object)
+				})
+				It("should create a passthrough route for the agent gateway", func() {
+					t.checkRoute(t.NewAgentGatewayRoute())
+				})
+				It("should add the external host to the agent proxy certificate", func() {
+					t.checkCertificate(t.NewAgentProxyCertWithExternalHosts([]string{"agent.example.com"}, nil))
+				})
+				It("should create agent client certificates", func() {
+					t.checkCertificate(t.NewAgentClientCert("external-app"))
+					t.expectCertSecretOwned(t.NewAgentClientCert("external-app"))
+				})
+				It("should allow ingress to the agent gateway from the router", func() {
+					t.checkNetworkPolicy(t.NewCryostatNetworkPolicyWithAgentGateway(
+						netv1.NetworkPolicyPeer{
+							NamespaceSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									"policy-group.network.openshift.io/ingress": "",
+								},
+							},
+						}))
+				})
+				It("should not create a load balancer", func() {
+					t.expectNoService(t.NewAgentGatewayLoadBalancerService().Name)
+				})
+				Context("with a load balancer", func() {
+					BeforeEach(func() {
+						cr.Spec.NetworkOptions = t.NewCryostatWithAgentGatewayLoadBalancer().Spec.NetworkOptions
+					})
+					It("should create a load balancer service", func() {
+						t.checkService(t.NewAgentGatewayLoadBalancerService())
+					})
+					It("should allow ingress to the agent gateway from any source", func() {
+						t.checkNetworkPolicy(t.NewCryostatNetworkPolicyWithAgentGateway())
+					})
+					Context("when addresses are assigned", func() {
+						JustBeforeEach(func() {
+							svc := &corev1.Service{}
+							expected := t.NewAgentGatewayLoadBalancerService()
+							err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, svc)
+							Expect(err).ToNot(HaveOccurred())
+							svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{
+								{
+									Hostname: "lb.example.com",
+								},
+								{
+									IP: "192.0.2.20",
+								},
+							}
+							err = t.Client.Status().Update(context.Background(), svc)
+							Expect(err).ToNot(HaveOccurred())
+
+							t.reconcileCryostatFully()
+						})
+						It("should add the addresses to the agent proxy certificate", func() {
+							t.checkCertificate(t.NewAgentProxyCertWithExternalHosts(
+								[]string{"agent.example.com", "lb.example.com"}, []string{"192.0.2.20"}))
+						})
+					})
+				})
+				Context("with cert-manager disabled", func() {
+					BeforeEach(func() {
+						disable := false
+						cr.Spec.EnableCertManager = &disable
+						t.TLS = false
+					})
+					It("should create an edge-terminated route for the agent gateway", func() {
+						t.checkRoute(t.NewAgentGatewayRoute())
+					})
+				})
+				Context("after removing the config", func() {
+					JustBeforeEach(func() {
+						cr := t.getCryostatInstance()
+						cr.Spec.NetworkOptions = nil
+						t.updateCryostatInstance(cr)
+
+						t.reconcileCryostatFully()
+					})
+					It("should delete the agent gateway route", func() {
+						route := &openshiftv1.Route{}
+						expected := t.NewAgentGatewayRoute()
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, route)
+						Expect(kerrors.IsNotFound(err)).To(BeTrue())
+					})
+					It("should delete agent client certificates", func() {
+						cert := &certv1.Certificate{}
+						expected := t.NewAgentClientCert("external-app")
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, cert)
+						Expect(kerrors.IsNotFound(err)).To(BeTrue())
+					})
+					It("should remove the external host from the agent proxy certificate", func() {
+						t.checkCertificate(t.NewAgentProxyCert())
+					})
+				})
+			})
 		})
 		Context("with security options", func() {
 			JustBeforeEach(func() {
@@ -1796,6 +3564,77 @@ func (c *controllerTest) commonTests() {
 
 			})
 		})
+		Context("with a TLS profile", func() {
+			BeforeEach(func() {
+				t.TLSProfile = operatorv1beta2.TLSProfileModern
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			Context("using a predefined type", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, t.NewCryostatWithTLSProfile(operatorv1beta2.TLSProfileModern).Object)
+				})
+				It("should configure the main deployment", func() {
+					t.expectMainDeployment()
+				})
+				It("should configure the database deployment", func() {
+					t.expectDatabaseDeployment()
+				})
+				It("should configure the storage deployment", func() {
+					t.expectStorageDeployment()
+				})
+				It("should configure the agent proxy config map", func() {
+					t.expectAgentProxyConfigMap()
+				})
+			})
+			Context("using a custom profile", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, t.NewCryostatWithCustomTLSProfile().Object)
+				})
+				It("should configure the main deployment", func() {
+					t.expectMainDeployment()
+				})
+				It("should configure the database deployment", func() {
+					t.expectDatabaseDeployment()
+				})
+				It("should configure the agent proxy config map", func() {
+					t.expectAgentProxyConfigMap()
+				})
+			})
+			Context("inherited from the API server", func() {
+				BeforeEach(func() {
+					t.objs = []ctrlclient.Object{
+						t.NewNamespace(), t.NewApiServerWithTLSProfile(), t.NewCryostatWithInheritedTLSProfile().Object,
+					}
+				})
+				It("should configure the main deployment", func() {
+					t.expectMainDeployment()
+				})
+				It("should configure the storage deployment", func() {
+					t.expectStorageDeployment()
+				})
+				It("should configure the agent proxy config map", func() {
+					t.expectAgentProxyConfigMap()
+				})
+			})
+			Context("with a type overriding the API server", func() {
+				BeforeEach(func() {
+					t.TLSProfile = operatorv1beta2.TLSProfileIntermediate
+					cr := t.NewCryostatWithInheritedTLSProfile()
+					cr.Spec.SecurityOptions.TLSProfile.Type = &[]operatorv1beta2.TLSProfileType{operatorv1beta2.TLSProfileIntermediate}[0]
+					t.objs = []ctrlclient.Object{
+						t.NewNamespace(), t.NewApiServerWithTLSProfile(), cr.Object,
+					}
+				})
+				It("should configure the main deployment", func() {
+					t.expectMainDeployment()
+				})
+				It("should configure the agent proxy config map", func() {
+					t.expectAgentProxyConfigMap()
+				})
+			})
+		})
 		Context("with Scheduling options", func() {
 			BeforeEach(func() {
 				t.objs = append(t.objs, t.NewCryostatWithScheduling().Object)
@@ -1806,7 +3645,23 @@ func (c *controllerTest) commonTests() {
 			It("should configure deployment appropriately", func() {
 				t.expectMainDeployment()
 			})
-
+			It("should use the same options for the database and storage", func() {
+				t.expectDatabaseDeployment()
+				t.expectStorageDeployment()
+			})
+		})
+		Context("with Scheduling options for each component", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithComponentScheduling().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should configure each deployment appropriately", func() {
+				t.expectMainDeployment()
+				t.expectDatabaseDeployment()
+				t.expectStorageDeployment()
+			})
 		})
 		Context("with target discovery options provided", func() {
 			JustBeforeEach(func() {
@@ -2109,6 +3964,26 @@ func (c *controllerTest) commonTests() {
 					t.expectTargetNamespaces()
 				})
 
+				It("should report the CA certificate copies in Status", func() {
+					t.expectStatusCertificates()
+				})
+
+				Context("with an out-of-date CA certificate copy", func() {
+					JustBeforeEach(func() {
+						secret := t.NewCACertSecret(targetNamespaces[0])
+						err := t.Client.Get(context.Background(), types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, secret)
+						Expect(err).ToNot(HaveOccurred())
+						secret.Data[corev1.TLSCertKey] = []byte("old CA")
+						err = t.Client.Update(context.Background(), secret)
+						Expect(err).ToNot(HaveOccurred())
+
+						t.reconcileCryostatFully()
+					})
+					It("should resynchronize the copy", func() {
+						t.expectCertificates()
+					})
+				})
+
 				Context("when deleted", func() {
 					Context("RoleBindings exist", func() {
 						JustBeforeEach(func() {
@@ -2257,6 +4132,47 @@ func (c *controllerTest) commonTests() {
 		JustAfterEach(func() {
 			c.commonJustAfterEach(t)
 		})
+		Context("with Grafana disabled", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithGrafanaDisabled().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should not proxy requests to Grafana", func() {
+				cm := &corev1.ConfigMap{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-oauth2-proxy-cfg", Namespace: t.Namespace}, cm)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cm.Data["alpha_config.json"]).ToNot(ContainSubstring("/grafana/"))
+				Expect(cm.Data["alpha_config.json"]).To(ContainSubstring("/storage/"))
+			})
+		})
+		Context("with Grafana in a separate deployment", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithSeparateGrafana().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should proxy requests to the Grafana service", func() {
+				cm := &corev1.ConfigMap{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-oauth2-proxy-cfg", Namespace: t.Namespace}, cm)
+				Expect(err).ToNot(HaveOccurred())
+				config := map[string]interface{}{}
+				err = json.Unmarshal([]byte(cm.Data["alpha_config.json"]), &config)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(config["upstreamConfig"]).To(HaveKeyWithValue("upstreams", ContainElement(And(
+					HaveKeyWithValue("id", "grafana"),
+					HaveKeyWithValue("uri", "https://"+t.Name+"-grafana:3000"),
+				))))
+			})
+			It("should trust the Cryostat CA in the auth proxy", func() {
+				container := t.getMainDeploymentContainer(t.Name + "-auth-proxy")
+				Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: "SSL_CERT_DIR",
+					Value: "/var/run/secrets/operator.cryostat.io/" + t.Name + "-ca-bundle"}))
+				Expect(container.VolumeMounts).To(ContainElement(HaveField("Name", "ca-bundle")))
+			})
+		})
 		Context("with TLS ingress", func() {
 			BeforeEach(func() {
 				t.objs = append(t.objs, t.NewCryostatWithIngress().Object)
@@ -2345,23 +4261,136 @@ func (c *controllerTest) commonTests() {
 				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, ingress)
 				Expect(kerrors.IsNotFound(err)).To(BeTrue())
 			})
-		})
-		Context("ingressSpec for one of the services is nil", func() {
-			var cr *model.CryostatInstance
-			BeforeEach(func() {
-				cr = t.NewCryostatWithIngress()
-				t.objs = append(t.objs, cr.Object)
+		})
+		Context("ingressSpec for one of the services is nil", func() {
+			var cr *model.CryostatInstance
+			BeforeEach(func() {
+				cr = t.NewCryostatWithIngress()
+				t.objs = append(t.objs, cr.Object)
+			})
+			It("should only create specified ingresses", func() {
+				c := t.getCryostatInstance()
+				c.Spec.NetworkOptions.CoreConfig.IngressSpec = nil
+				t.updateCryostatInstance(c)
+
+				t.reconcileCryostatFully()
+
+				ingress := &netv1.Ingress{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, ingress)
+				Expect(kerrors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+		Context("with agent gateway Ingress", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithAgentGatewayIngress().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should create the agent gateway ingress", func() {
+				t.checkIngress(t.NewAgentGatewayIngress())
+			})
+			It("should add the ingress hosts to the agent proxy certificate", func() {
+				t.checkCertificate(t.NewAgentProxyCertWithExternalHosts([]string{"agent.example.com"}, nil))
+			})
+			It("should allow ingress to the agent gateway from any source", func() {
+				t.checkNetworkPolicy(t.NewCryostatNetworkPolicyWithAgentGateway())
+			})
+			It("should not create routes", func() {
+				t.expectNoRoutes()
+			})
+			Context("after removing the ingress spec", func() {
+				JustBeforeEach(func() {
+					cr := t.getCryostatInstance()
+					cr.Spec.NetworkOptions.AgentGatewayConfig.IngressSpec = nil
+					t.updateCryostatInstance(cr)
+
+					t.reconcileCryostatFully()
+				})
+				It("should delete the agent gateway ingress", func() {
+					ingress := &netv1.Ingress{}
+					expected := t.NewAgentGatewayIngress()
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, ingress)
+					Expect(kerrors.IsNotFound(err)).To(BeTrue())
+				})
+			})
+		})
+		Context("with HTTPRoute", func() {
+			BeforeEach(func() {
+				t.GatewayAPIInstalled = true
+				t.BackendTLSPolicyInstalled = true
+				t.objs = append(t.objs, t.NewCryostatWithHTTPRoute().Object, t.NewGateway())
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should create HTTPRoute", func() {
+				t.expectHTTPRoute()
+			})
+			It("should create BackendTLSPolicy", func() {
+				t.expectBackendTLSPolicy()
+			})
+			It("should not create ingresses", func() {
+				t.expectNoIngresses()
+			})
+			It("should set ApplicationURL in CR Status", func() {
+				t.expectStatusApplicationURL()
+			})
+			Context("with BackendTLSPolicy disabled", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs[:len(t.objs)-2], t.NewCryostatWithHTTPRouteBackendTLSPolicyDisabled().Object,
+						t.NewGateway())
+				})
+				It("should create HTTPRoute", func() {
+					t.expectHTTPRoute()
+				})
+				It("should not create BackendTLSPolicy", func() {
+					t.expectNoBackendTLSPolicy()
+				})
+			})
+			Context("with BackendTLSPolicy API missing", func() {
+				BeforeEach(func() {
+					t.BackendTLSPolicyInstalled = false
+				})
+				It("should create HTTPRoute", func() {
+					t.expectHTTPRoute()
+				})
+				It("should not create BackendTLSPolicy", func() {
+					t.expectNoBackendTLSPolicy()
+				})
+			})
+			Context("without hostnames", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs[:len(t.objs)-2], t.NewCryostatWithHTTPRouteNoHostnames().Object,
+						t.NewGatewayWithAddress())
+				})
+				It("should use the Gateway address and listener port", func() {
+					instance := t.getCryostatInstance()
+					Expect(instance.Status.ApplicationURL).To(Equal("https://192.0.2.10:8443"))
+				})
 			})
-			It("should only create specified ingresses", func() {
-				c := t.getCryostatInstance()
-				c.Spec.NetworkOptions.CoreConfig.IngressSpec = nil
-				t.updateCryostatInstance(c)
-
-				t.reconcileCryostatFully()
-
-				ingress := &netv1.Ingress{}
-				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, ingress)
-				Expect(kerrors.IsNotFound(err)).To(BeTrue())
+			Context("and Gateway API missing", func() {
+				BeforeEach(func() {
+					t.GatewayAPIInstalled = false
+					t.BackendTLSPolicyInstalled = false
+				})
+				It("should not create HTTPRoute", func() {
+					t.expectNoHTTPRoute()
+				})
+			})
+			Context("then removed from the spec", func() {
+				JustBeforeEach(func() {
+					cr := t.getCryostatInstance()
+					cr.Spec.NetworkOptions = nil
+					t.updateCryostatInstance(cr)
+					t.reconcileCryostatFully()
+				})
+				It("should delete HTTPRoute", func() {
+					t.expectNoHTTPRoute()
+				})
+				It("should delete BackendTLSPolicy", func() {
+					t.expectNoBackendTLSPolicy()
+				})
 			})
 		})
 		Context("with OAuth2 proxy", func() {
@@ -2412,6 +4441,34 @@ func (c *controllerTest) commonTests() {
 				t.checkNetworkPolicy(t.NewReportsNetworkPolicy())
 			})
 		})
+		Context("with a TLS profile", func() {
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			Context("using a predefined type", func() {
+				BeforeEach(func() {
+					t.TLSProfile = operatorv1beta2.TLSProfileModern
+					cr := t.NewCryostatWithIngress()
+					cr.Spec.SecurityOptions = t.NewCryostatWithTLSProfile(operatorv1beta2.TLSProfileModern).Spec.SecurityOptions
+					t.objs = append(t.objs, cr.Object)
+				})
+				It("should configure the main deployment", func() {
+					t.expectMainDeployment()
+				})
+				It("should configure the OAuth2 config map", func() {
+					t.expectOAuth2ConfigMap()
+				})
+			})
+			Context("inherited from the API server", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, t.NewCryostatWithInheritedTLSProfile().Object)
+				})
+				It("should use the default profile", func() {
+					t.expectMainDeployment()
+					t.expectOAuth2ConfigMap()
+				})
+			})
+		})
 		Context("with security options", func() {
 			JustBeforeEach(func() {
 				t.reconcileCryostatFully()
@@ -2573,6 +4630,66 @@ func (c *controllerTest) commonTests() {
 				t.expectReportsDeploymentHasExtraMetadata()
 			})
 		})
+		Context("with label and annotation for specific components", func() {
+			BeforeEach(func() {
+				t.ReportReplicas = 1
+				t.objs = append(t.objs, t.NewCryostatWithComponentMetadata().Object)
+			})
+			JustBeforeEach(func() {
+				t.reconcileCryostatFully()
+			})
+			It("should add the common label and annotation to other components", func() {
+				t.expectMainDeploymentHasExtraMetadata()
+				t.expectReportsDeploymentHasExtraMetadata()
+			})
+			It("should prefer the component's label and annotation", func() {
+				deployment := &appsv1.Deployment{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-database", Namespace: t.Namespace}, deployment)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(deployment.Labels).To(Equal(map[string]string{
+					"app":                          t.Name,
+					"kind":                         "cryostat",
+					"component":                    "database",
+					"app.kubernetes.io/name":       "cryostat-database",
+					"myDeploymentExtraLabel":       "myDatabaseDeploymentLabel",
+					"mySecondDeploymentExtraLabel": "mySecondDeploymentLabel",
+					"myDatabaseLabel":              "database",
+				}))
+				Expect(deployment.Spec.Template.Annotations).To(HaveKeyWithValue("myPodExtraAnnotation", "myDatabasePodAnnotation"))
+				Expect(deployment.Spec.Template.Annotations).To(HaveKeyWithValue("mySecondPodExtraAnnotation", "mySecondPodAnnotation"))
+
+				deployment = &appsv1.Deployment{}
+				err = t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-storage", Namespace: t.Namespace}, deployment)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(deployment.Spec.Template.Annotations).To(HaveKeyWithValue("sidecar.istio.io/inject", "false"))
+				Expect(deployment.Spec.Template.Annotations).To(HaveKeyWithValue("myPodExtraAnnotation", "myPodAnnotation"))
+			})
+			It("should add the resource label and annotation to secrets, config maps and PVCs", func() {
+				objs := []ctrlclient.Object{}
+				for _, name := range []string{t.Name + "-oauth2-cookie", t.Name + "-db", t.Name + "-storage"} {
+					secret := &corev1.Secret{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: t.Namespace}, secret)
+					Expect(err).ToNot(HaveOccurred())
+					objs = append(objs, secret)
+				}
+				cm := &corev1.ConfigMap{}
+				err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-lock", Namespace: t.Namespace}, cm)
+				Expect(err).ToNot(HaveOccurred())
+				objs = append(objs, cm)
+				for _, component := range []string{"database", "storage"} {
+					pvc := &corev1.PersistentVolumeClaim{}
+					err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.getPVCStatus(component).ClaimName,
+						Namespace: t.Namespace}, pvc)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(pvc.Labels).To(HaveKeyWithValue("app", t.Name))
+					objs = append(objs, pvc)
+				}
+				for _, obj := range objs {
+					Expect(obj.GetLabels()).To(HaveKeyWithValue("cost-center", "observability"), obj.GetName())
+					Expect(obj.GetAnnotations()).To(HaveKeyWithValue("myResourceAnnotation", "myResource"), obj.GetName())
+				}
+			})
+		})
 	})
 
 	Describe("setting up with manager", func() {
@@ -2651,6 +4768,17 @@ func (c *controllerTest) commonTests() {
 				})
 			})
 
+			Context("Gateway API installed", func() {
+				BeforeEach(func() {
+					t.GatewayAPIInstalled = true
+					t.BackendTLSPolicyInstalled = true
+					t.OpenShift = false
+					ownsResources = append(ownsResources, &certv1.Certificate{}, &certv1.Issuer{},
+						&gatewayv1.HTTPRoute{}, &gatewayv1alpha3.BackendTLSPolicy{})
+				})
+				expectOwnedResources()
+			})
+
 			Context("cert-manager missing", func() {
 				BeforeEach(func() {
 					t.CertManagerMissing = true
@@ -2682,9 +4810,10 @@ func (c *controllerTest) commonTests() {
 			})
 
 			It("should watch specified resources", func() {
-				Expect(t.ControllerBuilder.WatchesCalls).To(HaveLen(len(expectedResources)))
+				// The last watches are for the API server, dashboard Config Maps and CryostatRestores
+				Expect(t.ControllerBuilder.WatchesCalls).To(HaveLen(len(expectedResources) + 5))
 				resources := []ctrlclient.Object{}
-				for _, watch := range t.ControllerBuilder.WatchesCalls {
+				for _, watch := range t.ControllerBuilder.WatchesCalls[:len(expectedResources)] {
 					resources = append(resources, watch.Object)
 				}
 				Expect(resources).To(ConsistOf(expectedResources))
@@ -2695,9 +4824,8 @@ func (c *controllerTest) commonTests() {
 				var obj ctrlclient.Object
 
 				JustBeforeEach(func() {
-					Expect(t.ControllerBuilder.WatchesCalls).To(HaveLen(len(expectedResources)))
 					Expect(t.ControllerBuilder.Predicates).To(HaveLen(len(expectedResources)))
-					for _, watch := range t.ControllerBuilder.WatchesCalls {
+					for _, watch := range t.ControllerBuilder.WatchesCalls[:len(expectedResources)] {
 						Expect(watch.Opts).To(HaveLen(1))
 						Expect(watch.Opts[0]).To(BeAssignableToTypeOf(builder.Predicates{}))
 					}
@@ -2753,9 +4881,8 @@ func (c *controllerTest) commonTests() {
 				var obj ctrlclient.Object
 
 				JustBeforeEach(func() {
-					Expect(t.ControllerBuilder.WatchesCalls).To(HaveLen(len(expectedResources)))
-					Expect(t.ControllerBuilder.MapFuncs).To(HaveLen(len(expectedResources)))
-					for i, watch := range t.ControllerBuilder.WatchesCalls {
+					Expect(t.ControllerBuilder.MapFuncs).To(HaveLen(len(expectedResources) + 5))
+					for i, watch := range t.ControllerBuilder.WatchesCalls[:len(expectedResources)] {
 						Expect(watch.EventHandler).ToNot(BeNil())
 						// Check that the handler uses the expected underlying type
 						mapFunc := t.ControllerBuilder.MapFuncs[i]
@@ -2813,6 +4940,118 @@ func (c *controllerTest) commonTests() {
 				})
 			})
 		})
+
+		Context("watches for the API server", func() {
+			var mapFunc handler.MapFunc
+
+			JustBeforeEach(func() {
+				watches := t.ControllerBuilder.WatchesCalls
+				Expect(watches[len(watches)-5].Object).To(Equal(&configv1.APIServer{}))
+				mapFunc = t.ControllerBuilder.MapFuncs[len(t.ControllerBuilder.MapFuncs)-5]
+			})
+
+			Context("with a Cryostat inheriting its TLS profile", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, t.NewCryostatWithInheritedTLSProfile().Object)
+				})
+
+				It("should reconcile the Cryostat", func() {
+					result := mapFunc(context.Background(), t.NewApiServerWithTLSProfile())
+					Expect(result).To(ConsistOf(newReconcileRequest(t.Namespace, t.Name)))
+				})
+			})
+
+			Context("with a Cryostat using its own TLS profile", func() {
+				BeforeEach(func() {
+					t.objs = append(t.objs, t.NewCryostatWithCustomTLSProfile().Object)
+				})
+
+				It("should not reconcile the Cryostat", func() {
+					result := mapFunc(context.Background(), t.NewApiServerWithTLSProfile())
+					Expect(result).To(BeEmpty())
+				})
+			})
+		})
+
+		Context("watches for Grafana dashboard Config Maps", func() {
+			var mapFunc handler.MapFunc
+
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithGrafanaOptions().Object)
+			})
+
+			JustBeforeEach(func() {
+				watches := t.ControllerBuilder.WatchesCalls
+				Expect(watches[len(watches)-4].Object).To(Equal(&corev1.ConfigMap{}))
+				mapFunc = t.ControllerBuilder.MapFuncs[len(t.ControllerBuilder.MapFuncs)-4]
+			})
+
+			It("should reconcile the Cryostat for a Config Map listed by name", func() {
+				result := mapFunc(context.Background(), t.NewGrafanaDashboardConfigMap("jfr-dashboards", nil))
+				Expect(result).To(ConsistOf(newReconcileRequest(t.Namespace, t.Name)))
+			})
+
+			It("should reconcile the Cryostat for a Config Map matching the selector", func() {
+				result := mapFunc(context.Background(), t.NewGrafanaDashboardConfigMap("team-dashboards",
+					map[string]string{"cryostat.io/dashboard": "true"}))
+				Expect(result).To(ConsistOf(newReconcileRequest(t.Namespace, t.Name)))
+			})
+
+			It("should ignore other Config Maps", func() {
+				result := mapFunc(context.Background(), t.NewGrafanaDashboardConfigMap("other-config",
+					map[string]string{"cryostat.io/dashboard": "false"}))
+				Expect(result).To(BeEmpty())
+			})
+		})
+
+		Context("watches for core environment sources", func() {
+			BeforeEach(func() {
+				t.objs = append(t.objs, t.NewCryostatWithCoreOptions().Object)
+			})
+
+			It("should reconcile the Cryostat for a Secret loaded by envFrom", func() {
+				watches := t.ControllerBuilder.WatchesCalls
+				Expect(watches[len(watches)-3].Object).To(Equal(&corev1.Secret{}))
+				mapFunc := t.ControllerBuilder.MapFuncs[len(t.ControllerBuilder.MapFuncs)-3]
+				result := mapFunc(context.Background(), t.NewCoreEnvFromSecret())
+				Expect(result).To(ConsistOf(newReconcileRequest(t.Namespace, t.Name)))
+			})
+
+			It("should reconcile the Cryostat for a Config Map loaded by envFrom", func() {
+				watches := t.ControllerBuilder.WatchesCalls
+				Expect(watches[len(watches)-2].Object).To(Equal(&corev1.ConfigMap{}))
+				mapFunc := t.ControllerBuilder.MapFuncs[len(t.ControllerBuilder.MapFuncs)-2]
+				cm := &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cryostat-extra-config",
+						Namespace: t.Namespace,
+					},
+				}
+				result := mapFunc(context.Background(), cm)
+				Expect(result).To(ConsistOf(newReconcileRequest(t.Namespace, t.Name)))
+			})
+
+			It("should ignore a Config Map with the name of a Secret loaded by envFrom", func() {
+				mapFunc := t.ControllerBuilder.MapFuncs[len(t.ControllerBuilder.MapFuncs)-2]
+				cm := &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      t.NewCoreEnvFromSecret().Name,
+						Namespace: t.Namespace,
+					},
+				}
+				Expect(mapFunc(context.Background(), cm)).To(BeEmpty())
+			})
+		})
+
+		Context("watches for CryostatRestores", func() {
+			It("should reconcile the Cryostat being restored into", func() {
+				watches := t.ControllerBuilder.WatchesCalls
+				Expect(watches[len(watches)-1].Object).To(Equal(&operatorv1beta2.CryostatRestore{}))
+				mapFunc := t.ControllerBuilder.MapFuncs[len(t.ControllerBuilder.MapFuncs)-1]
+				result := mapFunc(context.Background(), t.NewCryostatRestore())
+				Expect(result).To(ConsistOf(newReconcileRequest(t.Namespace, t.Name)))
+			})
+		})
 	})
 }
 
@@ -2854,7 +5093,30 @@ func (t *cryostatTestInput) reconcileCryostatFully() {
 		result, err := t.reconcile()
 		Expect(err).ToNot(HaveOccurred())
 		return result
-	}).WithTimeout(time.Minute).WithPolling(time.Millisecond).Should(Equal(reconcile.Result{}))
+	}).WithTimeout(time.Minute).WithPolling(time.Millisecond).Should(Satisfy(isFullyReconciled))
+}
+
+// isFullyReconciled returns whether the result requeues for nothing other than
+// the test certificates approaching their expiry
+func isFullyReconciled(result reconcile.Result) bool {
+	if result == (reconcile.Result{}) {
+		return true
+	}
+	expiryRequeue := time.Until(test.CertNotAfter.Add(-7 * 24 * time.Hour))
+	return !result.Requeue && (result.RequeueAfter-expiryRequeue).Abs() < time.Minute
+}
+
+// reconcileCryostatUntilRequeueAfter reconciles until waiting on a long-running operation,
+// such as a stage of a CA rotation, rather than a certificate becoming ready
+func (t *cryostatTestInput) reconcileCryostatUntilRequeueAfter() reconcile.Result {
+	var result reconcile.Result
+	Eventually(func() time.Duration {
+		var err error
+		result, err = t.reconcile()
+		Expect(err).ToNot(HaveOccurred())
+		return result.RequeueAfter
+	}).WithTimeout(time.Minute).WithPolling(time.Millisecond).Should(BeNumerically(">", 5*time.Second))
+	return result
 }
 
 func (t *cryostatTestInput) reconcileDeletedCryostat() {
@@ -2897,6 +5159,21 @@ func (t *cryostatTestInput) expectWaitingForCertificate() {
 		"WaitingForCertificate")
 }
 
+func (t *cryostatTestInput) checkCertificate(expected *certv1.Certificate) {
+	actual := &certv1.Certificate{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, actual)
+	Expect(err).ToNot(HaveOccurred())
+	t.checkMetadata(actual, expected)
+	Expect(actual.Spec).To(Equal(expected.Spec))
+}
+
+func (t *cryostatTestInput) expectCertSecretOwned(cert *certv1.Certificate) {
+	secret := &corev1.Secret{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: cert.Spec.SecretName, Namespace: cert.Namespace}, secret)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(metav1.IsControlledBy(secret, t.getCryostatInstance().Object)).To(BeTrue())
+}
+
 func (t *cryostatTestInput) expectCertificates() {
 	// Check certificates
 	certs := []*certv1.Certificate{t.NewCryostatCert(), t.NewCACert(), t.NewReportsCert(), t.NewAgentProxyCert(), t.NewDatabaseCert(), t.NewStorageCert()}
@@ -2924,6 +5201,14 @@ func (t *cryostatTestInput) expectCertificates() {
 	t.checkMetadata(secret, expectedSecret)
 	Expect(secret.Data).To(Equal(expectedSecret.Data))
 
+	// Check CA bundle secret
+	expectedSecret = t.NewCABundleSecret()
+	secret = &corev1.Secret{}
+	err = t.Client.Get(context.Background(), types.NamespacedName{Name: expectedSecret.Name, Namespace: expectedSecret.Namespace}, secret)
+	Expect(err).ToNot(HaveOccurred())
+	t.checkMetadata(secret, expectedSecret)
+	Expect(secret.Data).To(Equal(expectedSecret.Data))
+
 	// Check CA Cert secrets in each target namespace
 	Expect(t.TargetNamespaces).ToNot(BeEmpty())
 	for _, ns := range t.TargetNamespaces {
@@ -3003,6 +5288,15 @@ func (t *cryostatTestInput) expectRBAC() {
 	Expect(clusterBinding.GetAnnotations()).To(Equal(expectedClusterBinding.GetAnnotations()))
 	Expect(clusterBinding.Subjects).To(Equal(expectedClusterBinding.Subjects))
 	Expect(clusterBinding.RoleRef).To(Equal(expectedClusterBinding.RoleRef))
+
+	// Check for the operator's RoleBinding in the install namespace
+	expectedAPIBinding := t.NewAPIClientRoleBinding()
+	apiBinding := &rbacv1.RoleBinding{}
+	err = t.Client.Get(context.Background(), types.NamespacedName{Name: expectedAPIBinding.Name, Namespace: expectedAPIBinding.Namespace}, apiBinding)
+	Expect(err).ToNot(HaveOccurred())
+	t.checkMetadataNoOwner(apiBinding, expectedAPIBinding)
+	Expect(apiBinding.Subjects).To(Equal(expectedAPIBinding.Subjects))
+	Expect(apiBinding.RoleRef).To(Equal(expectedAPIBinding.RoleRef))
 }
 
 func (t *cryostatTestInput) checkClusterRoleBindingDeleted() {
@@ -3072,6 +5366,48 @@ func (t *cryostatTestInput) expectNoIngresses() {
 	Expect(kerrors.IsNotFound(err)).To(BeTrue())
 }
 
+func (t *cryostatTestInput) expectHTTPRoute() {
+	expected := t.NewCoreHTTPRoute()
+	route := &gatewayv1.HTTPRoute{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, route)
+	Expect(err).ToNot(HaveOccurred())
+
+	t.checkMetadata(route, expected)
+	Expect(route.Spec).To(Equal(expected.Spec))
+}
+
+func (t *cryostatTestInput) expectNoHTTPRoute() {
+	route := &gatewayv1.HTTPRoute{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, route)
+	Expect(kerrors.IsNotFound(err)).To(BeTrue())
+}
+
+func (t *cryostatTestInput) expectBackendTLSPolicy() {
+	expected := t.NewCoreBackendTLSPolicy()
+	policy := &gatewayv1alpha3.BackendTLSPolicy{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, policy)
+	Expect(err).ToNot(HaveOccurred())
+
+	t.checkMetadata(policy, expected)
+	Expect(policy.Spec).To(Equal(expected.Spec))
+
+	expectedCM := t.NewGatewayCAConfigMap()
+	cm := &corev1.ConfigMap{}
+	err = t.Client.Get(context.Background(), types.NamespacedName{Name: expectedCM.Name, Namespace: expectedCM.Namespace}, cm)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(cm.Data).To(Equal(expectedCM.Data))
+}
+
+func (t *cryostatTestInput) expectNoBackendTLSPolicy() {
+	policy := &gatewayv1alpha3.BackendTLSPolicy{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, policy)
+	Expect(kerrors.IsNotFound(err)).To(BeTrue())
+
+	cm := &corev1.ConfigMap{}
+	err = t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-gateway-ca", Namespace: t.Namespace}, cm)
+	Expect(kerrors.IsNotFound(err)).To(BeTrue())
+}
+
 func (t *cryostatTestInput) expectLockConfigMap() {
 	expected := t.NewLockConfigMap()
 	cm := &corev1.ConfigMap{}
@@ -3125,6 +5461,113 @@ func (t *cryostatTestInput) expectPVC(expectedPVC *corev1.PersistentVolumeClaim)
 	Expect(pvcStorage).To(Equal(expectedPVCStorage))
 }
 
+func (t *cryostatTestInput) getPVCStatus(component string) *operatorv1beta2.PersistentVolumeClaimStatus {
+	for _, status := range t.getCryostatInstance().Status.PersistentVolumeClaims {
+		if status.Component == component {
+			return &status
+		}
+	}
+	Fail(fmt.Sprintf("no PersistentVolumeClaimStatus for %s", component))
+	return nil
+}
+
+func (t *cryostatTestInput) getPVCMigrationJob() *batchv1.Job {
+	jobs := &batchv1.JobList{}
+	err := t.Client.List(context.Background(), jobs, ctrlclient.InNamespace(t.Namespace))
+	Expect(err).ToNot(HaveOccurred())
+	Expect(jobs.Items).To(HaveLen(1))
+	return &jobs.Items[0]
+}
+
+// writeDatabaseFiles creates a stand-in for a PostgreSQL data directory
+func writeDatabaseFiles(dir string, version string, contents string) {
+	Expect(os.MkdirAll(dir, 0o755)).To(Succeed())
+	Expect(os.WriteFile(filepath.Join(dir, "PG_VERSION"), []byte(version), 0o644)).To(Succeed())
+	Expect(os.WriteFile(filepath.Join(dir, "contents"), []byte(contents), 0o644)).To(Succeed())
+}
+
+// runDatabaseVersionCheck runs the script of the database's version check container against
+// the data directory, using a stand-in for the postgres binary, and returns its termination message
+func runDatabaseVersionCheck(container *corev1.Container, pgdata string, imageVersion string) string {
+	dir := GinkgoT().TempDir()
+	postgres := fmt.Sprintf("#!/bin/sh\necho 'postgres (PostgreSQL) %s.1'\n", imageVersion)
+	Expect(os.WriteFile(filepath.Join(dir, "postgres"), []byte(postgres), 0o755)).To(Succeed())
+	terminationLog := filepath.Join(dir, "termination-log")
+	script := strings.ReplaceAll(container.Command[2], "/dev/termination-log", terminationLog)
+
+	cmd := exec.Command(container.Command[0], container.Command[1], script)
+	cmd.Env = append(os.Environ(), "PGDATA="+pgdata, "PATH="+dir+":"+os.Getenv("PATH"))
+	output, err := cmd.CombinedOutput()
+	Expect(err).ToNot(HaveOccurred(), string(output))
+	message, err := os.ReadFile(terminationLog)
+	Expect(err).ToNot(HaveOccurred())
+	return string(message)
+}
+
+func (t *cryostatTestInput) getDatabaseUpgradeJob(toVersion string) *batchv1.Job {
+	job := &batchv1.Job{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-database-upgrade-" + toVersion,
+		Namespace: t.Namespace}, job)
+	Expect(err).ToNot(HaveOccurred())
+	return job
+}
+
+func (t *cryostatTestInput) requestPVCMigration(components string) {
+	cr := t.getCryostatInstance()
+	annotations := cr.Object.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations["operator.cryostat.io/migrate-pvcs"] = components
+	cr.Object.SetAnnotations(annotations)
+	err := t.Client.Update(context.Background(), cr.Object)
+	Expect(err).ToNot(HaveOccurred())
+}
+
+func (t *cryostatTestInput) getDeployment(name string) *appsv1.Deployment {
+	deploy := &appsv1.Deployment{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: t.Namespace}, deploy)
+	Expect(err).ToNot(HaveOccurred())
+	return deploy
+}
+
+func (t *cryostatTestInput) getDatabaseDeployment() *appsv1.Deployment {
+	return t.getDeployment(t.Name + "-database")
+}
+
+func (t *cryostatTestInput) getStorageDeployment() *appsv1.Deployment {
+	return t.getDeployment(t.Name + "-storage")
+}
+
+func (t *cryostatTestInput) expectEvent(eventType string) {
+	recorder := t.controller.GetConfig().EventRecorder.(*record.FakeRecorder)
+	var eventMsg string
+	Eventually(recorder.Events).Should(Receive(&eventMsg, ContainSubstring(eventType)))
+}
+
+func (t *cryostatTestInput) getPVC(name string) *corev1.PersistentVolumeClaim {
+	pvc := &corev1.PersistentVolumeClaim{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: t.Namespace}, pvc)
+	Expect(err).ToNot(HaveOccurred())
+	return pvc
+}
+
+func (t *cryostatTestInput) getVolumeSnapshots() []unstructured.Unstructured {
+	snapshots := &unstructured.UnstructuredList{}
+	snapshots.SetGroupVersionKind(test.VolumeSnapshotGVK.GroupVersion().WithKind(test.VolumeSnapshotGVK.Kind + "List"))
+	err := t.Client.List(context.Background(), snapshots, ctrlclient.InNamespace(t.Namespace))
+	Expect(err).ToNot(HaveOccurred())
+	return snapshots.Items
+}
+
+func (t *cryostatTestInput) setVolumeSnapshotStatus(status map[string]interface{}) {
+	for _, snapshot := range t.getVolumeSnapshots() {
+		snapshot.Object["status"] = status
+		err := t.Client.Update(context.Background(), &snapshot)
+		Expect(err).ToNot(HaveOccurred())
+	}
+}
+
 func (t *cryostatTestInput) expectDatabaseEmptyDir(expectedEmptyDir *corev1.EmptyDirVolumeSource) {
 	deployment := &appsv1.Deployment{}
 	err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-database", Namespace: t.Namespace}, deployment)
@@ -3218,11 +5661,38 @@ func (t *cryostatTestInput) expectStatusApplicationURL() {
 	Expect(instance.Status.ApplicationURL).To(Equal(fmt.Sprintf("https://%s.example.com", t.Name)))
 }
 
+func (t *cryostatTestInput) expectStatusComponents() {
+	cr := t.getCryostatInstance()
+	names := []string{"core", "database", "storage"}
+	if t.ReportReplicas > 0 {
+		names = []string{"core", "reports", "database", "storage"}
+	}
+	Expect(cr.Status.Components).To(HaveLen(len(names)))
+	for i, component := range cr.Status.Components {
+		Expect(component.Name).To(Equal(names[i]))
+		deploy := t.getDeployment(component.DeploymentName)
+		Expect(component.Replicas).To(Equal(*deploy.Spec.Replicas))
+		Expect(component.ReadyReplicas).To(Equal(deploy.Status.ReadyReplicas))
+		Expect(component.Image).ToNot(BeEmpty())
+		Expect(component.Endpoint).ToNot(BeEmpty())
+	}
+
+	core := cr.Status.Components[0]
+	Expect(core.DeploymentName).To(Equal(t.Name))
+	Expect(core.Endpoint).To(Equal(cr.Status.ApplicationURL))
+	Expect(core.Image).To(HaveSuffix(":" + cr.Status.Version))
+}
+
 func (t *cryostatTestInput) expectStatusDatabaseSecret() {
 	instance := t.getCryostatInstance()
 	Expect(instance.Status.DatabaseSecret).To(Equal(fmt.Sprintf("%s-db", t.Name)))
 }
 
+func (t *cryostatTestInput) expectStatusCertificates() {
+	cr := t.getCryostatInstance()
+	Expect(cr.Status.Certificates).To(ConsistOf(t.NewCertificateStatuses()))
+}
+
 func (t *cryostatTestInput) expectStatusStorageSecret() {
 	instance := t.getCryostatInstance()
 	Expect(instance.Status.StorageSecret).To(Equal(fmt.Sprintf("%s-storage", t.Name)))
@@ -3492,16 +5962,38 @@ func (t *cryostatTestInput) checkMainPodTemplate(deployment *appsv1.Deployment,
 	// Check that the proper Service Account is set
 	Expect(template.Spec.ServiceAccountName).To(Equal(t.Name))
 
-	if cr.Spec.SchedulingOptions != nil {
-		scheduling := cr.Spec.SchedulingOptions
-		Expect(template.Spec.NodeSelector).To(Equal(scheduling.NodeSelector))
-		if scheduling.Affinity != nil {
-			Expect(template.Spec.Affinity.PodAffinity).To(Equal(scheduling.Affinity.PodAffinity))
-			Expect(template.Spec.Affinity.PodAntiAffinity).To(Equal(scheduling.Affinity.PodAntiAffinity))
-			Expect(template.Spec.Affinity.NodeAffinity).To(Equal(scheduling.Affinity.NodeAffinity))
-		}
-		Expect(template.Spec.Tolerations).To(Equal(scheduling.Tolerations))
+	checkPodScheduling(&template, cr.Spec.SchedulingOptions)
+}
+
+func checkPodScheduling(template *corev1.PodTemplateSpec, scheduling *operatorv1beta2.SchedulingConfiguration) {
+	if scheduling == nil {
+		return
 	}
+	Expect(template.Spec.NodeSelector).To(Equal(scheduling.NodeSelector))
+	if scheduling.Affinity != nil {
+		Expect(template.Spec.Affinity.PodAffinity).To(Equal(scheduling.Affinity.PodAffinity))
+		Expect(template.Spec.Affinity.PodAntiAffinity).To(Equal(scheduling.Affinity.PodAntiAffinity))
+		Expect(template.Spec.Affinity.NodeAffinity).To(Equal(scheduling.Affinity.NodeAffinity))
+	}
+	Expect(template.Spec.Tolerations).To(Equal(scheduling.Tolerations))
+	Expect(template.Spec.TopologySpreadConstraints).To(Equal(scheduling.TopologySpreadConstraints))
+	Expect(template.Spec.PriorityClassName).To(Equal(scheduling.PriorityClassName))
+	Expect(template.Spec.RuntimeClassName).To(Equal(scheduling.RuntimeClassName))
+}
+
+// The database and storage use the core scheduling options, unless their own are configured
+func expectedDatabaseScheduling(cr *model.CryostatInstance) *operatorv1beta2.SchedulingConfiguration {
+	if cr.Spec.DatabaseOptions != nil && cr.Spec.DatabaseOptions.SchedulingOptions != nil {
+		return cr.Spec.DatabaseOptions.SchedulingOptions
+	}
+	return cr.Spec.SchedulingOptions
+}
+
+func expectedStorageScheduling(cr *model.CryostatInstance) *operatorv1beta2.SchedulingConfiguration {
+	if cr.Spec.ObjectStorageOptions != nil && cr.Spec.ObjectStorageOptions.SchedulingOptions != nil {
+		return cr.Spec.ObjectStorageOptions.SchedulingOptions
+	}
+	return cr.Spec.SchedulingOptions
 }
 
 func (t *cryostatTestInput) expectMainPodTemplateHasExtraMetadata(deployment *appsv1.Deployment, cr *model.CryostatInstance) {
@@ -3557,20 +6049,19 @@ func (t *cryostatTestInput) expectDatabaseDeployment() {
 	databaseContainer := template.Spec.Containers[0]
 	t.checkDatabaseContainer(&databaseContainer, t.NewDatabaseContainerResource(cr), t.NewDatabaseSecurityContext(cr), dbSecretProvided)
 
+	// Check that the data version is checked before the database starts
+	Expect(template.Spec.InitContainers).To(HaveLen(1))
+	checkContainer := template.Spec.InitContainers[0]
+	Expect(checkContainer.Name).To(Equal("check-data-version"))
+	Expect(checkContainer.Image).To(Equal(databaseContainer.Image))
+	Expect(checkContainer.VolumeMounts).To(Equal(databaseContainer.VolumeMounts[:1]))
+	Expect(checkContainer.SecurityContext).To(Equal(databaseContainer.SecurityContext))
+
 	// Check that the default Service Account is used
 	Expect(template.Spec.ServiceAccountName).To(BeEmpty())
 	Expect(template.Spec.AutomountServiceAccountToken).To(BeNil())
 
-	if cr.Spec.SchedulingOptions != nil {
-		scheduling := cr.Spec.SchedulingOptions
-		Expect(template.Spec.NodeSelector).To(Equal(scheduling.NodeSelector))
-		if scheduling.Affinity != nil {
-			Expect(template.Spec.Affinity.PodAffinity).To(Equal(scheduling.Affinity.PodAffinity))
-			Expect(template.Spec.Affinity.PodAntiAffinity).To(Equal(scheduling.Affinity.PodAntiAffinity))
-			Expect(template.Spec.Affinity.NodeAffinity).To(Equal(scheduling.Affinity.NodeAffinity))
-		}
-		Expect(template.Spec.Tolerations).To(Equal(scheduling.Tolerations))
-	}
+	checkPodScheduling(&template, expectedDatabaseScheduling(cr))
 }
 
 func (t *cryostatTestInput) expectStorageDeployment() {
@@ -3614,16 +6105,7 @@ func (t *cryostatTestInput) expectStorageDeployment() {
 	Expect(template.Spec.ServiceAccountName).To(BeEmpty())
 	Expect(template.Spec.AutomountServiceAccountToken).To(BeNil())
 
-	if cr.Spec.SchedulingOptions != nil {
-		scheduling := cr.Spec.SchedulingOptions
-		Expect(template.Spec.NodeSelector).To(Equal(scheduling.NodeSelector))
-		if scheduling.Affinity != nil {
-			Expect(template.Spec.Affinity.PodAffinity).To(Equal(scheduling.Affinity.PodAffinity))
-			Expect(template.Spec.Affinity.PodAntiAffinity).To(Equal(scheduling.Affinity.PodAntiAffinity))
-			Expect(template.Spec.Affinity.NodeAffinity).To(Equal(scheduling.Affinity.NodeAffinity))
-		}
-		Expect(template.Spec.Tolerations).To(Equal(scheduling.Tolerations))
-	}
+	checkPodScheduling(&template, expectedStorageScheduling(cr))
 }
 
 func (t *cryostatTestInput) checkReportsDeployment() {
@@ -3669,15 +6151,8 @@ func (t *cryostatTestInput) checkReportsDeployment() {
 	Expect(template.Spec.ServiceAccountName).To(BeEmpty())
 	Expect(template.Spec.AutomountServiceAccountToken).To(BeNil())
 
-	if cr.Spec.ReportOptions != nil && cr.Spec.ReportOptions.SchedulingOptions != nil {
-		scheduling := cr.Spec.ReportOptions.SchedulingOptions
-		Expect(template.Spec.NodeSelector).To(Equal(scheduling.NodeSelector))
-		if scheduling.Affinity != nil {
-			Expect(template.Spec.Affinity.PodAffinity).To(Equal(scheduling.Affinity.PodAffinity))
-			Expect(template.Spec.Affinity.PodAntiAffinity).To(Equal(scheduling.Affinity.PodAntiAffinity))
-			Expect(template.Spec.Affinity.NodeAffinity).To(Equal(scheduling.Affinity.NodeAffinity))
-		}
-		Expect(template.Spec.Tolerations).To(Equal(scheduling.Tolerations))
+	if cr.Spec.ReportOptions != nil {
+		checkPodScheduling(&template, cr.Spec.ReportOptions.SchedulingOptions)
 	}
 }
 
@@ -3742,8 +6217,8 @@ func (t *cryostatTestInput) checkCoreContainer(container *corev1.Container, ingr
 	Expect(container.Env).To(ConsistOf(t.NewCoreEnvironmentVariables(reportsUrl, ingress, hasPortConfig, builtInDiscoveryDisabled, builtInPortConfigDisabled, dbSecretProvided)))
 	Expect(container.EnvFrom).To(ConsistOf(t.NewCoreEnvFromSource()))
 	Expect(container.VolumeMounts).To(ConsistOf(t.NewCoreVolumeMounts()))
-	Expect(container.LivenessProbe).To(Equal(t.NewCoreLivenessProbe()))
-	Expect(container.StartupProbe).To(Equal(t.NewCoreStartupProbe()))
+	Expect(container.LivenessProbe).To(Equal(t.NewCoreLivenessProbe(resources)))
+	Expect(container.StartupProbe).To(Equal(t.NewCoreStartupProbe(resources)))
 	Expect(container.SecurityContext).To(Equal(securityContext))
 
 	test.ExpectResourceRequirements(&container.Resources, resources)
@@ -3759,7 +6234,7 @@ func (t *cryostatTestInput) checkGrafanaContainer(container *corev1.Container, r
 	Expect(container.Ports).To(ConsistOf(t.NewGrafanaPorts()))
 	Expect(container.Env).To(ConsistOf(t.NewGrafanaEnvironmentVariables()))
 	Expect(container.VolumeMounts).To(BeEmpty())
-	Expect(container.LivenessProbe).To(Equal(t.NewGrafanaLivenessProbe()))
+	Expect(container.LivenessProbe).To(Equal(t.NewGrafanaLivenessProbe(resources)))
 	Expect(container.SecurityContext).To(Equal(securityContext))
 
 	test.ExpectResourceRequirements(&container.Resources, resources)
@@ -3776,7 +6251,7 @@ func (t *cryostatTestInput) checkDatasourceContainer(container *corev1.Container
 	Expect(container.Env).To(ConsistOf(t.NewDatasourceEnvironmentVariables()))
 	Expect(container.EnvFrom).To(BeEmpty())
 	Expect(container.VolumeMounts).To(BeEmpty())
-	Expect(container.LivenessProbe).To(Equal(t.NewDatasourceLivenessProbe()))
+	Expect(container.LivenessProbe).To(Equal(t.NewDatasourceLivenessProbe(resources)))
 	Expect(container.SecurityContext).To(Equal(securityContext))
 
 	test.ExpectResourceRequirements(&container.Resources, resources)
@@ -3801,7 +6276,7 @@ func (t *cryostatTestInput) checkAuthProxyContainer(container *corev1.Container,
 	Expect(container.Env).To(ConsistOf(t.NewAuthProxyEnvironmentVariables(authOptions)))
 	Expect(container.EnvFrom).To(ConsistOf(t.NewAuthProxyEnvFromSource()))
 	Expect(container.VolumeMounts).To(ConsistOf(t.NewAuthProxyVolumeMounts(authOptions)))
-	Expect(container.LivenessProbe).To(Equal(t.NewAuthProxyLivenessProbe()))
+	Expect(container.LivenessProbe).To(Equal(t.NewAuthProxyLivenessProbe(resources)))
 	Expect(container.SecurityContext).To(Equal(securityContext))
 
 	args, err := t.NewAuthProxyArguments(authOptions)
@@ -3826,7 +6301,7 @@ func (t *cryostatTestInput) checkAgentProxyContainer(container *corev1.Container
 	Expect(container.Env).To(ConsistOf(t.NewAgentProxyEnvironmentVariables()))
 	Expect(container.EnvFrom).To(ConsistOf(t.NewAgentProxyEnvFromSource()))
 	Expect(container.VolumeMounts).To(ConsistOf(t.NewAgentProxyVolumeMounts()))
-	Expect(container.LivenessProbe).To(Equal(t.NewAgentProxyLivenessProbe()))
+	Expect(container.LivenessProbe).To(Equal(t.NewAgentProxyLivenessProbe(resources)))
 	Expect(container.SecurityContext).To(Equal(securityContext))
 	Expect(container.Command).To(Equal(t.NewAgentProxyCommand()))
 
@@ -3843,7 +6318,7 @@ func (t *cryostatTestInput) checkReportsContainer(container *corev1.Container, r
 	Expect(container.Ports).To(ConsistOf(t.NewReportsPorts()))
 	Expect(container.Env).To(ConsistOf(t.NewReportsEnvironmentVariables(resources)))
 	Expect(container.VolumeMounts).To(ConsistOf(t.NewReportsVolumeMounts()))
-	Expect(container.LivenessProbe).To(Equal(t.NewReportsLivenessProbe()))
+	Expect(container.LivenessProbe).To(Equal(t.NewReportsLivenessProbe(resources)))
 	Expect(container.SecurityContext).To(Equal(securityContext))
 
 	test.ExpectResourceRequirements(&container.Resources, resources)
@@ -3861,7 +6336,7 @@ func (t *cryostatTestInput) checkStorageContainer(container *corev1.Container, r
 	Expect(container.Args).To(ConsistOf(t.NewStorageArgs()))
 	Expect(container.EnvFrom).To(BeEmpty())
 	Expect(container.VolumeMounts).To(ConsistOf(t.NewStorageVolumeMounts()))
-	Expect(container.LivenessProbe).To(Equal(t.NewStorageLivenessProbe()))
+	Expect(container.LivenessProbe).To(Equal(t.NewStorageLivenessProbe(resources)))
 	Expect(container.SecurityContext).To(Equal(securityContext))
 
 	test.ExpectResourceRequirements(&container.Resources, resources)
@@ -3879,7 +6354,7 @@ func (t *cryostatTestInput) checkDatabaseContainer(container *corev1.Container,
 	Expect(container.Args).To(ConsistOf(t.NewDatabaseArgs()))
 	Expect(container.EnvFrom).To(BeEmpty())
 	Expect(container.VolumeMounts).To(ConsistOf(t.NewDatabaseVolumeMounts()))
-	Expect(container.ReadinessProbe).To(Equal(t.NewDatabaseReadinessProbe()))
+	Expect(container.ReadinessProbe).To(Equal(t.NewDatabaseReadinessProbe(resources)))
 	Expect(container.SecurityContext).To(Equal(securityContext))
 
 	test.ExpectResourceRequirements(&container.Resources, resources)
@@ -3896,6 +6371,43 @@ func (t *cryostatTestInput) checkCoreHasEnvironmentVariables(expectedEnvVars []c
 	Expect(coreContainer.Env).To(ContainElements(expectedEnvVars))
 }
 
+func (t *cryostatTestInput) expectDeploymentImages(deployName string, images map[string]string) {
+	deploy := &appsv1.Deployment{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: deployName, Namespace: t.Namespace}, deploy)
+	Expect(err).ToNot(HaveOccurred())
+	for _, container := range deploy.Spec.Template.Spec.Containers {
+		if image, ok := images[container.Name]; ok {
+			Expect(container.Image).To(Equal(image), "Container %s", container.Name)
+			delete(images, container.Name)
+		}
+	}
+	Expect(images).To(BeEmpty(), "Containers missing from Deployment %s", deployName)
+}
+
+func (t *cryostatTestInput) getCoreContainer() *corev1.Container {
+	return t.getMainDeploymentContainer(t.Name)
+}
+
+func (t *cryostatTestInput) getMainDeploymentContainer(name string) *corev1.Container {
+	deploy := &appsv1.Deployment{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name, Namespace: t.Namespace}, deploy)
+	Expect(err).ToNot(HaveOccurred())
+	for i, container := range deploy.Spec.Template.Spec.Containers {
+		if container.Name == name {
+			return &deploy.Spec.Template.Spec.Containers[i]
+		}
+	}
+	Fail("Container " + name + " not found in Deployment " + deploy.Name)
+	return nil
+}
+
+func (t *cryostatTestInput) getGrafanaDeployment() *appsv1.Deployment {
+	deploy := &appsv1.Deployment{}
+	err := t.Client.Get(context.Background(), types.NamespacedName{Name: t.Name + "-grafana", Namespace: t.Namespace}, deploy)
+	Expect(err).ToNot(HaveOccurred())
+	return deploy
+}
+
 func (t *cryostatTestInput) getCryostatInstance() *model.CryostatInstance {
 	cr, err := t.lookupCryostatInstance()
 	Expect(err).ToNot(HaveOccurred())
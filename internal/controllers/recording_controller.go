@@ -0,0 +1,483 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/common"
+	"github.com/cryostatio/cryostat-operator/internal/cryostatclient"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Verify that *RecordingReconciler implements CommonReconciler.
+var _ CommonReconciler = (*RecordingReconciler)(nil)
+
+// RecordingReconciler reconciles a Recording object
+type RecordingReconciler struct {
+	*ReconcilerConfig
+}
+
+// Name used for Finalizer that handles Recording deletion
+const recordingFinalizer = "operator.cryostat.io/recording.finalizer"
+
+// How often recordings are compared against Cryostat, since changes
+// within Cryostat do not trigger a reconcile
+const recordingSyncPeriod = time.Minute
+
+// Event template used when a Recording does not specify one
+const (
+	defaultEventTemplateName = "Continuous"
+	defaultEventTemplateType = "TARGET"
+)
+
+// Reasons for Recording Conditions
+const (
+	reasonRecordingsSynchronized  = "RecordingsSynchronized"
+	reasonCryostatNotFound        = "CryostatNotFound"
+	reasonNamespaceNotTargeted    = "NamespaceNotTargeted"
+	reasonCryostatPaused          = "CryostatPaused"
+	reasonCryostatReconcilePaused = "CryostatReconcilePaused"
+	reasonInvalidTarget           = "InvalidTarget"
+	reasonNoMatchingTargets       = "NoMatchingTargets"
+	reasonCryostatAPIError        = "CryostatAPIError"
+)
+
+func NewRecordingReconciler(config *ReconcilerConfig) (*RecordingReconciler, error) {
+	return &RecordingReconciler{
+		ReconcilerConfig: config,
+	}, nil
+}
+
+// +kubebuilder:rbac:groups=operator.cryostat.io,resources=recordings,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=operator.cryostat.io,resources=recordings/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=operator.cryostat.io,resources=recordings/finalizers,verbs=update
+
+// Reconcile processes a Recording CR and manages the corresponding recordings in Cryostat
+func (r *RecordingReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+
+	reqLogger.Info("Reconciling Recording")
+
+	// Fetch the Recording instance
+	recording := &operatorv1beta2.Recording{}
+	err := r.Client.Get(ctx, request.NamespacedName, recording)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			reqLogger.Info("Recording instance not found")
+			return reconcile.Result{}, nil
+		}
+		reqLogger.Error(err, "Error reading Recording instance")
+		return reconcile.Result{}, err
+	}
+
+	// Check if this Recording is being deleted
+	if recording.GetDeletionTimestamp() != nil {
+		if controllerutil.ContainsFinalizer(recording, recordingFinalizer) {
+			err := r.finalizeRecording(ctx, reqLogger, recording)
+			if err != nil {
+				if isCryostatPaused(err) {
+					// Reconciled again once the Cryostat is resumed
+					reqLogger.Info("Cryostat is paused, waiting to remove recordings")
+					return reconcile.Result{}, nil
+				}
+				return reconcile.Result{}, err
+			}
+
+			err = common.RemoveFinalizer(ctx, r.Client, recording, recordingFinalizer)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		return reconcile.Result{}, nil
+	}
+
+	// Add our finalizer, so we can clean up the recordings in Cryostat upon deletion
+	if !controllerutil.ContainsFinalizer(recording, recordingFinalizer) {
+		err := common.AddFinalizer(ctx, r.Client, recording, recordingFinalizer)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if err := validateRecordingTarget(&recording.Spec.Target); err != nil {
+		return reconcile.Result{}, r.updateRecordingCondition(ctx, recording, metav1.ConditionFalse,
+			reasonInvalidTarget, err.Error())
+	}
+
+	cr, err := r.getTargetingCryostat(ctx, &recording.Spec.Cryostat, recording.Namespace)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// Reconciled again once the Cryostat is created
+			return reconcile.Result{}, r.updateRecordingCondition(ctx, recording, metav1.ConditionFalse,
+				reasonCryostatNotFound, fmt.Sprintf("Cryostat %s/%s does not exist",
+					getCryostatNamespace(&recording.Spec.Cryostat, recording.Namespace), recording.Spec.Cryostat.Name))
+		} else if errors.Is(err, errNamespaceNotTargeted) {
+			return reconcile.Result{}, r.updateRecordingCondition(ctx, recording, metav1.ConditionFalse,
+				reasonNamespaceNotTargeted, fmt.Sprintf("Namespace %s is not a target namespace of Cryostat %s/%s",
+					recording.Namespace, getCryostatNamespace(&recording.Spec.Cryostat, recording.Namespace),
+					recording.Spec.Cryostat.Name))
+		} else if isCryostatPaused(err) {
+			// Reconciled again once the Cryostat is resumed
+			reason, message := getCryostatPausedCondition(err,
+				getCryostatNamespace(&recording.Spec.Cryostat, recording.Namespace), recording.Spec.Cryostat.Name)
+			return reconcile.Result{}, r.updateRecordingCondition(ctx, recording, metav1.ConditionFalse, reason, message)
+		}
+		return reconcile.Result{}, err
+	}
+
+	apiClient, err := r.newCryostatAPIClient(ctx, cr)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err = r.synchronizeRecordings(ctx, apiClient, recording)
+	if err != nil {
+		reqLogger.Error(err, "Failed to synchronize recordings with Cryostat")
+		updateErr := r.updateRecordingCondition(ctx, recording, metav1.ConditionFalse, reasonCryostatAPIError, err.Error())
+		if updateErr != nil {
+			return reconcile.Result{}, updateErr
+		}
+		return reconcile.Result{}, err
+	}
+
+	if len(recording.Status.Targets) == 0 {
+		return reconcile.Result{RequeueAfter: recordingSyncPeriod}, r.updateRecordingCondition(ctx, recording,
+			metav1.ConditionFalse, reasonNoMatchingTargets, "No targets discovered by Cryostat match the Recording's target")
+	}
+	return reconcile.Result{RequeueAfter: recordingSyncPeriod}, r.updateRecordingCondition(ctx, recording,
+		metav1.ConditionTrue, reasonRecordingsSynchronized, fmt.Sprintf("Recording is present in %d target(s)",
+			len(recording.Status.Targets)))
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RecordingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c := r.NewControllerBuilder(mgr)
+	c = c.For(&operatorv1beta2.Recording{})
+	// Reconcile Recordings whenever the Cryostat they refer to changes, such as its target namespaces
+	c = c.Watches(&operatorv1beta2.Cryostat{}, c.EnqueueRequestsFromMapFunc(r.recordingsForCryostat))
+	return c.Complete(r)
+}
+
+func (r *RecordingReconciler) GetConfig() *ReconcilerConfig {
+	return r.ReconcilerConfig
+}
+
+func (r *RecordingReconciler) recordingsForCryostat(ctx context.Context, obj client.Object) []reconcile.Request {
+	recordings := &operatorv1beta2.RecordingList{}
+	err := r.Client.List(ctx, recordings)
+	if err != nil {
+		r.Log.Error(err, "failed to list Recordings", "Cryostat", obj.GetName())
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, recording := range recordings.Items {
+		if recording.Spec.Cryostat.Name == obj.GetName() &&
+			getCryostatNamespace(&recording.Spec.Cryostat, recording.Namespace) == obj.GetNamespace() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Name:      recording.Name,
+				Namespace: recording.Namespace,
+			}})
+		}
+	}
+	return requests
+}
+
+func validateRecordingTarget(target *operatorv1beta2.RecordingTarget) error {
+	if target.Selector == nil && len(target.ConnectURL) == 0 {
+		return errors.New("one of spec.target.selector or spec.target.connectUrl must be specified")
+	}
+	if target.Selector != nil && len(target.ConnectURL) > 0 {
+		return errors.New("only one of spec.target.selector or spec.target.connectUrl may be specified")
+	}
+	if target.Selector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(target.Selector); err != nil {
+			return fmt.Errorf("spec.target.selector is invalid: %s", err.Error())
+		}
+	}
+	return nil
+}
+
+// synchronizeRecordings ensures that a recording exists within each matching target,
+// and updates the Recording's status to reflect these recordings
+func (r *RecordingReconciler) synchronizeRecordings(ctx context.Context, apiClient cryostatclient.Client,
+	recording *operatorv1beta2.Recording) error {
+	targets, err := r.findRecordingTargets(ctx, apiClient, recording)
+	if err != nil {
+		return err
+	}
+
+	// Targets that no longer exist are dropped from the status
+	previous := recording.Status.Targets
+	recording.Status.Targets = []operatorv1beta2.RecordingTargetStatus{}
+	for _, target := range targets {
+		status := findRecordingTargetStatus(previous, target.ID)
+		if status == nil {
+			status = &operatorv1beta2.RecordingTargetStatus{
+				ConnectURL: target.ConnectURL,
+				TargetID:   target.ID,
+				PodName:    target.CryostatAnnotation(cryostatclient.AnnotationPodName),
+			}
+		}
+
+		err := r.synchronizeRecording(ctx, apiClient, recording, status)
+		// Record any progress made, even if the synchronization failed
+		recording.Status.Targets = append(recording.Status.Targets, *status)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *RecordingReconciler) synchronizeRecording(ctx context.Context, apiClient cryostatclient.Client,
+	recording *operatorv1beta2.Recording, status *operatorv1beta2.RecordingTargetStatus) error {
+	active, err := findActiveRecording(ctx, apiClient, status.TargetID, recording.Name)
+	if err != nil {
+		return err
+	}
+	if active == nil {
+		if status.RecordingID != nil {
+			// The recording was removed from this target outside of the operator.
+			// Don't start it again, as that would likely be unexpected.
+			status.State = ""
+			return nil
+		}
+		active, err = apiClient.CreateRecording(ctx, status.TargetID, newRecordingCreateOptions(recording))
+		if err != nil {
+			return err
+		}
+		r.Log.Info("Started recording", "Recording.Namespace", recording.Namespace, "Recording.Name", recording.Name,
+			"Target", status.ConnectURL)
+	}
+	status.RecordingID = &active.ID
+	status.State = active.State
+
+	if active.State == cryostatclient.RecordingStateStopped && recording.Spec.ArchiveOnStop && len(status.Archives) == 0 {
+		archive, err := apiClient.ArchiveRecording(ctx, status.TargetID, active.ID)
+		if err != nil {
+			return err
+		}
+		status.Archives = append(status.Archives, archive)
+	}
+	return nil
+}
+
+// finalizeRecording stops, optionally archives, and deletes the recordings managed by this Recording
+func (r *RecordingReconciler) finalizeRecording(ctx context.Context, reqLogger logr.Logger,
+	recording *operatorv1beta2.Recording) error {
+	cr, err := r.getTargetingCryostat(ctx, &recording.Spec.Cryostat, recording.Namespace)
+	if err != nil {
+		if kerrors.IsNotFound(err) || errors.Is(err, errNamespaceNotTargeted) {
+			// Cryostat can no longer record this namespace, so there is nothing to clean up
+			reqLogger.Info("Cryostat not available, skipping removal of recordings")
+			return nil
+		}
+		return err
+	}
+
+	apiClient, err := r.newCryostatAPIClient(ctx, cr)
+	if err != nil {
+		return err
+	}
+
+	for i := range recording.Status.Targets {
+		status := &recording.Status.Targets[i]
+		err := r.removeRecording(ctx, apiClient, recording, status)
+		if err != nil && !cryostatclient.IsNotFound(err) {
+			reqLogger.Error(err, "Failed to remove recording", "Target", status.ConnectURL)
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *RecordingReconciler) removeRecording(ctx context.Context, apiClient cryostatclient.Client,
+	recording *operatorv1beta2.Recording, status *operatorv1beta2.RecordingTargetStatus) error {
+	active, err := findActiveRecording(ctx, apiClient, status.TargetID, recording.Name)
+	if err != nil || active == nil {
+		return err
+	}
+
+	if recording.Spec.ArchiveOnStop && len(status.Archives) == 0 {
+		if active.State != cryostatclient.RecordingStateStopped {
+			err := apiClient.StopRecording(ctx, status.TargetID, active.ID)
+			if err != nil {
+				return err
+			}
+		}
+		archive, err := apiClient.ArchiveRecording(ctx, status.TargetID, active.ID)
+		if err != nil {
+			return err
+		}
+		status.Archives = append(status.Archives, archive)
+	}
+
+	err = apiClient.DeleteRecording(ctx, status.TargetID, active.ID)
+	if err != nil {
+		return err
+	}
+	r.Log.Info("Deleted recording", "Recording.Namespace", recording.Namespace, "Recording.Name", recording.Name,
+		"Target", status.ConnectURL)
+	return nil
+}
+
+// findRecordingTargets returns the targets discovered by Cryostat that match the Recording's target,
+// with at most one target for each JVM
+func (r *RecordingReconciler) findRecordingTargets(ctx context.Context, apiClient cryostatclient.Client,
+	recording *operatorv1beta2.Recording) ([]cryostatclient.Target, error) {
+	var podNames map[string]struct{}
+	if recording.Spec.Target.Selector != nil {
+		var err error
+		podNames, err = r.getSelectedPodNames(ctx, recording)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	targets, err := apiClient.ListTargets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].ID < targets[j].ID
+	})
+
+	result := []cryostatclient.Target{}
+	jvmIDs := map[string]struct{}{}
+	for _, target := range targets {
+		// Only consider targets within the Recording's namespace
+		if target.CryostatAnnotation(cryostatclient.AnnotationNamespace) != recording.Namespace {
+			continue
+		}
+		if podNames != nil {
+			if _, pres := podNames[target.CryostatAnnotation(cryostatclient.AnnotationPodName)]; !pres {
+				continue
+			}
+		} else if target.ConnectURL != recording.Spec.Target.ConnectURL {
+			continue
+		}
+		if len(target.JvmID) > 0 {
+			if _, pres := jvmIDs[target.JvmID]; pres {
+				continue
+			}
+			jvmIDs[target.JvmID] = struct{}{}
+		}
+		result = append(result, target)
+	}
+	return result, nil
+}
+
+func (r *RecordingReconciler) getSelectedPodNames(ctx context.Context, recording *operatorv1beta2.Recording) (map[string]struct{}, error) {
+	selector, err := metav1.LabelSelectorAsSelector(recording.Spec.Target.Selector)
+	if err != nil {
+		return nil, err
+	}
+	// Only metadata is needed to match labels
+	pods := &metav1.PartialObjectMetadataList{}
+	pods.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("PodList"))
+	err = r.Client.List(ctx, pods, client.InNamespace(recording.Namespace), client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		return nil, err
+	}
+
+	podNames := make(map[string]struct{}, len(pods.Items))
+	for _, pod := range pods.Items {
+		podNames[pod.Name] = struct{}{}
+	}
+	return podNames, nil
+}
+
+func findActiveRecording(ctx context.Context, apiClient cryostatclient.Client, targetID int64,
+	name string) (*cryostatclient.Recording, error) {
+	recordings, err := apiClient.ListRecordings(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range recordings {
+		if recordings[i].Name == name {
+			return &recordings[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func findRecordingTargetStatus(statuses []operatorv1beta2.RecordingTargetStatus, targetID int64) *operatorv1beta2.RecordingTargetStatus {
+	for i := range statuses {
+		if statuses[i].TargetID == targetID {
+			return statuses[i].DeepCopy()
+		}
+	}
+	return nil
+}
+
+func newRecordingCreateOptions(recording *operatorv1beta2.Recording) *cryostatclient.RecordingCreateOptions {
+	templateName := defaultEventTemplateName
+	templateType := defaultEventTemplateType
+	if recording.Spec.EventTemplate != nil {
+		templateName = recording.Spec.EventTemplate.Name
+		if len(recording.Spec.EventTemplate.Type) > 0 {
+			templateType = recording.Spec.EventTemplate.Type
+		}
+	}
+
+	options := &cryostatclient.RecordingCreateOptions{
+		RecordingName: recording.Name,
+		Events:        cryostatclient.EventSpecifier(templateName, templateType),
+		ToDisk:        true,
+	}
+	if recording.Spec.Duration != nil {
+		options.Duration = int64(recording.Spec.Duration.Seconds())
+	}
+	if recording.Spec.MaxAge != nil {
+		options.MaxAge = int64(recording.Spec.MaxAge.Seconds())
+	}
+	if recording.Spec.MaxSize != nil {
+		options.MaxSize = recording.Spec.MaxSize.Value()
+	}
+	return options
+}
+
+func (r *RecordingReconciler) updateRecordingCondition(ctx context.Context, recording *operatorv1beta2.Recording,
+	status metav1.ConditionStatus, reason string, message string) error {
+	meta.SetStatusCondition(&recording.Status.Conditions, metav1.Condition{
+		Type:               string(operatorv1beta2.ConditionTypeRecordingSynchronized),
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: recording.Generation,
+	})
+	err := r.Client.Status().Update(ctx, recording)
+	if err != nil {
+		r.Log.Error(err, "failed to update condition", "Recording.Namespace", recording.Namespace,
+			"Recording.Name", recording.Name)
+	}
+	return err
+}
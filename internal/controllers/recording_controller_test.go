@@ -0,0 +1,503 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controllers"
+	"github.com/cryostatio/cryostat-operator/internal/cryostatclient"
+	"github.com/cryostatio/cryostat-operator/internal/test"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type recordingTestInput struct {
+	controller *controllers.RecordingReconciler
+	recording  *operatorv1beta2.Recording
+	*test.ControllerTestInput
+}
+
+var _ = Describe("RecordingController", func() {
+	var t *recordingTestInput
+	appNamespace := "my-apps"
+
+	BeforeEach(func() {
+		t = &recordingTestInput{
+			ControllerTestInput: test.NewControllerTestInput(),
+		}
+		t.recording = t.NewRecording(appNamespace)
+		t.Objs = []ctrlclient.Object{
+			t.NewNamespace(),
+			t.NewOtherNamespace(appNamespace),
+			t.NewCryostatWithTargetStatus(appNamespace).Object,
+			t.NewCABundleSecret(),
+			t.NewRecordingTargetPod("my-app-1", appNamespace, map[string]string{"app": "my-app"}),
+			t.NewRecordingTargetPod("my-app-2", appNamespace, map[string]string{"app": "my-app"}),
+			t.NewRecordingTargetPod("other-app", appNamespace, map[string]string{"app": "other-app"}),
+		}
+		t.CryostatClient = test.NewFakeCryostatClient(
+			t.NewRecordingTarget(1, "jvm-1", "my-app-1", appNamespace),
+			t.NewRecordingTarget(2, "jvm-2", "my-app-2", appNamespace),
+			// Another connection to the same JVM
+			t.NewRecordingTarget(3, "jvm-2", "my-app-2", appNamespace),
+			t.NewRecordingTarget(4, "jvm-4", "other-app", appNamespace),
+			// Same pod name and labels, but in a different namespace
+			t.NewRecordingTarget(5, "jvm-5", "my-app-1", "other-namespace"),
+		)
+	})
+
+	JustBeforeEach(func() {
+		t.Objs = append(t.Objs, t.recording)
+		var err error
+		t.controller, err = controllers.NewRecordingReconciler(t.NewReconcilerConfig(&operatorv1beta2.Recording{}))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Describe("reconciling a request", func() {
+		Context("with a label selector", func() {
+			JustBeforeEach(func() {
+				t.reconcileRecording()
+			})
+
+			It("should add a finalizer", func() {
+				t.ExpectFinalizer(t.recording, "operator.cryostat.io/recording.finalizer")
+			})
+
+			It("should connect to Cryostat using its service and CA bundle", func() {
+				Expect(t.CryostatClient.Configs).ToNot(BeEmpty())
+				config := t.CryostatClient.Configs[0]
+				Expect(config.BaseURL.String()).To(Equal("https://cryostat.test.svc:4180"))
+				Expect(config.CACert).To(Equal(t.NewCABundleSecret().Data["ca.crt"]))
+			})
+
+			It("should create a recording in each selected JVM", func() {
+				Expect(t.CryostatClient.Recordings[1]).To(HaveLen(1))
+				Expect(t.CryostatClient.Recordings[2]).To(HaveLen(1))
+				Expect(t.CryostatClient.Recordings[3]).To(BeEmpty())
+				Expect(t.CryostatClient.Recordings[4]).To(BeEmpty())
+				Expect(t.CryostatClient.Recordings[5]).To(BeEmpty())
+			})
+
+			It("should use the default recording options", func() {
+				options := t.CryostatClient.CreateOptions[t.CryostatClient.Recordings[1][0].ID]
+				Expect(options).To(Equal(cryostatclient.RecordingCreateOptions{
+					RecordingName: "my-recording",
+					Events:        "template=Continuous,type=TARGET",
+					ToDisk:        true,
+				}))
+			})
+
+			It("should report the recordings in the status", func() {
+				recording := t.getRecording()
+				Expect(recording.Status.Targets).To(HaveLen(2))
+				t.expectTargetStatus(recording.Status.Targets[0], 1, "my-app-1", cryostatclient.RecordingStateRunning)
+				t.expectTargetStatus(recording.Status.Targets[1], 2, "my-app-2", cryostatclient.RecordingStateRunning)
+				t.expectCondition(recording, metav1.ConditionTrue, "RecordingsSynchronized")
+			})
+
+			It("should not create the recordings again", func() {
+				t.reconcileRecording()
+				Expect(t.CryostatClient.Recordings[1]).To(HaveLen(1))
+				Expect(t.CryostatClient.Recordings[2]).To(HaveLen(1))
+			})
+
+			It("should not archive running recordings", func() {
+				Expect(t.CryostatClient.Archives).To(BeEmpty())
+			})
+
+			It("should not restart a recording removed outside the operator", func() {
+				delete(t.CryostatClient.Recordings, 1)
+				t.reconcileRecording()
+				Expect(t.CryostatClient.Recordings[1]).To(BeEmpty())
+				recording := t.getRecording()
+				Expect(recording.Status.Targets[0].RecordingID).ToNot(BeNil())
+				Expect(recording.Status.Targets[0].State).To(BeEmpty())
+			})
+
+			Context("when a target disappears", func() {
+				JustBeforeEach(func() {
+					t.CryostatClient.Targets = t.CryostatClient.Targets[1:]
+					t.reconcileRecording()
+				})
+
+				It("should remove it from the status", func() {
+					recording := t.getRecording()
+					Expect(recording.Status.Targets).To(HaveLen(1))
+					t.expectTargetStatus(recording.Status.Targets[0], 2, "my-app-2", cryostatclient.RecordingStateRunning)
+				})
+			})
+
+			Context("when deleted", func() {
+				JustBeforeEach(func() {
+					t.deleteRecording()
+				})
+
+				It("should delete the recordings", func() {
+					Expect(t.CryostatClient.Recordings[1]).To(BeEmpty())
+					Expect(t.CryostatClient.Recordings[2]).To(BeEmpty())
+				})
+
+				It("should not archive the recordings", func() {
+					Expect(t.CryostatClient.Archives).To(BeEmpty())
+				})
+
+				It("should remove the finalizer", func() {
+					t.expectNoRecording()
+				})
+			})
+		})
+
+		Context("with recording options", func() {
+			BeforeEach(func() {
+				t.recording = t.NewRecordingWithOptions(appNamespace)
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileRecording()
+			})
+
+			It("should create the recording with the options", func() {
+				options := t.CryostatClient.CreateOptions[t.CryostatClient.Recordings[1][0].ID]
+				Expect(options).To(Equal(cryostatclient.RecordingCreateOptions{
+					RecordingName: "my-recording",
+					Events:        "template=Profiling,type=CUSTOM",
+					Duration:      300,
+					ToDisk:        true,
+					MaxSize:       10 * 1024 * 1024,
+					MaxAge:        3600,
+				}))
+			})
+
+			Context("when the recordings stop", func() {
+				JustBeforeEach(func() {
+					t.CryostatClient.StopAll()
+					t.reconcileRecording()
+				})
+
+				It("should archive each recording", func() {
+					recording := t.getRecording()
+					Expect(recording.Status.Targets).To(HaveLen(2))
+					Expect(recording.Status.Targets[0].State).To(Equal(cryostatclient.RecordingStateStopped))
+					Expect(recording.Status.Targets[0].Archives).To(Equal([]string{"target-1_my-recording_1.jfr"}))
+					Expect(recording.Status.Targets[1].Archives).To(Equal([]string{"target-2_my-recording_2.jfr"}))
+				})
+
+				It("should archive each recording only once", func() {
+					t.reconcileRecording()
+					Expect(t.CryostatClient.Archives).To(HaveLen(2))
+				})
+
+				Context("and the Recording is deleted", func() {
+					JustBeforeEach(func() {
+						t.deleteRecording()
+					})
+
+					It("should not archive the recordings again", func() {
+						Expect(t.CryostatClient.Archives).To(HaveLen(2))
+						Expect(t.CryostatClient.Recordings[1]).To(BeEmpty())
+						Expect(t.CryostatClient.Recordings[2]).To(BeEmpty())
+					})
+				})
+			})
+
+			Context("when deleted while running", func() {
+				JustBeforeEach(func() {
+					t.deleteRecording()
+				})
+
+				It("should archive and delete the recordings", func() {
+					Expect(t.CryostatClient.Archives).To(ConsistOf("target-1_my-recording_1.jfr", "target-2_my-recording_2.jfr"))
+					Expect(t.CryostatClient.Recordings[1]).To(BeEmpty())
+					Expect(t.CryostatClient.Recordings[2]).To(BeEmpty())
+					t.expectNoRecording()
+				})
+			})
+		})
+
+		Context("with a connection URL", func() {
+			BeforeEach(func() {
+				t.recording = t.NewRecordingWithConnectURL(appNamespace, "http://other-app.my-apps.pod:9977")
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileRecording()
+			})
+
+			It("should create a recording in only that target", func() {
+				Expect(t.CryostatClient.Recordings[1]).To(BeEmpty())
+				Expect(t.CryostatClient.Recordings[2]).To(BeEmpty())
+				Expect(t.CryostatClient.Recordings[4]).To(HaveLen(1))
+				recording := t.getRecording()
+				Expect(recording.Status.Targets).To(HaveLen(1))
+				t.expectTargetStatus(recording.Status.Targets[0], 4, "other-app", cryostatclient.RecordingStateRunning)
+			})
+		})
+
+		Context("with a connection URL in another namespace", func() {
+			BeforeEach(func() {
+				t.recording = t.NewRecordingWithConnectURL(appNamespace, "http://my-app-1.other-namespace.pod:9977")
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileRecording()
+			})
+
+			It("should not create a recording", func() {
+				Expect(t.CryostatClient.Recordings[5]).To(BeEmpty())
+				t.expectCondition(t.getRecording(), metav1.ConditionFalse, "NoMatchingTargets")
+			})
+		})
+
+		Context("with both a selector and connection URL", func() {
+			BeforeEach(func() {
+				t.recording.Spec.Target.ConnectURL = "http://other-app.my-apps.pod:9977"
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileRecording()
+			})
+
+			It("should report an invalid target", func() {
+				Expect(t.CryostatClient.Recordings).To(BeEmpty())
+				t.expectCondition(t.getRecording(), metav1.ConditionFalse, "InvalidTarget")
+			})
+		})
+
+		Context("with no matching pods", func() {
+			BeforeEach(func() {
+				t.recording.Spec.Target.Selector.MatchLabels["app"] = "missing"
+			})
+
+			It("should requeue and report no matching targets", func() {
+				result, err := t.reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(time.Minute))
+				t.expectCondition(t.getRecording(), metav1.ConditionFalse, "NoMatchingTargets")
+			})
+		})
+
+		Context("with a missing Cryostat", func() {
+			BeforeEach(func() {
+				t.recording.Spec.Cryostat.Name = "missing"
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileRecording()
+			})
+
+			It("should report that Cryostat was not found", func() {
+				Expect(t.CryostatClient.Configs).To(BeEmpty())
+				t.expectCondition(t.getRecording(), metav1.ConditionFalse, "CryostatNotFound")
+			})
+
+			It("should remove the finalizer when deleted", func() {
+				t.deleteRecording()
+				t.expectNoRecording()
+			})
+		})
+
+		Context("with a paused Cryostat", func() {
+			BeforeEach(func() {
+				cr := t.NewCryostatWithTargetStatus(appNamespace)
+				cr.Spec.Paused = true
+				t.Objs[2] = cr.Object
+			})
+
+			It("should report that Cryostat is paused without requeueing", func() {
+				result, err := t.reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{}))
+				Expect(t.CryostatClient.Configs).To(BeEmpty())
+				t.expectCondition(t.getRecording(), metav1.ConditionFalse, "CryostatPaused")
+			})
+		})
+
+		Context("with reconciliation of Cryostat paused", func() {
+			BeforeEach(func() {
+				cr := t.NewCryostatWithTargetStatus(appNamespace)
+				cr.Spec.ReconcilePaused = true
+				t.Objs[2] = cr.Object
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileRecording()
+			})
+
+			It("should report that reconciliation is paused", func() {
+				Expect(t.CryostatClient.Configs).To(BeEmpty())
+				t.expectCondition(t.getRecording(), metav1.ConditionFalse, "CryostatReconcilePaused")
+			})
+		})
+
+		Context("when deleted while Cryostat is paused", func() {
+			JustBeforeEach(func() {
+				t.reconcileRecording()
+				t.UpdateCryostat(func(cr *operatorv1beta2.Cryostat) {
+					cr.Spec.Paused = true
+				})
+				t.deleteRecording()
+			})
+
+			It("should keep the recordings and finalizer", func() {
+				Expect(t.CryostatClient.Recordings[1]).To(HaveLen(1))
+				t.ExpectFinalizer(t.getRecording(), "operator.cryostat.io/recording.finalizer")
+			})
+
+			Context("then resumed", func() {
+				JustBeforeEach(func() {
+					t.UpdateCryostat(func(cr *operatorv1beta2.Cryostat) {
+						cr.Spec.Paused = false
+					})
+					t.reconcileRecording()
+				})
+
+				It("should delete the recordings", func() {
+					Expect(t.CryostatClient.Recordings[1]).To(BeEmpty())
+					t.expectNoRecording()
+				})
+			})
+		})
+
+		Context("without a Cryostat namespace", func() {
+			BeforeEach(func() {
+				t.recording = t.NewRecording(t.Namespace)
+				t.recording.Spec.Cryostat.Namespace = ""
+				t.Objs[2] = t.NewCryostatWithTargetStatus(t.Namespace).Object
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileRecording()
+			})
+
+			It("should use the Cryostat in the Recording's namespace", func() {
+				Expect(t.CryostatClient.Configs).ToNot(BeEmpty())
+				t.expectCondition(t.getRecording(), metav1.ConditionFalse, "NoMatchingTargets")
+			})
+		})
+
+		Context("in a namespace not targeted by Cryostat", func() {
+			BeforeEach(func() {
+				t.Objs[2] = t.NewCryostatWithTargetStatus("other-namespace").Object
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileRecording()
+			})
+
+			It("should report that the namespace is not targeted", func() {
+				Expect(t.CryostatClient.Configs).To(BeEmpty())
+				t.expectCondition(t.getRecording(), metav1.ConditionFalse, "NamespaceNotTargeted")
+			})
+		})
+
+		Context("with cert-manager disabled", func() {
+			BeforeEach(func() {
+				cr := t.NewCryostatWithTargetStatus(appNamespace)
+				certManager := false
+				cr.Spec.EnableCertManager = &certManager
+				t.Objs[2] = cr.Object
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileRecording()
+			})
+
+			It("should connect to Cryostat without TLS", func() {
+				Expect(t.CryostatClient.Configs).ToNot(BeEmpty())
+				config := t.CryostatClient.Configs[0]
+				Expect(config.BaseURL.String()).To(Equal("http://cryostat.test.svc:4180"))
+				Expect(config.CACert).To(BeEmpty())
+			})
+		})
+
+		Context("when the Cryostat API fails", func() {
+			BeforeEach(func() {
+				t.CryostatClient.Err = errors.New("connection refused")
+			})
+
+			It("should return an error and report it", func() {
+				_, err := t.reconcile()
+				Expect(err).To(HaveOccurred())
+				t.expectCondition(t.getRecording(), metav1.ConditionFalse, "CryostatAPIError")
+			})
+		})
+	})
+
+	Describe("setting up the controller", func() {
+		JustBeforeEach(func() {
+			err := t.controller.SetupWithManager(nil)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should reconcile Recordings when their Cryostat changes", func() {
+			builder := t.ControllerBuilder
+			Expect(builder.MapFuncs).To(HaveLen(1))
+			other := t.NewRecording(appNamespace)
+			other.Name = "other-recording"
+			other.Spec.Cryostat.Name = "other"
+			Expect(t.Client.Create(context.Background(), other)).To(Succeed())
+
+			requests := builder.MapFuncs[0](context.Background(), t.NewCryostat().Object)
+			Expect(requests).To(ConsistOf(reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: "my-recording", Namespace: appNamespace},
+			}))
+		})
+	})
+})
+
+func (t *recordingTestInput) reconcile() (reconcile.Result, error) {
+	return t.Reconcile(t.controller, t.recording)
+}
+
+func (t *recordingTestInput) reconcileRecording() {
+	t.ExpectReconcile(t.controller, t.recording)
+}
+
+func (t *recordingTestInput) deleteRecording() {
+	t.DeleteAndReconcile(t.controller, t.getRecording())
+}
+
+func (t *recordingTestInput) getRecording() *operatorv1beta2.Recording {
+	recording := t.recording.DeepCopy()
+	t.Get(recording)
+	return recording
+}
+
+func (t *recordingTestInput) expectNoRecording() {
+	t.ExpectNotFound(t.recording.DeepCopy())
+}
+
+func (t *recordingTestInput) expectTargetStatus(status operatorv1beta2.RecordingTargetStatus, targetID int64,
+	podName string, state string) {
+	Expect(status.TargetID).To(Equal(targetID))
+	Expect(status.PodName).To(Equal(podName))
+	Expect(status.ConnectURL).To(Equal(t.NewRecordingTarget(targetID, "", podName, t.recording.Namespace).ConnectURL))
+	Expect(status.RecordingID).ToNot(BeNil())
+	Expect(*status.RecordingID).To(Equal(t.CryostatClient.Recordings[targetID][0].ID))
+	Expect(status.State).To(Equal(state))
+}
+
+func (t *recordingTestInput) expectCondition(recording *operatorv1beta2.Recording, status metav1.ConditionStatus, reason string) {
+	test.ExpectCondition(recording.Status.Conditions, string(operatorv1beta2.ConditionTypeRecordingSynchronized),
+		status, reason, recording.Generation)
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
 	routev1 "github.com/openshift/api/route/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -62,7 +63,7 @@ func (r *Reconciler) reconcileRoute(ctx context.Context, route *routev1.Route, s
 	if err != nil {
 		return nil, err
 	}
-	route, err = r.createOrUpdateRoute(ctx, route, cr.Object, svc, port, tls, config)
+	route, err = r.createOrUpdateRoute(ctx, route, cr.Object, svc, port, newRouteTLSConfig(tls), config)
 	if err != nil {
 		return nil, err
 	}
@@ -78,23 +79,23 @@ func (r *Reconciler) reconcileRoute(ctx context.Context, route *routev1.Route, s
 	}, nil
 }
 
-func (r *Reconciler) createOrUpdateRoute(ctx context.Context, route *routev1.Route, owner metav1.Object,
-	svc *corev1.Service, exposePort *corev1.ServicePort, tlsConfig *resource_definitions.TLSConfig, config *operatorv1beta2.NetworkConfiguration) (*routev1.Route, error) {
+func newRouteTLSConfig(tlsConfig *resource_definitions.TLSConfig) *routev1.TLSConfig {
 	// Use edge termination by default
-	var routeTLS *routev1.TLSConfig
 	if tlsConfig == nil {
-		routeTLS = &routev1.TLSConfig{
+		return &routev1.TLSConfig{
 			Termination:                   routev1.TLSTerminationEdge,
 			InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
 		}
-	} else {
-		routeTLS = &routev1.TLSConfig{
-			Termination:                   routev1.TLSTerminationReencrypt,
-			DestinationCACertificate:      string(tlsConfig.CACert),
-			InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
-		}
 	}
+	return &routev1.TLSConfig{
+		Termination:                   routev1.TLSTerminationReencrypt,
+		DestinationCACertificate:      string(tlsConfig.CACert),
+		InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
+	}
+}
 
+func (r *Reconciler) createOrUpdateRoute(ctx context.Context, route *routev1.Route, owner metav1.Object,
+	svc *corev1.Service, exposePort *corev1.ServicePort, routeTLS *routev1.TLSConfig, config *operatorv1beta2.NetworkConfiguration) (*routev1.Route, error) {
 	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, route, func() error {
 		// Set labels and annotations from CR
 		common.MergeLabelsAndAnnotations(&route.ObjectMeta, config.Labels, config.Annotations)
@@ -154,3 +155,13 @@ func configureRoute(config *operatorv1beta2.NetworkConfiguration, appLabel strin
 	config.Labels["app"] = appLabel
 	config.Labels["component"] = componentLabel
 }
+
+func (r *Reconciler) deleteRoute(ctx context.Context, route *routev1.Route) error {
+	err := r.Client.Delete(ctx, route)
+	if err != nil && !errors.IsNotFound(err) {
+		r.Log.Error(err, "Could not delete route", "name", route.Name, "namespace", route.Namespace)
+		return err
+	}
+	r.Log.Info("Route deleted", "name", route.Name, "namespace", route.Namespace)
+	return nil
+}
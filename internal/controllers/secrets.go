@@ -46,6 +46,7 @@ func (r *Reconciler) reconcileAuthProxyCookieSecret(ctx context.Context, cr *mod
 	}
 
 	return r.createOrUpdateSecret(ctx, secret, cr.Object, func() error {
+		mergeResourceMetadata(cr, &secret.ObjectMeta)
 		if secret.StringData == nil {
 			secret.StringData = map[string]string{}
 		}
@@ -88,6 +89,7 @@ func (r *Reconciler) reconcileDatabaseConnectionSecret(ctx context.Context, cr *
 
 	if !secretProvided {
 		err := r.createOrUpdateSecret(ctx, secret, cr.Object, func() error {
+			mergeResourceMetadata(cr, &secret.ObjectMeta)
 			if secret.StringData == nil {
 				secret.StringData = map[string]string{}
 			}
@@ -127,6 +129,7 @@ func (r *Reconciler) reconcileStorageSecret(ctx context.Context, cr *model.Cryos
 	}
 
 	err := r.createOrUpdateSecret(ctx, secret, cr.Object, func() error {
+		mergeResourceMetadata(cr, &secret.ObjectMeta)
 		if secret.StringData == nil {
 			secret.StringData = map[string]string{}
 		}
@@ -65,6 +65,23 @@ func (r *Reconciler) reconcileCoreService(ctx context.Context, cr *model.Cryosta
 		return err
 	}
 
+	if r.IsGatewayAPIInstalled {
+		err = r.reconcileCoreHTTPRoute(ctx, svc, cr, tls, specs)
+		if err != nil {
+			return err
+		}
+		if isCoreHTTPRouteRequested(cr) {
+			// The HTTPRoute replaces the Route or Ingress
+			if r.IsOpenShift {
+				return r.deleteRoute(ctx, newCoreRoute(cr))
+			}
+			return r.deleteIngress(ctx, newCoreIngress(cr))
+		}
+	} else if isCoreHTTPRouteRequested(cr) {
+		r.Log.Info("Gateway API is not installed, ignoring HTTPRoute configuration",
+			"name", cr.Name, "namespace", cr.InstallNamespace)
+	}
+
 	if r.IsOpenShift {
 		return r.reconcileCoreRoute(ctx, svc, cr, tls, specs)
 	} else {
@@ -116,6 +133,59 @@ func (r *Reconciler) reconcileReportsService(ctx context.Context, cr *model.Cryo
 	return nil
 }
 
+func (r *Reconciler) reconcileGrafanaService(ctx context.Context, cr *model.CryostatInstance,
+	tls *resource_definitions.TLSConfig, specs *resource_definitions.ServiceSpecs) error {
+	config := configureGrafanaService(cr)
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name + "-grafana",
+			Namespace: cr.InstallNamespace,
+		},
+	}
+
+	if !resource_definitions.IsGrafanaSeparate(cr) {
+		// Delete service if it exists
+		return r.deleteService(ctx, svc)
+	}
+	err := r.createOrUpdateService(ctx, svc, cr.Object, &config.ServiceConfig, func() error {
+		svc.Spec.Selector = map[string]string{
+			"app":       cr.Name,
+			"component": "grafana",
+		}
+		svc.Spec.Ports = []corev1.ServicePort{
+			{
+				Name:       constants.HttpPortName,
+				Port:       *config.HTTPPort,
+				TargetPort: intstr.IntOrString{IntVal: constants.GrafanaContainerPort},
+			},
+			{
+				Name:       "datasource",
+				Port:       *config.DatasourcePort,
+				TargetPort: intstr.IntOrString{IntVal: constants.DatasourceContainerPort},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Set Grafana and datasource URLs for the auth proxy and Cryostat to use
+	scheme := "https"
+	if tls == nil {
+		scheme = "http"
+	}
+	specs.GrafanaURL = &url.URL{
+		Scheme: scheme,
+		Host:   fmt.Sprintf("%s:%d", svc.Name, *config.HTTPPort),
+	}
+	specs.DatasourceURL = &url.URL{
+		Scheme: scheme,
+		Host:   fmt.Sprintf("%s:%d", svc.Name, *config.DatasourcePort),
+	}
+	return nil
+}
+
 func newAgentService(cr *model.CryostatInstance) *corev1.Service {
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -125,11 +195,12 @@ func newAgentService(cr *model.CryostatInstance) *corev1.Service {
 	}
 }
 
-func (r *Reconciler) reconcileAgentGatewayService(ctx context.Context, cr *model.CryostatInstance) error {
+func (r *Reconciler) reconcileAgentGatewayService(ctx context.Context, cr *model.CryostatInstance,
+	tls *resource_definitions.TLSConfig) error {
 	svc := newAgentService(cr)
 	config := configureAgentGatewayService(cr)
 
-	return r.createOrUpdateService(ctx, svc, cr.Object, &config.ServiceConfig, func() error {
+	err := r.createOrUpdateService(ctx, svc, cr.Object, &config.ServiceConfig, func() error {
 		svc.Spec.Selector = map[string]string{
 			"app":       cr.Name,
 			"component": "cryostat",
@@ -143,6 +214,12 @@ func (r *Reconciler) reconcileAgentGatewayService(ctx context.Context, cr *model
 		}
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	// Expose the agent gateway outside of the cluster, if requested
+	return r.reconcileAgentGatewayNetwork(ctx, cr, svc, tls)
 }
 
 func (r *Reconciler) reconcileDatabaseService(ctx context.Context, cr *model.CryostatInstance, tls *resource_definitions.TLSConfig,
@@ -332,6 +409,31 @@ func configureReportsService(cr *model.CryostatInstance) *operatorv1beta2.Report
 	return config
 }
 
+func configureGrafanaService(cr *model.CryostatInstance) *operatorv1beta2.GrafanaServiceConfig {
+	// Check CR for config
+	var config *operatorv1beta2.GrafanaServiceConfig
+	if cr.Spec.ServiceOptions == nil || cr.Spec.ServiceOptions.GrafanaConfig == nil {
+		config = &operatorv1beta2.GrafanaServiceConfig{}
+	} else {
+		config = cr.Spec.ServiceOptions.GrafanaConfig.DeepCopy()
+	}
+
+	// Apply common service defaults
+	configureService(&config.ServiceConfig, cr.Name, "grafana")
+
+	// Apply default HTTP and datasource ports if not provided
+	if config.HTTPPort == nil {
+		httpPort := constants.GrafanaContainerPort
+		config.HTTPPort = &httpPort
+	}
+	if config.DatasourcePort == nil {
+		datasourcePort := constants.DatasourceContainerPort
+		config.DatasourcePort = &datasourcePort
+	}
+
+	return config
+}
+
 func configureDatabaseService(cr *model.CryostatInstance) *operatorv1beta2.DatabaseServiceConfig {
 	// Check CR for config
 	var config *operatorv1beta2.DatabaseServiceConfig
@@ -0,0 +1,283 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	resources "github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// componentDeployment describes where to find the status of a Cryostat component
+type componentDeployment struct {
+	name           string
+	deploymentName string
+	containerName  string
+	endpoint       *url.URL
+}
+
+func getComponentDeployments(cr *model.CryostatInstance, specs *resources.ServiceSpecs) []componentDeployment {
+	components := []componentDeployment{
+		{name: "core", deploymentName: cr.Name, containerName: cr.Name, endpoint: specs.CoreURL},
+	}
+	if cr.Spec.ReportOptions != nil && cr.Spec.ReportOptions.Replicas > 0 {
+		components = append(components, componentDeployment{name: "reports", deploymentName: cr.Name + "-reports",
+			containerName: cr.Name + "-reports", endpoint: specs.ReportsURL})
+	}
+	if resources.IsGrafanaSeparate(cr) {
+		components = append(components, componentDeployment{name: "grafana", deploymentName: cr.Name + "-grafana",
+			containerName: cr.Name + "-grafana", endpoint: specs.GrafanaURL})
+	}
+	return append(components,
+		componentDeployment{name: "database", deploymentName: cr.Name + "-database", containerName: cr.Name + "-db",
+			endpoint: specs.DatabaseURL},
+		componentDeployment{name: "storage", deploymentName: cr.Name + "-storage", containerName: cr.Name + "-storage",
+			endpoint: specs.StorageURL},
+	)
+}
+
+// Event types to inform users of components rolling out new images
+const (
+	eventComponentUpgradeStartedType   = "ComponentUpgradeStarted"
+	eventComponentUpgradeSucceededType = "ComponentUpgradeSucceeded"
+	eventComponentUpgradeFailedType    = "ComponentUpgradeFailed"
+)
+
+// Reason of a Deployment's Progressing condition when its rollout is stuck
+const deploymentProgressDeadlineExceeded = "ProgressDeadlineExceeded"
+
+// componentRollout is the state of a component's Deployment
+type componentRollout struct {
+	available bool
+	upgrading bool
+	// Only set if the upgrade failed to roll out
+	failure string
+}
+
+// updateReadyStatus summarizes the Deployment of each component, reports whether components are
+// rolling out new images, and sets the Ready condition once all components are available.
+// It also records that the current generation was reconciled.
+func (r *Reconciler) updateReadyStatus(ctx context.Context, cr *model.CryostatInstance, specs *resources.ServiceSpecs) error {
+	components := []operatorv1beta2.ComponentStatus{}
+	unavailable, upgrading, failures := []string{}, []string{}, []string{}
+	for _, component := range getComponentDeployments(cr, specs) {
+		previous := findComponentStatus(cr.Status.Components, component.name)
+		status, rollout, err := r.getComponentStatus(ctx, cr, &component, previous)
+		if err != nil {
+			return err
+		}
+		components = append(components, *status)
+		if !rollout.available {
+			unavailable = append(unavailable, component.name)
+		}
+		if rollout.upgrading {
+			upgrading = append(upgrading, component.name)
+		}
+		if len(rollout.failure) > 0 {
+			failures = append(failures, fmt.Sprintf("%s: %s", component.name, rollout.failure))
+		}
+		r.emitComponentUpgradeEvents(cr, previous, status)
+	}
+	cr.Status.Components = components
+	cr.Status.Version = getImageVersion(components[0].Image)
+	cr.Status.ObservedGeneration = cr.Object.GetGeneration()
+
+	if len(upgrading) > 0 {
+		setCondition(cr, operatorv1beta2.ConditionTypeUpgrading, metav1.ConditionTrue, reasonUpgradeInProgress,
+			fmt.Sprintf("Rolling out new images for components: %s.", strings.Join(upgrading, ", ")))
+	} else {
+		setCondition(cr, operatorv1beta2.ConditionTypeUpgrading, metav1.ConditionFalse, reasonUpgradeComplete,
+			"All components are running their desired images.")
+	}
+	if len(failures) > 0 {
+		message := fmt.Sprintf("New images failed to roll out for components. %s.", strings.Join(failures, "; "))
+		if !meta.IsStatusConditionTrue(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeUpgradeFailed)) {
+			r.EventRecorder.Event(cr.Object, corev1.EventTypeWarning, eventComponentUpgradeFailedType, message)
+		}
+		setCondition(cr, operatorv1beta2.ConditionTypeUpgradeFailed, metav1.ConditionTrue, reasonUpgradeRolloutFailed, message)
+	} else {
+		setCondition(cr, operatorv1beta2.ConditionTypeUpgradeFailed, metav1.ConditionFalse, reasonNoUpgradeFailures,
+			"No components failed to roll out new images.")
+	}
+
+	if cr.Spec.Paused {
+		return r.updateCondition(ctx, cr, operatorv1beta2.ConditionTypeReady, metav1.ConditionFalse, reasonPaused,
+			"Cryostat is paused.")
+	}
+	if len(unavailable) > 0 {
+		return r.updateCondition(ctx, cr, operatorv1beta2.ConditionTypeReady, metav1.ConditionFalse,
+			reasonComponentsNotReady, fmt.Sprintf("Components not yet available: %s.", strings.Join(unavailable, ", ")))
+	}
+	return r.updateCondition(ctx, cr, operatorv1beta2.ConditionTypeReady, metav1.ConditionTrue, reasonAllComponentsReady,
+		"All Cryostat components are available.")
+}
+
+// getComponentStatus summarizes the component's Deployment and the image run by its pods
+func (r *Reconciler) getComponentStatus(ctx context.Context, cr *model.CryostatInstance, component *componentDeployment,
+	previous *operatorv1beta2.ComponentStatus) (*operatorv1beta2.ComponentStatus, *componentRollout, error) {
+	status := &operatorv1beta2.ComponentStatus{
+		Name:           component.name,
+		DeploymentName: component.deploymentName,
+	}
+	if component.endpoint != nil {
+		status.Endpoint = component.endpoint.String()
+	}
+	rollout := &componentRollout{}
+
+	deploy := &appsv1.Deployment{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: component.deploymentName, Namespace: cr.InstallNamespace}, deploy)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return status, rollout, nil
+		}
+		return nil, nil, err
+	}
+
+	status.Replicas = 1
+	if deploy.Spec.Replicas != nil {
+		status.Replicas = *deploy.Spec.Replicas
+	}
+	status.ReadyReplicas = deploy.Status.ReadyReplicas
+	for _, container := range deploy.Spec.Template.Spec.Containers {
+		if container.Name == component.containerName {
+			status.Image = container.Image
+		}
+	}
+
+	status.CurrentImage, status.CurrentImageDigest, err = r.getCurrentImage(ctx, deploy, component.containerName, status.Image)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(status.CurrentImage) == 0 && previous != nil {
+		// No pods are ready, such as while scaled down, so the image they run is unchanged
+		status.CurrentImage, status.CurrentImageDigest = previous.CurrentImage, previous.CurrentImageDigest
+	}
+	status.ImageDigest = getImageDigest(status.Image)
+	if len(status.ImageDigest) == 0 && status.CurrentImage == status.Image {
+		status.ImageDigest = status.CurrentImageDigest
+	}
+
+	available := findDeployCondition(deploy.Status.Conditions, appsv1.DeploymentAvailable)
+	rollout.available = available != nil && available.Status == corev1.ConditionTrue
+	rollout.upgrading = len(status.CurrentImage) > 0 && status.CurrentImage != status.Image
+	progressing := findDeployCondition(deploy.Status.Conditions, appsv1.DeploymentProgressing)
+	if rollout.upgrading && progressing != nil && progressing.Reason == deploymentProgressDeadlineExceeded {
+		rollout.failure = progressing.Message
+	}
+	return status, rollout, nil
+}
+
+// getCurrentImage returns the image, and its digest, run by the named container in the Deployment's ready pods.
+// If the ready pods run different images, such as during a rollout, the image other than the desired one is returned.
+func (r *Reconciler) getCurrentImage(ctx context.Context, deploy *appsv1.Deployment, containerName string,
+	desiredImage string) (string, string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(deploy.Spec.Selector)
+	if err != nil {
+		return "", "", err
+	}
+	pods := &corev1.PodList{}
+	err = r.APIReader.List(ctx, pods, client.InNamespace(deploy.Namespace), client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		return "", "", err
+	}
+
+	image, digest := "", ""
+	for _, pod := range pods.Items {
+		podImage := getPodContainerImage(&pod, containerName)
+		if !isPodReady(&pod) || len(podImage) == 0 || (len(image) > 0 && podImage == desiredImage) {
+			continue
+		}
+		image, digest = podImage, ""
+		for _, container := range pod.Status.ContainerStatuses {
+			if container.Name == containerName {
+				digest = getImageDigest(container.ImageID)
+			}
+		}
+	}
+	return image, digest, nil
+}
+
+// emitComponentUpgradeEvents informs users when a component starts and finishes rolling out a new image
+func (r *Reconciler) emitComponentUpgradeEvents(cr *model.CryostatInstance, previous *operatorv1beta2.ComponentStatus,
+	status *operatorv1beta2.ComponentStatus) {
+	if previous == nil || len(previous.Image) == 0 {
+		// Newly deployed
+		return
+	}
+	if previous.Image != status.Image {
+		r.Log.Info("Upgrading component", "component", status.Name, "from", previous.Image, "to", status.Image)
+		r.EventRecorder.Eventf(cr.Object, corev1.EventTypeNormal, eventComponentUpgradeStartedType,
+			"Upgrading %s from %s to %s", status.Name, previous.Image, status.Image)
+	}
+	if previous.CurrentImage != previous.Image && len(previous.CurrentImage) > 0 && status.CurrentImage == status.Image {
+		r.EventRecorder.Eventf(cr.Object, corev1.EventTypeNormal, eventComponentUpgradeSucceededType,
+			"Upgraded %s to %s", status.Name, status.Image)
+	}
+}
+
+func findComponentStatus(components []operatorv1beta2.ComponentStatus, name string) *operatorv1beta2.ComponentStatus {
+	for i := range components {
+		if components[i].Name == name {
+			return &components[i]
+		}
+	}
+	return nil
+}
+
+func getPodContainerImage(pod *corev1.Pod, containerName string) string {
+	for _, container := range pod.Spec.Containers {
+		if container.Name == containerName {
+			return container.Image
+		}
+	}
+	return ""
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// getImageDigest returns the digest of an image reference or image ID, or an empty string if it has none
+func getImageDigest(image string) string {
+	_, digest, _ := strings.Cut(image, "@")
+	return digest
+}
+
+// getImageVersion returns the tag of an image reference, or an empty string if it has none
+func getImageVersion(image string) string {
+	ref, _, _ := strings.Cut(image, "@")
+	idx := strings.LastIndex(ref, ":")
+	if idx < 0 || idx < strings.LastIndex(ref, "/") {
+		return ""
+	}
+	return ref[idx+1:]
+}
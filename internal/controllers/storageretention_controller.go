@@ -0,0 +1,347 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	resources "github.com/cryostatio/cryostat-operator/internal/controllers/common/resource_definitions"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
+	"github.com/cryostatio/cryostat-operator/internal/cryostatclient"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Verify that *StorageRetentionReconciler implements CommonReconciler.
+var _ CommonReconciler = (*StorageRetentionReconciler)(nil)
+
+// StorageRetentionReconciler applies the retention policy of a Cryostat CR to its
+// archived recordings, and reports how much of the object storage they use
+type StorageRetentionReconciler struct {
+	*ReconcilerConfig
+}
+
+const (
+	// How often archived recordings are pruned and their usage is reported
+	storageRetentionSyncPeriod = 5 * time.Minute
+	// Percentage of the object storage PVC's capacity used by archived recordings
+	// at which the StorageNearCapacity condition becomes true
+	storageNearCapacityPercent = 80
+)
+
+// Reasons for the StorageRetentionApplied and StorageNearCapacity Conditions
+const (
+	reasonRetentionPolicyApplied = "RetentionPolicyApplied"
+	reasonStorageNearCapacity    = "ArchivesNearCapacity"
+	reasonStorageWithinCapacity  = "ArchivesWithinCapacity"
+)
+
+const (
+	eventArchivesPrunedType      = "ArchivedRecordingsPruned"
+	eventStorageNearCapacityType = "StorageNearCapacity"
+)
+
+func NewStorageRetentionReconciler(config *ReconcilerConfig) (*StorageRetentionReconciler, error) {
+	return &StorageRetentionReconciler{
+		ReconcilerConfig: config,
+	}, nil
+}
+
+// Reconcile deletes archived recordings exceeding the retention policy of a Cryostat CR,
+// and records the usage of the remaining archived recordings in its status
+func (r *StorageRetentionReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+
+	// Fetch the Cryostat instance
+	cr := &operatorv1beta2.Cryostat{}
+	err := r.Client.Get(ctx, request.NamespacedName, cr)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		reqLogger.Error(err, "Error reading Cryostat instance")
+		return reconcile.Result{}, err
+	}
+
+	// Archived recordings are removed along with Cryostat. While reconciliation is
+	// paused, leave the status as it is. Reconciled again once resumed.
+	if cr.GetDeletionTimestamp() != nil || cr.Spec.ReconcilePaused {
+		return reconcile.Result{}, nil
+	}
+
+	// Only write the status when this controller changed it
+	original := cr.Status.DeepCopy()
+	retention := getStorageRetention(cr)
+	if retention == nil {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, string(operatorv1beta2.ConditionTypeStorageRetentionApplied))
+	}
+
+	// The Cryostat API is unavailable while paused. Reconciled again once resumed.
+	if cr.Spec.Paused {
+		return reconcile.Result{}, r.updateStorageStatus(ctx, cr, original)
+	}
+
+	if !meta.IsStatusConditionTrue(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeMainDeploymentAvailable)) {
+		if retention != nil {
+			r.setStorageCondition(cr, operatorv1beta2.ConditionTypeStorageRetentionApplied, metav1.ConditionFalse,
+				reasonWaitingForCryostat, "Waiting for Cryostat to become available")
+		}
+		// Reconciled again once the main deployment becomes available
+		return reconcile.Result{}, r.updateStorageStatus(ctx, cr, original)
+	}
+
+	reqLogger.Info("Reconciling archived recording storage")
+
+	apiClient, err := r.newCryostatAPIClient(ctx, model.FromCryostat(cr))
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	archives, err := apiClient.ListArchivedRecordings(ctx)
+	if err != nil {
+		reqLogger.Error(err, "Failed to list archived recordings")
+		if retention != nil {
+			r.setStorageCondition(cr, operatorv1beta2.ConditionTypeStorageRetentionApplied, metav1.ConditionFalse,
+				reasonCryostatAPIError, err.Error())
+			if updateErr := r.updateStorageStatus(ctx, cr, original); updateErr != nil {
+				return reconcile.Result{}, updateErr
+			}
+		}
+		return reconcile.Result{}, err
+	}
+
+	pruned := 0
+	if retention != nil {
+		var expired []cryostatclient.ArchivedRecording
+		archives, expired = selectExpiredArchives(archives, retention, time.Now())
+		for _, archive := range expired {
+			err := apiClient.DeleteArchivedRecording(ctx, archive.Name)
+			if err != nil && !cryostatclient.IsNotFound(err) {
+				r.EventRecorder.Eventf(cr, corev1.EventTypeWarning, eventArchivesPrunedType,
+					"Failed to delete archived recording %s: %s", archive.Name, err.Error())
+				r.setStorageCondition(cr, operatorv1beta2.ConditionTypeStorageRetentionApplied, metav1.ConditionFalse,
+					reasonCryostatAPIError, err.Error())
+				if updateErr := r.updateStorageStatus(ctx, cr, original); updateErr != nil {
+					return reconcile.Result{}, updateErr
+				}
+				return reconcile.Result{}, err
+			}
+			pruned++
+		}
+		if pruned > 0 {
+			reqLogger.Info("Deleted archived recordings exceeding the retention policy", "count", pruned)
+			r.EventRecorder.Eventf(cr, corev1.EventTypeNormal, eventArchivesPrunedType,
+				"Deleted %d archived recording(s) exceeding the retention policy", pruned)
+		}
+		r.setStorageCondition(cr, operatorv1beta2.ConditionTypeStorageRetentionApplied, metav1.ConditionTrue,
+			reasonRetentionPolicyApplied, fmt.Sprintf("%d archived recording(s) within the retention policy, %d deleted",
+				len(archives), pruned))
+	}
+
+	capacity, err := r.getStorageCapacity(ctx, cr)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	usage := newStorageUsageStatus(archives, capacity, pruned)
+	cr.Status.StorageUsage = usage
+	r.checkStorageCapacity(cr, usage)
+
+	// Prune periodically, and keep reporting the usage of the object storage PVC.
+	// Without either, there is nothing to check again.
+	result := reconcile.Result{}
+	if retention != nil || capacity != nil {
+		result.RequeueAfter = storageRetentionSyncPeriod
+	}
+	return result, r.updateStorageStatus(ctx, cr, original)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *StorageRetentionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c := r.NewControllerBuilder(mgr)
+	// The Cryostat controller already uses the default name
+	c = c.Named("storageretention")
+	// Archived recordings are checked periodically, so ignore updates to the status
+	// other than the main deployment becoming available or unavailable
+	c = c.For(&operatorv1beta2.Cryostat{}, c.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{},
+		mainDeploymentAvailabilityChanged)))
+	return c.Complete(r)
+}
+
+func (r *StorageRetentionReconciler) GetConfig() *ReconcilerConfig {
+	return r.ReconcilerConfig
+}
+
+// getStorageCapacity returns the capacity of the Cryostat CR's object storage PVC,
+// or nil if it does not use one
+func (r *StorageRetentionReconciler) getStorageCapacity(ctx context.Context, cr *operatorv1beta2.Cryostat) (*resource.Quantity, error) {
+	cfg := getObjectStorageConfiguration(cr.Spec.StorageOptions)
+	if cfg != nil && cfg.EmptyDir != nil && cfg.EmptyDir.Enabled {
+		return nil, nil
+	}
+	pvc := &corev1.PersistentVolumeClaim{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: resources.PersistentVolumeClaimName(model.FromCryostat(cr), "storage"),
+		Namespace: cr.Namespace}, pvc)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	// Prefer the actual capacity, which may exceed the request, once the PVC is bound
+	if capacity, pres := pvc.Status.Capacity[corev1.ResourceStorage]; pres {
+		return &capacity, nil
+	}
+	if request, pres := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; pres {
+		return &request, nil
+	}
+	return nil, nil
+}
+
+// checkStorageCapacity warns the user when archived recordings are close to
+// filling the object storage PVC
+func (r *StorageRetentionReconciler) checkStorageCapacity(cr *operatorv1beta2.Cryostat, usage *operatorv1beta2.StorageUsageStatus) {
+	if usage.Capacity == nil || usage.Capacity.IsZero() {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, string(operatorv1beta2.ConditionTypeStorageNearCapacity))
+		return
+	}
+	percent := usage.ArchivedRecordingsSize.Value() * 100 / usage.Capacity.Value()
+	if percent >= storageNearCapacityPercent {
+		msg := fmt.Sprintf("Archived recordings use %s (%d%%) of the %s object storage volume",
+			usage.ArchivedRecordingsSize.String(), percent, usage.Capacity.String())
+		// Only warn when the archived recordings first reach the threshold
+		if !meta.IsStatusConditionTrue(cr.Status.Conditions, string(operatorv1beta2.ConditionTypeStorageNearCapacity)) {
+			r.EventRecorder.Event(cr, corev1.EventTypeWarning, eventStorageNearCapacityType, msg)
+		}
+		r.setStorageCondition(cr, operatorv1beta2.ConditionTypeStorageNearCapacity, metav1.ConditionTrue,
+			reasonStorageNearCapacity, msg)
+		return
+	}
+	r.setStorageCondition(cr, operatorv1beta2.ConditionTypeStorageNearCapacity, metav1.ConditionFalse,
+		reasonStorageWithinCapacity, fmt.Sprintf("Archived recordings use %d%% of the object storage volume", percent))
+}
+
+func (r *StorageRetentionReconciler) setStorageCondition(cr *operatorv1beta2.Cryostat, condType operatorv1beta2.CryostatConditionType,
+	status metav1.ConditionStatus, reason string, message string) {
+	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+		Type:               string(condType),
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: cr.Generation,
+	})
+}
+
+func (r *StorageRetentionReconciler) updateStorageStatus(ctx context.Context, cr *operatorv1beta2.Cryostat,
+	original *operatorv1beta2.CryostatStatus) error {
+	if equality.Semantic.DeepEqual(original, &cr.Status) {
+		return nil
+	}
+	err := r.Client.Status().Update(ctx, cr)
+	if err != nil {
+		r.Log.Error(err, "failed to update storage status", "Cryostat.Namespace", cr.Namespace, "Cryostat.Name", cr.Name)
+	}
+	return err
+}
+
+// mainDeploymentAvailabilityChanged accepts updates to a Cryostat CR that change
+// whether its main deployment is available
+var mainDeploymentAvailabilityChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldCr, okOld := e.ObjectOld.(*operatorv1beta2.Cryostat)
+		newCr, okNew := e.ObjectNew.(*operatorv1beta2.Cryostat)
+		if !okOld || !okNew {
+			return false
+		}
+		condType := string(operatorv1beta2.ConditionTypeMainDeploymentAvailable)
+		return meta.IsStatusConditionTrue(oldCr.Status.Conditions, condType) !=
+			meta.IsStatusConditionTrue(newCr.Status.Conditions, condType)
+	},
+}
+
+func getStorageRetention(cr *operatorv1beta2.Cryostat) *operatorv1beta2.StorageRetention {
+	if cr.Spec.StorageOptions == nil {
+		return nil
+	}
+	return cr.Spec.StorageOptions.Retention
+}
+
+// selectExpiredArchives splits the archived recordings into those to keep and those
+// exceeding the retention policy. Newer archived recordings are kept in preference
+// to older ones.
+func selectExpiredArchives(archives []cryostatclient.ArchivedRecording, retention *operatorv1beta2.StorageRetention,
+	now time.Time) (kept []cryostatclient.ArchivedRecording, expired []cryostatclient.ArchivedRecording) {
+	sorted := append([]cryostatclient.ArchivedRecording{}, archives...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].ArchivedTime != sorted[j].ArchivedTime {
+			return sorted[i].ArchivedTime > sorted[j].ArchivedTime
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	perTarget := map[string]int32{}
+	var totalSize int64
+	sizeExceeded := false
+	for _, archive := range sorted {
+		if retention.MaxAge != nil && time.Unix(archive.ArchivedTime, 0).Add(retention.MaxAge.Duration).Before(now) {
+			expired = append(expired, archive)
+			continue
+		}
+		if retention.MaxArchivesPerTarget != nil && perTarget[archive.JvmID] >= *retention.MaxArchivesPerTarget {
+			expired = append(expired, archive)
+			continue
+		}
+		if retention.MaxTotalSize != nil {
+			// Once the limit is reached, all older archived recordings are deleted
+			sizeExceeded = sizeExceeded || totalSize+archive.Size > retention.MaxTotalSize.Value()
+			if sizeExceeded {
+				expired = append(expired, archive)
+				continue
+			}
+		}
+		perTarget[archive.JvmID]++
+		totalSize += archive.Size
+		kept = append(kept, archive)
+	}
+	return kept, expired
+}
+
+func newStorageUsageStatus(archives []cryostatclient.ArchivedRecording, capacity *resource.Quantity,
+	pruned int) *operatorv1beta2.StorageUsageStatus {
+	var size int64
+	for _, archive := range archives {
+		size += archive.Size
+	}
+	now := metav1.Now()
+	return &operatorv1beta2.StorageUsageStatus{
+		ArchivedRecordings:     int32(len(archives)),
+		ArchivedRecordingsSize: *resource.NewQuantity(size, resource.BinarySI),
+		Capacity:               capacity,
+		PrunedRecordings:       int32(pruned),
+		LastUpdateTime:         &now,
+	}
+}
@@ -0,0 +1,436 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controllers"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
+	"github.com/cryostatio/cryostat-operator/internal/cryostatclient"
+	"github.com/cryostatio/cryostat-operator/internal/test"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type storageRetentionTestInput struct {
+	controller *controllers.StorageRetentionReconciler
+	cr         *model.CryostatInstance
+	pvc        *corev1.PersistentVolumeClaim
+	*test.ControllerTestInput
+}
+
+var _ = Describe("StorageRetentionController", func() {
+	var t *storageRetentionTestInput
+
+	BeforeEach(func() {
+		t = &storageRetentionTestInput{
+			ControllerTestInput: test.NewControllerTestInput(),
+		}
+		t.cr = t.NewCryostatWithStorageRetention()
+		t.pvc = t.NewStoragePVC()
+		t.Objs = []ctrlclient.Object{
+			t.NewNamespace(),
+			t.NewCABundleSecret(),
+		}
+		mib := int64(1024 * 1024)
+		t.CryostatClient.ArchivedRecordings = []cryostatclient.ArchivedRecording{
+			t.NewArchivedRecording("abcd", "a1.jfr", mib, time.Hour),
+			t.NewArchivedRecording("abcd", "a2.jfr", mib, 2*time.Hour),
+			t.NewArchivedRecording("abcd", "a3.jfr", mib, 3*time.Hour),
+			t.NewArchivedRecording("abcd", "a4.jfr", mib, 4*time.Hour),
+			t.NewArchivedRecording("efgh", "b1.jfr", 2*mib, 30*time.Minute),
+			t.NewArchivedRecording("efgh", "b2.jfr", 2*mib, 48*time.Hour),
+		}
+	})
+
+	JustBeforeEach(func() {
+		t.Objs = append(t.Objs, t.cr.Object)
+		if t.pvc != nil {
+			t.Objs = append(t.Objs, t.pvc)
+		}
+		var err error
+		t.controller, err = controllers.NewStorageRetentionReconciler(t.NewReconcilerConfig())
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Describe("reconciling a request", func() {
+		Context("with a retention policy", func() {
+			JustBeforeEach(func() {
+				t.reconcileStorage()
+			})
+
+			It("should delete archived recordings exceeding the policy", func() {
+				t.expectArchives("b1.jfr", "a1.jfr", "a2.jfr")
+			})
+
+			It("should report the usage in the status", func() {
+				usage := t.getCryostat().Status.StorageUsage
+				Expect(usage).ToNot(BeNil())
+				Expect(usage.ArchivedRecordings).To(Equal(int32(3)))
+				Expect(usage.ArchivedRecordingsSize.Equal(resource.MustParse("4Mi"))).To(BeTrue())
+				Expect(usage.Capacity).ToNot(BeNil())
+				Expect(usage.Capacity.Equal(resource.MustParse("10Gi"))).To(BeTrue())
+				Expect(usage.PrunedRecordings).To(Equal(int32(3)))
+				Expect(usage.LastUpdateTime).ToNot(BeNil())
+			})
+
+			It("should set the StorageRetentionApplied condition", func() {
+				t.expectCondition(t.getCryostat(), operatorv1beta2.ConditionTypeStorageRetentionApplied,
+					metav1.ConditionTrue, "RetentionPolicyApplied")
+			})
+
+			It("should set the StorageNearCapacity condition", func() {
+				t.expectCondition(t.getCryostat(), operatorv1beta2.ConditionTypeStorageNearCapacity,
+					metav1.ConditionFalse, "ArchivesWithinCapacity")
+			})
+
+			It("should emit an event", func() {
+				Expect(t.Recorder.Events).To(Receive(Equal(
+					"Normal ArchivedRecordingsPruned Deleted 3 archived recording(s) exceeding the retention policy")))
+			})
+
+			It("should requeue to check again later", func() {
+				result, err := t.reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: 5 * time.Minute}))
+				Expect(t.getCryostat().Status.StorageUsage.PrunedRecordings).To(BeZero())
+			})
+		})
+
+		Context("with only a maximum age", func() {
+			BeforeEach(func() {
+				t.cr.Spec.StorageOptions.Retention.MaxTotalSize = nil
+				t.cr.Spec.StorageOptions.Retention.MaxArchivesPerTarget = nil
+			})
+
+			It("should delete older archived recordings", func() {
+				t.reconcileStorage()
+				t.expectArchives("b1.jfr", "a1.jfr", "a2.jfr", "a3.jfr", "a4.jfr")
+			})
+		})
+
+		Context("with only a per-target limit", func() {
+			BeforeEach(func() {
+				t.cr.Spec.StorageOptions.Retention.MaxAge = nil
+				t.cr.Spec.StorageOptions.Retention.MaxTotalSize = nil
+				t.cr.Spec.StorageOptions.Retention.MaxArchivesPerTarget = &[]int32{1}[0]
+			})
+
+			It("should keep the newest archived recording of each target", func() {
+				t.reconcileStorage()
+				t.expectArchives("b1.jfr", "a1.jfr")
+			})
+		})
+
+		Context("with only a maximum total size", func() {
+			BeforeEach(func() {
+				t.cr.Spec.StorageOptions.Retention.MaxAge = nil
+				t.cr.Spec.StorageOptions.Retention.MaxArchivesPerTarget = nil
+			})
+
+			It("should delete the oldest archived recordings", func() {
+				t.reconcileStorage()
+				t.expectArchives("b1.jfr", "a1.jfr", "a2.jfr")
+			})
+		})
+
+		Context("without a retention policy", func() {
+			BeforeEach(func() {
+				t.cr.Spec.StorageOptions = nil
+				t.cr.Status.Conditions = append(t.cr.Status.Conditions, metav1.Condition{
+					Type:   string(operatorv1beta2.ConditionTypeStorageRetentionApplied),
+					Status: metav1.ConditionTrue,
+					Reason: "RetentionPolicyApplied",
+				})
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileStorage()
+			})
+
+			It("should not delete any archived recordings", func() {
+				Expect(t.CryostatClient.ArchivedRecordings).To(HaveLen(6))
+			})
+
+			It("should report the usage in the status", func() {
+				usage := t.getCryostat().Status.StorageUsage
+				Expect(usage).ToNot(BeNil())
+				Expect(usage.ArchivedRecordings).To(Equal(int32(6)))
+				Expect(usage.ArchivedRecordingsSize.Equal(resource.MustParse("8Mi"))).To(BeTrue())
+				Expect(usage.PrunedRecordings).To(BeZero())
+			})
+
+			It("should remove the StorageRetentionApplied condition", func() {
+				condition := meta.FindStatusCondition(t.getCryostat().Status.Conditions,
+					string(operatorv1beta2.ConditionTypeStorageRetentionApplied))
+				Expect(condition).To(BeNil())
+			})
+
+			It("should requeue to report the usage again later", func() {
+				result, err := t.reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: 5 * time.Minute}))
+			})
+
+			Context("and without an object storage PVC", func() {
+				BeforeEach(func() {
+					t.pvc = nil
+				})
+
+				It("should not requeue", func() {
+					result, err := t.reconcile()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result).To(Equal(reconcile.Result{}))
+				})
+			})
+		})
+
+		Context("when archived recordings are close to filling the PVC", func() {
+			BeforeEach(func() {
+				t.pvc.Status.Capacity = corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("5Mi"),
+				}
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileStorage()
+			})
+
+			It("should report the capacity of the bound PVC", func() {
+				capacity := t.getCryostat().Status.StorageUsage.Capacity
+				Expect(capacity).ToNot(BeNil())
+				Expect(capacity.Equal(resource.MustParse("5Mi"))).To(BeTrue())
+			})
+
+			It("should set the StorageNearCapacity condition", func() {
+				t.expectCondition(t.getCryostat(), operatorv1beta2.ConditionTypeStorageNearCapacity,
+					metav1.ConditionTrue, "ArchivesNearCapacity")
+			})
+
+			It("should emit a warning event", func() {
+				Expect(t.Recorder.Events).To(Receive(HavePrefix("Normal ArchivedRecordingsPruned")))
+				Expect(t.Recorder.Events).To(Receive(Equal(
+					"Warning StorageNearCapacity Archived recordings use 4Mi (80%) of the 5Mi object storage volume")))
+			})
+
+			Context("when reconciled again", func() {
+				JustBeforeEach(func() {
+					Expect(t.Recorder.Events).To(Receive(HavePrefix("Normal ArchivedRecordingsPruned")))
+					Expect(t.Recorder.Events).To(Receive(HavePrefix("Warning StorageNearCapacity")))
+					t.reconcileStorage()
+				})
+
+				It("should not emit another warning event", func() {
+					Expect(t.Recorder.Events).ToNot(Receive())
+				})
+
+				It("should keep the StorageNearCapacity condition", func() {
+					t.expectCondition(t.getCryostat(), operatorv1beta2.ConditionTypeStorageNearCapacity,
+						metav1.ConditionTrue, "ArchivesNearCapacity")
+				})
+			})
+		})
+
+		Context("with an emptyDir for object storage", func() {
+			BeforeEach(func() {
+				t.cr.Spec.StorageOptions.ObjectStorage = &operatorv1beta2.StorageConfiguration{
+					EmptyDir: &operatorv1beta2.EmptyDirConfig{
+						Enabled: true,
+					},
+				}
+				t.cr.Status.Conditions = append(t.cr.Status.Conditions, metav1.Condition{
+					Type:   string(operatorv1beta2.ConditionTypeStorageNearCapacity),
+					Status: metav1.ConditionFalse,
+					Reason: "ArchivesWithinCapacity",
+				})
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileStorage()
+			})
+
+			It("should not report a capacity", func() {
+				Expect(t.getCryostat().Status.StorageUsage.Capacity).To(BeNil())
+			})
+
+			It("should remove the StorageNearCapacity condition", func() {
+				condition := meta.FindStatusCondition(t.getCryostat().Status.Conditions,
+					string(operatorv1beta2.ConditionTypeStorageNearCapacity))
+				Expect(condition).To(BeNil())
+			})
+		})
+
+		Context("when Cryostat is not yet available", func() {
+			BeforeEach(func() {
+				t.cr.Status.Conditions = nil
+			})
+
+			It("should wait for Cryostat without requeuing", func() {
+				result, err := t.reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{}))
+				Expect(t.CryostatClient.ArchivedRecordings).To(HaveLen(6))
+				t.expectCondition(t.getCryostat(), operatorv1beta2.ConditionTypeStorageRetentionApplied,
+					metav1.ConditionFalse, "WaitingForCryostat")
+			})
+
+			It("should not update the status again", func() {
+				t.reconcileStorage()
+				resourceVersion := t.getCryostat().ResourceVersion
+				t.reconcileStorage()
+				Expect(t.getCryostat().ResourceVersion).To(Equal(resourceVersion))
+			})
+
+			Context("without a retention policy", func() {
+				BeforeEach(func() {
+					t.cr.Spec.StorageOptions = nil
+				})
+
+				It("should not update the status", func() {
+					resourceVersion := t.getCryostat().ResourceVersion
+					t.reconcileStorage()
+					Expect(t.getCryostat().ResourceVersion).To(Equal(resourceVersion))
+				})
+			})
+		})
+
+		Context("when paused", func() {
+			BeforeEach(func() {
+				t.cr.Spec.Paused = true
+			})
+
+			It("should not contact Cryostat or requeue", func() {
+				resourceVersion := t.getCryostat().ResourceVersion
+				result, err := t.reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{}))
+				Expect(t.CryostatClient.Configs).To(BeEmpty())
+				Expect(t.CryostatClient.ArchivedRecordings).To(HaveLen(6))
+				Expect(t.getCryostat().ResourceVersion).To(Equal(resourceVersion))
+			})
+		})
+
+		Context("with reconciliation paused", func() {
+			BeforeEach(func() {
+				t.cr.Spec.ReconcilePaused = true
+				// A condition that would otherwise be removed without a retention policy
+				t.cr.Spec.StorageOptions = nil
+				meta.SetStatusCondition(&t.cr.Status.Conditions, metav1.Condition{
+					Type:   string(operatorv1beta2.ConditionTypeStorageRetentionApplied),
+					Status: metav1.ConditionTrue,
+					Reason: "RetentionPolicyApplied",
+				})
+			})
+
+			It("should leave Cryostat and its status unchanged", func() {
+				resourceVersion := t.getCryostat().ResourceVersion
+				result, err := t.reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{}))
+				Expect(t.CryostatClient.Configs).To(BeEmpty())
+				Expect(t.getCryostat().ResourceVersion).To(Equal(resourceVersion))
+			})
+		})
+
+		Context("when the Cryostat API fails", func() {
+			BeforeEach(func() {
+				t.CryostatClient.Err = errors.New("connection refused")
+			})
+
+			It("should report the error", func() {
+				_, err := t.reconcile()
+				Expect(err).To(HaveOccurred())
+				t.expectCondition(t.getCryostat(), operatorv1beta2.ConditionTypeStorageRetentionApplied,
+					metav1.ConditionFalse, "CryostatAPIError")
+			})
+		})
+
+		Context("with a missing Cryostat", func() {
+			JustBeforeEach(func() {
+				err := t.Client.Delete(context.Background(), t.cr.Object)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should do nothing", func() {
+				t.reconcileStorage()
+				Expect(t.CryostatClient.Configs).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("setting up the controller", func() {
+		JustBeforeEach(func() {
+			err := t.controller.SetupWithManager(nil)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should use a distinct name", func() {
+			Expect(t.ControllerBuilder.Name).To(Equal("storageretention"))
+		})
+
+		It("should reconcile when the main deployment becomes available", func() {
+			Expect(t.ControllerBuilder.Predicates).To(HaveLen(1))
+			pred := t.ControllerBuilder.Predicates[0]
+			available := t.getCryostat()
+			unavailable := available.DeepCopy()
+			unavailable.Status.Conditions = nil
+			Expect(pred.Update(event.UpdateEvent{ObjectOld: unavailable, ObjectNew: available})).To(BeTrue())
+
+			// Ignore other changes to the status
+			updated := available.DeepCopy()
+			updated.Status.ApplicationURL = "https://updated.example.com"
+			Expect(pred.Update(event.UpdateEvent{ObjectOld: available, ObjectNew: updated})).To(BeFalse())
+		})
+	})
+})
+
+func (t *storageRetentionTestInput) reconcile() (reconcile.Result, error) {
+	return t.Reconcile(t.controller, t.cr.Object)
+}
+
+func (t *storageRetentionTestInput) reconcileStorage() {
+	t.ExpectReconcile(t.controller, t.cr.Object)
+}
+
+func (t *storageRetentionTestInput) getCryostat() *operatorv1beta2.Cryostat {
+	cr := &operatorv1beta2.Cryostat{}
+	cr.Name, cr.Namespace = t.cr.Name, t.cr.InstallNamespace
+	t.Get(cr)
+	return cr
+}
+
+func (t *storageRetentionTestInput) expectArchives(names ...string) {
+	remaining := []string{}
+	for _, archive := range t.CryostatClient.ArchivedRecordings {
+		remaining = append(remaining, archive.Name)
+	}
+	Expect(remaining).To(ConsistOf(names))
+}
+
+func (t *storageRetentionTestInput) expectCondition(cr *operatorv1beta2.Cryostat, condType operatorv1beta2.CryostatConditionType,
+	status metav1.ConditionStatus, reason string) {
+	test.ExpectCondition(cr.Status.Conditions, string(condType), status, reason, cr.Generation)
+}
@@ -0,0 +1,434 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/common"
+	"github.com/cryostatio/cryostat-operator/internal/cryostatclient"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Verify that *StoredCredentialReconciler implements CommonReconciler.
+var _ CommonReconciler = (*StoredCredentialReconciler)(nil)
+
+// StoredCredentialReconciler reconciles a StoredCredential object
+type StoredCredentialReconciler struct {
+	*ReconcilerConfig
+}
+
+// Name used for Finalizer that handles StoredCredential deletion
+const storedCredentialFinalizer = "operator.cryostat.io/storedcredential.finalizer"
+
+// How often stored credentials are checked for removal from Cryostat
+const storedCredentialSyncPeriod = time.Minute
+
+// Keys of the credential within the Secret, matching the kubernetes.io/basic-auth Secret type
+const (
+	credentialUsernameKey = corev1.BasicAuthUsernameKey
+	credentialPasswordKey = corev1.BasicAuthPasswordKey
+)
+
+// Reasons for StoredCredential Conditions
+const (
+	reasonCredentialStored = "CredentialStored"
+	reasonSecretNotFound   = "SecretNotFound"
+	reasonInvalidSecret    = "InvalidSecret"
+	reasonInvalidMatchExpr = "InvalidMatchExpression"
+)
+
+func NewStoredCredentialReconciler(config *ReconcilerConfig) (*StoredCredentialReconciler, error) {
+	return &StoredCredentialReconciler{
+		ReconcilerConfig: config,
+	}, nil
+}
+
+// +kubebuilder:rbac:groups=operator.cryostat.io,resources=storedcredentials,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=operator.cryostat.io,resources=storedcredentials/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=operator.cryostat.io,resources=storedcredentials/finalizers,verbs=update
+
+// Reconcile processes a StoredCredential CR and manages the corresponding stored credential in Cryostat
+func (r *StoredCredentialReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+
+	reqLogger.Info("Reconciling StoredCredential")
+
+	// Fetch the StoredCredential instance
+	credential := &operatorv1beta2.StoredCredential{}
+	err := r.Client.Get(ctx, request.NamespacedName, credential)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			reqLogger.Info("StoredCredential instance not found")
+			return reconcile.Result{}, nil
+		}
+		reqLogger.Error(err, "Error reading StoredCredential instance")
+		return reconcile.Result{}, err
+	}
+
+	// Check if this StoredCredential is being deleted
+	if credential.GetDeletionTimestamp() != nil {
+		if controllerutil.ContainsFinalizer(credential, storedCredentialFinalizer) {
+			err := r.finalizeStoredCredential(ctx, reqLogger, credential)
+			if err != nil {
+				if isCryostatPaused(err) {
+					// Reconciled again once the Cryostat is resumed
+					reqLogger.Info("Cryostat is paused, waiting to remove stored credential")
+					return reconcile.Result{}, nil
+				}
+				return reconcile.Result{}, err
+			}
+
+			err = common.RemoveFinalizer(ctx, r.Client, credential, storedCredentialFinalizer)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		return reconcile.Result{}, nil
+	}
+
+	// Add our finalizer, so we can delete the credential in Cryostat upon deletion
+	if !controllerutil.ContainsFinalizer(credential, storedCredentialFinalizer) {
+		err := common.AddFinalizer(ctx, r.Client, credential, storedCredentialFinalizer)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	cr, err := r.getTargetingCryostat(ctx, &credential.Spec.Cryostat, credential.Namespace)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// Reconciled again once the Cryostat is created
+			return reconcile.Result{}, r.updateStoredCredentialCondition(ctx, credential, metav1.ConditionFalse,
+				reasonCryostatNotFound, fmt.Sprintf("Cryostat %s/%s does not exist",
+					getCryostatNamespace(&credential.Spec.Cryostat, credential.Namespace), credential.Spec.Cryostat.Name))
+		} else if errors.Is(err, errNamespaceNotTargeted) {
+			return reconcile.Result{}, r.updateStoredCredentialCondition(ctx, credential, metav1.ConditionFalse,
+				reasonNamespaceNotTargeted, fmt.Sprintf("Namespace %s is not a target namespace of Cryostat %s/%s",
+					credential.Namespace, getCryostatNamespace(&credential.Spec.Cryostat, credential.Namespace),
+					credential.Spec.Cryostat.Name))
+		} else if isCryostatPaused(err) {
+			// Reconciled again once the Cryostat is resumed
+			reason, message := getCryostatPausedCondition(err,
+				getCryostatNamespace(&credential.Spec.Cryostat, credential.Namespace), credential.Spec.Cryostat.Name)
+			return reconcile.Result{}, r.updateStoredCredentialCondition(ctx, credential, metav1.ConditionFalse, reason, message)
+		}
+		return reconcile.Result{}, err
+	}
+
+	apiClient, err := r.newCryostatAPIClient(ctx, cr)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	secret := &corev1.Secret{}
+	err = r.Client.Get(ctx, types.NamespacedName{Name: credential.Spec.SecretName, Namespace: credential.Namespace}, secret)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+	reason, message := "", ""
+	if exprErr := validateMatchExpression(credential.Spec.MatchExpression); exprErr != nil {
+		reason, message = reasonInvalidMatchExpr, fmt.Sprintf("Match expression is invalid: %s", exprErr.Error())
+	} else if err != nil {
+		reason, message = reasonSecretNotFound, fmt.Sprintf("Secret %s does not exist", credential.Spec.SecretName)
+	} else if len(secret.Data[credentialUsernameKey]) == 0 || len(secret.Data[credentialPasswordKey]) == 0 {
+		reason, message = reasonInvalidSecret, fmt.Sprintf("Secret %s must contain the keys %q and %q",
+			credential.Spec.SecretName, credentialUsernameKey, credentialPasswordKey)
+	}
+	if len(reason) > 0 {
+		// Remove any credential stored from a previous version of the Secret or specification.
+		// Reconciled again once the Secret or StoredCredential is created or updated.
+		err = r.removeStoredCredential(ctx, apiClient, credential)
+		if err != nil {
+			reqLogger.Error(err, "Failed to delete stored credential from Cryostat")
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, r.updateStoredCredentialCondition(ctx, credential, metav1.ConditionFalse, reason, message)
+	}
+
+	err = r.synchronizeStoredCredential(ctx, reqLogger, apiClient, credential, secret)
+	if err != nil {
+		reqLogger.Error(err, "Failed to synchronize stored credential with Cryostat")
+		updateErr := r.updateStoredCredentialCondition(ctx, credential, metav1.ConditionFalse, reasonCryostatAPIError, err.Error())
+		if updateErr != nil {
+			return reconcile.Result{}, updateErr
+		}
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{RequeueAfter: storedCredentialSyncPeriod}, r.updateStoredCredentialCondition(ctx, credential,
+		metav1.ConditionTrue, reasonCredentialStored, fmt.Sprintf("Credential is stored in Cryostat with ID %d",
+			*credential.Status.CredentialID))
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *StoredCredentialReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c := r.NewControllerBuilder(mgr)
+	c = c.For(&operatorv1beta2.StoredCredential{})
+	// Reconcile StoredCredentials whenever the Cryostat they refer to changes, such as its target namespaces
+	c = c.Watches(&operatorv1beta2.Cryostat{}, c.EnqueueRequestsFromMapFunc(r.credentialsForCryostat))
+	// Replace or remove the stored credential whenever its Secret changes
+	c = c.Watches(&corev1.Secret{}, c.EnqueueRequestsFromMapFunc(r.credentialsForSecret))
+	return c.Complete(r)
+}
+
+func (r *StoredCredentialReconciler) GetConfig() *ReconcilerConfig {
+	return r.ReconcilerConfig
+}
+
+func (r *StoredCredentialReconciler) credentialsForCryostat(ctx context.Context, obj client.Object) []reconcile.Request {
+	credentials := &operatorv1beta2.StoredCredentialList{}
+	err := r.Client.List(ctx, credentials)
+	if err != nil {
+		r.Log.Error(err, "failed to list StoredCredentials", "Cryostat", obj.GetName())
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, credential := range credentials.Items {
+		if credential.Spec.Cryostat.Name == obj.GetName() &&
+			getCryostatNamespace(&credential.Spec.Cryostat, credential.Namespace) == obj.GetNamespace() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Name:      credential.Name,
+				Namespace: credential.Namespace,
+			}})
+		}
+	}
+	return requests
+}
+
+func (r *StoredCredentialReconciler) credentialsForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	credentials := &operatorv1beta2.StoredCredentialList{}
+	err := r.Client.List(ctx, credentials, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		r.Log.Error(err, "failed to list StoredCredentials", "Secret", obj.GetName())
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, credential := range credentials.Items {
+		if credential.Spec.SecretName == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Name:      credential.Name,
+				Namespace: credential.Namespace,
+			}})
+		}
+	}
+	return requests
+}
+
+// synchronizeStoredCredential stores the credential in Cryostat, replacing any
+// credential stored from an older version of the Secret or specification
+func (r *StoredCredentialReconciler) synchronizeStoredCredential(ctx context.Context, reqLogger logr.Logger,
+	apiClient cryostatclient.Client, credential *operatorv1beta2.StoredCredential, secret *corev1.Secret) error {
+	matchExpression := getStoredCredentialMatchExpression(credential)
+	if credential.Status.CredentialID != nil {
+		if credential.Status.SecretResourceVersion == secret.ResourceVersion &&
+			credential.Status.MatchExpression == matchExpression {
+			// Verify that the credential was not removed from Cryostat
+			_, err := apiClient.GetCredential(ctx, *credential.Status.CredentialID)
+			if err == nil {
+				return nil
+			}
+			if !cryostatclient.IsNotFound(err) {
+				return err
+			}
+			reqLogger.Info("Stored credential is missing from Cryostat, storing it again",
+				"CredentialID", *credential.Status.CredentialID)
+			credential.Status.CredentialID = nil
+		} else {
+			// Cryostat does not support updating a stored credential, so replace it
+			err := r.removeStoredCredential(ctx, apiClient, credential)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// A credential may have been stored by an earlier attempt whose status update failed.
+	// Its password cannot be verified, so replace it rather than storing a duplicate.
+	err := r.removeOrphanedCredentials(ctx, reqLogger, apiClient, matchExpression)
+	if err != nil {
+		return err
+	}
+
+	created, err := apiClient.CreateCredential(ctx, &cryostatclient.CredentialCreateOptions{
+		MatchExpression: matchExpression,
+		Username:        string(secret.Data[credentialUsernameKey]),
+		Password:        string(secret.Data[credentialPasswordKey]),
+	})
+	if err != nil {
+		return err
+	}
+	reqLogger.Info("Stored credential in Cryostat", "CredentialID", created.ID)
+	credential.Status.CredentialID = &created.ID
+	credential.Status.MatchExpression = matchExpression
+	credential.Status.SecretResourceVersion = secret.ResourceVersion
+	return nil
+}
+
+// removeOrphanedCredentials deletes any credentials in Cryostat with the match expression
+// of this StoredCredential, which are not recorded in its status
+func (r *StoredCredentialReconciler) removeOrphanedCredentials(ctx context.Context, reqLogger logr.Logger,
+	apiClient cryostatclient.Client, matchExpression string) error {
+	credentials, err := apiClient.ListCredentials(ctx)
+	if err != nil {
+		return err
+	}
+	for _, existing := range credentials {
+		if existing.MatchExpression != matchExpression {
+			continue
+		}
+		reqLogger.Info("Removing orphaned stored credential from Cryostat", "CredentialID", existing.ID)
+		err = apiClient.DeleteCredential(ctx, existing.ID)
+		if err != nil && !cryostatclient.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeStoredCredential deletes the credential from Cryostat, if one was stored
+func (r *StoredCredentialReconciler) removeStoredCredential(ctx context.Context, apiClient cryostatclient.Client,
+	credential *operatorv1beta2.StoredCredential) error {
+	if credential.Status.CredentialID == nil {
+		return nil
+	}
+	err := apiClient.DeleteCredential(ctx, *credential.Status.CredentialID)
+	if err != nil && !cryostatclient.IsNotFound(err) {
+		return err
+	}
+	credential.Status.CredentialID = nil
+	credential.Status.MatchExpression = ""
+	credential.Status.SecretResourceVersion = ""
+	return nil
+}
+
+func (r *StoredCredentialReconciler) finalizeStoredCredential(ctx context.Context, reqLogger logr.Logger,
+	credential *operatorv1beta2.StoredCredential) error {
+	cr, err := r.getTargetingCryostat(ctx, &credential.Spec.Cryostat, credential.Namespace)
+	if err != nil {
+		if kerrors.IsNotFound(err) || errors.Is(err, errNamespaceNotTargeted) {
+			// Cryostat no longer targets this namespace, so there is nothing to clean up
+			reqLogger.Info("Cryostat not available, skipping removal of stored credential")
+			return nil
+		}
+		return err
+	}
+
+	apiClient, err := r.newCryostatAPIClient(ctx, cr)
+	if err != nil {
+		return err
+	}
+	return r.removeStoredCredential(ctx, apiClient, credential)
+}
+
+// getStoredCredentialMatchExpression limits the credential's match expression to targets
+// within its own namespace, so it cannot be used to connect to targets in other namespaces
+func getStoredCredentialMatchExpression(credential *operatorv1beta2.StoredCredential) string {
+	namespaceExpr := fmt.Sprintf("target.annotations.cryostat['%s'] == '%s'", cryostatclient.AnnotationNamespace,
+		credential.Namespace)
+	if len(credential.Spec.MatchExpression) == 0 {
+		return namespaceExpr
+	}
+	return fmt.Sprintf("%s && (%s)", namespaceExpr, credential.Spec.MatchExpression)
+}
+
+// Opening bracket for each closing bracket in a CEL expression
+var openingBrackets = map[byte]byte{')': '(', ']': '[', '}': '{'}
+
+// validateMatchExpression checks that the expression is self-contained, so that it cannot
+// close the parentheses it is wrapped in and escape the namespace restriction. The full
+// CEL syntax is checked by Cryostat when the credential is stored.
+func validateMatchExpression(expression string) error {
+	var stack []byte
+	for i := 0; i < len(expression); i++ {
+		c := expression[i]
+		switch c {
+		case '\'', '"':
+			end, err := skipStringLiteral(expression, i)
+			if err != nil {
+				return err
+			}
+			i = end
+		case '(', '[', '{':
+			stack = append(stack, c)
+		case ')', ']', '}':
+			if len(stack) == 0 || stack[len(stack)-1] != openingBrackets[c] {
+				return fmt.Errorf("unexpected %q at position %d", c, i)
+			}
+			stack = stack[:len(stack)-1]
+		case '/':
+			if strings.HasPrefix(expression[i:], "//") {
+				return fmt.Errorf("comments are not allowed, found at position %d", i)
+			}
+		}
+	}
+	if len(stack) > 0 {
+		return fmt.Errorf("%q is never closed", stack[len(stack)-1])
+	}
+	return nil
+}
+
+// skipStringLiteral returns the index of the closing quote of the CEL string literal
+// starting at the given index
+func skipStringLiteral(expression string, start int) (int, error) {
+	quote := expression[start : start+1]
+	if strings.HasPrefix(expression[start:], strings.Repeat(quote, 3)) {
+		quote = strings.Repeat(quote, 3)
+	}
+	// Backslashes are not escapes within raw strings, such as r'\'
+	prefix := strings.ToLower(expression[max(start-2, 0):start])
+	raw := strings.HasSuffix(prefix, "r") || prefix == "rb"
+	for i := start + len(quote); i < len(expression); i++ {
+		if expression[i] == '\\' && !raw {
+			i++
+		} else if strings.HasPrefix(expression[i:], quote) {
+			return i + len(quote) - 1, nil
+		}
+	}
+	return 0, fmt.Errorf("string starting at position %d is never closed", start)
+}
+
+func (r *StoredCredentialReconciler) updateStoredCredentialCondition(ctx context.Context,
+	credential *operatorv1beta2.StoredCredential, status metav1.ConditionStatus, reason string, message string) error {
+	meta.SetStatusCondition(&credential.Status.Conditions, metav1.Condition{
+		Type:               string(operatorv1beta2.ConditionTypeStoredCredentialSynchronized),
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: credential.Generation,
+	})
+	err := r.Client.Status().Update(ctx, credential)
+	if err != nil {
+		r.Log.Error(err, "failed to update condition", "StoredCredential.Namespace", credential.Namespace,
+			"StoredCredential.Name", credential.Name)
+	}
+	return err
+}
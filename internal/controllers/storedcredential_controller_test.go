@@ -0,0 +1,455 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controllers"
+	"github.com/cryostatio/cryostat-operator/internal/cryostatclient"
+	"github.com/cryostatio/cryostat-operator/internal/test"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type storedCredentialTestInput struct {
+	controller *controllers.StoredCredentialReconciler
+	credential *operatorv1beta2.StoredCredential
+	*test.ControllerTestInput
+}
+
+var _ = Describe("StoredCredentialController", func() {
+	var t *storedCredentialTestInput
+	appNamespace := "my-apps"
+	expectedExpression := "target.annotations.cryostat['NAMESPACE'] == 'my-apps' && (target.labels.app == 'my-app')"
+
+	BeforeEach(func() {
+		t = &storedCredentialTestInput{
+			ControllerTestInput: test.NewControllerTestInput(),
+		}
+		t.credential = t.NewStoredCredential(appNamespace)
+		t.Objs = []ctrlclient.Object{
+			t.NewNamespace(),
+			t.NewOtherNamespace(appNamespace),
+			t.NewCryostatWithTargetStatus(appNamespace).Object,
+			t.NewCABundleSecret(),
+			t.NewStoredCredentialSecret(appNamespace),
+		}
+	})
+
+	JustBeforeEach(func() {
+		t.Objs = append(t.Objs, t.credential)
+		var err error
+		t.controller, err = controllers.NewStoredCredentialReconciler(t.NewReconcilerConfig(&operatorv1beta2.StoredCredential{}))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Describe("reconciling a request", func() {
+		Context("with a valid Secret", func() {
+			JustBeforeEach(func() {
+				t.reconcileStoredCredential()
+			})
+
+			It("should add a finalizer", func() {
+				t.ExpectFinalizer(t.credential, "operator.cryostat.io/storedcredential.finalizer")
+			})
+
+			It("should store the credential limited to its namespace", func() {
+				Expect(t.CryostatClient.Credentials).To(Equal(map[int64]cryostatclient.CredentialCreateOptions{
+					1: {
+						MatchExpression: expectedExpression,
+						Username:        "jmx-user",
+						Password:        "jmx-pass",
+					},
+				}))
+			})
+
+			It("should report the credential in the status", func() {
+				credential := t.getStoredCredential()
+				Expect(credential.Status.CredentialID).To(Equal(&[]int64{1}[0]))
+				Expect(credential.Status.MatchExpression).To(Equal(expectedExpression))
+				Expect(credential.Status.SecretResourceVersion).To(Equal(t.getSecret().ResourceVersion))
+				t.expectCondition(credential, metav1.ConditionTrue, "CredentialStored")
+			})
+
+			It("should requeue to detect removal", func() {
+				result, err := t.reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: time.Minute}))
+			})
+
+			It("should not store an unchanged credential again", func() {
+				t.reconcileStoredCredential()
+				Expect(t.CryostatClient.Credentials).To(HaveLen(1))
+				Expect(t.CryostatClient.Credentials).To(HaveKey(int64(1)))
+			})
+
+			Context("when the Secret changes", func() {
+				JustBeforeEach(func() {
+					secret := t.getSecret()
+					secret.Data[corev1.BasicAuthPasswordKey] = []byte("rotated")
+					Expect(t.Client.Update(context.Background(), secret)).To(Succeed())
+					t.reconcileStoredCredential()
+				})
+
+				It("should replace the credential", func() {
+					Expect(t.CryostatClient.Credentials).To(HaveLen(1))
+					Expect(t.CryostatClient.Credentials[2].Password).To(Equal("rotated"))
+					Expect(*t.getStoredCredential().Status.CredentialID).To(Equal(int64(2)))
+				})
+			})
+
+			Context("when the match expression changes", func() {
+				JustBeforeEach(func() {
+					credential := t.getStoredCredential()
+					credential.Spec.MatchExpression = ""
+					Expect(t.Client.Update(context.Background(), credential)).To(Succeed())
+					t.reconcileStoredCredential()
+				})
+
+				It("should replace the credential", func() {
+					Expect(t.CryostatClient.Credentials).To(HaveLen(1))
+					Expect(t.CryostatClient.Credentials[2].MatchExpression).To(Equal("target.annotations.cryostat['NAMESPACE'] == 'my-apps'"))
+				})
+			})
+
+			Context("when the credential is removed from Cryostat", func() {
+				JustBeforeEach(func() {
+					delete(t.CryostatClient.Credentials, 1)
+					t.reconcileStoredCredential()
+				})
+
+				It("should store the credential again", func() {
+					Expect(t.CryostatClient.Credentials).To(HaveKey(int64(2)))
+					Expect(*t.getStoredCredential().Status.CredentialID).To(Equal(int64(2)))
+				})
+			})
+
+			DescribeTable("when the match expression could escape the namespace restriction",
+				func(expression string) {
+					credential := t.getStoredCredential()
+					credential.Spec.MatchExpression = expression
+					Expect(t.Client.Update(context.Background(), credential)).To(Succeed())
+					t.reconcileStoredCredential()
+
+					Expect(t.CryostatClient.Credentials).To(BeEmpty())
+					credential = t.getStoredCredential()
+					Expect(credential.Status.CredentialID).To(BeNil())
+					t.expectCondition(credential, metav1.ConditionFalse, "InvalidMatchExpression")
+				},
+				Entry("closing the parentheses", "true) || (true"),
+				Entry("leaving parentheses open", "true || (true"),
+				Entry("mismatching brackets", "[true) || (true]"),
+				Entry("closing the parentheses after a raw string", `r'\') || (true || r'x'`),
+				Entry("commenting out the parentheses", "true // comment"),
+				Entry("leaving a string open", "target.labels.app == 'my-app"),
+			)
+
+			It("should allow brackets within strings", func() {
+				credential := t.getStoredCredential()
+				credential.Spec.MatchExpression = `target.labels['app'] in ['(', "\")", '''{''']`
+				Expect(t.Client.Update(context.Background(), credential)).To(Succeed())
+				t.reconcileStoredCredential()
+				t.expectCondition(t.getStoredCredential(), metav1.ConditionTrue, "CredentialStored")
+			})
+
+			Context("when the Secret is deleted", func() {
+				JustBeforeEach(func() {
+					Expect(t.Client.Delete(context.Background(), t.getSecret())).To(Succeed())
+					t.reconcileStoredCredential()
+				})
+
+				It("should delete the credential", func() {
+					Expect(t.CryostatClient.Credentials).To(BeEmpty())
+					credential := t.getStoredCredential()
+					Expect(credential.Status.CredentialID).To(BeNil())
+					t.expectCondition(credential, metav1.ConditionFalse, "SecretNotFound")
+				})
+			})
+
+			Context("when deleted", func() {
+				JustBeforeEach(func() {
+					t.deleteStoredCredential()
+				})
+
+				It("should delete the credential", func() {
+					Expect(t.CryostatClient.Credentials).To(BeEmpty())
+				})
+
+				It("should remove the finalizer", func() {
+					t.expectNoStoredCredential()
+				})
+			})
+		})
+
+		Context("when the status update fails after storing the credential", func() {
+			BeforeEach(func() {
+				failed := false
+				t.Interceptors.SubResourceUpdate = func(ctx context.Context, client ctrlclient.Client, subResourceName string,
+					obj ctrlclient.Object, opts ...ctrlclient.SubResourceUpdateOption) error {
+					if !failed {
+						failed = true
+						return errors.New("conflict")
+					}
+					return client.SubResource(subResourceName).Update(ctx, obj, opts...)
+				}
+			})
+
+			JustBeforeEach(func() {
+				_, err := t.reconcile()
+				Expect(err).To(HaveOccurred())
+				Expect(t.CryostatClient.Credentials).To(HaveKey(int64(1)))
+				t.reconcileStoredCredential()
+			})
+
+			It("should replace the orphaned credential", func() {
+				Expect(t.CryostatClient.Credentials).To(HaveLen(1))
+				Expect(t.CryostatClient.Credentials).To(HaveKey(int64(2)))
+				Expect(*t.getStoredCredential().Status.CredentialID).To(Equal(int64(2)))
+			})
+		})
+
+		Context("with a missing Secret", func() {
+			BeforeEach(func() {
+				t.Objs = t.Objs[:len(t.Objs)-1]
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileStoredCredential()
+			})
+
+			It("should report that the Secret was not found", func() {
+				Expect(t.CryostatClient.Credentials).To(BeEmpty())
+				t.expectCondition(t.getStoredCredential(), metav1.ConditionFalse, "SecretNotFound")
+			})
+		})
+
+		Context("with a Secret missing the password", func() {
+			BeforeEach(func() {
+				secret := t.NewStoredCredentialSecret(appNamespace)
+				delete(secret.Data, corev1.BasicAuthPasswordKey)
+				t.Objs[len(t.Objs)-1] = secret
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileStoredCredential()
+			})
+
+			It("should report an invalid Secret", func() {
+				Expect(t.CryostatClient.Credentials).To(BeEmpty())
+				t.expectCondition(t.getStoredCredential(), metav1.ConditionFalse, "InvalidSecret")
+			})
+		})
+
+		Context("without a match expression", func() {
+			BeforeEach(func() {
+				t.credential.Spec.MatchExpression = ""
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileStoredCredential()
+			})
+
+			It("should match all targets in the namespace", func() {
+				Expect(t.CryostatClient.Credentials[1].MatchExpression).To(Equal("target.annotations.cryostat['NAMESPACE'] == 'my-apps'"))
+			})
+		})
+
+		Context("with a missing Cryostat", func() {
+			BeforeEach(func() {
+				t.credential.Spec.Cryostat.Name = "missing"
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileStoredCredential()
+			})
+
+			It("should report that Cryostat was not found", func() {
+				Expect(t.CryostatClient.Configs).To(BeEmpty())
+				t.expectCondition(t.getStoredCredential(), metav1.ConditionFalse, "CryostatNotFound")
+			})
+
+			It("should remove the finalizer when deleted", func() {
+				t.deleteStoredCredential()
+				t.expectNoStoredCredential()
+			})
+		})
+
+		Context("in a namespace not targeted by Cryostat", func() {
+			BeforeEach(func() {
+				t.Objs[2] = t.NewCryostatWithTargetStatus("other-namespace").Object
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileStoredCredential()
+			})
+
+			It("should report that the namespace is not targeted", func() {
+				Expect(t.CryostatClient.Configs).To(BeEmpty())
+				t.expectCondition(t.getStoredCredential(), metav1.ConditionFalse, "NamespaceNotTargeted")
+			})
+		})
+
+		Context("with a paused Cryostat", func() {
+			BeforeEach(func() {
+				cr := t.NewCryostatWithTargetStatus(appNamespace)
+				cr.Spec.Paused = true
+				t.Objs[2] = cr.Object
+			})
+
+			It("should report that Cryostat is paused without requeueing", func() {
+				result, err := t.reconcile()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{}))
+				Expect(t.CryostatClient.Configs).To(BeEmpty())
+				t.expectCondition(t.getStoredCredential(), metav1.ConditionFalse, "CryostatPaused")
+			})
+		})
+
+		Context("with reconciliation of Cryostat paused", func() {
+			BeforeEach(func() {
+				cr := t.NewCryostatWithTargetStatus(appNamespace)
+				cr.Spec.ReconcilePaused = true
+				t.Objs[2] = cr.Object
+			})
+
+			JustBeforeEach(func() {
+				t.reconcileStoredCredential()
+			})
+
+			It("should report that reconciliation is paused", func() {
+				Expect(t.CryostatClient.Configs).To(BeEmpty())
+				t.expectCondition(t.getStoredCredential(), metav1.ConditionFalse, "CryostatReconcilePaused")
+			})
+		})
+
+		Context("when deleted while Cryostat is paused", func() {
+			JustBeforeEach(func() {
+				t.reconcileStoredCredential()
+				t.UpdateCryostat(func(cr *operatorv1beta2.Cryostat) {
+					cr.Spec.ReconcilePaused = true
+				})
+				t.deleteStoredCredential()
+			})
+
+			It("should keep the credential and finalizer", func() {
+				Expect(t.CryostatClient.Credentials).To(HaveLen(1))
+				t.ExpectFinalizer(t.getStoredCredential(), "operator.cryostat.io/storedcredential.finalizer")
+			})
+
+			Context("then resumed", func() {
+				JustBeforeEach(func() {
+					t.UpdateCryostat(func(cr *operatorv1beta2.Cryostat) {
+						cr.Spec.ReconcilePaused = false
+					})
+					t.reconcileStoredCredential()
+				})
+
+				It("should delete the credential", func() {
+					Expect(t.CryostatClient.Credentials).To(BeEmpty())
+					t.expectNoStoredCredential()
+				})
+			})
+		})
+
+		Context("when the Cryostat API fails", func() {
+			BeforeEach(func() {
+				t.CryostatClient.Err = errors.New("connection refused")
+			})
+
+			It("should return an error and report it", func() {
+				_, err := t.reconcile()
+				Expect(err).To(HaveOccurred())
+				t.expectCondition(t.getStoredCredential(), metav1.ConditionFalse, "CryostatAPIError")
+			})
+		})
+	})
+
+	Describe("setting up the controller", func() {
+		JustBeforeEach(func() {
+			err := t.controller.SetupWithManager(nil)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should reconcile StoredCredentials when their Cryostat changes", func() {
+			builder := t.ControllerBuilder
+			Expect(builder.MapFuncs).To(HaveLen(2))
+			other := t.NewStoredCredential(appNamespace)
+			other.Name = "other-credential"
+			other.Spec.Cryostat.Name = "other"
+			Expect(t.Client.Create(context.Background(), other)).To(Succeed())
+
+			requests := builder.MapFuncs[0](context.Background(), t.NewCryostat().Object)
+			Expect(requests).To(ConsistOf(reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: "my-credential", Namespace: appNamespace},
+			}))
+		})
+
+		It("should reconcile StoredCredentials when their Secret changes", func() {
+			builder := t.ControllerBuilder
+			Expect(builder.MapFuncs).To(HaveLen(2))
+			other := t.NewStoredCredential(appNamespace)
+			other.Name = "other-credential"
+			other.Spec.SecretName = "other-secret"
+			Expect(t.Client.Create(context.Background(), other)).To(Succeed())
+
+			requests := builder.MapFuncs[1](context.Background(), t.NewStoredCredentialSecret(appNamespace))
+			Expect(requests).To(ConsistOf(reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: "my-credential", Namespace: appNamespace},
+			}))
+		})
+	})
+})
+
+func (t *storedCredentialTestInput) reconcile() (reconcile.Result, error) {
+	return t.Reconcile(t.controller, t.credential)
+}
+
+func (t *storedCredentialTestInput) reconcileStoredCredential() {
+	t.ExpectReconcile(t.controller, t.credential)
+}
+
+func (t *storedCredentialTestInput) deleteStoredCredential() {
+	t.DeleteAndReconcile(t.controller, t.getStoredCredential())
+}
+
+func (t *storedCredentialTestInput) getStoredCredential() *operatorv1beta2.StoredCredential {
+	credential := t.credential.DeepCopy()
+	t.Get(credential)
+	return credential
+}
+
+func (t *storedCredentialTestInput) getSecret() *corev1.Secret {
+	secret := t.NewStoredCredentialSecret(t.credential.Namespace)
+	t.Get(secret)
+	return secret
+}
+
+func (t *storedCredentialTestInput) expectNoStoredCredential() {
+	t.ExpectNotFound(t.credential.DeepCopy())
+}
+
+func (t *storedCredentialTestInput) expectCondition(credential *operatorv1beta2.StoredCredential, status metav1.ConditionStatus, reason string) {
+	test.ExpectCondition(credential.Status.Conditions, string(operatorv1beta2.ConditionTypeStoredCredentialSynchronized),
+		status, reason, credential.Generation)
+}
@@ -0,0 +1,88 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/common"
+	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
+	configv1 "github.com/openshift/api/config/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// getTLSProfile determines the TLS profile to be used by all Cryostat components.
+// An explicitly requested profile type takes precedence. Otherwise, the profile
+// of the cluster's API server is used on OpenShift if requested, falling back to
+// the Intermediate profile.
+func (r *Reconciler) getTLSProfile(ctx context.Context, cr *model.CryostatInstance) (*common.TLSProfile, error) {
+	config := &operatorv1beta2.TLSProfile{}
+	if cr.Spec.SecurityOptions != nil && cr.Spec.SecurityOptions.TLSProfile != nil {
+		config = cr.Spec.SecurityOptions.TLSProfile
+	}
+
+	if config.Type != nil {
+		if *config.Type == operatorv1beta2.TLSProfileCustom && config.Custom == nil {
+			r.Log.Info("Custom TLS profile requested without any custom parameters, using the Intermediate profile")
+		}
+		return common.NewTLSProfile(*config.Type, config.Custom), nil
+	}
+
+	if config.InheritFromAPIServer != nil && *config.InheritFromAPIServer {
+		if !r.IsOpenShift {
+			r.Log.Info("Inheriting the API server TLS profile is only supported on OpenShift, using the Intermediate profile")
+		} else {
+			apiServer := &configv1.APIServer{}
+			err := r.Client.Get(ctx, types.NamespacedName{Name: apiServerName}, apiServer)
+			if err != nil {
+				return nil, err
+			}
+			return common.NewTLSProfileFromSecurityProfile(apiServer.Spec.TLSSecurityProfile), nil
+		}
+	}
+
+	return common.NewTLSProfile(operatorv1beta2.TLSProfileIntermediate, nil), nil
+}
+
+// mapFromAPIServer enqueues the Cryostats that inherit the TLS profile of the cluster's API server
+func (r *Reconciler) mapFromAPIServer(ctx context.Context, obj client.Object) []reconcile.Request {
+	if obj.GetName() != apiServerName {
+		return nil
+	}
+	cryostats := &operatorv1beta2.CryostatList{}
+	err := r.Client.List(ctx, cryostats)
+	if err != nil {
+		r.Log.Error(err, "failed to list Cryostats", "APIServer", obj.GetName())
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, cr := range cryostats.Items {
+		if cr.Spec.SecurityOptions == nil || cr.Spec.SecurityOptions.TLSProfile == nil {
+			continue
+		}
+		config := cr.Spec.SecurityOptions.TLSProfile
+		if config.Type == nil && config.InheritFromAPIServer != nil && *config.InheritFromAPIServer {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Name:      cr.Name,
+				Namespace: cr.Namespace,
+			}})
+		}
+	}
+	return requests
+}
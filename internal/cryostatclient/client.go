@@ -0,0 +1,403 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cryostatclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+)
+
+// Timeout for each request to the Cryostat API
+const requestTimeout = 30 * time.Second
+
+// Maximum length of a response body included in an error
+const maxErrorBodyLength = 512
+
+// Config contains the parameters needed to connect to a Cryostat instance
+type Config struct {
+	// Base URL of the Cryostat instance, such as https://cryostat.my-namespace.svc:4180
+	BaseURL *url.URL
+	// PEM-encoded CA certificates used to verify Cryostat's certificate. If empty,
+	// the system's trusted CAs are used.
+	CACert []byte
+	// Bearer token used to authenticate with Cryostat
+	BearerToken string
+	// File containing the bearer token, which is periodically reread if set
+	BearerTokenFile string
+}
+
+// Client performs operations using the Cryostat HTTP API
+type Client interface {
+	// ListTargets returns all targets discovered by Cryostat
+	ListTargets(ctx context.Context) ([]Target, error)
+	// ListRecordings returns all active recordings in the target
+	ListRecordings(ctx context.Context, targetID int64) ([]Recording, error)
+	// CreateRecording starts a new recording in the target
+	CreateRecording(ctx context.Context, targetID int64, options *RecordingCreateOptions) (*Recording, error)
+	// StopRecording stops a running recording
+	StopRecording(ctx context.Context, targetID int64, recordingID int64) error
+	// ArchiveRecording copies the recording's data into Cryostat's archives,
+	// and returns the name of the archived recording
+	ArchiveRecording(ctx context.Context, targetID int64, recordingID int64) (string, error)
+	// DeleteRecording stops the recording, if running, and removes it from the target
+	DeleteRecording(ctx context.Context, targetID int64, recordingID int64) error
+	// ListArchivedRecordings returns all recordings in Cryostat's archives
+	ListArchivedRecordings(ctx context.Context) ([]ArchivedRecording, error)
+	// DeleteArchivedRecording removes a recording from Cryostat's archives
+	DeleteArchivedRecording(ctx context.Context, name string) error
+	// GetRule returns the automated rule with the given name
+	GetRule(ctx context.Context, name string) (*Rule, error)
+	// CreateRule creates a new automated rule
+	CreateRule(ctx context.Context, rule *Rule) (*Rule, error)
+	// SetRuleEnabled enables or disables an automated rule
+	SetRuleEnabled(ctx context.Context, name string, enabled bool) error
+	// DeleteRule deletes an automated rule. If clean is true, recordings
+	// started by the rule are also stopped.
+	DeleteRule(ctx context.Context, name string, clean bool) error
+	// ListCredentials returns all stored credentials, without their usernames and passwords
+	ListCredentials(ctx context.Context) ([]Credential, error)
+	// GetCredential returns the stored credential with the given ID
+	GetCredential(ctx context.Context, id int64) (*Credential, error)
+	// CreateCredential stores a new credential for all targets matching its expression
+	CreateCredential(ctx context.Context, options *CredentialCreateOptions) (*Credential, error)
+	// DeleteCredential deletes a stored credential
+	DeleteCredential(ctx context.Context, id int64) error
+	// ListEventTemplates returns all event templates known to Cryostat
+	ListEventTemplates(ctx context.Context) ([]EventTemplate, error)
+	// UploadEventTemplate uploads a custom event template from the contents of a .jfc file
+	UploadEventTemplate(ctx context.Context, filename string, content []byte) error
+	// DeleteEventTemplate deletes a custom event template
+	DeleteEventTemplate(ctx context.Context, name string) error
+}
+
+// Factory creates a Client for the Cryostat instance described by the Config
+type Factory func(config *Config) (Client, error)
+
+// NewFactory returns a Factory that creates clients authenticating
+// with the same credentials as the provided REST config
+func NewFactory(restConfig *rest.Config) Factory {
+	return func(config *Config) (Client, error) {
+		configCopy := *config
+		configCopy.BearerToken = restConfig.BearerToken
+		configCopy.BearerTokenFile = restConfig.BearerTokenFile
+		return NewClient(&configCopy)
+	}
+}
+
+// APIError is returned when the Cryostat API responds with an unsuccessful status code
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s %s failed with status code %d: %s", e.Method, e.Path, e.StatusCode, e.Body)
+}
+
+// IsNotFound returns whether the error indicates that the requested
+// resource does not exist in Cryostat
+func IsNotFound(err error) bool {
+	apiErr := &APIError{}
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+type client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+}
+
+var _ Client = (*client)(nil)
+
+// NewClient creates a Client for the Cryostat instance described by the Config
+func NewClient(config *Config) (Client, error) {
+	httpTransport := http.DefaultTransport.(*http.Transport).Clone()
+	if len(config.CACert) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(config.CACert) {
+			return nil, errors.New("no valid CA certificates found for Cryostat")
+		}
+		httpTransport.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	var rt http.RoundTripper = httpTransport
+	if len(config.BearerToken) > 0 || len(config.BearerTokenFile) > 0 {
+		var err error
+		rt, err = transport.NewBearerAuthWithRefreshRoundTripper(config.BearerToken, config.BearerTokenFile, rt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &client{
+		baseURL: config.BaseURL,
+		httpClient: &http.Client{
+			Transport: rt,
+			Timeout:   requestTimeout,
+		},
+	}, nil
+}
+
+func (c *client) ListTargets(ctx context.Context) ([]Target, error) {
+	targets := []Target{}
+	err := c.doJSON(ctx, http.MethodGet, "/api/v4/targets", nil, "", &targets)
+	if err != nil {
+		return nil, err
+	}
+	return targets, nil
+}
+
+func (c *client) ListRecordings(ctx context.Context, targetID int64) ([]Recording, error) {
+	recordings := []Recording{}
+	err := c.doJSON(ctx, http.MethodGet, recordingsPath(targetID), nil, "", &recordings)
+	if err != nil {
+		return nil, err
+	}
+	return recordings, nil
+}
+
+func (c *client) CreateRecording(ctx context.Context, targetID int64, options *RecordingCreateOptions) (*Recording, error) {
+	recording := &Recording{}
+	err := c.doJSON(ctx, http.MethodPost, recordingsPath(targetID), strings.NewReader(options.ToFormData()),
+		"application/x-www-form-urlencoded", recording)
+	if err != nil {
+		return nil, err
+	}
+	return recording, nil
+}
+
+func (c *client) StopRecording(ctx context.Context, targetID int64, recordingID int64) error {
+	_, err := c.do(ctx, http.MethodPatch, recordingPath(targetID, recordingID), strings.NewReader("STOP"), "text/plain")
+	return err
+}
+
+func (c *client) ArchiveRecording(ctx context.Context, targetID int64, recordingID int64) (string, error) {
+	body, err := c.do(ctx, http.MethodPatch, recordingPath(targetID, recordingID), strings.NewReader("SAVE"), "text/plain")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+func (c *client) DeleteRecording(ctx context.Context, targetID int64, recordingID int64) error {
+	_, err := c.do(ctx, http.MethodDelete, recordingPath(targetID, recordingID), nil, "")
+	return err
+}
+
+func (c *client) ListArchivedRecordings(ctx context.Context) ([]ArchivedRecording, error) {
+	archives := []ArchivedRecording{}
+	err := c.doJSON(ctx, http.MethodGet, "/api/v4/recordings", nil, "", &archives)
+	if err != nil {
+		return nil, err
+	}
+	return archives, nil
+}
+
+func (c *client) DeleteArchivedRecording(ctx context.Context, name string) error {
+	_, err := c.do(ctx, http.MethodDelete, "/api/v4/recordings/"+url.PathEscape(name), nil, "")
+	return err
+}
+
+func (c *client) GetRule(ctx context.Context, name string) (*Rule, error) {
+	rule := &Rule{}
+	err := c.doJSON(ctx, http.MethodGet, rulePath(name), nil, "", rule)
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (c *client) CreateRule(ctx context.Context, rule *Rule) (*Rule, error) {
+	body, err := json.Marshal(rule)
+	if err != nil {
+		return nil, err
+	}
+	created := &Rule{}
+	err = c.doJSON(ctx, http.MethodPost, "/api/v4/rules", bytes.NewReader(body), "application/json", created)
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (c *client) SetRuleEnabled(ctx context.Context, name string, enabled bool) error {
+	body, err := json.Marshal(map[string]bool{"enabled": enabled})
+	if err != nil {
+		return err
+	}
+	_, err = c.do(ctx, http.MethodPatch, rulePath(name), bytes.NewReader(body), "application/json")
+	return err
+}
+
+func (c *client) DeleteRule(ctx context.Context, name string, clean bool) error {
+	path := rulePath(name) + "?clean=" + strconv.FormatBool(clean)
+	_, err := c.do(ctx, http.MethodDelete, path, nil, "")
+	return err
+}
+
+func (c *client) ListCredentials(ctx context.Context) ([]Credential, error) {
+	credentials := []Credential{}
+	err := c.doJSON(ctx, http.MethodGet, "/api/v4/credentials", nil, "", &credentials)
+	if err != nil {
+		return nil, err
+	}
+	return credentials, nil
+}
+
+func (c *client) GetCredential(ctx context.Context, id int64) (*Credential, error) {
+	credential := &Credential{}
+	err := c.doJSON(ctx, http.MethodGet, credentialPath(id), nil, "", credential)
+	if err != nil {
+		return nil, err
+	}
+	// The response describes the credential's matches, rather than the credential itself
+	credential.ID = id
+	return credential, nil
+}
+
+func (c *client) CreateCredential(ctx context.Context, options *CredentialCreateOptions) (*Credential, error) {
+	credential := &Credential{}
+	err := c.doJSON(ctx, http.MethodPost, "/api/v4/credentials", strings.NewReader(options.ToFormData()),
+		"application/x-www-form-urlencoded", credential)
+	if err != nil {
+		return nil, err
+	}
+	return credential, nil
+}
+
+func (c *client) DeleteCredential(ctx context.Context, id int64) error {
+	_, err := c.do(ctx, http.MethodDelete, credentialPath(id), nil, "")
+	return err
+}
+
+func (c *client) ListEventTemplates(ctx context.Context) ([]EventTemplate, error) {
+	templates := []EventTemplate{}
+	err := c.doJSON(ctx, http.MethodGet, "/api/v4/event_templates", nil, "", &templates)
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (c *client) UploadEventTemplate(ctx context.Context, filename string, content []byte) error {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("template", filename)
+	if err != nil {
+		return err
+	}
+	_, err = part.Write(content)
+	if err != nil {
+		return err
+	}
+	err = writer.Close()
+	if err != nil {
+		return err
+	}
+	_, err = c.do(ctx, http.MethodPost, "/api/v4/event_templates", body, writer.FormDataContentType())
+	return err
+}
+
+func (c *client) DeleteEventTemplate(ctx context.Context, name string) error {
+	_, err := c.do(ctx, http.MethodDelete, "/api/v4/event_templates/"+url.PathEscape(name), nil, "")
+	return err
+}
+
+func credentialPath(id int64) string {
+	return fmt.Sprintf("/api/v4/credentials/%d", id)
+}
+
+func rulePath(name string) string {
+	return "/api/v4/rules/" + url.PathEscape(name)
+}
+
+func recordingsPath(targetID int64) string {
+	return fmt.Sprintf("/api/v4/targets/%d/recordings", targetID)
+}
+
+func recordingPath(targetID int64, recordingID int64) string {
+	return fmt.Sprintf("%s/%d", recordingsPath(targetID), recordingID)
+}
+
+func (c *client) doJSON(ctx context.Context, method string, path string, body io.Reader, contentType string,
+	result interface{}) error {
+	respBody, err := c.do(ctx, method, path, body, contentType)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(respBody, result)
+	if err != nil {
+		return fmt.Errorf("failed to parse response from %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+func (c *client) do(ctx context.Context, method string, path string, body io.Reader, contentType string) ([]byte, error) {
+	reqURL, err := c.baseURL.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, reqURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "*/*")
+	if len(contentType) > 0 {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg := string(respBody)
+		if len(msg) > maxErrorBodyLength {
+			msg = msg[:maxErrorBodyLength]
+		}
+		return nil, &APIError{
+			Method:     method,
+			Path:       path,
+			StatusCode: resp.StatusCode,
+			Body:       msg,
+		}
+	}
+	return respBody, nil
+}
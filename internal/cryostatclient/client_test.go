@@ -0,0 +1,258 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cryostatclient_test
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"github.com/cryostatio/cryostat-operator/internal/cryostatclient"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/rest"
+)
+
+type request struct {
+	method        string
+	path          string
+	contentType   string
+	authorization string
+	body          string
+}
+
+var _ = Describe("Client", func() {
+	var server *httptest.Server
+	var requests []request
+	var status int
+	var response string
+	var client cryostatclient.Client
+
+	BeforeEach(func() {
+		requests = nil
+		status = http.StatusOK
+		response = ""
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			Expect(err).ToNot(HaveOccurred())
+			requests = append(requests, request{
+				method:        r.Method,
+				path:          r.URL.Path,
+				contentType:   r.Header.Get("Content-Type"),
+				authorization: r.Header.Get("Authorization"),
+				body:          string(body),
+			})
+			w.WriteHeader(status)
+			_, err = w.Write([]byte(response))
+			Expect(err).ToNot(HaveOccurred())
+		}))
+		DeferCleanup(server.Close)
+
+		baseURL, err := url.Parse(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+		factory := cryostatclient.NewFactory(&rest.Config{BearerToken: "myToken"})
+		client, err = factory(&cryostatclient.Config{
+			BaseURL: baseURL,
+			CACert:  caCert,
+		})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should list targets", func() {
+		response = `[{"id":1,"jvmId":"abcd","connectUrl":"http://app:9977","alias":"app",` +
+			`"annotations":{"cryostat":[{"key":"NAMESPACE","value":"my-apps"},{"key":"POD_NAME","value":"app-1"}],"platform":[]}}]`
+		targets, err := client.ListTargets(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(targets).To(HaveLen(1))
+		Expect(targets[0].ID).To(Equal(int64(1)))
+		Expect(targets[0].JvmID).To(Equal("abcd"))
+		Expect(targets[0].CryostatAnnotation(cryostatclient.AnnotationNamespace)).To(Equal("my-apps"))
+		Expect(targets[0].CryostatAnnotation(cryostatclient.AnnotationPodName)).To(Equal("app-1"))
+		Expect(requests).To(ConsistOf(request{
+			method:        http.MethodGet,
+			path:          "/api/v4/targets",
+			authorization: "Bearer myToken",
+		}))
+	})
+
+	It("should create a recording", func() {
+		response = `{"id":2,"name":"my-recording","state":"RUNNING"}`
+		recording, err := client.CreateRecording(context.Background(), 1, &cryostatclient.RecordingCreateOptions{
+			RecordingName: "my-recording",
+			Events:        cryostatclient.EventSpecifier("Continuous", "TARGET"),
+			Duration:      30,
+			ToDisk:        true,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recording.ID).To(Equal(int64(2)))
+		Expect(recording.State).To(Equal(cryostatclient.RecordingStateRunning))
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].method).To(Equal(http.MethodPost))
+		Expect(requests[0].path).To(Equal("/api/v4/targets/1/recordings"))
+		Expect(requests[0].contentType).To(Equal("application/x-www-form-urlencoded"))
+		form, err := url.ParseQuery(requests[0].body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(form.Get("recordingName")).To(Equal("my-recording"))
+		Expect(form.Get("events")).To(Equal("template=Continuous,type=TARGET"))
+		Expect(form.Get("duration")).To(Equal("30"))
+		Expect(form.Get("toDisk")).To(Equal("true"))
+	})
+
+	It("should stop a recording", func() {
+		err := client.StopRecording(context.Background(), 1, 2)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requests).To(ConsistOf(request{
+			method:        http.MethodPatch,
+			path:          "/api/v4/targets/1/recordings/2",
+			contentType:   "text/plain",
+			authorization: "Bearer myToken",
+			body:          "STOP",
+		}))
+	})
+
+	It("should archive a recording", func() {
+		response = "app_my-recording_20240101T000000Z.jfr\n"
+		name, err := client.ArchiveRecording(context.Background(), 1, 2)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(name).To(Equal("app_my-recording_20240101T000000Z.jfr"))
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].body).To(Equal("SAVE"))
+	})
+
+	It("should delete a recording", func() {
+		err := client.DeleteRecording(context.Background(), 1, 2)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].method).To(Equal(http.MethodDelete))
+		Expect(requests[0].path).To(Equal("/api/v4/targets/1/recordings/2"))
+	})
+
+	It("should list archived recordings", func() {
+		response = `[{"jvmId":"abcd","name":"app_my-recording_20240101T000000Z.jfr","size":1024,"archivedTime":1704067200}]`
+		archives, err := client.ListArchivedRecordings(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(archives).To(ConsistOf(cryostatclient.ArchivedRecording{
+			JvmID:        "abcd",
+			Name:         "app_my-recording_20240101T000000Z.jfr",
+			Size:         1024,
+			ArchivedTime: 1704067200,
+		}))
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].method).To(Equal(http.MethodGet))
+		Expect(requests[0].path).To(Equal("/api/v4/recordings"))
+	})
+
+	It("should delete an archived recording", func() {
+		err := client.DeleteArchivedRecording(context.Background(), "app_my-recording_20240101T000000Z.jfr")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].method).To(Equal(http.MethodDelete))
+		Expect(requests[0].path).To(Equal("/api/v4/recordings/app_my-recording_20240101T000000Z.jfr"))
+	})
+
+	It("should list credentials", func() {
+		response = `[{"id":3,"matchExpression":"true"}]`
+		credentials, err := client.ListCredentials(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(credentials).To(ConsistOf(cryostatclient.Credential{ID: 3, MatchExpression: "true"}))
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].method).To(Equal(http.MethodGet))
+		Expect(requests[0].path).To(Equal("/api/v4/credentials"))
+	})
+
+	It("should create a credential", func() {
+		response = `{"id":3,"matchExpression":"true"}`
+		credential, err := client.CreateCredential(context.Background(), &cryostatclient.CredentialCreateOptions{
+			MatchExpression: "true",
+			Username:        "user",
+			Password:        "pass",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(credential.ID).To(Equal(int64(3)))
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].method).To(Equal(http.MethodPost))
+		Expect(requests[0].path).To(Equal("/api/v4/credentials"))
+		Expect(requests[0].contentType).To(Equal("application/x-www-form-urlencoded"))
+		form, err := url.ParseQuery(requests[0].body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(form.Get("matchExpression")).To(Equal("true"))
+		Expect(form.Get("username")).To(Equal("user"))
+		Expect(form.Get("password")).To(Equal("pass"))
+	})
+
+	It("should delete a credential", func() {
+		err := client.DeleteCredential(context.Background(), 3)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].method).To(Equal(http.MethodDelete))
+		Expect(requests[0].path).To(Equal("/api/v4/credentials/3"))
+	})
+
+	It("should upload an event template", func() {
+		err := client.UploadEventTemplate(context.Background(), "my-template.jfc", []byte("<configuration/>"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].method).To(Equal(http.MethodPost))
+		Expect(requests[0].path).To(Equal("/api/v4/event_templates"))
+		mediaType, params, err := mime.ParseMediaType(requests[0].contentType)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mediaType).To(Equal("multipart/form-data"))
+		reader := multipart.NewReader(strings.NewReader(requests[0].body), params["boundary"])
+		part, err := reader.NextPart()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(part.FormName()).To(Equal("template"))
+		Expect(part.FileName()).To(Equal("my-template.jfc"))
+		content, err := io.ReadAll(part)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("<configuration/>"))
+	})
+
+	It("should delete an event template", func() {
+		err := client.DeleteEventTemplate(context.Background(), "My Template")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].method).To(Equal(http.MethodDelete))
+		Expect(requests[0].path).To(Equal("/api/v4/event_templates/My Template"))
+	})
+
+	Context("with an error response", func() {
+		BeforeEach(func() {
+			status = http.StatusNotFound
+			response = "no such recording"
+		})
+
+		It("should return an APIError", func() {
+			err := client.DeleteRecording(context.Background(), 1, 2)
+			Expect(err).To(HaveOccurred())
+			Expect(cryostatclient.IsNotFound(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("no such recording"))
+		})
+	})
+
+	It("should reject an invalid CA certificate", func() {
+		_, err := cryostatclient.NewClient(&cryostatclient.Config{
+			BaseURL: &url.URL{Scheme: "https", Host: "cryostat"},
+			CACert:  []byte("not a certificate"),
+		})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,183 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cryostatclient
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// Keys of annotations that Cryostat adds to targets it discovers
+const (
+	AnnotationNamespace = "NAMESPACE"
+	AnnotationPodName   = "POD_NAME"
+)
+
+// Recording states reported by Cryostat
+const (
+	RecordingStateRunning = "RUNNING"
+	RecordingStateStopped = "STOPPED"
+)
+
+// KeyValue is a single label or annotation
+type KeyValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// TargetAnnotations are the annotations of a target, grouped by their source
+type TargetAnnotations struct {
+	Cryostat []KeyValue `json:"cryostat"`
+	Platform []KeyValue `json:"platform"`
+}
+
+// Target is a JVM discovered by Cryostat
+type Target struct {
+	ID          int64             `json:"id"`
+	JvmID       string            `json:"jvmId,omitempty"`
+	ConnectURL  string            `json:"connectUrl"`
+	Alias       string            `json:"alias,omitempty"`
+	Labels      []KeyValue        `json:"labels,omitempty"`
+	Annotations TargetAnnotations `json:"annotations"`
+}
+
+// CryostatAnnotation returns the value of the annotation with the given key added
+// by Cryostat to this target, or an empty string if there is none
+func (t *Target) CryostatAnnotation(key string) string {
+	for _, annotation := range t.Annotations.Cryostat {
+		if annotation.Key == key {
+			return annotation.Value
+		}
+	}
+	return ""
+}
+
+// Recording is an active recording within a target
+type Recording struct {
+	ID          int64  `json:"id"`
+	RemoteID    int64  `json:"remoteId,omitempty"`
+	Name        string `json:"name"`
+	State       string `json:"state"`
+	StartTime   int64  `json:"startTime,omitempty"`
+	Duration    int64  `json:"duration,omitempty"`
+	Continuous  bool   `json:"continuous,omitempty"`
+	ToDisk      bool   `json:"toDisk,omitempty"`
+	MaxSize     int64  `json:"maxSize,omitempty"`
+	MaxAge      int64  `json:"maxAge,omitempty"`
+	DownloadURL string `json:"downloadUrl,omitempty"`
+	ReportURL   string `json:"reportUrl,omitempty"`
+}
+
+// ArchivedRecording is a recording whose data is stored in Cryostat's archives
+type ArchivedRecording struct {
+	// ID of the JVM the recording was archived from
+	JvmID       string `json:"jvmId"`
+	Name        string `json:"name"`
+	DownloadURL string `json:"downloadUrl,omitempty"`
+	ReportURL   string `json:"reportUrl,omitempty"`
+	// Size in bytes
+	Size int64 `json:"size"`
+	// Time the recording was archived, in seconds since the epoch
+	ArchivedTime int64 `json:"archivedTime"`
+}
+
+// RecordingCreateOptions are the parameters used to start a new recording
+type RecordingCreateOptions struct {
+	RecordingName string
+	// Event specifier, such as "template=Continuous,type=TARGET"
+	Events string
+	// Duration in seconds, or zero for a continuous recording
+	Duration int64
+	ToDisk   bool
+	// Maximum size in bytes, or zero for the target's default
+	MaxSize int64
+	// Maximum age in seconds, or zero for the target's default
+	MaxAge int64
+}
+
+// ToFormData encodes the options as a form body for the Cryostat API
+func (opts *RecordingCreateOptions) ToFormData() string {
+	formData := &url.Values{}
+
+	formData.Add("recordingName", opts.RecordingName)
+	formData.Add("events", opts.Events)
+	formData.Add("duration", strconv.FormatInt(opts.Duration, 10))
+	formData.Add("toDisk", strconv.FormatBool(opts.ToDisk))
+	formData.Add("maxSize", strconv.FormatInt(opts.MaxSize, 10))
+	formData.Add("maxAge", strconv.FormatInt(opts.MaxAge, 10))
+
+	return formData.Encode()
+}
+
+// EventSpecifier returns the event specifier for an event template
+// with the given name and type
+func EventSpecifier(templateName string, templateType string) string {
+	return "template=" + templateName + ",type=" + templateType
+}
+
+// Rule is an automated rule, which starts recordings in all matching targets
+type Rule struct {
+	ID                    int64  `json:"id,omitempty"`
+	Name                  string `json:"name"`
+	Description           string `json:"description"`
+	MatchExpression       string `json:"matchExpression"`
+	EventSpecifier        string `json:"eventSpecifier"`
+	ArchivalPeriodSeconds int64  `json:"archivalPeriodSeconds"`
+	InitialDelaySeconds   int64  `json:"initialDelaySeconds"`
+	PreservedArchives     int32  `json:"preservedArchives"`
+	MaxAgeSeconds         int64  `json:"maxAgeSeconds"`
+	MaxSizeBytes          int64  `json:"maxSizeBytes"`
+	Enabled               bool   `json:"enabled"`
+}
+
+// Credential is a stored credential, which Cryostat uses to connect
+// to all matching targets
+type Credential struct {
+	ID              int64  `json:"id"`
+	MatchExpression string `json:"matchExpression"`
+}
+
+// CredentialCreateOptions are the parameters used to store a new credential
+type CredentialCreateOptions struct {
+	MatchExpression string
+	Username        string
+	Password        string
+}
+
+// ToFormData encodes the options as a form body for the Cryostat API
+func (opts *CredentialCreateOptions) ToFormData() string {
+	formData := &url.Values{}
+
+	formData.Add("matchExpression", opts.MatchExpression)
+	formData.Add("username", opts.Username)
+	formData.Add("password", opts.Password)
+
+	return formData.Encode()
+}
+
+// EventTemplate is a Flight Recorder event template known to Cryostat
+type EventTemplate struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Provider    string `json:"provider"`
+	// Type of the template, such as TARGET or CUSTOM
+	Type string `json:"type"`
+}
+
+// Event template types
+const (
+	EventTemplateTypeTarget = "TARGET"
+	EventTemplateTypeCustom = "CUSTOM"
+)
@@ -40,6 +40,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
 
 	operatorv1beta1 "github.com/cryostatio/cryostat-operator/api/v1beta1"
 	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
@@ -47,6 +49,7 @@ import (
 	"github.com/cryostatio/cryostat-operator/internal/controllers"
 	"github.com/cryostatio/cryostat-operator/internal/controllers/common"
 	"github.com/cryostatio/cryostat-operator/internal/controllers/constants"
+	"github.com/cryostatio/cryostat-operator/internal/cryostatclient"
 	"github.com/cryostatio/cryostat-operator/internal/webhooks"
 	"github.com/cryostatio/cryostat-operator/internal/webhooks/agent"
 	// +kubebuilder:scaffold:imports
@@ -68,6 +71,8 @@ func init() {
 	utilruntime.Must(consolev1.AddToScheme(scheme))
 	utilruntime.Must(configv1.AddToScheme(scheme))
 	utilruntime.Must(openshiftoperatorv1.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1alpha3.AddToScheme(scheme))
 
 	utilruntime.Must(operatorv1beta2.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
@@ -168,6 +173,23 @@ func main() {
 		setupLog.Info("did not find cert-manager installation")
 	}
 
+	gatewayAPI, err := isGatewayAPIInstalled(dc)
+	if err != nil {
+		setupLog.Error(err, "could not determine whether the Gateway API is installed")
+		os.Exit(1)
+	}
+	backendTLSPolicy := false
+	if gatewayAPI {
+		setupLog.Info("found Gateway API installation")
+		backendTLSPolicy, err = isBackendTLSPolicyInstalled(dc)
+		if err != nil {
+			setupLog.Error(err, "could not determine whether the BackendTLSPolicy API is installed")
+			os.Exit(1)
+		}
+	} else {
+		setupLog.Info("did not find Gateway API installation")
+	}
+
 	// Optionally install OpenShift Console Plugin
 	if consolePlugin {
 		// Look up operator namespace
@@ -206,7 +228,7 @@ func main() {
 	}
 
 	config := newReconcilerConfig(mgr, "Cryostat", "cryostat-controller", openShift, certManager,
-		insightsURL)
+		gatewayAPI, backendTLSPolicy, insightsURL)
 	controller, err := controllers.NewCryostatReconciler(config)
 	if err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Cryostat")
@@ -225,6 +247,83 @@ func main() {
 		setupLog.Error(err, "unable to create webhook", "webhook", "Pod")
 		os.Exit(1)
 	}
+	recordingConfig := newReconcilerConfig(mgr, "Recording", "recording-controller", openShift, certManager,
+		gatewayAPI, backendTLSPolicy, insightsURL)
+	recordingController, err := controllers.NewRecordingReconciler(recordingConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Recording")
+		os.Exit(1)
+	}
+	if err = recordingController.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to add controller to manager", "controller", "Recording")
+		os.Exit(1)
+	}
+	automatedRuleConfig := newReconcilerConfig(mgr, "AutomatedRule", "automatedrule-controller", openShift, certManager,
+		gatewayAPI, backendTLSPolicy, insightsURL)
+	automatedRuleController, err := controllers.NewAutomatedRuleReconciler(automatedRuleConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AutomatedRule")
+		os.Exit(1)
+	}
+	if err = automatedRuleController.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to add controller to manager", "controller", "AutomatedRule")
+		os.Exit(1)
+	}
+	storedCredentialConfig := newReconcilerConfig(mgr, "StoredCredential", "storedcredential-controller", openShift,
+		certManager, gatewayAPI, backendTLSPolicy, insightsURL)
+	storedCredentialController, err := controllers.NewStoredCredentialReconciler(storedCredentialConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "StoredCredential")
+		os.Exit(1)
+	}
+	if err = storedCredentialController.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to add controller to manager", "controller", "StoredCredential")
+		os.Exit(1)
+	}
+	eventTemplateConfig := newReconcilerConfig(mgr, "EventTemplate", "eventtemplate-controller", openShift,
+		certManager, gatewayAPI, backendTLSPolicy, insightsURL)
+	eventTemplateController, err := controllers.NewEventTemplateReconciler(eventTemplateConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "EventTemplate")
+		os.Exit(1)
+	}
+	if err = eventTemplateController.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to add controller to manager", "controller", "EventTemplate")
+		os.Exit(1)
+	}
+	storageRetentionConfig := newReconcilerConfig(mgr, "StorageRetention", "storageretention-controller", openShift,
+		certManager, gatewayAPI, backendTLSPolicy, insightsURL)
+	storageRetentionController, err := controllers.NewStorageRetentionReconciler(storageRetentionConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "StorageRetention")
+		os.Exit(1)
+	}
+	if err = storageRetentionController.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to add controller to manager", "controller", "StorageRetention")
+		os.Exit(1)
+	}
+	backupConfig := newReconcilerConfig(mgr, "CryostatBackup", "cryostatbackup-controller", openShift,
+		certManager, gatewayAPI, backendTLSPolicy, insightsURL)
+	backupController, err := controllers.NewCryostatBackupReconciler(backupConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CryostatBackup")
+		os.Exit(1)
+	}
+	if err = backupController.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to add controller to manager", "controller", "CryostatBackup")
+		os.Exit(1)
+	}
+	restoreConfig := newReconcilerConfig(mgr, "CryostatRestore", "cryostatrestore-controller", openShift,
+		certManager, gatewayAPI, backendTLSPolicy, insightsURL)
+	restoreController, err := controllers.NewCryostatRestoreReconciler(restoreConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CryostatRestore")
+		os.Exit(1)
+	}
+	if err = restoreController.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to add controller to manager", "controller", "CryostatRestore")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -254,18 +353,32 @@ func isCertManagerInstalled(client discovery.DiscoveryInterface) (bool, error) {
 	return discovery.IsResourceEnabled(client, certv1.SchemeGroupVersion.WithResource("issuers"))
 }
 
+func isGatewayAPIInstalled(client discovery.DiscoveryInterface) (bool, error) {
+	return discovery.IsResourceEnabled(client, gatewayv1.SchemeGroupVersion.WithResource("httproutes"))
+}
+
+func isBackendTLSPolicyInstalled(client discovery.DiscoveryInterface) (bool, error) {
+	return discovery.IsResourceEnabled(client, gatewayv1alpha3.SchemeGroupVersion.WithResource("backendtlspolicies"))
+}
+
 func newReconcilerConfig(mgr ctrl.Manager, logName string, eventRecorderName string, openShift bool,
-	certManager bool, insightsURL *url.URL) *controllers.ReconcilerConfig {
+	certManager bool, gatewayAPI bool, backendTLSPolicy bool, insightsURL *url.URL) *controllers.ReconcilerConfig {
 	return &controllers.ReconcilerConfig{
-		Client:                 mgr.GetClient(),
-		Log:                    ctrl.Log.WithName("controllers").WithName(logName),
-		Scheme:                 mgr.GetScheme(),
-		IsOpenShift:            openShift,
-		IsCertManagerInstalled: certManager,
-		EventRecorder:          mgr.GetEventRecorderFor(eventRecorderName),
-		RESTMapper:             mgr.GetRESTMapper(),
-		InsightsProxy:          insightsURL,
-		NewControllerBuilder:   common.NewControllerBuilder,
+		Client:                      mgr.GetClient(),
+		APIReader:                   mgr.GetAPIReader(),
+		Log:                         ctrl.Log.WithName("controllers").WithName(logName),
+		Scheme:                      mgr.GetScheme(),
+		IsOpenShift:                 openShift,
+		IsCertManagerInstalled:      certManager,
+		IsGatewayAPIInstalled:       gatewayAPI,
+		IsBackendTLSPolicyInstalled: backendTLSPolicy,
+		EventRecorder:               mgr.GetEventRecorderFor(eventRecorderName),
+		RESTMapper:                  mgr.GetRESTMapper(),
+		InsightsProxy:               insightsURL,
+		NewControllerBuilder:        common.NewControllerBuilder,
+		NewCryostatClient:           cryostatclient.NewFactory(mgr.GetConfig()),
+		OperatorNamespace:           os.Getenv("OPERATOR_NAMESPACE"),
+		OperatorServiceAccount:      os.Getenv("OPERATOR_SERVICE_ACCOUNT"),
 		ReconcilerTLS: common.NewReconcilerTLS(&common.ReconcilerTLSConfig{
 			Client: mgr.GetClient(),
 		}),
@@ -28,6 +28,7 @@ import (
 // TestCtrlBuilder is a fake ControllerBuilder to aid testing of
 // controller watches
 type TestCtrlBuilder struct {
+	Name           string
 	ForCalls       []ForArgs
 	OwnsCalls      []OwnsArgs
 	WatchesCalls   []WatchesArgs
@@ -63,6 +64,11 @@ func NewControllerBuilder(config *TestReconcilerConfig) func(ctrl.Manager) commo
 	}
 }
 
+func (b *TestCtrlBuilder) Named(name string) common.ControllerBuilder {
+	b.Name = name
+	return b
+}
+
 func (b *TestCtrlBuilder) For(object client.Object, opts ...builder.ForOption) common.ControllerBuilder {
 	b.ForCalls = append(b.ForCalls, ForArgs{
 		Object: object,
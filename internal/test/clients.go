@@ -90,6 +90,9 @@ func (c *testClient) makeCertificatesReady(ctx context.Context, obj runtime.Obje
 			Type:   certv1.CertificateConditionReady,
 			Status: certMeta.ConditionTrue,
 		})
+		// Use cert-manager's default duration and renewal time
+		cert.Status.NotAfter = &metav1.Time{Time: CertNotAfter}
+		cert.Status.RenewalTime = &metav1.Time{Time: CertRenewalTime}
 		err := c.Status().Update(context.Background(), cert)
 		gomega.Expect(err).ToNot(gomega.HaveOccurred())
 	}
@@ -116,7 +119,8 @@ func (c *testClient) updateRouteStatus(ctx context.Context, obj runtime.Object)
 
 func (c *testClient) matchesCert(cert *certv1.Certificate) bool {
 	return c.matchesName(cert, c.NewCryostatCert(), c.NewCACert(), c.NewReportsCert(), c.NewAgentProxyCert(),
-		c.NewDatabaseCert(), c.NewStorageCert()) || c.matchesPrefix(cert, c.GetAgentCertPrefix())
+		c.NewDatabaseCert(), c.NewStorageCert(), c.NewGrafanaCert()) || c.matchesPrefix(cert, c.GetAgentCertPrefix()) ||
+		c.matchesPrefix(cert, c.NewCACert().Name+"-")
 }
 
 func (c *testClient) migrateStringData(obj runtime.Object) {
@@ -153,6 +157,10 @@ func (c *timestampClient) Create(ctx context.Context, obj ctrlclient.Object, opt
 
 var creationTimestamp = metav1.NewTime(time.Unix(1664573254, 0))
 
+// Expiry and renewal times of certificates issued during tests
+var CertNotAfter = time.Now().Add(90 * 24 * time.Hour).Truncate(time.Second)
+var CertRenewalTime = CertNotAfter.Add(-30 * 24 * time.Hour)
+
 func SetCreationTimestamp(objs ...ctrlclient.Object) error {
 	for _, obj := range objs {
 		metaObj, err := meta.Accessor(obj)
@@ -0,0 +1,139 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
+	"github.com/cryostatio/cryostat-operator/internal/controllers"
+	"github.com/onsi/gomega"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ControllerTestInput holds the state shared by tests of the controllers that
+// manage custom resources other than Cryostat
+type ControllerTestInput struct {
+	Objs           []ctrlclient.Object
+	CryostatClient *FakeCryostatClient
+	Recorder       *record.FakeRecorder
+	// Optional hooks into the fake client, used to simulate API server failures
+	Interceptors interceptor.Funcs
+	TestReconcilerConfig
+	*TestResources
+}
+
+func NewControllerTestInput() *ControllerTestInput {
+	return &ControllerTestInput{
+		CryostatClient: NewFakeCryostatClient(),
+		Recorder:       record.NewFakeRecorder(1024),
+		TestReconcilerConfig: TestReconcilerConfig{
+			ControllerBuilder: &TestCtrlBuilder{},
+		},
+		TestResources: &TestResources{
+			Name:      "cryostat",
+			Namespace: "test",
+		},
+	}
+}
+
+// NewReconcilerConfig creates the fake client from the test's objects, and returns
+// a ReconcilerConfig using it. The Cryostat and each type in statusObjs are
+// given a status subresource.
+func (t *ControllerTestInput) NewReconcilerConfig(statusObjs ...ctrlclient.Object) *controllers.ReconcilerConfig {
+	s := NewTestScheme()
+	err := SetCreationTimestamp(t.Objs...)
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	statusObjs = append([]ctrlclient.Object{&operatorv1beta2.Cryostat{}}, statusObjs...)
+	t.Client = fake.NewClientBuilder().WithScheme(s).WithObjects(t.Objs...).
+		WithStatusSubresource(statusObjs...).WithInterceptorFuncs(t.Interceptors).Build()
+	return &controllers.ReconcilerConfig{
+		Client:                 t.Client,
+		APIReader:              t.Client,
+		Scheme:                 s,
+		EventRecorder:          t.Recorder,
+		Log:                    zap.New(),
+		IsCertManagerInstalled: !t.CertManagerMissing,
+		ReconcilerTLS:          NewTestReconcilerTLS(&t.TestReconcilerConfig),
+		NewControllerBuilder:   NewControllerBuilder(&t.TestReconcilerConfig),
+		NewCryostatClient:      t.CryostatClient.Factory(),
+		OSUtils:                NewTestOSUtils(&t.TestReconcilerConfig),
+	}
+}
+
+// Reconcile sends a request for obj to the reconciler
+func (t *ControllerTestInput) Reconcile(r reconcile.Reconciler, obj ctrlclient.Object) (reconcile.Result, error) {
+	req := reconcile.Request{NamespacedName: ctrlclient.ObjectKeyFromObject(obj)}
+	return r.Reconcile(context.Background(), req)
+}
+
+// ExpectReconcile sends a request for obj to the reconciler, which must succeed
+func (t *ControllerTestInput) ExpectReconcile(r reconcile.Reconciler, obj ctrlclient.Object) {
+	_, err := t.Reconcile(r, obj)
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+}
+
+// Get replaces obj with the latest copy from the fake client
+func (t *ControllerTestInput) Get(obj ctrlclient.Object) {
+	err := t.Client.Get(context.Background(), ctrlclient.ObjectKeyFromObject(obj), obj)
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+}
+
+// UpdateCryostat applies update to the test's Cryostat, such as to pause it
+func (t *ControllerTestInput) UpdateCryostat(update func(cr *operatorv1beta2.Cryostat)) {
+	cr := &operatorv1beta2.Cryostat{}
+	err := t.Client.Get(context.Background(), ctrlclient.ObjectKey{Name: t.Name, Namespace: t.Namespace}, cr)
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	update(cr)
+	err = t.Client.Update(context.Background(), cr)
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+}
+
+// DeleteAndReconcile deletes obj, then reconciles it so its finalizer runs
+func (t *ControllerTestInput) DeleteAndReconcile(r reconcile.Reconciler, obj ctrlclient.Object) {
+	err := t.Client.Delete(context.Background(), obj)
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	t.ExpectReconcile(r, obj)
+}
+
+func (t *ControllerTestInput) ExpectNotFound(obj ctrlclient.Object) {
+	err := t.Client.Get(context.Background(), ctrlclient.ObjectKeyFromObject(obj), obj)
+	gomega.Expect(kerrors.IsNotFound(err)).To(gomega.BeTrue())
+}
+
+func (t *ControllerTestInput) ExpectFinalizer(obj ctrlclient.Object, finalizer string) {
+	t.Get(obj)
+	gomega.Expect(controllerutil.ContainsFinalizer(obj, finalizer)).To(gomega.BeTrue())
+}
+
+// ExpectCondition checks that a condition is present with the given status and reason,
+// and was set for the latest generation of its object
+func ExpectCondition(conditions []metav1.Condition, conditionType string, status metav1.ConditionStatus,
+	reason string, generation int64) {
+	condition := meta.FindStatusCondition(conditions, conditionType)
+	gomega.Expect(condition).ToNot(gomega.BeNil())
+	gomega.Expect(condition.Status).To(gomega.Equal(status))
+	gomega.Expect(condition.Reason).To(gomega.Equal(reason))
+	gomega.Expect(condition.ObservedGeneration).To(gomega.Equal(generation))
+}
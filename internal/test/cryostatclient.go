@@ -0,0 +1,397 @@
+// Copyright The Cryostat Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/cryostatio/cryostat-operator/internal/cryostatclient"
+)
+
+// FakeCryostatClient is an in-memory implementation of the Cryostat API
+type FakeCryostatClient struct {
+	// Targets discovered by this fake Cryostat
+	Targets []cryostatclient.Target
+	// Active recordings, keyed by target ID
+	Recordings map[int64][]cryostatclient.Recording
+	// Options used to create each recording, keyed by recording ID
+	CreateOptions map[int64]cryostatclient.RecordingCreateOptions
+	// Names of recordings archived using ArchiveRecording
+	Archives []string
+	// Recordings in the archives
+	ArchivedRecordings []cryostatclient.ArchivedRecording
+	// Automated rules, keyed by name
+	Rules map[string]cryostatclient.Rule
+	// Names of automated rules deleted along with their recordings
+	CleanedRules []string
+	// Stored credentials, keyed by ID
+	Credentials map[int64]cryostatclient.CredentialCreateOptions
+	// Contents of custom event templates, keyed by template name
+	EventTemplates map[string]string
+	// Number of event templates uploaded
+	TemplateUploads int
+	// Configurations passed to the factory
+	Configs []*cryostatclient.Config
+	// If set, all API calls fail with this error
+	Err error
+
+	nextRecordingID int64
+	nextRuleID      int64
+	nextCredID      int64
+}
+
+var _ cryostatclient.Client = (*FakeCryostatClient)(nil)
+
+func NewFakeCryostatClient(targets ...cryostatclient.Target) *FakeCryostatClient {
+	return &FakeCryostatClient{
+		Targets:        targets,
+		Recordings:     map[int64][]cryostatclient.Recording{},
+		CreateOptions:  map[int64]cryostatclient.RecordingCreateOptions{},
+		Rules:          map[string]cryostatclient.Rule{},
+		Credentials:    map[int64]cryostatclient.CredentialCreateOptions{},
+		EventTemplates: map[string]string{},
+	}
+}
+
+// Factory returns a factory that always returns this client
+func (c *FakeCryostatClient) Factory() cryostatclient.Factory {
+	return func(config *cryostatclient.Config) (cryostatclient.Client, error) {
+		c.Configs = append(c.Configs, config)
+		return c, nil
+	}
+}
+
+func (c *FakeCryostatClient) ListTargets(ctx context.Context) ([]cryostatclient.Target, error) {
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	return append([]cryostatclient.Target{}, c.Targets...), nil
+}
+
+func (c *FakeCryostatClient) ListRecordings(ctx context.Context, targetID int64) ([]cryostatclient.Recording, error) {
+	if err := c.checkTarget(http.MethodGet, targetID); err != nil {
+		return nil, err
+	}
+	return append([]cryostatclient.Recording{}, c.Recordings[targetID]...), nil
+}
+
+func (c *FakeCryostatClient) CreateRecording(ctx context.Context, targetID int64,
+	options *cryostatclient.RecordingCreateOptions) (*cryostatclient.Recording, error) {
+	if err := c.checkTarget(http.MethodPost, targetID); err != nil {
+		return nil, err
+	}
+	c.nextRecordingID++
+	recording := cryostatclient.Recording{
+		ID:         c.nextRecordingID,
+		Name:       options.RecordingName,
+		State:      cryostatclient.RecordingStateRunning,
+		Duration:   options.Duration * 1000,
+		Continuous: options.Duration == 0,
+		ToDisk:     options.ToDisk,
+		MaxSize:    options.MaxSize,
+		MaxAge:     options.MaxAge * 1000,
+	}
+	c.Recordings[targetID] = append(c.Recordings[targetID], recording)
+	c.CreateOptions[recording.ID] = *options
+	return &recording, nil
+}
+
+func (c *FakeCryostatClient) StopRecording(ctx context.Context, targetID int64, recordingID int64) error {
+	recording, err := c.findRecording(http.MethodPatch, targetID, recordingID)
+	if err != nil {
+		return err
+	}
+	recording.State = cryostatclient.RecordingStateStopped
+	return nil
+}
+
+func (c *FakeCryostatClient) ArchiveRecording(ctx context.Context, targetID int64, recordingID int64) (string, error) {
+	recording, err := c.findRecording(http.MethodPatch, targetID, recordingID)
+	if err != nil {
+		return "", err
+	}
+	archive := fmt.Sprintf("target-%d_%s_%d.jfr", targetID, recording.Name, len(c.Archives)+1)
+	c.Archives = append(c.Archives, archive)
+	c.ArchivedRecordings = append(c.ArchivedRecordings, cryostatclient.ArchivedRecording{
+		JvmID:        c.jvmID(targetID),
+		Name:         archive,
+		ArchivedTime: time.Now().Unix(),
+	})
+	return archive, nil
+}
+
+func (c *FakeCryostatClient) ListArchivedRecordings(ctx context.Context) ([]cryostatclient.ArchivedRecording, error) {
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	return append([]cryostatclient.ArchivedRecording{}, c.ArchivedRecordings...), nil
+}
+
+func (c *FakeCryostatClient) DeleteArchivedRecording(ctx context.Context, name string) error {
+	if c.Err != nil {
+		return c.Err
+	}
+	for i, archive := range c.ArchivedRecordings {
+		if archive.Name == name {
+			c.ArchivedRecordings = append(c.ArchivedRecordings[:i], c.ArchivedRecordings[i+1:]...)
+			return nil
+		}
+	}
+	return &cryostatclient.APIError{
+		Method:     http.MethodDelete,
+		Path:       "/api/v4/recordings/" + name,
+		StatusCode: http.StatusNotFound,
+	}
+}
+
+func (c *FakeCryostatClient) DeleteRecording(ctx context.Context, targetID int64, recordingID int64) error {
+	if _, err := c.findRecording(http.MethodDelete, targetID, recordingID); err != nil {
+		return err
+	}
+	recordings := []cryostatclient.Recording{}
+	for _, recording := range c.Recordings[targetID] {
+		if recording.ID != recordingID {
+			recordings = append(recordings, recording)
+		}
+	}
+	c.Recordings[targetID] = recordings
+	return nil
+}
+
+func (c *FakeCryostatClient) GetRule(ctx context.Context, name string) (*cryostatclient.Rule, error) {
+	rule, err := c.findRule(http.MethodGet, name)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (c *FakeCryostatClient) CreateRule(ctx context.Context, rule *cryostatclient.Rule) (*cryostatclient.Rule, error) {
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	if _, pres := c.Rules[rule.Name]; pres {
+		return nil, &cryostatclient.APIError{
+			Method:     http.MethodPost,
+			Path:       "/api/v4/rules",
+			StatusCode: http.StatusConflict,
+		}
+	}
+	c.nextRuleID++
+	created := *rule
+	created.ID = c.nextRuleID
+	c.Rules[created.Name] = created
+	return &created, nil
+}
+
+func (c *FakeCryostatClient) SetRuleEnabled(ctx context.Context, name string, enabled bool) error {
+	rule, err := c.findRule(http.MethodPatch, name)
+	if err != nil {
+		return err
+	}
+	rule.Enabled = enabled
+	c.Rules[name] = rule
+	return nil
+}
+
+func (c *FakeCryostatClient) DeleteRule(ctx context.Context, name string, clean bool) error {
+	if _, err := c.findRule(http.MethodDelete, name); err != nil {
+		return err
+	}
+	delete(c.Rules, name)
+	if clean {
+		c.CleanedRules = append(c.CleanedRules, name)
+	}
+	return nil
+}
+
+func (c *FakeCryostatClient) findRule(method string, name string) (cryostatclient.Rule, error) {
+	if c.Err != nil {
+		return cryostatclient.Rule{}, c.Err
+	}
+	rule, pres := c.Rules[name]
+	if !pres {
+		return cryostatclient.Rule{}, &cryostatclient.APIError{
+			Method:     method,
+			Path:       "/api/v4/rules/" + name,
+			StatusCode: http.StatusNotFound,
+		}
+	}
+	return rule, nil
+}
+
+func (c *FakeCryostatClient) ListCredentials(ctx context.Context) ([]cryostatclient.Credential, error) {
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	credentials := []cryostatclient.Credential{}
+	for id, credential := range c.Credentials {
+		credentials = append(credentials, cryostatclient.Credential{ID: id, MatchExpression: credential.MatchExpression})
+	}
+	sort.Slice(credentials, func(i, j int) bool {
+		return credentials[i].ID < credentials[j].ID
+	})
+	return credentials, nil
+}
+
+func (c *FakeCryostatClient) GetCredential(ctx context.Context, id int64) (*cryostatclient.Credential, error) {
+	credential, err := c.findCredential(http.MethodGet, id)
+	if err != nil {
+		return nil, err
+	}
+	return &cryostatclient.Credential{ID: id, MatchExpression: credential.MatchExpression}, nil
+}
+
+func (c *FakeCryostatClient) CreateCredential(ctx context.Context,
+	options *cryostatclient.CredentialCreateOptions) (*cryostatclient.Credential, error) {
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	c.nextCredID++
+	c.Credentials[c.nextCredID] = *options
+	return &cryostatclient.Credential{ID: c.nextCredID, MatchExpression: options.MatchExpression}, nil
+}
+
+func (c *FakeCryostatClient) DeleteCredential(ctx context.Context, id int64) error {
+	if _, err := c.findCredential(http.MethodDelete, id); err != nil {
+		return err
+	}
+	delete(c.Credentials, id)
+	return nil
+}
+
+func (c *FakeCryostatClient) findCredential(method string, id int64) (cryostatclient.CredentialCreateOptions, error) {
+	if c.Err != nil {
+		return cryostatclient.CredentialCreateOptions{}, c.Err
+	}
+	credential, pres := c.Credentials[id]
+	if !pres {
+		return cryostatclient.CredentialCreateOptions{}, &cryostatclient.APIError{
+			Method:     method,
+			Path:       fmt.Sprintf("/api/v4/credentials/%d", id),
+			StatusCode: http.StatusNotFound,
+		}
+	}
+	return credential, nil
+}
+
+func (c *FakeCryostatClient) ListEventTemplates(ctx context.Context) ([]cryostatclient.EventTemplate, error) {
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	templates := []cryostatclient.EventTemplate{
+		{Name: "Continuous", Provider: "Oracle", Type: cryostatclient.EventTemplateTypeTarget},
+	}
+	for name := range c.EventTemplates {
+		templates = append(templates, cryostatclient.EventTemplate{Name: name, Type: cryostatclient.EventTemplateTypeCustom})
+	}
+	return templates, nil
+}
+
+func (c *FakeCryostatClient) UploadEventTemplate(ctx context.Context, filename string, content []byte) error {
+	if c.Err != nil {
+		return c.Err
+	}
+	template := struct {
+		Label string `xml:"label,attr"`
+	}{}
+	if err := xml.Unmarshal(content, &template); err != nil || len(template.Label) == 0 {
+		return &cryostatclient.APIError{
+			Method:     http.MethodPost,
+			Path:       "/api/v4/event_templates",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+	if _, pres := c.EventTemplates[template.Label]; pres {
+		return &cryostatclient.APIError{
+			Method:     http.MethodPost,
+			Path:       "/api/v4/event_templates",
+			StatusCode: http.StatusConflict,
+		}
+	}
+	c.EventTemplates[template.Label] = string(content)
+	c.TemplateUploads++
+	return nil
+}
+
+func (c *FakeCryostatClient) DeleteEventTemplate(ctx context.Context, name string) error {
+	if c.Err != nil {
+		return c.Err
+	}
+	if _, pres := c.EventTemplates[name]; !pres {
+		return &cryostatclient.APIError{
+			Method:     http.MethodDelete,
+			Path:       "/api/v4/event_templates/" + name,
+			StatusCode: http.StatusNotFound,
+		}
+	}
+	delete(c.EventTemplates, name)
+	return nil
+}
+
+// StopAll marks all recordings as stopped, as if their duration elapsed
+func (c *FakeCryostatClient) StopAll() {
+	for targetID := range c.Recordings {
+		for i := range c.Recordings[targetID] {
+			c.Recordings[targetID][i].State = cryostatclient.RecordingStateStopped
+		}
+	}
+}
+
+func (c *FakeCryostatClient) checkTarget(method string, targetID int64) error {
+	if c.Err != nil {
+		return c.Err
+	}
+	for _, target := range c.Targets {
+		if target.ID == targetID {
+			return nil
+		}
+	}
+	return &cryostatclient.APIError{
+		Method:     method,
+		Path:       fmt.Sprintf("/api/v4/targets/%d", targetID),
+		StatusCode: http.StatusNotFound,
+	}
+}
+
+func (c *FakeCryostatClient) jvmID(targetID int64) string {
+	for _, target := range c.Targets {
+		if target.ID == targetID {
+			return target.JvmID
+		}
+	}
+	return ""
+}
+
+func (c *FakeCryostatClient) findRecording(method string, targetID int64, recordingID int64) (*cryostatclient.Recording, error) {
+	if err := c.checkTarget(method, targetID); err != nil {
+		return nil, err
+	}
+	for i := range c.Recordings[targetID] {
+		if c.Recordings[targetID][i].ID == recordingID {
+			return &c.Recordings[targetID][i], nil
+		}
+	}
+	return nil, &cryostatclient.APIError{
+		Method:     method,
+		Path:       fmt.Sprintf("/api/v4/targets/%d/recordings/%d", targetID, recordingID),
+		StatusCode: http.StatusNotFound,
+	}
+}
@@ -40,6 +40,8 @@ type TestReconcilerConfig struct {
 	GeneratedPasswords             []string
 	ControllerBuilder              *TestCtrlBuilder
 	CertManagerMissing             bool
+	GatewayAPIInstalled            bool
+	BackendTLSPolicyInstalled      bool
 }
 
 func NewTestReconcilerTLS(config *TestReconcilerConfig) common.ReconcilerTLS {
@@ -21,11 +21,13 @@ import (
 	"hash/fnv"
 	"slices"
 	"strings"
+	"time"
 
 	certv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	certMeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
 	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
 	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
+	"github.com/cryostatio/cryostat-operator/internal/cryostatclient"
 	"github.com/onsi/gomega"
 	configv1 "github.com/openshift/api/config/v1"
 	consolev1 "github.com/openshift/api/console/v1"
@@ -39,12 +41,16 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/scheme"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
 )
 
 type TestResources struct {
@@ -59,6 +65,7 @@ type TestResources struct {
 	DisableAgentHostnameVerify bool
 	AllowAgentInsecure         bool
 	DatabaseSecret             *corev1.Secret
+	TLSProfile                 operatorv1beta2.TLSProfileType
 }
 
 func NewTestScheme() *runtime.Scheme {
@@ -70,13 +77,26 @@ func NewTestScheme() *runtime.Scheme {
 		certv1.AddToScheme,
 		routev1.AddToScheme,
 		consolev1.AddToScheme,
+		gatewayv1.AddToScheme,
+		gatewayv1alpha3.AddToScheme,
 	)
 	err := sb.AddToScheme(s)
 	gomega.Expect(err).ToNot(gomega.HaveOccurred())
 
+	// The operator manages VolumeSnapshots as unstructured objects
+	s.AddKnownTypeWithName(VolumeSnapshotGVK, &unstructured.Unstructured{})
+	s.AddKnownTypeWithName(VolumeSnapshotGVK.GroupVersion().WithKind(VolumeSnapshotGVK.Kind+"List"),
+		&unstructured.UnstructuredList{})
+
 	return s
 }
 
+var VolumeSnapshotGVK = schema.GroupVersionKind{
+	Group:   "snapshot.storage.k8s.io",
+	Version: "v1",
+	Kind:    "VolumeSnapshot",
+}
+
 func NewTESTRESTMapper() meta.RESTMapper {
 	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{
 		certv1.SchemeGroupVersion,
@@ -87,6 +107,8 @@ func NewTESTRESTMapper() meta.RESTMapper {
 		Version: certv1.SchemeGroupVersion.Version,
 		Kind:    certv1.IssuerKind,
 	}, meta.RESTScopeNamespace)
+	// Add VolumeSnapshot GVK
+	mapper.Add(VolumeSnapshotGVK, meta.RESTScopeNamespace)
 	return mapper
 }
 
@@ -175,6 +197,42 @@ func (r *TestResources) addIngressToCryostat(cr *model.CryostatInstance) *model.
 	return cr
 }
 
+func (r *TestResources) NewCryostatWithHTTPRoute() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	gatewayNamespace := gatewayv1.Namespace("gateway-ns")
+	cr.Spec.NetworkOptions = &operatorv1beta2.NetworkConfigurationList{
+		CoreConfig: &operatorv1beta2.NetworkConfiguration{
+			ResourceMetadata: operatorv1beta2.ResourceMetadata{
+				Annotations: map[string]string{"custom": "annotation"},
+				Labels:      map[string]string{"custom": "label"},
+			},
+			HTTPRoute: &operatorv1beta2.HTTPRouteConfiguration{
+				ParentRef: gatewayv1.ParentReference{
+					Name:      "my-gateway",
+					Namespace: &gatewayNamespace,
+				},
+				Hostnames: []gatewayv1.Hostname{gatewayv1.Hostname(r.Name + ".example.com")},
+			},
+		},
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatWithHTTPRouteBackendTLSPolicyDisabled() *model.CryostatInstance {
+	cr := r.NewCryostatWithHTTPRoute()
+	disabled := true
+	cr.Spec.NetworkOptions.CoreConfig.HTTPRoute.BackendTLSPolicy = &operatorv1beta2.BackendTLSPolicyConfiguration{
+		Disabled: &disabled,
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatWithHTTPRouteNoHostnames() *model.CryostatInstance {
+	cr := r.NewCryostatWithHTTPRoute()
+	cr.Spec.NetworkOptions.CoreConfig.HTTPRoute.Hostnames = nil
+	return cr
+}
+
 func (r *TestResources) NewCryostatWithPVCSpec() *model.CryostatInstance {
 	cr := r.NewCryostat()
 	cr.Spec.StorageOptions = &operatorv1beta2.StorageConfigurations{
@@ -559,6 +617,74 @@ func (r *TestResources) NewCryostatWithCoreRouteHost() *model.CryostatInstance {
 	return cr
 }
 
+func (r *TestResources) NewCryostatWithAgentGatewayNetwork() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	cr.Spec.NetworkOptions = &operatorv1beta2.NetworkConfigurationList{
+		AgentGatewayConfig: &operatorv1beta2.AgentGatewayNetworkConfiguration{
+			ExternalHost: &[]string{"agent.example.com"}[0],
+			ClientCertificates: []operatorv1beta2.AgentClientCertificate{
+				{
+					Name: "external-app",
+				},
+			},
+			ResourceMetadata: operatorv1beta2.ResourceMetadata{
+				Annotations: map[string]string{"custom": "annotation"},
+				Labels:      map[string]string{"custom": "label"},
+			},
+		},
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatWithAgentGatewayLoadBalancer() *model.CryostatInstance {
+	cr := r.NewCryostatWithAgentGatewayNetwork()
+	cr.Spec.NetworkOptions.AgentGatewayConfig.LoadBalancerConfig = &operatorv1beta2.AgentGatewayLoadBalancerConfig{
+		Port: &[]int32{9443}[0],
+		ResourceMetadata: operatorv1beta2.ResourceMetadata{
+			Annotations: map[string]string{"lb": "annotation"},
+			Labels:      map[string]string{"lb": "label"},
+		},
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatWithAgentGatewayIngress() *model.CryostatInstance {
+	cr := r.NewCryostatWithAgentGatewayNetwork()
+	cr.Spec.NetworkOptions.AgentGatewayConfig.ExternalHost = nil
+	cr.Spec.NetworkOptions.AgentGatewayConfig.Annotations["nginx.ingress.kubernetes.io/ssl-passthrough"] = "true"
+	cr.Spec.NetworkOptions.AgentGatewayConfig.IngressSpec = r.newAgentGatewayIngressSpec()
+	return cr
+}
+
+func (r *TestResources) newAgentGatewayIngressSpec() *netv1.IngressSpec {
+	pathtype := netv1.PathTypePrefix
+	return &netv1.IngressSpec{
+		Rules: []netv1.IngressRule{
+			{
+				Host: "agent.example.com",
+				IngressRuleValue: netv1.IngressRuleValue{
+					HTTP: &netv1.HTTPIngressRuleValue{
+						Paths: []netv1.HTTPIngressPath{
+							{
+								Path:     "/",
+								PathType: &pathtype,
+								Backend: netv1.IngressBackend{
+									Service: &netv1.IngressServiceBackend{
+										Name: r.Name + "-agent",
+										Port: netv1.ServiceBackendPort{
+											Number: 8282,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func (r *TestResources) NewCryostatWithReportsResources() *model.CryostatInstance {
 	cr := r.NewCryostat()
 	cr.Spec.ReportOptions = &operatorv1beta2.ReportConfiguration{
@@ -649,6 +775,39 @@ func (r *TestResources) NewCryostatWithScheduling() *model.CryostatInstance {
 	return cr
 }
 
+func populateCryostatWithComponentScheduling() *operatorv1beta2.SchedulingConfiguration {
+	scheduling := populateCryostatWithScheduling()
+	scheduling.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{
+		{
+			MaxSkew:           1,
+			TopologyKey:       "topology.kubernetes.io/zone",
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+			LabelSelector:     metav1.AddLabelToSelector(&metav1.LabelSelector{}, "pod", "good"),
+		},
+	}
+	scheduling.PriorityClassName = "high-priority"
+	scheduling.RuntimeClassName = &[]string{"kata"}[0]
+	return scheduling
+}
+
+func (r *TestResources) NewCryostatWithComponentScheduling() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	cr.Spec.SchedulingOptions = populateCryostatWithComponentScheduling()
+	databaseScheduling := populateCryostatWithComponentScheduling()
+	databaseScheduling.NodeSelector = map[string]string{"disk": "ssd"}
+	databaseScheduling.PriorityClassName = "database-priority"
+	cr.Spec.DatabaseOptions = &operatorv1beta2.DatabaseOptions{
+		SchedulingOptions: databaseScheduling,
+	}
+	storageScheduling := populateCryostatWithComponentScheduling()
+	storageScheduling.NodeSelector = map[string]string{"storage": "large"}
+	storageScheduling.RuntimeClassName = nil
+	cr.Spec.ObjectStorageOptions = &operatorv1beta2.ObjectStorageOptions{
+		SchedulingOptions: storageScheduling,
+	}
+	return cr
+}
+
 func (r *TestResources) NewCryostatWithReportsScheduling() *model.CryostatInstance {
 	cr := r.NewCryostat()
 	cr.Spec.ReportOptions = &operatorv1beta2.ReportConfiguration{
@@ -855,6 +1014,48 @@ func (r *TestResources) NewCryostatWithReportSubprocessHeapSpec() *model.Cryosta
 	return cr
 }
 
+func (r *TestResources) NewCryostatWithTLSProfile(profileType operatorv1beta2.TLSProfileType) *model.CryostatInstance {
+	cr := r.NewCryostat()
+	cr.Spec.SecurityOptions = &operatorv1beta2.SecurityOptions{
+		TLSProfile: &operatorv1beta2.TLSProfile{
+			Type: &profileType,
+		},
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatWithCustomTLSProfile() *model.CryostatInstance {
+	cr := r.NewCryostatWithTLSProfile(operatorv1beta2.TLSProfileCustom)
+	cr.Spec.SecurityOptions.TLSProfile.Custom = &operatorv1beta2.CustomTLSProfile{
+		Ciphers: []string{
+			"TLS_AES_128_GCM_SHA256",
+			"TLS_AES_256_GCM_SHA384",
+			"TLS_CHACHA20_POLY1305_SHA256",
+		},
+		MinTLSVersion: operatorv1beta2.VersionTLS13,
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatWithInheritedTLSProfile() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	cr.Spec.SecurityOptions = &operatorv1beta2.SecurityOptions{
+		TLSProfile: &operatorv1beta2.TLSProfile{
+			InheritFromAPIServer: &[]bool{true}[0],
+		},
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatWithCARotation(revision string, overlap time.Duration) *model.CryostatInstance {
+	cr := r.NewCryostat()
+	cr.Spec.CARotation = &operatorv1beta2.CARotationOptions{
+		Revision:      revision,
+		OverlapPeriod: &metav1.Duration{Duration: overlap},
+	}
+	return cr
+}
+
 func (r *TestResources) NewCryostatWithSecurityOptions() *model.CryostatInstance {
 	cr := r.NewCryostat()
 	privEscalation := true
@@ -958,137 +1159,456 @@ func (r *TestResources) NewCryostatWithDatabaseSecretProvided() *model.CryostatI
 	return cr
 }
 
-func (r *TestResources) NewCryostatWithAdditionalMetadata() *model.CryostatInstance {
+func (r *TestResources) NewCryostatWithImageOverrides() *model.CryostatInstance {
 	cr := r.NewCryostat()
-	cr.Spec.OperandMetadata = &operatorv1beta2.OperandMetadata{
-		DeploymentMetadata: &operatorv1beta2.ResourceMetadata{
-			Labels: map[string]string{
-				"myDeploymentExtraLabel":       "myDeploymentLabel",
-				"mySecondDeploymentExtraLabel": "mySecondDeploymentLabel",
-				// below, labels that should be discarded as overriden by the default
-				"app":                    "myApp",
-				"component":              "myComponent",
-				"kind":                   "myKind",
-				"app.kubernetes.io/name": "myName",
+	cr.Spec.Images = &operatorv1beta2.ImageOverrides{
+		Core:       "mirror.example.com/cryostat/core:custom",
+		Datasource: "mirror.example.com/cryostat/datasource:custom",
+		Grafana:    "mirror.example.com/cryostat/grafana:custom",
+		Reports:    "mirror.example.com/cryostat/reports:custom",
+		Storage:    "mirror.example.com/cryostat/storage:custom",
+		Database:   "mirror.example.com/cryostat/database:custom",
+		AuthProxy:  "mirror.example.com/cryostat/auth-proxy:custom",
+		AgentProxy: "mirror.example.com/cryostat/agent-proxy:custom",
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatWithImagePullSecrets() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	cr.Spec.ImagePullSecrets = []corev1.LocalObjectReference{
+		{Name: "mirror-pull-secret"},
+		{Name: "other-pull-secret"},
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatWithCoreOptions() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	optional := true
+	cr.Spec.CoreOptions = &operatorv1beta2.CoreOptions{
+		Env: []corev1.EnvVar{
+			{Name: "CRYOSTAT_DISCOVERY_JDP_ENABLED", Value: "true"},
+			// Set by the operator, so this should be ignored
+			{Name: "QUARKUS_HTTP_PORT", Value: "9000"},
+		},
+		EnvFrom: []corev1.EnvFromSource{
+			{
+				SecretRef: &corev1.SecretEnvSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "cryostat-extra-env"},
+				},
 			},
-			Annotations: map[string]string{
-				"myDeploymentExtraAnnotation":       "myDeploymentAnnotation",
-				"mySecondDeploymentExtraAnnotation": "mySecondDeploymentAnnotation",
-				// below, annotation that should be discarded as overriden by the default
-				"app.openshift.io/connects-to": "connectToMe",
+			{
+				ConfigMapRef: &corev1.ConfigMapEnvSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "cryostat-extra-config"},
+					Optional:             &optional,
+				},
 			},
 		},
-		PodMetadata: &operatorv1beta2.ResourceMetadata{
-			Labels: map[string]string{
-				"myPodExtraLabel":       "myPodLabel",
-				"myPodSecondExtraLabel": "myPodSecondLabel",
-				// below, labels that should be discarded as overriden by the default
-				"app":       "myApp",
-				"component": "myComponent",
-				"kind":      "myKind",
+		JavaOptions: "-Xmx1g",
+	}
+	return cr
+}
+
+func (r *TestResources) NewCoreEnvFromSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cryostat-extra-env",
+			Namespace: r.Namespace,
+		},
+		Data: map[string][]byte{
+			"QUARKUS_LOG_LEVEL": []byte("DEBUG"),
+		},
+	}
+}
+
+func (r *TestResources) NewCryostatWithGrafanaOptions() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	cr.Spec.GrafanaOptions = &operatorv1beta2.GrafanaOptions{
+		DashboardConfigMaps: []string{"jfr-dashboards"},
+		DashboardSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"cryostat.io/dashboard": "true",
 			},
-			Annotations: map[string]string{
-				"myPodExtraAnnotation":       "myPodAnnotation",
-				"mySecondPodExtraAnnotation": "mySecondPodAnnotation",
+		},
+		Datasources: []operatorv1beta2.GrafanaDatasource{
+			{
+				Name: "Prometheus",
+				Type: "prometheus",
+				URL:  "http://prometheus.monitoring.svc:9090",
+				JSONData: map[string]string{
+					"httpMethod":      "POST",
+					"httpHeaderName1": "Authorization",
+				},
+				SecureJSONData: map[string]string{
+					"httpHeaderValue1":  "Bearer placeholder",
+					"basicAuthPassword": "hunter2",
+				},
+				SecureJSONDataFrom: map[string]corev1.SecretKeySelector{
+					"httpHeaderValue1": {
+						LocalObjectReference: corev1.LocalObjectReference{Name: "prometheus-token"},
+						Key:                  "token",
+					},
+				},
 			},
 		},
+		Plugins: []string{"grafana-clock-panel"},
+		Env: []corev1.EnvVar{
+			{Name: "GF_LOG_LEVEL", Value: "debug"},
+			// Set by the operator, so this should be ignored
+			{Name: "GF_SERVER_DOMAIN", Value: "grafana.example.com"},
+			// Not a Grafana setting, so this should be ignored
+			{Name: "EXTRA_VAR", Value: "ignored"},
+		},
 	}
 	return cr
 }
 
-func (r *TestResources) NewCryostatWithAgentHostnameVerifyDisabled() *model.CryostatInstance {
+func (r *TestResources) NewGrafanaDatasourceSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "prometheus-token",
+			Namespace: r.Namespace,
+		},
+		Data: map[string][]byte{
+			"token": []byte("Bearer secret-token"),
+		},
+	}
+}
+
+func (r *TestResources) NewCryostatWithGrafanaDisabled() *model.CryostatInstance {
 	cr := r.NewCryostat()
-	cr.Spec.AgentOptions = &operatorv1beta2.AgentOptions{
-		DisableHostnameVerification: true,
+	cr.Spec.GrafanaOptions = &operatorv1beta2.GrafanaOptions{
+		Disabled: true,
 	}
 	return cr
 }
 
-func (r *TestResources) NewCryostatWithAgentInsecureAllowed() *model.CryostatInstance {
+func (r *TestResources) NewCryostatWithSeparateGrafana() *model.CryostatInstance {
 	cr := r.NewCryostat()
-	cr.Spec.AgentOptions = &operatorv1beta2.AgentOptions{
-		AllowInsecure: true,
+	cr.Spec.GrafanaOptions = &operatorv1beta2.GrafanaOptions{
+		SeparateDeployment: true,
+		SchedulingOptions: &operatorv1beta2.SchedulingConfiguration{
+			NodeSelector: map[string]string{"node": "grafana"},
+		},
 	}
 	return cr
 }
 
-func (r *TestResources) NewCryostatService() *corev1.Service {
-	appProtocol := "http"
-	if r.TLS {
-		appProtocol = "https"
-	}
-	return &corev1.Service{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      r.Name,
-			Namespace: r.Namespace,
-			Labels: map[string]string{
-				"app":                         r.Name,
-				"component":                   "cryostat",
-				"app.kubernetes.io/name":      "cryostat",
-				"app.kubernetes.io/instance":  r.Name,
-				"app.kubernetes.io/component": "cryostat",
-				"app.kubernetes.io/part-of":   "cryostat",
+func (r *TestResources) NewCryostatWithProbeOptions() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	cr.Spec.ProbeOptions = &operatorv1beta2.ProbeOptions{
+		Core: &operatorv1beta2.PodProbeConfiguration{
+			ContainerProbeConfiguration: operatorv1beta2.ContainerProbeConfiguration{
+				LivenessProbe: &operatorv1beta2.ProbeSettings{
+					PeriodSeconds:    &[]int32{20}[0],
+					TimeoutSeconds:   &[]int32{5}[0],
+					FailureThreshold: &[]int32{6}[0],
+				},
+				StartupProbe: &operatorv1beta2.StartupProbeSettings{
+					ProbeSettings: operatorv1beta2.ProbeSettings{
+						InitialDelaySeconds: &[]int32{30}[0],
+						FailureThreshold:    &[]int32{60}[0],
+					},
+				},
 			},
+			TerminationGracePeriodSeconds: &[]int64{90}[0],
 		},
-		Spec: corev1.ServiceSpec{
-			Type: corev1.ServiceTypeClusterIP,
-			Selector: map[string]string{
-				"app":       r.Name,
-				"component": "cryostat",
+		Database: &operatorv1beta2.PodProbeConfiguration{
+			ContainerProbeConfiguration: operatorv1beta2.ContainerProbeConfiguration{
+				LivenessProbe: &operatorv1beta2.ProbeSettings{
+					InitialDelaySeconds: &[]int32{10}[0],
+				},
 			},
-			Ports: []corev1.ServicePort{
-				{
-					Name:        "http",
-					Port:        4180,
-					TargetPort:  intstr.FromInt(4180),
-					AppProtocol: &appProtocol,
+			TerminationGracePeriodSeconds: &[]int64{120}[0],
+		},
+		Storage: &operatorv1beta2.PodProbeConfiguration{
+			ContainerProbeConfiguration: operatorv1beta2.ContainerProbeConfiguration{
+				StartupProbe: &operatorv1beta2.StartupProbeSettings{
+					Disabled: true,
+				},
+			},
+		},
+		Datasource: &operatorv1beta2.ContainerProbeConfiguration{
+			StartupProbe: &operatorv1beta2.StartupProbeSettings{
+				ProbeSettings: operatorv1beta2.ProbeSettings{
+					PeriodSeconds:    &[]int32{5}[0],
+					FailureThreshold: &[]int32{24}[0],
 				},
 			},
 		},
 	}
+	return cr
 }
 
-func (r *TestResources) NewCryostatNetworkPolicy() *netv1.NetworkPolicy {
-	return &netv1.NetworkPolicy{
+func (r *TestResources) NewGrafanaDashboardConfigMap(name string, labels map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-internal-ingress", r.Name),
+			Name:      name,
 			Namespace: r.Namespace,
+			Labels:    labels,
 		},
-		Spec: netv1.NetworkPolicySpec{
-			PodSelector: metav1.LabelSelector{
-				MatchLabels: map[string]string{
-					"app":       r.Name,
-					"component": "cryostat",
-					"kind":      "cryostat",
-				},
-			},
-			Ingress: []netv1.NetworkPolicyIngressRule{
-				{
-					From: []netv1.NetworkPolicyPeer{
-						{
-							NamespaceSelector: &metav1.LabelSelector{},
+		Data: map[string]string{
+			"recordings.json": `{"title": "Recordings", "panels": []}`,
+		},
+	}
+}
+
+func (r *TestResources) NewCryostatWithPodTemplateOverrides() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	cr.Spec.Overrides = &operatorv1beta2.PodTemplateOverrides{
+		Core: &corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name: r.Name,
+						Env: []corev1.EnvVar{
+							{Name: "QUARKUS_LOG_LEVEL", Value: "DEBUG"},
 						},
-						{
-							NamespaceSelector: &metav1.LabelSelector{
-								MatchLabels: map[string]string{
-									"policy-group.network.openshift.io/ingress": "",
-								},
-							},
+					},
+					{
+						Name:  "log-shipper",
+						Image: "registry.example.com/log-shipper:latest",
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "log-shipper-config", MountPath: "/etc/log-shipper"},
 						},
 					},
-					Ports: []netv1.NetworkPolicyPort{
-						{
-							Port: &intstr.IntOrString{IntVal: 4180},
+				},
+				Volumes: []corev1.Volume{
+					{
+						Name: "log-shipper-config",
+						VolumeSource: corev1.VolumeSource{
+							EmptyDir: &corev1.EmptyDirVolumeSource{},
 						},
 					},
 				},
-				{
-					From: []netv1.NetworkPolicyPeer{
-						{
-							NamespaceSelector: &metav1.LabelSelector{
-								MatchExpressions: []metav1.LabelSelectorRequirement{
-									{
-										Key:      "kubernetes.io/metadata.name",
+			},
+		},
+		Storage: &corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"backup.example.com/exclude": "true",
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name: r.Name + "-storage",
+						Env: []corev1.EnvVar{
+							{Name: "CRYOSTAT_STORAGE_DEBUG", Value: "true"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatWithConflictingPodTemplateOverrides() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	cr.Spec.Overrides = &operatorv1beta2.PodTemplateOverrides{
+		Core: &corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name:  r.Name,
+						Image: "mirror.example.com/cryostat/cryostat:custom",
+					},
+				},
+			},
+		},
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatWithInvalidPodTemplateOverrides() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	cr.Spec.Overrides = &operatorv1beta2.PodTemplateOverrides{
+		Database: &corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name: r.Name + "-db",
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "missing-volume", MountPath: "/var/lib/extra"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatWithAdditionalMetadata() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	cr.Spec.OperandMetadata = &operatorv1beta2.OperandMetadata{
+		DeploymentMetadata: &operatorv1beta2.ResourceMetadata{
+			Labels: map[string]string{
+				"myDeploymentExtraLabel":       "myDeploymentLabel",
+				"mySecondDeploymentExtraLabel": "mySecondDeploymentLabel",
+				// below, labels that should be discarded as overriden by the default
+				"app":                    "myApp",
+				"component":              "myComponent",
+				"kind":                   "myKind",
+				"app.kubernetes.io/name": "myName",
+			},
+			Annotations: map[string]string{
+				"myDeploymentExtraAnnotation":       "myDeploymentAnnotation",
+				"mySecondDeploymentExtraAnnotation": "mySecondDeploymentAnnotation",
+				// below, annotation that should be discarded as overriden by the default
+				"app.openshift.io/connects-to": "connectToMe",
+			},
+		},
+		PodMetadata: &operatorv1beta2.ResourceMetadata{
+			Labels: map[string]string{
+				"myPodExtraLabel":       "myPodLabel",
+				"myPodSecondExtraLabel": "myPodSecondLabel",
+				// below, labels that should be discarded as overriden by the default
+				"app":       "myApp",
+				"component": "myComponent",
+				"kind":      "myKind",
+			},
+			Annotations: map[string]string{
+				"myPodExtraAnnotation":       "myPodAnnotation",
+				"mySecondPodExtraAnnotation": "mySecondPodAnnotation",
+			},
+		},
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatWithComponentMetadata() *model.CryostatInstance {
+	cr := r.NewCryostatWithAdditionalMetadata()
+	cr.Spec.OperandMetadata.Database = &operatorv1beta2.ComponentMetadata{
+		DeploymentMetadata: &operatorv1beta2.ResourceMetadata{
+			Labels: map[string]string{
+				"myDeploymentExtraLabel": "myDatabaseDeploymentLabel",
+				"myDatabaseLabel":        "database",
+				// below, labels that should be discarded as overriden by the default
+				"component": "myComponent",
+			},
+		},
+		PodMetadata: &operatorv1beta2.ResourceMetadata{
+			Annotations: map[string]string{
+				"myPodExtraAnnotation": "myDatabasePodAnnotation",
+			},
+		},
+	}
+	cr.Spec.OperandMetadata.Storage = &operatorv1beta2.ComponentMetadata{
+		PodMetadata: &operatorv1beta2.ResourceMetadata{
+			Annotations: map[string]string{
+				"sidecar.istio.io/inject": "false",
+			},
+		},
+	}
+	cr.Spec.OperandMetadata.ResourceMetadata = &operatorv1beta2.ResourceMetadata{
+		Labels: map[string]string{
+			"cost-center": "observability",
+			// below, labels that should be discarded as overriden by the default
+			"app": "myApp",
+		},
+		Annotations: map[string]string{
+			"myResourceAnnotation": "myResource",
+		},
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatWithAgentHostnameVerifyDisabled() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	cr.Spec.AgentOptions = &operatorv1beta2.AgentOptions{
+		DisableHostnameVerification: true,
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatWithAgentInsecureAllowed() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	cr.Spec.AgentOptions = &operatorv1beta2.AgentOptions{
+		AllowInsecure: true,
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatService() *corev1.Service {
+	appProtocol := "http"
+	if r.TLS {
+		appProtocol = "https"
+	}
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.Name,
+			Namespace: r.Namespace,
+			Labels: map[string]string{
+				"app":                         r.Name,
+				"component":                   "cryostat",
+				"app.kubernetes.io/name":      "cryostat",
+				"app.kubernetes.io/instance":  r.Name,
+				"app.kubernetes.io/component": "cryostat",
+				"app.kubernetes.io/part-of":   "cryostat",
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeClusterIP,
+			Selector: map[string]string{
+				"app":       r.Name,
+				"component": "cryostat",
+			},
+			Ports: []corev1.ServicePort{
+				{
+					Name:        "http",
+					Port:        4180,
+					TargetPort:  intstr.FromInt(4180),
+					AppProtocol: &appProtocol,
+				},
+			},
+		},
+	}
+}
+
+func (r *TestResources) NewCryostatNetworkPolicy() *netv1.NetworkPolicy {
+	return &netv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-internal-ingress", r.Name),
+			Namespace: r.Namespace,
+		},
+		Spec: netv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app":       r.Name,
+					"component": "cryostat",
+					"kind":      "cryostat",
+				},
+			},
+			Ingress: []netv1.NetworkPolicyIngressRule{
+				{
+					From: []netv1.NetworkPolicyPeer{
+						{
+							NamespaceSelector: &metav1.LabelSelector{},
+						},
+						{
+							NamespaceSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									"policy-group.network.openshift.io/ingress": "",
+								},
+							},
+						},
+					},
+					Ports: []netv1.NetworkPolicyPort{
+						{
+							Port: &intstr.IntOrString{IntVal: 4180},
+						},
+					},
+				},
+				{
+					From: []netv1.NetworkPolicyPeer{
+						{
+							NamespaceSelector: &metav1.LabelSelector{
+								MatchExpressions: []metav1.LabelSelectorRequirement{
+									{
+										Key:      "kubernetes.io/metadata.name",
 										Operator: "In",
 										Values:   r.TargetNamespaces,
 									},
@@ -1107,6 +1627,22 @@ func (r *TestResources) NewCryostatNetworkPolicy() *netv1.NetworkPolicy {
 	}
 }
 
+func (r *TestResources) NewCryostatNetworkPolicyWithAgentGateway(from ...netv1.NetworkPolicyPeer) *netv1.NetworkPolicy {
+	policy := r.NewCryostatNetworkPolicy()
+	rule := netv1.NetworkPolicyIngressRule{
+		Ports: []netv1.NetworkPolicyPort{
+			{
+				Port: &intstr.IntOrString{IntVal: 8282},
+			},
+		},
+	}
+	if len(from) > 0 {
+		rule.From = from
+	}
+	policy.Spec.Ingress = append(policy.Spec.Ingress, rule)
+	return policy
+}
+
 func (r *TestResources) NewDatabaseNetworkPolicy() *netv1.NetworkPolicy {
 	return &netv1.NetworkPolicy{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1138,6 +1674,20 @@ func (r *TestResources) NewDatabaseNetworkPolicy() *netv1.NetworkPolicy {
 								},
 							},
 						},
+						{
+							NamespaceSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									"kubernetes.io/metadata.name": r.Namespace,
+								},
+							},
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									"app":       r.Name,
+									"component": "backup",
+									"kind":      "cryostat",
+								},
+							},
+						},
 					},
 					Ports: []netv1.NetworkPolicyPort{
 						{
@@ -1181,6 +1731,20 @@ func (r *TestResources) NewStorageNetworkPolicy() *netv1.NetworkPolicy {
 								},
 							},
 						},
+						{
+							NamespaceSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									"kubernetes.io/metadata.name": r.Namespace,
+								},
+							},
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									"app":       r.Name,
+									"component": "backup",
+									"kind":      "cryostat",
+								},
+							},
+						},
 					},
 					Ports: []netv1.NetworkPolicyPort{
 						{
@@ -1237,30 +1801,24 @@ func (r *TestResources) NewReportsNetworkPolicy() *netv1.NetworkPolicy {
 }
 
 func (r *TestResources) NewGrafanaService() *corev1.Service {
-	c := true
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      r.Name + "-grafana",
 			Namespace: r.Namespace,
-			OwnerReferences: []metav1.OwnerReference{
-				{
-					APIVersion: operatorv1beta2.GroupVersion.String(),
-					Kind:       "Cryostat",
-					Name:       r.Name,
-					UID:        "",
-					Controller: &c,
-				},
-			},
 			Labels: map[string]string{
-				"app":       r.Name,
-				"component": "cryostat",
+				"app":                         r.Name,
+				"component":                   "grafana",
+				"app.kubernetes.io/name":      "cryostat",
+				"app.kubernetes.io/instance":  r.Name,
+				"app.kubernetes.io/component": "grafana",
+				"app.kubernetes.io/part-of":   "cryostat",
 			},
 		},
 		Spec: corev1.ServiceSpec{
 			Type: corev1.ServiceTypeClusterIP,
 			Selector: map[string]string{
 				"app":       r.Name,
-				"component": "cryostat",
+				"component": "grafana",
 			},
 			Ports: []corev1.ServicePort{
 				{
@@ -1268,6 +1826,57 @@ func (r *TestResources) NewGrafanaService() *corev1.Service {
 					Port:       3000,
 					TargetPort: intstr.FromInt(3000),
 				},
+				{
+					Name:       "datasource",
+					Port:       8989,
+					TargetPort: intstr.FromInt(8989),
+				},
+			},
+		},
+	}
+}
+
+func (r *TestResources) NewGrafanaNetworkPolicy() *netv1.NetworkPolicy {
+	return &netv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-grafana-internal-ingress", r.Name),
+			Namespace: r.Namespace,
+		},
+		Spec: netv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app":       r.Name,
+					"component": "grafana",
+					"kind":      "cryostat",
+				},
+			},
+			Ingress: []netv1.NetworkPolicyIngressRule{
+				{
+					From: []netv1.NetworkPolicyPeer{
+						{
+							NamespaceSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									"kubernetes.io/metadata.name": r.Namespace,
+								},
+							},
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									"app":       r.Name,
+									"component": "cryostat",
+									"kind":      "cryostat",
+								},
+							},
+						},
+					},
+					Ports: []netv1.NetworkPolicyPort{
+						{
+							Port: &intstr.IntOrString{IntVal: 3000},
+						},
+						{
+							Port: &intstr.IntOrString{IntVal: 8989},
+						},
+					},
+				},
 			},
 		},
 	}
@@ -1371,30 +1980,61 @@ func (r *TestResources) NewStorageService() *corev1.Service {
 			Type: corev1.ServiceTypeClusterIP,
 			Selector: map[string]string{
 				"app":       r.Name,
-				"component": "storage",
+				"component": "storage",
+			},
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "http",
+					Port:       8333,
+					TargetPort: intstr.FromInt(8333),
+				},
+			},
+		},
+	}
+}
+
+func (r *TestResources) NewReportsService() *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.Name + "-reports",
+			Namespace: r.Namespace,
+			Labels: map[string]string{
+				"app":                         r.Name,
+				"component":                   "reports",
+				"app.kubernetes.io/name":      "cryostat",
+				"app.kubernetes.io/instance":  r.Name,
+				"app.kubernetes.io/component": "reports",
+				"app.kubernetes.io/part-of":   "cryostat",
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeClusterIP,
+			Selector: map[string]string{
+				"app":       r.Name,
+				"component": "reports",
 			},
 			Ports: []corev1.ServicePort{
 				{
 					Name:       "http",
-					Port:       8333,
-					TargetPort: intstr.FromInt(8333),
+					Port:       10000,
+					TargetPort: intstr.FromInt(10000),
 				},
 			},
 		},
 	}
 }
 
-func (r *TestResources) NewReportsService() *corev1.Service {
+func (r *TestResources) NewAgentGatewayService() *corev1.Service {
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      r.Name + "-reports",
+			Name:      r.Name + "-agent",
 			Namespace: r.Namespace,
 			Labels: map[string]string{
 				"app":                         r.Name,
-				"component":                   "reports",
+				"component":                   "cryostat-agent-gateway",
 				"app.kubernetes.io/name":      "cryostat",
 				"app.kubernetes.io/instance":  r.Name,
-				"app.kubernetes.io/component": "reports",
+				"app.kubernetes.io/component": "cryostat-agent-gateway",
 				"app.kubernetes.io/part-of":   "cryostat",
 			},
 		},
@@ -1402,25 +2042,27 @@ func (r *TestResources) NewReportsService() *corev1.Service {
 			Type: corev1.ServiceTypeClusterIP,
 			Selector: map[string]string{
 				"app":       r.Name,
-				"component": "reports",
+				"component": "cryostat",
 			},
 			Ports: []corev1.ServicePort{
 				{
 					Name:       "http",
-					Port:       10000,
-					TargetPort: intstr.FromInt(10000),
+					Port:       8282,
+					TargetPort: intstr.FromInt(8282),
 				},
 			},
 		},
 	}
 }
 
-func (r *TestResources) NewAgentGatewayService() *corev1.Service {
+func (r *TestResources) NewAgentGatewayLoadBalancerService() *corev1.Service {
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      r.Name + "-agent",
-			Namespace: r.Namespace,
+			Name:        r.Name + "-agent-external",
+			Namespace:   r.Namespace,
+			Annotations: map[string]string{"lb": "annotation"},
 			Labels: map[string]string{
+				"lb":                          "label",
 				"app":                         r.Name,
 				"component":                   "cryostat-agent-gateway",
 				"app.kubernetes.io/name":      "cryostat",
@@ -1430,7 +2072,7 @@ func (r *TestResources) NewAgentGatewayService() *corev1.Service {
 			},
 		},
 		Spec: corev1.ServiceSpec{
-			Type: corev1.ServiceTypeClusterIP,
+			Type: corev1.ServiceTypeLoadBalancer,
 			Selector: map[string]string{
 				"app":       r.Name,
 				"component": "cryostat",
@@ -1438,7 +2080,7 @@ func (r *TestResources) NewAgentGatewayService() *corev1.Service {
 			Ports: []corev1.ServicePort{
 				{
 					Name:       "http",
-					Port:       8282,
+					Port:       9443,
 					TargetPort: intstr.FromInt(8282),
 				},
 			},
@@ -1605,6 +2247,7 @@ func (r *TestResources) NewAgentCertSecretCopy(ns string) *corev1.Secret {
 		"operator.cryostat.io/namespace": r.Namespace,
 	}
 	secret.Namespace = ns
+	secret.Data["ca.crt"] = []byte(r.Name + "-ca-bytes")
 	return secret
 }
 
@@ -1660,6 +2303,18 @@ func (r *TestResources) OtherDatabaseSecret() *corev1.Secret {
 	}
 }
 
+func (r *TestResources) NewCABundleSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.Name + "-ca-bundle",
+			Namespace: r.Namespace,
+		},
+		Data: map[string][]byte{
+			"ca.crt": []byte(r.Name + "-ca-bytes"),
+		},
+	}
+}
+
 func (r *TestResources) NewKeystoreSecret() *corev1.Secret {
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1754,6 +2409,33 @@ func (r *TestResources) NewReportsCert() *certv1.Certificate {
 	}
 }
 
+func (r *TestResources) NewGrafanaCert() *certv1.Certificate {
+	return &certv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.Name + "-grafana",
+			Namespace: r.Namespace,
+		},
+		Spec: certv1.CertificateSpec{
+			CommonName: "cryostat-grafana",
+			DNSNames: []string{
+				r.Name + "-grafana",
+				fmt.Sprintf(r.Name+"-grafana.%s.svc", r.Namespace),
+				fmt.Sprintf(r.Name+"-grafana.%s.svc.cluster.local", r.Namespace),
+				"localhost",
+			},
+			SecretName: r.Name + "-grafana-tls",
+			IssuerRef: certMeta.ObjectReference{
+				Name: r.Name + "-ca",
+			},
+			Usages: []certv1.KeyUsage{
+				certv1.UsageDigitalSignature,
+				certv1.UsageKeyEncipherment,
+				certv1.UsageServerAuth,
+			},
+		},
+	}
+}
+
 func (r *TestResources) NewDatabaseCert() *certv1.Certificate {
 	return &certv1.Certificate{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1812,6 +2494,38 @@ func (r *TestResources) NewAgentProxyCert() *certv1.Certificate {
 	}
 }
 
+func (r *TestResources) NewAgentProxyCertWithExternalHosts(dnsNames []string, ipAddresses []string) *certv1.Certificate {
+	cert := r.NewAgentProxyCert()
+	cert.Spec.DNSNames = append(cert.Spec.DNSNames, dnsNames...)
+	cert.Spec.IPAddresses = ipAddresses
+	return cert
+}
+
+func (r *TestResources) NewAgentClientCert(clientName string) *certv1.Certificate {
+	return &certv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.Name + "-agent-client-" + clientName,
+			Namespace: r.Namespace,
+			Labels: map[string]string{
+				"app":       r.Name,
+				"component": "cryostat-agent-client",
+			},
+		},
+		Spec: certv1.CertificateSpec{
+			CommonName: clientName,
+			SecretName: r.Name + "-agent-client-" + clientName,
+			IssuerRef: certMeta.ObjectReference{
+				Name: r.Name + "-ca",
+			},
+			Usages: []certv1.KeyUsage{
+				certv1.UsageDigitalSignature,
+				certv1.UsageKeyEncipherment,
+				certv1.UsageClientAuth,
+			},
+		},
+	}
+}
+
 func (r *TestResources) NewStorageCert() *certv1.Certificate {
 	return &certv1.Certificate{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1862,6 +2576,14 @@ func (r *TestResources) NewCACert() *certv1.Certificate {
 	}
 }
 
+func (r *TestResources) NewCACertForRevision(revision string) *certv1.Certificate {
+	cert := r.NewCACert()
+	cert.Name += "-" + revision
+	cert.Spec.CommonName += "-" + revision
+	cert.Spec.SecretName += "-" + revision
+	return cert
+}
+
 func (r *TestResources) OtherCACert() *certv1.Certificate {
 	cert := r.NewCACert()
 	cert.Spec.CommonName = fmt.Sprintf("ca.%s.cert-manager", r.Name)
@@ -1895,6 +2617,45 @@ func (r *TestResources) NewAgentCert(namespace string) *certv1.Certificate {
 	}
 }
 
+func (r *TestResources) NewCertificateStatuses() []operatorv1beta2.CertificateStatus {
+	caCopies := []string{}
+	for _, ns := range r.TargetNamespaces {
+		if ns != r.Namespace {
+			caCopies = append(caCopies, ns)
+		}
+	}
+	statuses := []operatorv1beta2.CertificateStatus{
+		r.newCertificateStatus(r.NewCACert(), caCopies...),
+		r.newCertificateStatus(r.NewCryostatCert()),
+		r.newCertificateStatus(r.NewReportsCert()),
+		r.newCertificateStatus(r.NewDatabaseCert()),
+		r.newCertificateStatus(r.NewStorageCert()),
+		r.newCertificateStatus(r.NewAgentProxyCert()),
+	}
+	for _, ns := range r.TargetNamespaces {
+		if ns != r.Namespace {
+			statuses = append(statuses, r.newCertificateStatus(r.NewAgentCert(ns), ns))
+		} else {
+			statuses = append(statuses, r.newCertificateStatus(r.NewAgentCert(ns)))
+		}
+	}
+	return statuses
+}
+
+func (r *TestResources) newCertificateStatus(cert *certv1.Certificate, copyNamespaces ...string) operatorv1beta2.CertificateStatus {
+	status := operatorv1beta2.CertificateStatus{
+		Name:        cert.Name,
+		Namespace:   cert.Namespace,
+		SecretName:  cert.Spec.SecretName,
+		NotAfter:    &metav1.Time{Time: CertNotAfter},
+		RenewalTime: &metav1.Time{Time: CertRenewalTime},
+	}
+	if len(copyNamespaces) > 0 {
+		status.CopyNamespaces = copyNamespaces
+	}
+	return status
+}
+
 func (r *TestResources) NewCertSecret(cert *certv1.Certificate) *corev1.Secret {
 	// The secret's data isn't important, we simply need it to exist
 	return &corev1.Secret{
@@ -1939,6 +2700,12 @@ func (r *TestResources) NewCryostatCAIssuer() *certv1.Issuer {
 	}
 }
 
+func (r *TestResources) NewCryostatCAIssuerForRevision(revision string) *certv1.Issuer {
+	issuer := r.NewCryostatCAIssuer()
+	issuer.Spec.CA.SecretName += "-" + revision
+	return issuer
+}
+
 func (r *TestResources) OtherCAIssuer() *certv1.Issuer {
 	return &certv1.Issuer{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1956,10 +2723,11 @@ func (r *TestResources) OtherCAIssuer() *certv1.Issuer {
 }
 
 func (r *TestResources) newPVC(spec *corev1.PersistentVolumeClaimSpec, labels map[string]string,
-	annotations map[string]string, name string) *corev1.PersistentVolumeClaim {
+	annotations map[string]string, component string) *corev1.PersistentVolumeClaim {
+	labels["component"] = component
 	return &corev1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        name,
+			Name:        r.Name + "-" + component,
 			Namespace:   r.Namespace,
 			Annotations: annotations,
 			Labels:      labels,
@@ -1978,7 +2746,7 @@ func (r *TestResources) NewDefaultPVC() *corev1.PersistentVolumeClaim {
 		},
 	}, map[string]string{
 		"app": r.Name,
-	}, nil, r.Name+"-database")
+	}, nil, "database")
 }
 
 func (r *TestResources) NewDatabasePVC() *corev1.PersistentVolumeClaim {
@@ -1991,7 +2759,7 @@ func (r *TestResources) NewDatabasePVC() *corev1.PersistentVolumeClaim {
 		},
 	}, map[string]string{
 		"app": r.Name,
-	}, nil, r.Name+"-database")
+	}, nil, "database")
 }
 
 func (r *TestResources) NewStoragePVC() *corev1.PersistentVolumeClaim {
@@ -2004,7 +2772,7 @@ func (r *TestResources) NewStoragePVC() *corev1.PersistentVolumeClaim {
 		},
 	}, map[string]string{
 		"app": r.Name,
-	}, nil, r.Name+"-storage")
+	}, nil, "storage")
 }
 
 func (r *TestResources) NewCustomStoragePVC() *corev1.PersistentVolumeClaim {
@@ -2022,7 +2790,7 @@ func (r *TestResources) NewCustomStoragePVC() *corev1.PersistentVolumeClaim {
 		"app": r.Name,
 	}, map[string]string{
 		"my/custom": "storage",
-	}, r.Name+"-storage")
+	}, "storage")
 }
 
 func (r *TestResources) NewCustomStoragePVCLegacy() *corev1.PersistentVolumeClaim {
@@ -2040,7 +2808,7 @@ func (r *TestResources) NewCustomStoragePVCLegacy() *corev1.PersistentVolumeClai
 		"app": r.Name,
 	}, map[string]string{
 		"my/custom": "annotation",
-	}, r.Name+"-storage")
+	}, "storage")
 }
 
 func (r *TestResources) NewCustomStoragePVCSomeDefault() *corev1.PersistentVolumeClaim {
@@ -2055,7 +2823,7 @@ func (r *TestResources) NewCustomStoragePVCSomeDefault() *corev1.PersistentVolum
 		},
 	}, map[string]string{
 		"app": r.Name,
-	}, nil, r.Name+"-storage")
+	}, nil, "storage")
 }
 
 func (r *TestResources) NewCustomStoragePVCSomeDefaultLegacy() *corev1.PersistentVolumeClaim {
@@ -2070,7 +2838,7 @@ func (r *TestResources) NewCustomStoragePVCSomeDefaultLegacy() *corev1.Persisten
 		},
 	}, map[string]string{
 		"app": r.Name,
-	}, nil, r.Name+"-storage")
+	}, nil, "storage")
 }
 
 func (r *TestResources) NewDefaultStoragePVCWithLabel() *corev1.PersistentVolumeClaim {
@@ -2084,7 +2852,7 @@ func (r *TestResources) NewDefaultStoragePVCWithLabel() *corev1.PersistentVolume
 	}, map[string]string{
 		"app": r.Name,
 		"my":  "storage",
-	}, nil, r.Name+"-storage")
+	}, nil, "storage")
 }
 
 func (r *TestResources) NewDefaultStoragePVCWithLabelLegacy() *corev1.PersistentVolumeClaim {
@@ -2098,7 +2866,7 @@ func (r *TestResources) NewDefaultStoragePVCWithLabelLegacy() *corev1.Persistent
 	}, map[string]string{
 		"app": r.Name,
 		"my":  "label",
-	}, nil, r.Name+"-storage")
+	}, nil, "storage")
 }
 
 func (r *TestResources) NewCustomDatabasePVC() *corev1.PersistentVolumeClaim {
@@ -2116,7 +2884,7 @@ func (r *TestResources) NewCustomDatabasePVC() *corev1.PersistentVolumeClaim {
 		"app": r.Name,
 	}, map[string]string{
 		"my/custom": "database",
-	}, r.Name+"-database")
+	}, "database")
 }
 
 func (r *TestResources) NewCustomDatabasePVCLegacy() *corev1.PersistentVolumeClaim {
@@ -2134,7 +2902,7 @@ func (r *TestResources) NewCustomDatabasePVCLegacy() *corev1.PersistentVolumeCla
 		"app": r.Name,
 	}, map[string]string{
 		"my/custom": "annotation",
-	}, r.Name+"-database")
+	}, "database")
 }
 
 func (r *TestResources) NewCustomDatabasePVCSomeDefault() *corev1.PersistentVolumeClaim {
@@ -2149,7 +2917,7 @@ func (r *TestResources) NewCustomDatabasePVCSomeDefault() *corev1.PersistentVolu
 		},
 	}, map[string]string{
 		"app": r.Name,
-	}, nil, r.Name+"-database")
+	}, nil, "database")
 }
 
 func (r *TestResources) NewCustomDatabasePVCSomeDefaultLegacy() *corev1.PersistentVolumeClaim {
@@ -2164,7 +2932,7 @@ func (r *TestResources) NewCustomDatabasePVCSomeDefaultLegacy() *corev1.Persiste
 		},
 	}, map[string]string{
 		"app": r.Name,
-	}, nil, r.Name+"-database")
+	}, nil, "database")
 }
 
 func (r *TestResources) NewDefaultDatabasePVCWithLabel() *corev1.PersistentVolumeClaim {
@@ -2178,7 +2946,7 @@ func (r *TestResources) NewDefaultDatabasePVCWithLabel() *corev1.PersistentVolum
 	}, map[string]string{
 		"app": r.Name,
 		"my":  "database",
-	}, nil, r.Name+"-database")
+	}, nil, "database")
 }
 
 func (r *TestResources) NewDefaultDatabasePVCWithLabelLegacy() *corev1.PersistentVolumeClaim {
@@ -2192,7 +2960,7 @@ func (r *TestResources) NewDefaultDatabasePVCWithLabelLegacy() *corev1.Persisten
 	}, map[string]string{
 		"app": r.Name,
 		"my":  "label",
-	}, nil, r.Name+"-database")
+	}, nil, "database")
 }
 
 func (r *TestResources) NewDefaultEmptyDir() *corev1.EmptyDirVolumeSource {
@@ -2312,6 +3080,7 @@ func (r *TestResources) NewMainPodAnnotations() map[string]string {
 			r.NewCertSecret(r.NewDatabaseCert()),
 			r.NewCertSecret(r.NewStorageCert()),
 			r.NewCertSecret(r.NewAgentProxyCert()),
+			r.NewCABundleSecret(),
 		)
 	}
 
@@ -2527,6 +3296,12 @@ func (r *TestResources) NewCoreEnvironmentVariables(reportsUrl string, ingress b
 		envs = append(envs, corev1.EnvVar{
 			Name:  "QUARKUS_DATASOURCE_JDBC_URL",
 			Value: fmt.Sprintf("jdbc:postgresql://%s-database.%s.svc.cluster.local:5432/cryostat?ssl=true&sslmode=verify-full&sslcert=&sslrootcert=/var/run/secrets/operator.cryostat.io/%s-database-tls/ca.crt", r.Name, r.Namespace, r.Name),
+		}, corev1.EnvVar{
+			Name:  "QUARKUS_TLS_PROTOCOLS",
+			Value: strings.Join(r.tlsProtocols(), ","),
+		}, corev1.EnvVar{
+			Name:  "QUARKUS_TLS_CIPHER_SUITES",
+			Value: strings.Join(r.tlsIANACiphers(), ","),
 		})
 	} else {
 		envs = append(envs, corev1.EnvVar{
@@ -2696,6 +3471,12 @@ func (r *TestResources) NewReportsEnvironmentVariables(resources *corev1.Resourc
 		}, corev1.EnvVar{
 			Name:  "QUARKUS_HTTP_INSECURE_REQUESTS",
 			Value: "disabled",
+		}, corev1.EnvVar{
+			Name:  "QUARKUS_HTTP_SSL_PROTOCOLS",
+			Value: strings.Join(r.tlsProtocols(), ","),
+		}, corev1.EnvVar{
+			Name:  "QUARKUS_HTTP_SSL_CIPHER_SUITES",
+			Value: strings.Join(r.tlsIANACiphers(), ","),
 		})
 	} else {
 		envs = append(envs, corev1.EnvVar{
@@ -2741,6 +3522,18 @@ func (r *TestResources) NewStorageEnvironmentVariables() []corev1.EnvVar {
 			},
 		},
 	}
+	if r.TLS {
+		envs = append(envs, corev1.EnvVar{
+			Name:  "WEED_TLS_MIN_VERSION",
+			Value: r.tlsMinVersion(),
+		})
+		if ciphers := r.tlsGoCiphers(); len(ciphers) > 0 {
+			envs = append(envs, corev1.EnvVar{
+				Name:  "WEED_TLS_CIPHER_SUITES",
+				Value: strings.Join(ciphers, ","),
+			})
+		}
+	}
 	return envs
 }
 
@@ -2817,7 +3610,12 @@ func (r *TestResources) NewDatabaseArgs() []string {
 			fmt.Sprintf("ssl_cert_file=/var/run/secrets/operator.cryostat.io/%s-database-tls/%s", r.Name, corev1.TLSCertKey),
 			"-c",
 			fmt.Sprintf("ssl_key_file=/var/run/secrets/operator.cryostat.io/%s-database-tls/%s", r.Name, corev1.TLSPrivateKeyKey),
+			"-c",
+			"ssl_min_protocol_version="+r.tlsProtocols()[0],
 		)
+		if ciphers := r.tlsOpenSSLCiphers(); len(ciphers) > 0 {
+			args = append(args, "-c", "ssl_ciphers="+strings.Join(ciphers, ":"))
+		}
 	}
 
 	return args
@@ -3032,7 +3830,11 @@ func (r *TestResources) NewAuthProxyArguments(authOptions *operatorv1beta2.Autho
 			"--https-address=0.0.0.0:4180",
 			fmt.Sprintf("--tls-cert=/var/run/secrets/operator.cryostat.io/%s/%s", r.Name+"-tls", corev1.TLSCertKey),
 			fmt.Sprintf("--tls-key=/var/run/secrets/operator.cryostat.io/%s/%s", r.Name+"-tls", corev1.TLSPrivateKeyKey),
+			"--tls-min-version=Version"+strings.ReplaceAll(r.oauth2ProxyMinVersion(), ".", ""),
 		)
+		for _, cipher := range r.tlsGoCiphers() {
+			args = append(args, "--tls-cipher-suite="+cipher)
+		}
 	} else {
 		args = append(args,
 			"--http-address=0.0.0.0:4180",
@@ -3151,6 +3953,10 @@ func (r *TestResources) NewAgentProxyVolumeMounts() []corev1.VolumeMount {
 			Name:      "agent-proxy-tls-secret",
 			MountPath: fmt.Sprintf("/var/run/secrets/operator.cryostat.io/%s-agent-tls", r.Name),
 			ReadOnly:  true,
+		}, corev1.VolumeMount{
+			Name:      "ca-bundle",
+			MountPath: fmt.Sprintf("/var/run/secrets/operator.cryostat.io/%s-ca-bundle", r.Name),
+			ReadOnly:  true,
 		})
 	}
 
@@ -3206,19 +4012,32 @@ func (r *TestResources) NewAuthPropertiesVolumeMount() corev1.VolumeMount {
 	}
 }
 
-func (r *TestResources) NewCoreLivenessProbe() *corev1.Probe {
-	return &corev1.Probe{
+func (r *TestResources) NewCoreLivenessProbe(resources *corev1.ResourceRequirements) *corev1.Probe {
+	probe := &corev1.Probe{
 		ProbeHandler: r.newCoreProbeHandler(),
 	}
+	if factor := probeScaleFactor(resources, "500m"); factor > 1 {
+		probe.TimeoutSeconds = factor
+	}
+	return probe
 }
 
-func (r *TestResources) NewCoreStartupProbe() *corev1.Probe {
+func (r *TestResources) NewCoreStartupProbe(resources *corev1.ResourceRequirements) *corev1.Probe {
 	return &corev1.Probe{
 		ProbeHandler:     r.newCoreProbeHandler(),
-		FailureThreshold: 18,
+		FailureThreshold: 18 * probeScaleFactor(resources, "500m"),
 	}
 }
 
+// probeScaleFactor returns how much the operator extends probe allowances for a container
+// requesting less CPU than its default
+func probeScaleFactor(resources *corev1.ResourceRequirements, defaultCpu string) int32 {
+	baseline := resource.MustParse(defaultCpu)
+	requested := resources.Requests.Cpu().MilliValue()
+	factor := (baseline.MilliValue() + requested - 1) / requested
+	return int32(min(max(factor, 1), 4))
+}
+
 func (r *TestResources) newCoreProbeHandler() corev1.ProbeHandler {
 	return corev1.ProbeHandler{
 		HTTPGet: &corev1.HTTPGetAction{
@@ -3229,8 +4048,8 @@ func (r *TestResources) newCoreProbeHandler() corev1.ProbeHandler {
 	}
 }
 
-func (r *TestResources) NewGrafanaLivenessProbe() *corev1.Probe {
-	return &corev1.Probe{
+func (r *TestResources) NewGrafanaLivenessProbe(resources *corev1.ResourceRequirements) *corev1.Probe {
+	probe := &corev1.Probe{
 		ProbeHandler: corev1.ProbeHandler{
 			HTTPGet: &corev1.HTTPGetAction{
 				Port:   intstr.IntOrString{IntVal: 3000},
@@ -3239,26 +4058,34 @@ func (r *TestResources) NewGrafanaLivenessProbe() *corev1.Probe {
 			},
 		},
 	}
+	if factor := probeScaleFactor(resources, "25m"); factor > 1 {
+		probe.TimeoutSeconds = factor
+	}
+	return probe
 }
 
-func (r *TestResources) NewDatasourceLivenessProbe() *corev1.Probe {
-	return &corev1.Probe{
+func (r *TestResources) NewDatasourceLivenessProbe(resources *corev1.ResourceRequirements) *corev1.Probe {
+	probe := &corev1.Probe{
 		ProbeHandler: corev1.ProbeHandler{
 			Exec: &corev1.ExecAction{
 				Command: []string{"curl", "--fail", "http://127.0.0.1:8989"},
 			},
 		},
 	}
+	if factor := probeScaleFactor(resources, "200m"); factor > 1 {
+		probe.TimeoutSeconds = factor
+	}
+	return probe
 }
 
-func (r *TestResources) NewStorageLivenessProbe() *corev1.Probe {
+func (r *TestResources) NewStorageLivenessProbe(resources *corev1.ResourceRequirements) *corev1.Probe {
 	protocol := corev1.URISchemeHTTP
 	port := int32(8333)
 
 	if r.TLS {
 		protocol = corev1.URISchemeHTTPS
 	}
-	return &corev1.Probe{
+	probe := &corev1.Probe{
 		ProbeHandler: corev1.ProbeHandler{
 			HTTPGet: &corev1.HTTPGetAction{
 				Port:   intstr.IntOrString{IntVal: port},
@@ -3268,19 +4095,27 @@ func (r *TestResources) NewStorageLivenessProbe() *corev1.Probe {
 		},
 		FailureThreshold: 2,
 	}
+	if factor := probeScaleFactor(resources, "50m"); factor > 1 {
+		probe.TimeoutSeconds = factor
+	}
+	return probe
 }
 
-func (r *TestResources) NewDatabaseReadinessProbe() *corev1.Probe {
-	return &corev1.Probe{
+func (r *TestResources) NewDatabaseReadinessProbe(resources *corev1.ResourceRequirements) *corev1.Probe {
+	probe := &corev1.Probe{
 		ProbeHandler: corev1.ProbeHandler{
 			Exec: &corev1.ExecAction{
 				Command: []string{"pg_isready", "-U", "cryostat", "-d", "cryostat"},
 			},
 		},
 	}
+	if factor := probeScaleFactor(resources, "25m"); factor > 1 {
+		probe.TimeoutSeconds = factor
+	}
+	return probe
 }
 
-func (r *TestResources) NewAuthProxyLivenessProbe() *corev1.Probe {
+func (r *TestResources) NewAuthProxyLivenessProbe(resources *corev1.ResourceRequirements) *corev1.Probe {
 	protocol := corev1.URISchemeHTTP
 	if r.TLS {
 		protocol = corev1.URISchemeHTTPS
@@ -3289,7 +4124,7 @@ func (r *TestResources) NewAuthProxyLivenessProbe() *corev1.Probe {
 	if r.OpenShift {
 		path = "/oauth2/healthz"
 	}
-	return &corev1.Probe{
+	probe := &corev1.Probe{
 		ProbeHandler: corev1.ProbeHandler{
 			HTTPGet: &corev1.HTTPGetAction{
 				Port:   intstr.IntOrString{IntVal: 4180},
@@ -3298,10 +4133,14 @@ func (r *TestResources) NewAuthProxyLivenessProbe() *corev1.Probe {
 			},
 		},
 	}
+	if factor := probeScaleFactor(resources, "25m"); factor > 1 {
+		probe.TimeoutSeconds = factor
+	}
+	return probe
 }
 
-func (r *TestResources) NewAgentProxyLivenessProbe() *corev1.Probe {
-	return &corev1.Probe{
+func (r *TestResources) NewAgentProxyLivenessProbe(resources *corev1.ResourceRequirements) *corev1.Probe {
+	probe := &corev1.Probe{
 		ProbeHandler: corev1.ProbeHandler{
 			HTTPGet: &corev1.HTTPGetAction{
 				Port:   intstr.IntOrString{IntVal: 8281},
@@ -3310,14 +4149,18 @@ func (r *TestResources) NewAgentProxyLivenessProbe() *corev1.Probe {
 			},
 		},
 	}
+	if factor := probeScaleFactor(resources, "25m"); factor > 1 {
+		probe.TimeoutSeconds = factor
+	}
+	return probe
 }
 
-func (r *TestResources) NewReportsLivenessProbe() *corev1.Probe {
+func (r *TestResources) NewReportsLivenessProbe(resources *corev1.ResourceRequirements) *corev1.Probe {
 	protocol := corev1.URISchemeHTTPS
 	if !r.TLS {
 		protocol = corev1.URISchemeHTTP
 	}
-	return &corev1.Probe{
+	probe := &corev1.Probe{
 		ProbeHandler: corev1.ProbeHandler{
 			HTTPGet: &corev1.HTTPGetAction{
 				Port:   intstr.IntOrString{IntVal: 10000},
@@ -3326,6 +4169,10 @@ func (r *TestResources) NewReportsLivenessProbe() *corev1.Probe {
 			},
 		},
 	}
+	if factor := probeScaleFactor(resources, "500m"); factor > 1 {
+		probe.TimeoutSeconds = factor
+	}
+	return probe
 }
 
 func (r *TestResources) NewMainDeploymentSelector() *metav1.LabelSelector {
@@ -3535,7 +4382,7 @@ func (r *TestResources) newVolumes(certProjections []corev1.VolumeProjection) []
 		projs = append(projs, corev1.VolumeProjection{
 			Secret: &corev1.SecretProjection{
 				LocalObjectReference: corev1.LocalObjectReference{
-					Name: r.Name + "-tls",
+					Name: r.Name + "-ca-bundle",
 				},
 				Items: []corev1.KeyToPath{
 					{
@@ -3581,6 +4428,15 @@ func (r *TestResources) newVolumes(certProjections []corev1.VolumeProjection) []
 					},
 				},
 			},
+			corev1.Volume{
+				Name: "ca-bundle",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName:  r.Name + "-ca-bundle",
+						DefaultMode: &readOnlymode,
+					},
+				},
+			},
 			corev1.Volume{
 				Name: "database-tls-secret",
 				VolumeSource: corev1.VolumeSource{
@@ -3890,6 +4746,43 @@ func (r *TestResources) newRoute(name string, port int) *routev1.Route {
 	}
 }
 
+func (r *TestResources) NewAgentGatewayRoute() *routev1.Route {
+	route := r.newRoute(r.Name+"-agent", 8282)
+	route.Annotations = map[string]string{"custom": "annotation"}
+	route.Labels = map[string]string{
+		"custom":    "label",
+		"app":       r.Name,
+		"component": "cryostat-agent-gateway",
+	}
+	route.Spec.Host = "agent.example.com"
+	if r.TLS {
+		route.Spec.TLS = &routev1.TLSConfig{
+			Termination:                   routev1.TLSTerminationPassthrough,
+			InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyNone,
+		}
+	}
+	return route
+}
+
+func (r *TestResources) NewAgentGatewayIngress() *netv1.Ingress {
+	return &netv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.Name + "-agent",
+			Namespace: r.Namespace,
+			Annotations: map[string]string{
+				"custom": "annotation",
+				"nginx.ingress.kubernetes.io/ssl-passthrough": "true",
+			},
+			Labels: map[string]string{
+				"custom":    "label",
+				"app":       r.Name,
+				"component": "cryostat-agent-gateway",
+			},
+		},
+		Spec: *r.newAgentGatewayIngressSpec(),
+	}
+}
+
 func (r *TestResources) OtherCoreRoute() *routev1.Route {
 	return &routev1.Route{
 		ObjectMeta: metav1.ObjectMeta{
@@ -4005,6 +4898,160 @@ func (r *TestResources) OtherCoreIngress() *netv1.Ingress {
 	}
 }
 
+func (r *TestResources) NewGateway() *gatewayv1.Gateway {
+	hostname := gatewayv1.Hostname("*.example.com")
+	return &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-gateway",
+			Namespace: "gateway-ns",
+		},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "example",
+			Listeners: []gatewayv1.Listener{
+				{
+					Name:     "http",
+					Protocol: gatewayv1.HTTPProtocolType,
+					Port:     80,
+					Hostname: &hostname,
+				},
+				{
+					Name:     "https",
+					Protocol: gatewayv1.HTTPSProtocolType,
+					Port:     443,
+					Hostname: &hostname,
+				},
+			},
+		},
+	}
+}
+
+func (r *TestResources) NewGatewayWithAddress() *gatewayv1.Gateway {
+	gateway := r.NewGateway()
+	gateway.Spec.Listeners = []gatewayv1.Listener{
+		{
+			Name:     "https",
+			Protocol: gatewayv1.HTTPSProtocolType,
+			Port:     8443,
+		},
+	}
+	gateway.Status.Addresses = []gatewayv1.GatewayStatusAddress{
+		{
+			Value: "192.0.2.10",
+		},
+	}
+	return gateway
+}
+
+func (r *TestResources) NewCoreHTTPRoute() *gatewayv1.HTTPRoute {
+	group := gatewayv1.Group(gatewayv1.GroupName)
+	kind := gatewayv1.Kind("Gateway")
+	gatewayNamespace := gatewayv1.Namespace("gateway-ns")
+	pathType := gatewayv1.PathMatchPathPrefix
+	pathValue := "/"
+	backendGroup := gatewayv1.Group("")
+	backendKind := gatewayv1.Kind("Service")
+	backendPort := gatewayv1.PortNumber(4180)
+	weight := int32(1)
+	return &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        r.Name,
+			Namespace:   r.Namespace,
+			Annotations: map[string]string{"custom": "annotation"},
+			Labels: map[string]string{
+				"custom":    "label",
+				"app":       r.Name,
+				"component": "cryostat",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{
+					{
+						Group:     &group,
+						Kind:      &kind,
+						Name:      "my-gateway",
+						Namespace: &gatewayNamespace,
+					},
+				},
+			},
+			Hostnames: []gatewayv1.Hostname{gatewayv1.Hostname(r.Name + ".example.com")},
+			Rules: []gatewayv1.HTTPRouteRule{
+				{
+					Matches: []gatewayv1.HTTPRouteMatch{
+						{
+							Path: &gatewayv1.HTTPPathMatch{
+								Type:  &pathType,
+								Value: &pathValue,
+							},
+						},
+					},
+					BackendRefs: []gatewayv1.HTTPBackendRef{
+						{
+							BackendRef: gatewayv1.BackendRef{
+								BackendObjectReference: gatewayv1.BackendObjectReference{
+									Group: &backendGroup,
+									Kind:  &backendKind,
+									Name:  gatewayv1.ObjectName(r.Name),
+									Port:  &backendPort,
+								},
+								Weight: &weight,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *TestResources) NewCoreBackendTLSPolicy() *gatewayv1alpha3.BackendTLSPolicy {
+	sectionName := gatewayv1alpha2.SectionName("http")
+	return &gatewayv1alpha3.BackendTLSPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.Name,
+			Namespace: r.Namespace,
+			Labels: map[string]string{
+				"app":       r.Name,
+				"component": "cryostat",
+			},
+		},
+		Spec: gatewayv1alpha3.BackendTLSPolicySpec{
+			TargetRefs: []gatewayv1alpha2.LocalPolicyTargetReferenceWithSectionName{
+				{
+					LocalPolicyTargetReference: gatewayv1alpha2.LocalPolicyTargetReference{
+						Group: "",
+						Kind:  "Service",
+						Name:  gatewayv1.ObjectName(r.Name),
+					},
+					SectionName: &sectionName,
+				},
+			},
+			Validation: gatewayv1alpha3.BackendTLSPolicyValidation{
+				CACertificateRefs: []gatewayv1.LocalObjectReference{
+					{
+						Group: "",
+						Kind:  "ConfigMap",
+						Name:  gatewayv1.ObjectName(r.Name + "-gateway-ca"),
+					},
+				},
+				Hostname: gatewayv1.PreciseHostname(fmt.Sprintf("%s.%s.svc", r.Name, r.Namespace)),
+			},
+		},
+	}
+}
+
+func (r *TestResources) NewGatewayCAConfigMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.Name + "-gateway-ca",
+			Namespace: r.Namespace,
+		},
+		Data: map[string]string{
+			"ca.crt": r.Name + "-ca-bytes",
+		},
+	}
+}
+
 func (r *TestResources) newNetworkConfigurationList() operatorv1beta2.NetworkConfigurationList {
 	coreSVC := r.NewCryostatService()
 	coreIng := r.newNetworkConfiguration(coreSVC.Name, coreSVC.Spec.Ports[0].Port)
@@ -4239,22 +5286,51 @@ func (r *TestResources) clusterUniqueShortSuffix() string {
 	return fmt.Sprintf("%x", hash.Sum([]byte{}))
 }
 
-func (r *TestResources) NewClusterRoleBinding() *rbacv1.ClusterRoleBinding {
-	return &rbacv1.ClusterRoleBinding{
+func (r *TestResources) NewClusterRoleBinding() *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: r.getClusterUniqueName(),
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      r.Name,
+				Namespace: r.Namespace,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     "cryostat-operator-cryostat",
+		},
+	}
+}
+
+const (
+	OperatorNamespace      = "cryostat-operator-system"
+	OperatorServiceAccount = "cryostat-operator-service-account"
+)
+
+func (r *TestResources) NewAPIClientRoleBinding() *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: r.getClusterUniqueName(),
+			Name:      r.Name + "-operator-api-client",
+			Namespace: r.Namespace,
+			Labels: map[string]string{
+				"app": r.Name,
+			},
 		},
 		Subjects: []rbacv1.Subject{
 			{
 				Kind:      rbacv1.ServiceAccountKind,
-				Name:      r.Name,
-				Namespace: r.Namespace,
+				Name:      OperatorServiceAccount,
+				Namespace: OperatorNamespace,
 			},
 		},
 		RoleRef: rbacv1.RoleRef{
 			APIGroup: "rbac.authorization.k8s.io",
 			Kind:     "ClusterRole",
-			Name:     "cryostat-operator-cryostat",
+			Name:     "cryostat-operator-api-client",
 		},
 	}
 }
@@ -4383,6 +5459,15 @@ func (r *TestResources) NewApiServer() *configv1.APIServer {
 	}
 }
 
+func (r *TestResources) NewApiServerWithTLSProfile() *configv1.APIServer {
+	apiServer := r.NewApiServer()
+	apiServer.Spec.TLSSecurityProfile = &configv1.TLSSecurityProfile{
+		Type:   configv1.TLSProfileModernType,
+		Modern: &configv1.ModernTLSProfile{},
+	}
+	return apiServer
+}
+
 func (r *TestResources) NewApiServerWithApplicationURL() *configv1.APIServer {
 	return &configv1.APIServer{
 		ObjectMeta: metav1.ObjectMeta{
@@ -4621,10 +5706,9 @@ http {
 
 		ssl_dhparam /etc/nginx-cryostat/dhparam.pem;
 
-		# intermediate configuration
-		ssl_protocols TLSv1.2 TLSv1.3;
-		ssl_ciphers ECDHE-ECDSA-AES128-GCM-SHA256:ECDHE-RSA-AES128-GCM-SHA256:ECDHE-ECDSA-AES256-GCM-SHA384:ECDHE-RSA-AES256-GCM-SHA384:ECDHE-ECDSA-CHACHA20-POLY1305:ECDHE-RSA-CHACHA20-POLY1305:DHE-RSA-AES128-GCM-SHA256:DHE-RSA-AES256-GCM-SHA384:DHE-RSA-CHACHA20-POLY1305;
-		ssl_prefer_server_ciphers off;
+		# configured TLS profile
+		ssl_protocols %s;
+		%sssl_prefer_server_ciphers off;
 
 		# HSTS (ngx_http_headers_module is required) (63072000 seconds)
 		add_header Strict-Transport-Security "max-age=63072000" always;
@@ -4633,10 +5717,10 @@ http {
 		ssl_stapling on;
 		ssl_stapling_verify on;
 
-		ssl_trusted_certificate /var/run/secrets/operator.cryostat.io/%s-agent-tls/ca.crt;
+		ssl_trusted_certificate /var/run/secrets/operator.cryostat.io/%s-ca-bundle/ca.crt;
 
 		# Client certificate authentication
-		ssl_client_certificate /var/run/secrets/operator.cryostat.io/%s-agent-tls/ca.crt;
+		ssl_client_certificate /var/run/secrets/operator.cryostat.io/%s-ca-bundle/ca.crt;
 		ssl_verify_client on;
 
 		location /api/v4/discovery/ {
@@ -4779,7 +5863,8 @@ func (r *TestResources) NewAgentProxyConfigMap() *corev1.ConfigMap {
 	var data map[string]string
 	if r.TLS {
 		data = map[string]string{
-			"nginx.conf": fmt.Sprintf(nginxFormatTLS, r.Name, r.Namespace, r.Name, r.Name, r.Name, r.Name),
+			"nginx.conf": fmt.Sprintf(nginxFormatTLS, r.Name, r.Namespace, r.Name, r.Name,
+				strings.Join(r.tlsProtocols(), " "), r.nginxSSLCiphers(), r.Name, r.Name),
 			"dhparam.pem": `-----BEGIN DH PARAMETERS-----
 MIIBCAKCAQEA//////////+t+FRYortKmq/cViAnPTzx2LnFg84tNpWp4TZBFGQz
 +8yTnc4kmz75fS/jY2MMddj2gbICrsRhetPfHtXV/WVhJDP1H18GbtCFY2VVPe0a
@@ -4813,7 +5898,8 @@ var alphaConfigTLS = `{
       },
       "Cert": {
         "fromFile": "/var/run/secrets/operator.cryostat.io/%s-tls/tls.crt"
-      }
+      },
+      "MinVersion": "%s"%s
     }
   },
   "upstreamConfig": {
@@ -4889,7 +5975,7 @@ var alphaConfigNoTLS = `{
 }`
 
 func (r *TestResources) NewOAuth2ProxyConfigMap() *corev1.ConfigMap {
-	alphaConfig := fmt.Sprintf(alphaConfigTLS, r.Name, r.Name)
+	alphaConfig := fmt.Sprintf(alphaConfigTLS, r.Name, r.Name, r.oauth2ProxyMinVersion(), r.oauth2ProxyCipherSuites())
 	if !r.TLS {
 		alphaConfig = alphaConfigNoTLS
 	}
@@ -4910,6 +5996,95 @@ func (r *TestResources) NewOAuth2ProxyConfigMapOld() *corev1.ConfigMap {
 	return cm
 }
 
+func (r *TestResources) oauth2ProxyMinVersion() string {
+	if r.TLSProfile == operatorv1beta2.TLSProfileModern {
+		return "TLS1.3"
+	}
+	return "TLS1.2"
+}
+
+func (r *TestResources) oauth2ProxyCipherSuites() string {
+	ciphers := r.tlsGoCiphers()
+	if len(ciphers) == 0 {
+		return ""
+	}
+	return ",\n      \"CipherSuites\": [\n        \"" + strings.Join(ciphers, "\",\n        \"") + "\"\n      ]"
+}
+
+func (r *TestResources) nginxSSLCiphers() string {
+	ciphers := r.tlsOpenSSLCiphers()
+	if len(ciphers) == 0 {
+		return ""
+	}
+	return "ssl_ciphers " + strings.Join(ciphers, ":") + ";\n\t\t"
+}
+
+func (r *TestResources) tlsProtocols() []string {
+	if r.TLSProfile == operatorv1beta2.TLSProfileModern {
+		return []string{"TLSv1.3"}
+	}
+	return []string{"TLSv1.2", "TLSv1.3"}
+}
+
+func (r *TestResources) tlsMinVersion() string {
+	if r.TLSProfile == operatorv1beta2.TLSProfileModern {
+		return "TLS 1.3"
+	}
+	return "TLS 1.2"
+}
+
+func (r *TestResources) tlsOpenSSLCiphers() []string {
+	if r.TLSProfile == operatorv1beta2.TLSProfileModern {
+		return []string{}
+	}
+	return []string{
+		"ECDHE-ECDSA-AES128-GCM-SHA256",
+		"ECDHE-RSA-AES128-GCM-SHA256",
+		"ECDHE-ECDSA-AES256-GCM-SHA384",
+		"ECDHE-RSA-AES256-GCM-SHA384",
+		"ECDHE-ECDSA-CHACHA20-POLY1305",
+		"ECDHE-RSA-CHACHA20-POLY1305",
+		"DHE-RSA-AES128-GCM-SHA256",
+		"DHE-RSA-AES256-GCM-SHA384",
+	}
+}
+
+func (r *TestResources) tlsIANACiphers() []string {
+	ciphers := []string{
+		"TLS_AES_128_GCM_SHA256",
+		"TLS_AES_256_GCM_SHA384",
+		"TLS_CHACHA20_POLY1305_SHA256",
+	}
+	if r.TLSProfile == operatorv1beta2.TLSProfileModern {
+		return ciphers
+	}
+	return append(ciphers,
+		"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+		"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+		"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+		"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+		"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256",
+		"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256",
+		"TLS_DHE_RSA_WITH_AES_128_GCM_SHA256",
+		"TLS_DHE_RSA_WITH_AES_256_GCM_SHA384",
+	)
+}
+
+// Cipher suites supported by Go's crypto/tls, excluding TLS 1.3
+func (r *TestResources) tlsGoCiphers() []string {
+	if r.TLSProfile == operatorv1beta2.TLSProfileModern {
+		return []string{}
+	}
+	return []string{
+		"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+		"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+		"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+		"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+		"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256",
+		"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256",
+	}
+}
+
 func (r *TestResources) getClusterUniqueName() string {
 	return "cryostat-" + r.clusterUniqueSuffix("")
 }
@@ -4954,3 +6129,444 @@ func (r *TestResources) NewGenericEvent(obj ctrlclient.Object) event.GenericEven
 		Object: obj,
 	}
 }
+
+func (r *TestResources) NewCryostatWithTargetStatus(targetNamespaces ...string) *model.CryostatInstance {
+	cr := r.NewCryostat()
+	cr.Spec.TargetNamespaces = targetNamespaces
+	cr.Status.TargetNamespaces = targetNamespaces
+	return cr
+}
+
+func (r *TestResources) NewRecording(namespace string) *operatorv1beta2.Recording {
+	return &operatorv1beta2.Recording{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-recording",
+			Namespace: namespace,
+		},
+		Spec: operatorv1beta2.RecordingSpec{
+			Cryostat: operatorv1beta2.CryostatReference{
+				Name:      r.Name,
+				Namespace: r.Namespace,
+			},
+			Target: operatorv1beta2.RecordingTarget{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"app": "my-app",
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *TestResources) NewRecordingWithOptions(namespace string) *operatorv1beta2.Recording {
+	recording := r.NewRecording(namespace)
+	maxSize := resource.MustParse("10Mi")
+	recording.Spec.EventTemplate = &operatorv1beta2.EventTemplateReference{
+		Name: "Profiling",
+		Type: "CUSTOM",
+	}
+	recording.Spec.Duration = &metav1.Duration{Duration: 5 * time.Minute}
+	recording.Spec.MaxAge = &metav1.Duration{Duration: time.Hour}
+	recording.Spec.MaxSize = &maxSize
+	recording.Spec.ArchiveOnStop = true
+	return recording
+}
+
+func (r *TestResources) NewRecordingWithConnectURL(namespace string, connectURL string) *operatorv1beta2.Recording {
+	recording := r.NewRecording(namespace)
+	recording.Spec.Target = operatorv1beta2.RecordingTarget{
+		ConnectURL: connectURL,
+	}
+	return recording
+}
+
+func (r *TestResources) NewRecordingTargetPod(name string, namespace string, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+	}
+}
+
+func (r *TestResources) NewRecordingTarget(id int64, jvmID string, podName string, namespace string) cryostatclient.Target {
+	return cryostatclient.Target{
+		ID:         id,
+		JvmID:      jvmID,
+		ConnectURL: fmt.Sprintf("http://%s.%s.pod:9977", podName, namespace),
+		Alias:      podName,
+		Annotations: cryostatclient.TargetAnnotations{
+			Cryostat: []cryostatclient.KeyValue{
+				{Key: cryostatclient.AnnotationNamespace, Value: namespace},
+				{Key: cryostatclient.AnnotationPodName, Value: podName},
+			},
+		},
+	}
+}
+
+func (r *TestResources) NewAutomatedRule() *operatorv1beta2.AutomatedRule {
+	return &operatorv1beta2.AutomatedRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-rule",
+			Namespace: r.Namespace,
+		},
+		Spec: operatorv1beta2.AutomatedRuleSpec{
+			CryostatName:    r.Name,
+			MatchExpression: "target.labels.app == 'my-app'",
+			EventSpecifier:  "template=Continuous,type=TARGET",
+		},
+	}
+}
+
+func (r *TestResources) NewAutomatedRuleWithOptions() *operatorv1beta2.AutomatedRule {
+	rule := r.NewAutomatedRule()
+	maxSize := resource.MustParse("10Mi")
+	enabled := false
+	rule.Spec.Name = "continuous_my_app"
+	rule.Spec.Description = "Continuous recording of my-app"
+	rule.Spec.ArchivalPeriod = &metav1.Duration{Duration: 30 * time.Minute}
+	rule.Spec.InitialDelay = &metav1.Duration{Duration: 10 * time.Second}
+	rule.Spec.PreservedArchives = 3
+	rule.Spec.MaxAge = &metav1.Duration{Duration: time.Hour}
+	rule.Spec.MaxSize = &maxSize
+	rule.Spec.Enabled = &enabled
+	return rule
+}
+
+func (r *TestResources) NewStoredCredential(namespace string) *operatorv1beta2.StoredCredential {
+	return &operatorv1beta2.StoredCredential{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-credential",
+			Namespace: namespace,
+		},
+		Spec: operatorv1beta2.StoredCredentialSpec{
+			Cryostat: operatorv1beta2.CryostatReference{
+				Name:      r.Name,
+				Namespace: r.Namespace,
+			},
+			SecretName:      "my-jmx-credentials",
+			MatchExpression: "target.labels.app == 'my-app'",
+		},
+	}
+}
+
+func (r *TestResources) NewStoredCredentialSecret(namespace string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-jmx-credentials",
+			Namespace: namespace,
+		},
+		Type: corev1.SecretTypeBasicAuth,
+		Data: map[string][]byte{
+			corev1.BasicAuthUsernameKey: []byte("jmx-user"),
+			corev1.BasicAuthPasswordKey: []byte("jmx-pass"),
+		},
+	}
+}
+
+func (r *TestResources) NewCryostatWithEventTemplateSelector(targetNamespaces ...string) *model.CryostatInstance {
+	cr := r.NewCryostatWithTargetStatus(targetNamespaces...)
+	cr.Spec.EventTemplateSelector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			"cryostat.io/event-template": "true",
+		},
+	}
+	cr.Status.Conditions = []metav1.Condition{
+		{
+			Type:   string(operatorv1beta2.ConditionTypeMainDeploymentAvailable),
+			Status: metav1.ConditionTrue,
+			Reason: "MinimumReplicasAvailable",
+		},
+	}
+	return cr
+}
+
+func (r *TestResources) NewEventTemplateConfigMap(name string, namespace string, templates map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"cryostat.io/event-template": "true",
+			},
+		},
+		Data: templates,
+	}
+}
+
+func (r *TestResources) NewEventTemplateFile(label string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<configuration version="2.0" label="%s" description="Test template" provider="Cryostat">
+  <event name="jdk.CPULoad">
+    <setting name="enabled">true</setting>
+    <setting name="period">1 s</setting>
+  </event>
+</configuration>
+`, label)
+}
+
+func (r *TestResources) NewCryostatWithStorageRetention() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	maxTotalSize := resource.MustParse("4Mi")
+	cr.Spec.StorageOptions = &operatorv1beta2.StorageConfigurations{
+		Retention: &operatorv1beta2.StorageRetention{
+			MaxAge:               &metav1.Duration{Duration: 24 * time.Hour},
+			MaxTotalSize:         &maxTotalSize,
+			MaxArchivesPerTarget: &[]int32{3}[0],
+		},
+	}
+	cr.Status.Conditions = []metav1.Condition{
+		{
+			Type:   string(operatorv1beta2.ConditionTypeMainDeploymentAvailable),
+			Status: metav1.ConditionTrue,
+			Reason: "MinimumReplicasAvailable",
+		},
+	}
+	return cr
+}
+
+func (r *TestResources) NewArchivedRecording(jvmID string, name string, size int64, age time.Duration) cryostatclient.ArchivedRecording {
+	return cryostatclient.ArchivedRecording{
+		JvmID:        jvmID,
+		Name:         name,
+		Size:         size,
+		ArchivedTime: time.Now().Add(-age).Unix(),
+	}
+}
+
+func (r *TestResources) NewCryostatWithBackendsAvailable() *model.CryostatInstance {
+	cr := r.NewCryostat()
+	cr.Status.Conditions = []metav1.Condition{
+		{
+			Type:   string(operatorv1beta2.ConditionTypeDatabaseDeploymentAvailable),
+			Status: metav1.ConditionTrue,
+			Reason: "MinimumReplicasAvailable",
+		},
+		{
+			Type:   string(operatorv1beta2.ConditionTypeStorageDeploymentAvailable),
+			Status: metav1.ConditionTrue,
+			Reason: "MinimumReplicasAvailable",
+		},
+	}
+	return cr
+}
+
+func (r *TestResources) NewCryostatBackup() *operatorv1beta2.CryostatBackup {
+	return &operatorv1beta2.CryostatBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-backup",
+			Namespace: r.Namespace,
+		},
+		Spec: operatorv1beta2.CryostatBackupSpec{
+			CryostatName: r.Name,
+			Destination: operatorv1beta2.BackupLocation{
+				PVC: &operatorv1beta2.BackupPVCLocation{
+					ClaimName: "my-backups",
+					Path:      "cryostat",
+				},
+			},
+		},
+	}
+}
+
+func (r *TestResources) NewCryostatBackupToS3() *operatorv1beta2.CryostatBackup {
+	backup := r.NewCryostatBackup()
+	backup.Spec.Destination = operatorv1beta2.BackupLocation{
+		S3: &operatorv1beta2.BackupS3Location{
+			Endpoint:   "https://s3.example.com",
+			Bucket:     "my-bucket",
+			Prefix:     "cryostat/",
+			Region:     "eu-west-1",
+			SecretName: "my-s3-credentials",
+		},
+	}
+	return backup
+}
+
+func (r *TestResources) NewCompletedCryostatBackup() *operatorv1beta2.CryostatBackup {
+	backup := r.NewCryostatBackup()
+	size := resource.MustParse("1Mi")
+	backup.Status = operatorv1beta2.CryostatBackupStatus{
+		Conditions: []metav1.Condition{
+			{
+				Type:   string(operatorv1beta2.ConditionTypeBackupComplete),
+				Status: metav1.ConditionTrue,
+				Reason: "BackupSucceeded",
+			},
+		},
+		JobName:     "my-backup-backup",
+		ArchiveName: r.Name + "-my-backup.tar.gz",
+		Size:        &size,
+		Checksum:    "sha256:0123456789abcdef",
+	}
+	return backup
+}
+
+func (r *TestResources) NewCryostatRestore() *operatorv1beta2.CryostatRestore {
+	return &operatorv1beta2.CryostatRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-restore",
+			Namespace: r.Namespace,
+		},
+		Spec: operatorv1beta2.CryostatRestoreSpec{
+			CryostatName: r.Name,
+			BackupName:   "my-backup",
+		},
+	}
+}
+
+func (r *TestResources) NewCryostatRestoreInProgress() *operatorv1beta2.CryostatRestore {
+	restore := r.NewCryostatRestore()
+	restore.Status = operatorv1beta2.CryostatRestoreStatus{
+		Conditions: []metav1.Condition{
+			{
+				Type:   string(operatorv1beta2.ConditionTypeRestoreComplete),
+				Status: metav1.ConditionFalse,
+				Reason: "RestoreInProgress",
+			},
+		},
+		JobName: "my-restore-restore",
+	}
+	return restore
+}
+
+func (r *TestResources) NewCryostatRestoreFromSource() *operatorv1beta2.CryostatRestore {
+	restore := r.NewCryostatRestore()
+	restore.Spec.BackupName = ""
+	restore.Spec.Source = &operatorv1beta2.BackupSource{
+		BackupLocation: operatorv1beta2.BackupLocation{
+			S3: &operatorv1beta2.BackupS3Location{
+				Endpoint:   "https://s3.example.com",
+				Bucket:     "my-bucket",
+				SecretName: "my-s3-credentials",
+			},
+		},
+		ArchiveName: "other-cryostat-my-backup.tar.gz",
+	}
+	return restore
+}
+
+func (r *TestResources) NewBackupJobPod(jobName string, exitCode int32, message string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName + "-abcde",
+			Namespace: r.Namespace,
+			Labels: map[string]string{
+				"job-name": jobName,
+			},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: "backup",
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: exitCode,
+							Message:  message,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *TestResources) NewDatabasePod(name string, image string, dataVersion string, imageVersion string) *corev1.Pod {
+	exitCode := int32(0)
+	if dataVersion != imageVersion {
+		exitCode = 1
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.Name + "-database-" + name,
+			Namespace: r.Namespace,
+			Labels: map[string]string{
+				"app":       r.Name,
+				"kind":      "cryostat",
+				"component": "database",
+			},
+		},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{
+				{
+					Name:  "check-data-version",
+					Image: image,
+				},
+			},
+			Containers: []corev1.Container{
+				{
+					Name:  r.Name + "-db",
+					Image: image,
+				},
+			},
+		},
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: "check-data-version",
+					LastTerminationState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: exitCode,
+							Message:  fmt.Sprintf(`{"dataVersion":"%s","imageVersion":"%s"}`, dataVersion, imageVersion),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *TestResources) NewCorePod(name string, image string, imageID string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.Name + "-" + name,
+			Namespace: r.Namespace,
+			Labels: map[string]string{
+				"app":       r.Name,
+				"kind":      "cryostat",
+				"component": "cryostat",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  r.Name,
+					Image: image,
+				},
+			},
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{
+				{
+					Type:   corev1.PodReady,
+					Status: corev1.ConditionTrue,
+				},
+			},
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:    r.Name,
+					Image:   image,
+					ImageID: imageID,
+					Ready:   true,
+				},
+			},
+		},
+	}
+}
+
+func (r *TestResources) NewCryostatWithPVCDeletionPolicy(policy operatorv1beta2.PVCDeletionPolicy) *model.CryostatInstance {
+	cr := r.NewCryostat()
+	snapshotClass := "my-snapshot-class"
+	cr.Spec.StorageOptions = &operatorv1beta2.StorageConfigurations{
+		Database: &operatorv1beta2.StorageConfiguration{
+			DeletionPolicy:          policy,
+			VolumeSnapshotClassName: &snapshotClass,
+		},
+		ObjectStorage: &operatorv1beta2.StorageConfiguration{
+			DeletionPolicy: policy,
+		},
+	}
+	return cr
+}
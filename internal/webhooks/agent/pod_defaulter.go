@@ -94,6 +94,12 @@ func (r *podMutator) Default(ctx context.Context, obj runtime.Object) error {
 			pod.Namespace, cr.Name, cr.Namespace)
 	}
 
+	// Leave the pod unmodified while this Cryostat is paused
+	if cr.Spec.Paused {
+		r.log.Info("Cryostat is paused, skipping agent injection", "cryostat", cr.Name, "namespace", pod.Namespace)
+		return nil
+	}
+
 	// Check whether TLS is enabled for this CR
 	crModel := model.FromCryostat(cr)
 	tlsEnabled := r.IsCertManagerEnabled(crModel)
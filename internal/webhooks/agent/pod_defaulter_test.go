@@ -224,6 +224,19 @@ var _ = Describe("PodDefaulter", func() {
 				ExpectPod()
 			})
 
+			Context("with a paused Cryostat", func() {
+				BeforeEach(func() {
+					cr := t.NewCryostat()
+					cr.Spec.Paused = true
+					t.objs = append(t.objs, cr.Object)
+					originalPod = t.NewPod()
+					// Should not inject the agent
+					expectedPod = originalPod
+				})
+
+				ExpectPod()
+			})
+
 			Context("with no name label", func() {
 				BeforeEach(func() {
 					t.objs = append(t.objs, t.NewCryostat().Object)
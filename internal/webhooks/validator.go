@@ -17,6 +17,7 @@ package webhooks
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
 	"github.com/go-logr/logr"
@@ -111,7 +112,37 @@ func (r *cryostatValidator) validate(ctx context.Context, obj runtime.Object, op
 		}
 	}
 
-	return nil, nil
+	return append(getImageOverrideWarnings(cr), getGrafanaEnvWarnings(cr)...), nil
+}
+
+// getImageOverrideWarnings warns users that the images they override are not managed by the operator
+func getImageOverrideWarnings(cr *operatorv1beta2.Cryostat) admission.Warnings {
+	images := cr.Spec.Images
+	if images == nil {
+		return nil
+	}
+	overrides := []struct {
+		component string
+		image     string
+	}{
+		{"core", images.Core},
+		{"datasource", images.Datasource},
+		{"grafana", images.Grafana},
+		{"reports", images.Reports},
+		{"storage", images.Storage},
+		{"database", images.Database},
+		{"authProxy", images.AuthProxy},
+		{"agentProxy", images.AgentProxy},
+	}
+	var warnings admission.Warnings
+	for _, override := range overrides {
+		if len(override.image) > 0 {
+			warnings = append(warnings, fmt.Sprintf("spec.images.%s overrides the image provided by the operator with %s. "+
+				"This image will not be updated when the operator is upgraded, and may not be supported.",
+				override.component, override.image))
+		}
+	}
+	return warnings
 }
 
 func translateExtra(extra map[string]authnv1.ExtraValue) map[string]authzv1.ExtraValue {
@@ -126,3 +157,19 @@ func translateExtra(extra map[string]authnv1.ExtraValue) map[string]authzv1.Extr
 
 	return result
 }
+
+// getGrafanaEnvWarnings warns users of environment variables for Grafana that will be ignored,
+// since they do not correspond to Grafana settings
+func getGrafanaEnvWarnings(cr *operatorv1beta2.Cryostat) admission.Warnings {
+	if cr.Spec.GrafanaOptions == nil {
+		return nil
+	}
+	var warnings admission.Warnings
+	for _, env := range cr.Spec.GrafanaOptions.Env {
+		if !strings.HasPrefix(env.Name, "GF_") {
+			warnings = append(warnings, fmt.Sprintf("spec.grafanaOptions.env contains %s, which is not a Grafana setting "+
+				"and will be ignored. Grafana settings must begin with \"GF_\".", env.Name))
+		}
+	}
+	return warnings
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"strconv"
 
+	operatorv1beta2 "github.com/cryostatio/cryostat-operator/api/v1beta2"
 	"github.com/cryostatio/cryostat-operator/internal/controllers/model"
 	"github.com/cryostatio/cryostat-operator/internal/test"
 	"github.com/cryostatio/cryostat-operator/internal/webhooks"
@@ -27,12 +28,25 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 )
 
+// warningRecorder collects the warnings returned by the API server
+type warningRecorder struct {
+	warnings []string
+}
+
+func (r *warningRecorder) HandleWarningHeader(code int, agent string, text string) {
+	r.warnings = append(r.warnings, text)
+}
+
 type validatorTestInput struct {
 	client ctrlclient.Client
 	objs   []ctrlclient.Object
@@ -97,9 +111,38 @@ var _ = Describe("CryostatValidator", func() {
 		})
 
 		Context("creates a Cryostat", func() {
-			It("should allow the request", func() {
-				err := t.client.Create(ctx, cr.Object)
-				Expect(err).ToNot(HaveOccurred())
+			It("should allow the request without warnings", func() {
+				warnings := t.createWithWarnings(cr)
+				Expect(warnings).To(BeEmpty())
+			})
+		})
+
+		Context("creates a Cryostat with image overrides", func() {
+			BeforeEach(func() {
+				cr = t.NewCryostatWithImageOverrides()
+			})
+
+			It("should allow the request with a warning for each image", func() {
+				warnings := t.createWithWarnings(cr)
+				Expect(warnings).To(HaveLen(8))
+				Expect(warnings).To(ContainElement("spec.images.core overrides the image provided by the operator with " +
+					"mirror.example.com/cryostat/core:custom. This image will not be updated when the operator is upgraded, " +
+					"and may not be supported."))
+				for _, component := range []string{"datasource", "grafana", "reports", "storage", "database", "authProxy", "agentProxy"} {
+					Expect(warnings).To(ContainElement(HavePrefix("spec.images." + component + " overrides")))
+				}
+			})
+		})
+
+		Context("creates a Cryostat with Grafana settings", func() {
+			BeforeEach(func() {
+				cr = t.NewCryostatWithGrafanaOptions()
+			})
+
+			It("should allow the request with a warning for the variable that is not a Grafana setting", func() {
+				warnings := t.createWithWarnings(cr)
+				Expect(warnings).To(ConsistOf("spec.grafanaOptions.env contains EXTRA_VAR, which is not a Grafana setting " +
+					"and will be ignored. Grafana settings must begin with \"GF_\"."))
 			})
 		})
 
@@ -190,3 +233,22 @@ func expectErrNotPermitted(actual error, op string, namespace string) {
 	Expect(kerrors.IsForbidden(actual)).To(BeTrue(), "expected Forbidden API error")
 	Expect(actual.Error()).To(ContainSubstring(expectedErr.Error()))
 }
+
+// createWithWarnings creates the Cryostat, and returns the warnings from the admission webhooks.
+// The controller-runtime client logs warnings instead of returning them, so a dynamic client is used.
+func (t *validatorTestInput) createWithWarnings(cr *model.CryostatInstance) []string {
+	recorder := &warningRecorder{}
+	config := rest.CopyConfig(cfg)
+	config.WarningHandler = recorder
+	client, err := dynamic.NewForConfig(config)
+	Expect(err).ToNot(HaveOccurred())
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cr.Object)
+	Expect(err).ToNot(HaveOccurred())
+	u := &unstructured.Unstructured{Object: obj}
+	u.SetGroupVersionKind(operatorv1beta2.GroupVersion.WithKind("Cryostat"))
+	_, err = client.Resource(operatorv1beta2.GroupVersion.WithResource("cryostats")).Namespace(cr.InstallNamespace).
+		Create(ctx, u, metav1.CreateOptions{})
+	Expect(err).ToNot(HaveOccurred())
+	return recorder.warnings
+}